@@ -31,16 +31,10 @@ export const filterProgrammersBySkill = async (searchSkill: string) => {
   }
 }
 
-type imageRequest = {
-  type: string;
-  image: string | ArrayBuffer | null;
-};
-
 type CreateUserRequest = {
   name: string;
   email: string;
   jobTitle: string;
-  image: imageRequest;
   skills: string[];
 }
 
@@ -1,4 +1,4 @@
-import { ChangeEvent, FormEvent, useState } from "react";
+import { FormEvent, useState } from "react";
 import { createUser } from "../../api";
 import "./addProgrammer.css";
 
@@ -6,20 +6,9 @@ const AddProgrammer = () => {
     const [name, setName] = useState<string>("");
     const [email, setEmail] = useState<string>("");
     const [jobTitle, setJobTitle] = useState<string>("");
-    const [imageFile, setImageFile] = useState<File | null>(null);
     const [skills, setSkills] = useState<string[]>([]);
     const [newSkill, setNewSkill] = useState<string>("");
 
-    function handleImageChange(e: ChangeEvent<HTMLInputElement>) {
-        const {
-            target: { files },
-        } = e;
-        if (files) {
-            const file = files[0];
-            setImageFile(file);
-        }
-    }
-
     function handleSkillAdd() {
         setSkills([...skills, newSkill]);
         setNewSkill("");
@@ -27,39 +16,19 @@ const AddProgrammer = () => {
 
     async function handleSubmit(e: FormEvent<HTMLFormElement>) {
         e.preventDefault()
-        if (imageFile) {
-            // Convert image to a string
-            const image = await readFileAsBase64(imageFile);
-
-            const imageRequest = {
-                type: "image/png",
-                image
-            }
 
-            await createUser({ name, email, jobTitle, image: imageRequest, skills })
+        await createUser({ name, email, jobTitle, skills })
 
-            // Reset the form values
-            setName("");
-            setEmail("");
-            setJobTitle("");
-            setImageFile(null);
-            setSkills([]);
-            setNewSkill("");
+        // Reset the form values
+        setName("");
+        setEmail("");
+        setJobTitle("");
+        setSkills([]);
+        setNewSkill("");
 
-            alert("Programmer added successfully!");
-        }
+        alert("Programmer added successfully!");
     }
 
-    // Helper function to read the image file as a base64 string
-    const readFileAsBase64 = (file: File): Promise<string | ArrayBuffer | null> => {
-        return new Promise((resolve, reject) => {
-            const reader = new FileReader();
-            reader.onload = () => resolve(reader.result);
-            reader.onerror = (error) => reject(error);
-            reader.readAsDataURL(file);
-        });
-    };
-
     return (
         <div className="formContainer">
             <form onSubmit={handleSubmit}>
@@ -97,19 +66,6 @@ const AddProgrammer = () => {
                         required
                     />
                 </div>
-                <div className="alignSameLine">
-                    <label>Image:</label>
-                    <input
-                        type="file"
-                        accept="image/*"
-                        onChange={handleImageChange}
-                        className="imageInput"
-                        required
-                    />
-                    <div className="tooltip">
-                        Please upload a PNG image of size less than 500kb.
-                    </div>
-                </div>
                 <>
                     <div className="alignSkillsItems">
                         <label>Skills:</label>
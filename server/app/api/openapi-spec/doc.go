@@ -0,0 +1,3 @@
+// Package openapispec contains the OpenAPI document of the REST API. The document is embedded in the binary so it can be
+// served at runtime, checked against the registered routes in tests & used to validate incoming requests
+package openapispec
@@ -0,0 +1,98 @@
+package openapispec
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// Document is the parsed OpenAPI document along with the router that matches requests to its operations
+type Document struct {
+	*openapi3.T
+	router routers.Router
+}
+
+// Operation returns the operation of the given method on the given path. The path may use either OpenAPI templates, e.g.
+// /users/{id}, or fiber route parameters, e.g. /users/:id. Nil is returned if there is no such operation
+func (d *Document) Operation(method, path string) *openapi3.Operation {
+	pathItem := d.Paths.Value(TemplatePath(path))
+	if pathItem == nil {
+		return nil
+	}
+
+	return pathItem.GetOperation(strings.ToUpper(method))
+}
+
+// Operations returns every operation in the document keyed by its method & path, e.g. "GET /users/{id}"
+func (d *Document) Operations() map[string]*openapi3.Operation {
+	operations := map[string]*openapi3.Operation{}
+	for path, pathItem := range d.Paths.Map() {
+		for method, operation := range pathItem.Operations() {
+			operations[method+" "+path] = operation
+		}
+	}
+
+	return operations
+}
+
+// FindRoute finds the route of the operation that handles a request returning it along with the values of its path
+// parameters. Nil is returned if the request is not described by the document
+func (d *Document) FindRoute(req *http.Request) (*routers.Route, map[string]string) {
+	route, pathParams, err := d.router.FindRoute(req)
+	if err != nil {
+		return nil, nil
+	}
+
+	return route, pathParams
+}
+
+// SchemaPropertyNames returns the names of the properties of the schema with the given name in the components of the document
+func (d *Document) SchemaPropertyNames(name string) []string {
+	schema, ok := d.Components.Schemas[name]
+	if !ok || schema.Value == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(schema.Value.Properties))
+	for property := range schema.Value.Properties {
+		names = append(names, property)
+	}
+
+	return names
+}
+
+// JSONFieldNames returns the JSON names of the fields of the given struct, which can be compared to the property names
+// of the schema describing the struct
+func JSONFieldNames(v any) []string {
+	t := reflect.TypeOf(v)
+
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = t.Field(i).Name
+		}
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// TemplatePath converts fiber route parameters, e.g. /users/:id to OpenAPI path templates, e.g. /users/{id} & removes
+// any trailing slash
+func TemplatePath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + strings.TrimSuffix(segment[1:], "?") + "}"
+		}
+	}
+
+	return "/" + strings.Join(segments, "/")
+}
@@ -0,0 +1,60 @@
+package openapispec
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	//go:embed openapi.yaml
+	specYAML []byte
+
+	//go:embed docs.html
+	docsHTML []byte
+)
+
+// JSON returns the OpenAPI document encoded as JSON
+func JSON() ([]byte, error) {
+	var spec any
+	if err := yaml.Unmarshal(specYAML, &spec); err != nil {
+		return nil, fmt.Errorf("failed to decode openapi spec: %w", err)
+	}
+
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode openapi spec: %w", err)
+	}
+
+	return specJSON, nil
+}
+
+// Load parses the OpenAPI document & builds the router that matches requests to its operations
+func Load() (*Document, error) {
+	spec, err := openapi3.NewLoader().LoadFromData(specYAML)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse openapi spec: %w", err)
+	}
+
+	// requests are matched on their path only, the servers of the document list where the API is served during development
+	// & the API is served behind any host in other environments
+	routed := *spec
+	routed.Servers = nil
+
+	router, err := gorillamux.NewRouter(&routed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build openapi router: %w", err)
+	}
+
+	return &Document{T: spec, router: router}, nil
+}
+
+// DocsHTML returns the HTML page that renders the API documentation with Swagger UI from the OpenAPI document served at
+// openapi.json relative to the page
+func DocsHTML() []byte {
+	return docsHTML
+}
@@ -0,0 +1,212 @@
+package openapispec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+
+	"github.com/BrianLusina/skillq/server/utils/validators"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// mediaTypeJSON is the media type of JSON request bodies, the only request bodies that are validated
+const mediaTypeJSON = "application/json"
+
+// ErrMalformedBody is returned when a request body that should be validated can not be decoded as JSON
+var ErrMalformedBody = errors.New("malformed request body")
+
+func init() {
+	// the formats used by the document that are not validated by default
+	openapi3.DefineStringFormatCallback("email", func(value string) error {
+		_, err := mail.ParseAddress(value)
+		return err
+	})
+	openapi3.DefineStringFormat("uuid", `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+}
+
+// ValidateRequest validates the parameters & JSON body of a request against the operation of the route it matched
+// returning every field that failed validation. The body is only read for operations that take JSON, which leaves the
+// bodies of other requests, such as multipart uploads streamed to their handlers, unread. ErrMalformedBody is returned if
+// the body can not be decoded
+func (d *Document) ValidateRequest(ctx context.Context, req *http.Request, route *routers.Route, pathParams map[string]string, body func() []byte) ([]validators.FieldError, error) {
+	options := &openapi3filter.Options{
+		AuthenticationFunc:  openapi3filter.NoopAuthenticationFunc,
+		MultiError:          true,
+		SkipSettingDefaults: true,
+	}
+
+	if takesJSON(route.Operation) {
+		raw := body()
+		if len(raw) > 0 && !json.Valid(raw) {
+			return nil, ErrMalformedBody
+		}
+		req.Body = io.NopCloser(bytes.NewReader(raw))
+		req.ContentLength = int64(len(raw))
+	} else {
+		options.ExcludeRequestBody = true
+	}
+
+	err := openapi3filter.ValidateRequest(ctx, &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+		Options:    options,
+	})
+	if err == nil {
+		return nil, nil
+	}
+
+	return requestFieldErrors(err)
+}
+
+// takesJSON checks if the operation has a JSON request body
+func takesJSON(operation *openapi3.Operation) bool {
+	if operation.RequestBody == nil || operation.RequestBody.Value == nil {
+		return false
+	}
+
+	return operation.RequestBody.Value.Content.Get(mediaTypeJSON) != nil
+}
+
+// requestFieldErrors maps the errors returned when validating a request to the fields that failed validation
+func requestFieldErrors(err error) ([]validators.FieldError, error) {
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		var fieldErrs []validators.FieldError
+		for _, err := range e {
+			errs, err := requestFieldErrors(err)
+			if err != nil {
+				return nil, err
+			}
+			fieldErrs = append(fieldErrs, errs...)
+		}
+		return fieldErrs, nil
+	case *openapi3filter.RequestError:
+		if e.Parameter == nil {
+			return schemaFieldErrors("", e.Err), nil
+		}
+
+		var parseErr *openapi3filter.ParseError
+		if errors.As(e.Err, &parseErr) && e.Parameter.Schema != nil && e.Parameter.Schema.Value != nil {
+			name := e.Parameter.Name
+			return []validators.FieldError{typeErr(name, e.Parameter.Schema.Value.Type.Slice())}, nil
+		}
+
+		return schemaFieldErrors(e.Parameter.Name, e.Err), nil
+	default:
+		return nil, err
+	}
+}
+
+// schemaFieldErrors maps the errors of validating a value against its schema to the fields that failed validation. Fields are
+// named after their path from the given name, the root of a request body is named body
+func schemaFieldErrors(name string, err error) []validators.FieldError {
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		var fieldErrs []validators.FieldError
+		for _, err := range e {
+			fieldErrs = append(fieldErrs, schemaFieldErrors(name, err)...)
+		}
+		return fieldErrs
+	case *openapi3.SchemaError:
+		return []validators.FieldError{schemaErr(fieldName(name, e.JSONPointer()), e)}
+	default:
+		field := fieldName(name, nil)
+		if errors.Is(err, openapi3filter.ErrInvalidRequired) {
+			return []validators.FieldError{requiredErr(field)}
+		}
+		return []validators.FieldError{{Field: field, Message: fmt.Sprintf("%s is invalid", field)}}
+	}
+}
+
+// schemaErr describes the keyword of the schema the field failed to validate against
+func schemaErr(field string, err *openapi3.SchemaError) validators.FieldError {
+	schema := err.Schema
+
+	var message string
+	switch err.SchemaField {
+	case "required":
+		return requiredErr(field)
+	case "type":
+		return typeErr(field, schema.Type.Slice())
+	case "minLength":
+		if schema.MinLength == 1 {
+			return requiredErr(field)
+		}
+		message = fmt.Sprintf("%s must be at least %d characters long", field, schema.MinLength)
+	case "maxLength":
+		message = fmt.Sprintf("%s must be at most %d characters long", field, *schema.MaxLength)
+	case "minItems":
+		message = fmt.Sprintf("%s must contain at least %d items", field, schema.MinItems)
+	case "maxItems":
+		message = fmt.Sprintf("%s must contain at most %d items", field, *schema.MaxItems)
+	case "minimum":
+		message = fmt.Sprintf("%s must be at least %v", field, *schema.Min)
+	case "maximum":
+		message = fmt.Sprintf("%s must be at most %v", field, *schema.Max)
+	case "enum":
+		values := make([]string, len(schema.Enum))
+		for i, value := range schema.Enum {
+			values[i] = fmt.Sprint(value)
+		}
+		message = fmt.Sprintf("%s must be one of %s", field, strings.Join(values, ", "))
+	case "pattern":
+		message = fmt.Sprintf("%s must match the pattern %s", field, schema.Pattern)
+	case "format":
+		switch schema.Format {
+		case "email":
+			message = fmt.Sprintf("%s must be a valid email address", field)
+		case "uuid":
+			message = fmt.Sprintf("%s must be a valid UUID", field)
+		case "date-time":
+			message = fmt.Sprintf("%s must be a valid RFC 3339 date time", field)
+		default:
+			message = fmt.Sprintf("%s must be a valid %s", field, schema.Format)
+		}
+	default:
+		message = fmt.Sprintf("%s is invalid: %s", field, err.Reason)
+	}
+
+	return validators.FieldError{Field: field, Message: message}
+}
+
+func requiredErr(field string) validators.FieldError {
+	return validators.FieldError{Field: field, Message: fmt.Sprintf("%s is a required field", field)}
+}
+
+func typeErr(field string, types []string) validators.FieldError {
+	return validators.FieldError{Field: field, Message: fmt.Sprintf("%s must be of type %s", field, strings.Join(types, " or "))}
+}
+
+// fieldName joins the JSON pointer of a value to the given name, e.g. image.content or skills[1]. The root of a request
+// body is named body
+func fieldName(name string, pointer []string) string {
+	var b strings.Builder
+	b.WriteString(name)
+
+	for _, key := range pointer {
+		if _, err := strconv.Atoi(key); err == nil {
+			b.WriteString("[" + key + "]")
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString(".")
+		}
+		b.WriteString(key)
+	}
+
+	if b.Len() == 0 {
+		return "body"
+	}
+
+	return b.String()
+}
@@ -0,0 +1,153 @@
+package openapispec
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/BrianLusina/skillq/server/utils/validators"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindRoute(t *testing.T) {
+	doc, err := Load()
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		method              string
+		path                string
+		expectedOperationID string
+		expectedPathParams  map[string]string
+	}{
+		{method: "POST", path: "/api/v1/users/verify-email", expectedOperationID: "verifyUserEmail", expectedPathParams: map[string]string{}},
+		{method: "GET", path: "/api/v1/users/123", expectedOperationID: "getUserById", expectedPathParams: map[string]string{"id": "123"}},
+		{method: "GET", path: "/api/v1/users", expectedOperationID: "getAllUsers", expectedPathParams: map[string]string{}},
+		{method: "GET", path: "/api/v1/users/skill/go", expectedOperationID: "getAllUsersBySkill", expectedPathParams: map[string]string{"skill": "go"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			route, pathParams := doc.FindRoute(httptest.NewRequest(tc.method, "https://skillq.example.com"+tc.path, nil))
+			assert.NotNil(t, route)
+			assert.Equal(t, tc.expectedOperationID, route.Operation.OperationID)
+			assert.Equal(t, tc.expectedPathParams, pathParams)
+		})
+	}
+
+	t.Run("returns nil for undocumented requests", func(t *testing.T) {
+		route, _ := doc.FindRoute(httptest.NewRequest("PATCH", "/api/v1/users/123/role", nil))
+		assert.Nil(t, route)
+	})
+}
+
+func TestValidateRequest(t *testing.T) {
+	doc, err := Load()
+	assert.NoError(t, err)
+
+	validate := func(method, target, body string) ([]validators.FieldError, error) {
+		req := httptest.NewRequest(method, target, nil)
+		req.Header.Set("Content-Type", mediaTypeJSON)
+
+		route, pathParams := doc.FindRoute(req)
+		assert.NotNil(t, route)
+
+		return doc.ValidateRequest(context.Background(), req, route, pathParams, func() []byte {
+			return []byte(body)
+		})
+	}
+
+	testCases := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		expectedErrors []validators.FieldError
+	}{
+		{
+			name:   "valid create user request",
+			method: "POST",
+			target: "/api/v1/users",
+			body:   `{"name": "Jane", "email": "jane@example.com", "password": "secret", "skills": ["go"], "jobTitle": "Engineer"}`,
+		},
+		{
+			name:   "invalid create user request",
+			method: "POST",
+			target: "/api/v1/users",
+			body:   `{"name": "J", "email": "not-an-email", "password": "", "skills": []}`,
+			expectedErrors: []validators.FieldError{
+				{Field: "jobTitle", Message: "jobTitle is a required field"},
+				{Field: "email", Message: "email must be a valid email address"},
+				{Field: "name", Message: "name must be at least 2 characters long"},
+				{Field: "password", Message: "password is a required field"},
+				{Field: "skills", Message: "skills must contain at least 1 items"},
+			},
+		},
+		{
+			name:           "missing request body",
+			method:         "POST",
+			target:         "/api/v1/auth/login",
+			expectedErrors: []validators.FieldError{{Field: "body", Message: "body is a required field"}},
+		},
+		{
+			name:   "nullable fields of a partial update",
+			method: "PATCH",
+			target: "/api/v1/users/8c4f3d0e-3b8a-4a37-9a0e-0c7d43e2f4a1",
+			body:   `{"name": null, "skills": null}`,
+		},
+		{
+			name:   "invalid partial update",
+			method: "PATCH",
+			target: "/api/v1/users/123",
+			body:   `{"skills": ["go", 1], "jobTitle": ""}`,
+			expectedErrors: []validators.FieldError{
+				{Field: "id", Message: "id must be a valid UUID"},
+				{Field: "jobTitle", Message: "jobTitle is a required field"},
+				{Field: "skills[1]", Message: "skills[1] must be of type string"},
+			},
+		},
+		{
+			name:   "valid query parameters",
+			method: "GET",
+			target: "/api/v1/users?limit=10&total=true&sortby=ASC",
+		},
+		{
+			name:   "invalid query parameters",
+			method: "GET",
+			target: "/api/v1/users?limit=1000&offset=first&total=yes&sortby=UP",
+			expectedErrors: []validators.FieldError{
+				{Field: "limit", Message: "limit must be at most 100"},
+				{Field: "offset", Message: "offset must be of type integer"},
+				{Field: "total", Message: "total must be of type boolean"},
+				{Field: "sortby", Message: "sortby must be one of ASC, DESC"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fieldErrs, err := validate(tc.method, tc.target, tc.body)
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, tc.expectedErrors, fieldErrs)
+		})
+	}
+
+	t.Run("malformed body", func(t *testing.T) {
+		_, err := validate("POST", "/api/v1/auth/login", `{"email":`)
+		assert.ErrorIs(t, err, ErrMalformedBody)
+	})
+
+	t.Run("leaves bodies that are not JSON unread", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/v1/users/8c4f3d0e-3b8a-4a37-9a0e-0c7d43e2f4a1/image", nil)
+		req.Header.Set("Content-Type", "multipart/form-data; boundary=boundary")
+
+		route, pathParams := doc.FindRoute(req)
+		assert.NotNil(t, route)
+
+		fieldErrs, err := doc.ValidateRequest(context.Background(), req, route, pathParams, func() []byte {
+			t.Fatal("the body of a multipart request was read")
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, fieldErrs)
+	})
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/auth"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound"
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// bearerScheme is the authorization scheme of access tokens
+	bearerScheme = "Bearer "
+
+	// UserIDLocalKey is the key of the authenticated user's ID in the locals of a request
+	UserIDLocalKey = "userID"
+)
+
+// Authenticate returns a middleware that authenticates a request with the bearer access token in its Authorization header.
+// The ID of the authenticated user is added to the user context of the request, which handlers pass on to services, & to
+// the locals of the request. Requests without a valid access token are rejected with an unauthorized error
+func Authenticate(authService inbound.AuthService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		header := c.Get(fiber.HeaderAuthorization)
+		if len(header) < len(bearerScheme) || !strings.EqualFold(header[:len(bearerScheme)], bearerScheme) {
+			return errdefs.NewUnauthorizedError("missing bearer access token", nil)
+		}
+
+		accessToken := strings.TrimSpace(header[len(bearerScheme):])
+
+		userID, err := authService.Authenticate(c.UserContext(), accessToken)
+		if err != nil {
+			return err
+		}
+
+		c.Locals(UserIDLocalKey, userID)
+		c.SetUserContext(auth.WithUserID(c.UserContext(), userID))
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/BrianLusina/skillq/server/app/api/rest/utils"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/auth"
+	mockusersvc "github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound/mocks"
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuthenticate(t *testing.T) {
+	newTestApp := func(t *testing.T) (*fiber.App, *mockusersvc.MockAuthService) {
+		mockCtrl := gomock.NewController(t)
+		mockAuthSvc := mockusersvc.NewMockAuthService(mockCtrl)
+
+		log, _ := logger.NewTestLogger()
+		app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler(log)})
+		app.Get("/protected", Authenticate(mockAuthSvc), func(c *fiber.Ctx) error {
+			userID, ok := auth.UserIDFromContext(c.UserContext())
+			assert.True(t, ok)
+			assert.Equal(t, userID, c.Locals(UserIDLocalKey))
+			return c.SendString(userID)
+		})
+
+		return app, mockAuthSvc
+	}
+
+	t.Run("rejects requests without a bearer access token", func(t *testing.T) {
+		for _, header := range []string{"", "Basic dXNlcjpwYXNz", "Bearer"} {
+			app, mockAuthSvc := newTestApp(t)
+			mockAuthSvc.EXPECT().Authenticate(gomock.Any(), gomock.Any()).Times(0)
+
+			req := httptest.NewRequest(fiber.MethodGet, "/protected", nil)
+			req.Header.Set(fiber.HeaderAuthorization, header)
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode, header)
+			assert.Equal(t, "Bearer", resp.Header.Get(fiber.HeaderWWWAuthenticate))
+		}
+	})
+
+	t.Run("rejects requests with an invalid access token", func(t *testing.T) {
+		app, mockAuthSvc := newTestApp(t)
+		mockAuthSvc.EXPECT().Authenticate(gomock.Any(), "invalid").
+			Return("", errdefs.NewUnauthorizedError("invalid access token", nil)).Times(1)
+
+		req := httptest.NewRequest(fiber.MethodGet, "/protected", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer invalid")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("adds the authenticated user to the request context", func(t *testing.T) {
+		app, mockAuthSvc := newTestApp(t)
+		mockAuthSvc.EXPECT().Authenticate(gomock.Any(), "valid").Return("user-id", nil).Times(1)
+
+		req := httptest.NewRequest(fiber.MethodGet, "/protected", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer valid")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// LimitBody returns a middleware that limits the size of request bodies the server streams rather than buffers, which are
+// bodies larger than the body limit of the server or sent with chunked encoding. Handlers reading such a body would
+// otherwise read all of it into memory whatever its size. Streamed bodies up to the limit are buffered for the handlers
+// & larger ones are rejected with 413 Request Entity Too Large. Requests for which skip returns true are passed on
+// untouched to handlers that stream their bodies & enforce their own limits
+func LimitBody(limit int, skip func(c *fiber.Ctx) bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !c.Request().IsBodyStream() || (skip != nil && skip(c)) {
+			return c.Next()
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Context().RequestBodyStream(), int64(limit)+1))
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "failed to read request body")
+		}
+
+		if len(body) > limit {
+			return fiber.ErrRequestEntityTooLarge
+		}
+
+		c.Request().SetBody(body)
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/BrianLusina/skillq/server/app/api/rest/utils"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitBody(t *testing.T) {
+	const limit = 32
+
+	log, _ := logger.NewTestLogger()
+	// bodies larger than the server's body limit are streamed
+	app := fiber.New(fiber.Config{
+		ErrorHandler:      utils.ErrorHandler(log),
+		StreamRequestBody: true,
+		BodyLimit:         8,
+	})
+	app.Use(LimitBody(limit, func(c *fiber.Ctx) bool {
+		return c.Path() == "/stream"
+	}))
+
+	echo := func(c *fiber.Ctx) error {
+		return c.Send(c.Body())
+	}
+	app.Post("/echo", echo)
+	app.Post("/stream", func(c *fiber.Ctx) error {
+		body, err := io.ReadAll(c.Context().RequestBodyStream())
+		if err != nil {
+			return err
+		}
+		return c.Send(body)
+	})
+
+	testCases := []struct {
+		name           string
+		path           string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name:           "buffers streamed bodies within the limit",
+			path:           "/echo",
+			body:           strings.Repeat("a", limit),
+			expectedStatus: fiber.StatusOK,
+		},
+		{
+			name:           "rejects streamed bodies over the limit",
+			path:           "/echo",
+			body:           strings.Repeat("a", limit+1),
+			expectedStatus: fiber.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "leaves bodies of skipped requests to their handlers",
+			path:           "/stream",
+			body:           strings.Repeat("a", limit*4),
+			expectedStatus: fiber.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, tc.path, strings.NewReader(tc.body)))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, resp.StatusCode)
+
+			if tc.expectedStatus == fiber.StatusOK {
+				body, err := io.ReadAll(resp.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.body, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,2 @@
+// Package middleware contains the fiber middleware of the REST API
+package middleware
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"errors"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/metrics"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// routeUnmatched labels requests that matched no route, so that requests for arbitrary paths do not each add a series
+	routeUnmatched = "unmatched"
+
+	// routeUnrouted labels requests that a middleware responded to before they were routed, such as requests rejected by
+	// request validation, as no route template is known for them
+	routeUnrouted = "unrouted"
+
+	// localRouteTemplate is the local the route template of a request is kept in once it is known, as the error that tells
+	// requests that matched no route apart is handled by the first middleware to see it
+	localRouteTemplate = "routeTemplate"
+
+	// localHandledError is the local the error a request failed with is kept in once the Metrics middleware has passed it
+	// to the error handler, so that the middlewares before it, such as Tracing, can still record it
+	localHandledError = "handledError"
+)
+
+// Metrics returns a middleware that records the metrics of every request by the template of the route it matched. Errors
+// returned by later handlers are passed to the error handler here, so that the status code it responds with is recorded,
+// & are kept for the middlewares before it
+func Metrics(m *metrics.HTTPMetrics) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		// the route of the middleware is the route of the request until it is routed past the middlewares
+		middlewareRoute := c.Route()
+
+		err := c.Next()
+		if err != nil {
+			c.Locals(localHandledError, err)
+			if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		m.Observe(c.Method(), routeTemplate(c, middlewareRoute, err), c.Response().StatusCode(), time.Since(start))
+
+		return nil
+	}
+}
+
+// routeTemplate is the template of the route a request matched, such as /api/v1/users/:id. Requests that are still on the
+// route of the middleware were either not matched by any route or responded to by a later middleware
+func routeTemplate(c *fiber.Ctx, middlewareRoute *fiber.Route, err error) string {
+	if route, ok := c.Locals(localRouteTemplate).(string); ok {
+		return route
+	}
+
+	route := c.Route().Path
+	// fiber responds to requests that match no route with its own not found & method not allowed errors
+	var fiberErr *fiber.Error
+	switch {
+	case errors.As(err, &fiberErr) && (fiberErr.Code == fiber.StatusNotFound || fiberErr.Code == fiber.StatusMethodNotAllowed):
+		route = routeUnmatched
+	case c.Route() == middlewareRoute:
+		route = routeUnrouted
+	}
+
+	c.Locals(localRouteTemplate, route)
+	return route
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/BrianLusina/skillq/server/app/api/rest/utils"
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/metrics"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	log, _ := logger.NewTestLogger()
+	registry := metrics.NewRegistry()
+
+	app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler(log)})
+	app.Use(Metrics(metrics.NewHTTPMetrics(registry)))
+	app.Use(func(c *fiber.Ctx) error {
+		if c.Get("X-Reject") != "" {
+			return utils.WriteWithError(c, fiber.StatusBadRequest, "rejected")
+		}
+		return c.Next()
+	})
+	app.Get("/api/v1/users/:id", func(c *fiber.Ctx) error {
+		if c.Params("id") == "missing" {
+			return errdefs.NewNotFoundError("user not found", nil)
+		}
+		return c.SendString(c.Params("id"))
+	})
+
+	for _, path := range []string{"/api/v1/users/1", "/api/v1/users/2", "/api/v1/users/missing", "/unknown/1", "/unknown/2"} {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+		assert.NoError(t, err)
+		assert.NotEqual(t, fiber.StatusInternalServerError, resp.StatusCode)
+	}
+
+	// requests rejected by a middleware before they are routed
+	req := httptest.NewRequest(fiber.MethodGet, "/api/v1/users/1", nil)
+	req.Header.Set("X-Reject", "true")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	rec := httptest.NewRecorder()
+	metrics.Handler(registry).ServeHTTP(rec, httptest.NewRequest(fiber.MethodGet, "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	assert.NoError(t, err)
+
+	scraped := string(body)
+	// requests are labelled by the route template, with the status code the error handler responded with
+	assert.Contains(t, scraped, `skillq_http_requests_total{method="GET",route="/api/v1/users/:id",status="200"} 2`)
+	assert.Contains(t, scraped, `skillq_http_requests_total{method="GET",route="/api/v1/users/:id",status="404"} 1`)
+	assert.Contains(t, scraped, `skillq_http_requests_total{method="GET",route="unmatched",status="404"} 2`)
+	assert.Contains(t, scraped, `skillq_http_requests_total{method="GET",route="unrouted",status="400"} 1`)
+	assert.False(t, strings.Contains(scraped, "/unknown/1"))
+	assert.False(t, strings.Contains(scraped, `route="/"`))
+}
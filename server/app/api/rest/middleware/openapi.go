@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	openapispec "github.com/BrianLusina/skillq/server/app/api/openapi-spec"
+	"github.com/BrianLusina/skillq/server/app/api/rest/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ValidateRequest returns a middleware that validates the parameters & JSON body of requests against the operation in the
+// OpenAPI document that handles them. Invalid requests are rejected listing every field that failed validation, requests
+// that are not described by the document are passed on untouched
+func ValidateRequest(doc *openapispec.Document) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := c.UserContext()
+
+		req, err := http.NewRequestWithContext(ctx, c.Method(), c.OriginalURL(), nil)
+		if err != nil {
+			return utils.WriteWithError(c, fiber.StatusBadRequest, err.Error())
+		}
+		// routes are not strict, so a trailing slash is matched to the operation without it
+		if len(req.URL.Path) > 1 {
+			req.URL.Path = strings.TrimSuffix(req.URL.Path, "/")
+		}
+		c.Request().Header.VisitAll(func(key, value []byte) {
+			req.Header.Add(string(key), string(value))
+		})
+
+		route, pathParams := doc.FindRoute(req)
+		if route == nil {
+			return c.Next()
+		}
+
+		fieldErrs, err := doc.ValidateRequest(ctx, req, route, pathParams, c.Body)
+		if err != nil {
+			if errors.Is(err, openapispec.ErrMalformedBody) {
+				return utils.WriteWithError(c, fiber.StatusBadRequest, utils.ErrMsgJSONDecode)
+			}
+			return err
+		}
+
+		if len(fieldErrs) > 0 {
+			return utils.WriteFieldErrs(c, fieldErrs)
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	openapispec "github.com/BrianLusina/skillq/server/app/api/openapi-spec"
+	"github.com/BrianLusina/skillq/server/app/api/rest/utils"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/utils/validators"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRequest(t *testing.T) {
+	doc, err := openapispec.Load()
+	assert.NoError(t, err)
+
+	log, _ := logger.NewTestLogger()
+	app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler(log)})
+	app.Use(ValidateRequest(doc))
+
+	handled := false
+	handler := func(c *fiber.Ctx) error {
+		handled = true
+		return c.SendStatus(fiber.StatusOK)
+	}
+	app.Post("/api/v1/auth/login", handler)
+	app.Get("/undocumented", handler)
+
+	testCases := []struct {
+		name            string
+		method          string
+		path            string
+		body            string
+		expectedStatus  int
+		expectedHandled bool
+		expectedErrors  []validators.FieldError
+	}{
+		{
+			name:            "passes valid requests on",
+			method:          fiber.MethodPost,
+			path:            "/api/v1/auth/login",
+			body:            `{"email": "jane@example.com", "password": "secret"}`,
+			expectedStatus:  fiber.StatusOK,
+			expectedHandled: true,
+		},
+		{
+			name:           "rejects invalid requests",
+			method:         fiber.MethodPost,
+			path:           "/api/v1/auth/login",
+			body:           `{"email": "not-an-email"}`,
+			expectedStatus: fiber.StatusUnprocessableEntity,
+			expectedErrors: []validators.FieldError{
+				{Field: "password", Message: "password is a required field"},
+				{Field: "email", Message: "email must be a valid email address"},
+			},
+		},
+		{
+			name:           "rejects malformed bodies",
+			method:         fiber.MethodPost,
+			path:           "/api/v1/auth/login",
+			body:           `{"email":`,
+			expectedStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:            "passes undocumented requests on",
+			method:          fiber.MethodGet,
+			path:            "/undocumented",
+			expectedStatus:  fiber.StatusOK,
+			expectedHandled: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handled = false
+
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tc.expectedHandled, handled)
+
+			if tc.expectedErrors != nil {
+				var problem utils.ProblemDetails
+				assert.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+				assert.ElementsMatch(t, tc.expectedErrors, problem.Errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/infra/ratelimit"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RateLimitKey returns the key a request is counted against, such as the IP address of the client. Requests for which it
+// returns a blank key are not counted against it
+type RateLimitKey func(c *fiber.Ctx) string
+
+// RateLimitConfig is the configuration of a rate limit
+type RateLimitConfig struct {
+	// Name identifies the rate limit, keeping the keys of different rate limits apart in the store
+	Name string
+
+	// Limit is the number of requests allowed per key in a window
+	Limit int
+
+	// Window is the duration of the window requests are counted in
+	Window time.Duration
+
+	// Keys are the keys every request is counted against. A request is rejected if it is over the limit of any of them
+	Keys []RateLimitKey
+}
+
+// ByIP counts requests against the IP address of the client. The app has to only trust the proxy header on requests sent
+// by its trusted proxies, otherwise clients can choose the address they are counted against
+func ByIP(c *fiber.Ctx) string {
+	return "ip:" + c.IP()
+}
+
+// ByParam counts requests against the value of the route parameter with the given name
+func ByParam(name string) RateLimitKey {
+	return func(c *fiber.Ctx) string {
+		value := c.Params(name)
+		if value == "" {
+			return ""
+		}
+		return name + ":" + value
+	}
+}
+
+// RateLimit returns a middleware that counts requests against each of the keys of the rate limit in the store. Requests that
+// are over the limit of any key are rejected with a too many requests error & a Retry-After header with the number of
+// seconds until the window of the key resets. Requests are rejected with an unavailable error if the store fails, so that
+// the limit cannot be bypassed while the store is down
+func RateLimit(store ratelimit.Store, config RateLimitConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var retryAfter time.Duration
+
+		for _, key := range config.Keys {
+			value := key(c)
+			if value == "" {
+				continue
+			}
+
+			result, err := store.Allow(c.UserContext(), fmt.Sprintf("%s:%s", config.Name, value), config.Limit, config.Window)
+			if err != nil {
+				return errdefs.NewUnavailableError("failed to check rate limit", err)
+			}
+
+			if !result.Allowed && result.RetryAfter > retryAfter {
+				retryAfter = result.RetryAfter
+			}
+		}
+
+		if retryAfter > 0 {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			return errdefs.NewTooManyRequestsError("too many requests, try again later", nil)
+		}
+
+		return c.Next()
+	}
+}
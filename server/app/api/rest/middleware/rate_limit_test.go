@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/app/api/rest/utils"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/ratelimit"
+	"github.com/BrianLusina/skillq/server/infra/ratelimit/memory"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingStore is a rate limit store that fails to count every action
+type failingStore struct{}
+
+func (failingStore) Allow(context.Context, string, int, time.Duration) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("store is down")
+}
+
+func TestRateLimit(t *testing.T) {
+	log, _ := logger.NewTestLogger()
+
+	// requests sent by app.Test come from 0.0.0.0, which is trusted as a proxy unless other proxies are given
+	newApp := func(store ratelimit.Store, trustedProxies ...string) *fiber.App {
+		if len(trustedProxies) == 0 {
+			trustedProxies = []string{"0.0.0.0"}
+		}
+
+		app := fiber.New(fiber.Config{
+			ErrorHandler:            utils.ErrorHandler(log),
+			ProxyHeader:             fiber.HeaderXForwardedFor,
+			EnableTrustedProxyCheck: true,
+			TrustedProxies:          trustedProxies,
+			EnableIPValidation:      true,
+		})
+		app.Post("/users/:id/resend", RateLimit(store, RateLimitConfig{
+			Name:   "resend",
+			Limit:  2,
+			Window: time.Hour,
+			Keys:   []RateLimitKey{ByIP, ByParam("id")},
+		}), func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusAccepted)
+		})
+		return app
+	}
+
+	send := func(app *fiber.App, ip, userID string) int {
+		req := httptest.NewRequest(fiber.MethodPost, "/users/"+userID+"/resend", nil)
+		req.Header.Set(fiber.HeaderXForwardedFor, ip)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		if resp.StatusCode == fiber.StatusTooManyRequests {
+			assert.Equal(t, "3600", resp.Header.Get(fiber.HeaderRetryAfter))
+		}
+		return resp.StatusCode
+	}
+
+	t.Run("limits requests per user", func(t *testing.T) {
+		app := newApp(memory.NewStore())
+
+		assert.Equal(t, fiber.StatusAccepted, send(app, "10.0.0.1", "user-1"))
+		assert.Equal(t, fiber.StatusAccepted, send(app, "10.0.0.2", "user-1"))
+		assert.Equal(t, fiber.StatusTooManyRequests, send(app, "10.0.0.3", "user-1"))
+		assert.Equal(t, fiber.StatusAccepted, send(app, "10.0.0.3", "user-2"))
+	})
+
+	t.Run("limits requests per IP address", func(t *testing.T) {
+		app := newApp(memory.NewStore())
+
+		assert.Equal(t, fiber.StatusAccepted, send(app, "10.0.0.1", "user-1"))
+		assert.Equal(t, fiber.StatusAccepted, send(app, "10.0.0.1", "user-2"))
+		assert.Equal(t, fiber.StatusTooManyRequests, send(app, "10.0.0.1", "user-3"))
+		assert.Equal(t, fiber.StatusAccepted, send(app, "10.0.0.2", "user-3"))
+	})
+
+	t.Run("ignores the proxy header of requests that are not sent by a trusted proxy", func(t *testing.T) {
+		app := newApp(memory.NewStore(), "10.1.0.0/16")
+
+		assert.Equal(t, fiber.StatusAccepted, send(app, "10.0.0.1", "user-1"))
+		assert.Equal(t, fiber.StatusAccepted, send(app, "10.0.0.2", "user-2"))
+		assert.Equal(t, fiber.StatusTooManyRequests, send(app, "10.0.0.3", "user-3"))
+	})
+
+	t.Run("rejects requests when the store fails", func(t *testing.T) {
+		app := newApp(failingStore{})
+
+		assert.Equal(t, fiber.StatusServiceUnavailable, send(app, "10.0.0.1", "user-1"))
+	})
+}
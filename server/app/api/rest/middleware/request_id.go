@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/requestid"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestID returns a middleware that identifies a request with the ID in its X-Request-ID header, or a new ID if the
+// header is missing or invalid, & echoes the ID back in the response. The ID & a logger that adds it to every line it
+// logs are added to the user context of the request, so that services & the tasks they publish log with the same ID
+func RequestID(log logger.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID := c.Get(requestid.Header)
+		if !requestid.IsValid(requestID) {
+			requestID = requestid.New()
+		}
+
+		c.Set(requestid.Header, requestID)
+
+		ctx := requestid.WithRequestID(c.UserContext(), requestID)
+		c.SetUserContext(logger.WithLogger(ctx, log.With("requestID", requestID)))
+
+		return c.Next()
+	}
+}
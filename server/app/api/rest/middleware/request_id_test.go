@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/requestid"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	newTestApp := func(t *testing.T, log logger.Logger) *fiber.App {
+		app := fiber.New()
+		app.Use(RequestID(log))
+		app.Get("/", func(c *fiber.Ctx) error {
+			requestID, ok := requestid.FromContext(c.UserContext())
+			assert.True(t, ok)
+
+			logger.FromContext(c.UserContext()).Info("handling request")
+			return c.SendString(requestID)
+		})
+
+		return app
+	}
+
+	t.Run("propagates the request ID of the client", func(t *testing.T) {
+		log, observed := logger.NewTestLogger()
+		app := newTestApp(t, log)
+
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		req.Header.Set(requestid.Header, "request-1")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, "request-1", resp.Header.Get(requestid.Header))
+		assert.Equal(t, "request-1", observed.All()[0].ContextMap()["requestID"])
+	})
+
+	t.Run("assigns a request ID when the client does not send one", func(t *testing.T) {
+		log, observed := logger.NewTestLogger()
+		app := newTestApp(t, log)
+
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		assert.NoError(t, err)
+
+		requestID := resp.Header.Get(requestid.Header)
+		assert.True(t, requestid.IsValid(requestID))
+		assert.Equal(t, requestID, observed.All()[0].ContextMap()["requestID"])
+	})
+
+	t.Run("replaces an invalid request ID", func(t *testing.T) {
+		log, _ := logger.NewTestLogger()
+		app := newTestApp(t, log)
+
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		req.Header.Set(requestid.Header, string(make([]byte, 200)))
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.True(t, requestid.IsValid(resp.Header.Get(requestid.Header)))
+	})
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the tracer of HTTP requests
+const tracerName = "github.com/BrianLusina/skillq/server/app/api/rest/middleware"
+
+// Tracing returns a middleware that handles every request in a server span created by the tracer provider. The span is
+// part of the trace of the client if the request carries its trace context & is added to the user context of the request,
+// so that the spans of services, mongo DB operations & published tasks are its children. It is named after the template
+// of the route the request matched & records the error the request failed with, so it has to be used before the Metrics
+// middleware, which responds to errors
+func Tracing(tracerProvider trace.TracerProvider) fiber.Handler {
+	tracer := tracerProvider.Tracer(tracerName)
+
+	return func(c *fiber.Ctx) error {
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), headerCarrier{c})
+
+		ctx, span := tracer.Start(ctx, c.Method(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Method()),
+				semconv.URLPath(c.Path()),
+			),
+		)
+		defer span.End()
+
+		c.SetUserContext(ctx)
+		middlewareRoute := c.Route()
+
+		err := c.Next()
+		// the Metrics middleware responds to the error, so it is only returned to this middleware if Metrics is not used
+		handledErr := err
+		if handledErr == nil {
+			handledErr, _ = c.Locals(localHandledError).(error)
+		}
+
+		route := routeTemplate(c, middlewareRoute, handledErr)
+		status := c.Response().StatusCode()
+
+		span.SetName(c.Method() + " " + route)
+		span.SetAttributes(semconv.HTTPRoute(route), semconv.HTTPResponseStatusCode(status))
+		if handledErr != nil {
+			span.RecordError(handledErr)
+		}
+		// only errors of the server fail the span, errors of the client are part of handling the request
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, "")
+		}
+
+		return err
+	}
+}
+
+// headerCarrier carries trace context in the headers of a request
+type headerCarrier struct {
+	c *fiber.Ctx
+}
+
+var _ propagation.TextMapCarrier = headerCarrier{}
+
+func (h headerCarrier) Get(key string) string {
+	return h.c.Get(key)
+}
+
+func (h headerCarrier) Set(key, value string) {
+	h.c.Request().Header.Set(key, value)
+}
+
+func (h headerCarrier) Keys() []string {
+	keys := []string{}
+	h.c.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
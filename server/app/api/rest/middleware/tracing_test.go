@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/BrianLusina/skillq/server/app/api/rest/utils"
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/metrics"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	defer otel.SetTextMapPropagator(otel.GetTextMapPropagator())
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	newTestApp := func() (*fiber.App, *tracetest.SpanRecorder) {
+		log, _ := logger.NewTestLogger()
+		recorder := tracetest.NewSpanRecorder()
+
+		app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler(log)})
+		app.Use(Tracing(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))))
+		app.Use(Metrics(metrics.NewHTTPMetrics(metrics.NewRegistry())))
+		app.Get("/api/v1/users/:id", func(c *fiber.Ctx) error {
+			switch c.Params("id") {
+			case "missing":
+				return errdefs.NewNotFoundError("user not found", nil)
+			case "broken":
+				return errdefs.NewUnavailableError("storage is down", nil)
+			}
+			return c.SendString(trace.SpanContextFromContext(c.UserContext()).TraceID().String())
+		})
+
+		return app, recorder
+	}
+
+	t.Run("continues the trace of the client in a span named after the route", func(t *testing.T) {
+		app, recorder := newTestApp()
+
+		req := httptest.NewRequest(fiber.MethodGet, "/api/v1/users/1", nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		spans := recorder.Ended()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, "GET /api/v1/users/:id", spans[0].Name())
+		assert.Equal(t, trace.SpanKindServer, spans[0].SpanKind())
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+		assert.Contains(t, spans[0].Attributes(), attribute.String("http.route", "/api/v1/users/:id"))
+		assert.Contains(t, spans[0].Attributes(), attribute.Int("http.response.status_code", fiber.StatusOK))
+		assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	})
+
+	t.Run("fails the span of a server error only & records the error the metrics middleware responded to", func(t *testing.T) {
+		app, recorder := newTestApp()
+
+		for _, path := range []string{"/api/v1/users/missing", "/api/v1/users/broken"} {
+			_, err := app.Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+			assert.NoError(t, err)
+		}
+
+		spans := recorder.Ended()
+		assert.Len(t, spans, 2)
+		assert.Contains(t, spans[0].Attributes(), attribute.Int("http.response.status_code", fiber.StatusNotFound))
+		assert.Equal(t, codes.Unset, spans[0].Status().Code)
+		assert.Contains(t, spans[1].Attributes(), attribute.Int("http.response.status_code", fiber.StatusServiceUnavailable))
+		assert.Equal(t, codes.Error, spans[1].Status().Code)
+		for _, span := range spans {
+			if assert.Len(t, span.Events(), 1) {
+				assert.Equal(t, "exception", span.Events()[0].Name)
+			}
+		}
+	})
+
+	t.Run("names the span of an unmatched request without its path", func(t *testing.T) {
+		app, recorder := newTestApp()
+
+		_, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/unknown/1", nil))
+		assert.NoError(t, err)
+
+		spans := recorder.Ended()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, "GET unmatched", spans[0].Name())
+	})
+}
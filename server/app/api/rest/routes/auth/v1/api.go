@@ -0,0 +1,23 @@
+package authv1
+
+import (
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/utils/validators"
+	"github.com/go-playground/validator/v10"
+)
+
+type AuthV1Api struct {
+	logger      logger.Logger
+	validator   *validator.Validate
+	authService inbound.AuthService
+}
+
+// NewAuthApi creates a new AuthV1Api structure
+func NewAuthApi(authService inbound.AuthService, log logger.Logger) AuthV1Api {
+	return AuthV1Api{
+		logger:      log,
+		validator:   validators.NewStructValidator(),
+		authService: authService,
+	}
+}
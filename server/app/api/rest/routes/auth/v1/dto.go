@@ -0,0 +1,34 @@
+package authv1
+
+import "time"
+
+// loginRequestDto is the DTO for a request to log in
+type loginRequestDto struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+// refreshTokenRequestDto is the DTO for a request that uses a refresh token
+type refreshTokenRequestDto struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+// forgotPasswordRequestDto is the DTO for a request to reset a forgotten password
+type forgotPasswordRequestDto struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// resetPasswordRequestDto is the DTO for a request to reset a password with a password reset token
+type resetPasswordRequestDto struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// tokenResponseDto is the DTO for a response with a pair of tokens
+type tokenResponseDto struct {
+	TokenType             string    `json:"tokenType"`
+	AccessToken           string    `json:"accessToken"`
+	AccessTokenExpiresAt  time.Time `json:"accessTokenExpiresAt"`
+	RefreshToken          string    `json:"refreshToken"`
+	RefreshTokenExpiresAt time.Time `json:"refreshTokenExpiresAt"`
+}
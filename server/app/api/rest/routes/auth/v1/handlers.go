@@ -0,0 +1,144 @@
+package authv1
+
+import (
+	"github.com/BrianLusina/skillq/server/app/api/rest/utils"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// HandleLogin logs in a user with their credentials, issuing an access token & a refresh token
+func (api *AuthV1Api) HandleLogin(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	log := logger.FromContextOr(ctx, api.logger)
+
+	payload := new(loginRequestDto)
+	if err := c.BodyParser(payload); err != nil {
+		log.Errorf("authapi/v1 login handler: failed to decode request: %v", err)
+		return utils.HandleDecodeErr(c, err)
+	}
+
+	if err := api.validator.Struct(payload); err != nil {
+		log.Errorf("authapi/v1 login handler: invalid request: %v", err)
+		return utils.WriteValidationErr(c, *payload, err)
+	}
+
+	tokens, err := api.authService.Login(ctx, inbound.LoginRequest{
+		Email:    payload.Email,
+		Password: payload.Password,
+	})
+	if err != nil {
+		log.Errorf("handler: failed to log in user: %v", err)
+		return err
+	}
+
+	return c.JSON(mapTokenResponseToDto(*tokens))
+}
+
+// HandleRefresh exchanges a refresh token for a new access token & refresh token
+func (api *AuthV1Api) HandleRefresh(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	log := logger.FromContextOr(ctx, api.logger)
+
+	payload := new(refreshTokenRequestDto)
+	if err := c.BodyParser(payload); err != nil {
+		log.Errorf("authapi/v1 refresh handler: failed to decode request: %v", err)
+		return utils.HandleDecodeErr(c, err)
+	}
+
+	if err := api.validator.Struct(payload); err != nil {
+		log.Errorf("authapi/v1 refresh handler: invalid request: %v", err)
+		return utils.WriteValidationErr(c, *payload, err)
+	}
+
+	tokens, err := api.authService.Refresh(ctx, payload.RefreshToken)
+	if err != nil {
+		log.Errorf("handler: failed to refresh tokens: %v", err)
+		return err
+	}
+
+	return c.JSON(mapTokenResponseToDto(*tokens))
+}
+
+// HandleLogout logs out a user by revoking their refresh token
+func (api *AuthV1Api) HandleLogout(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	log := logger.FromContextOr(ctx, api.logger)
+
+	payload := new(refreshTokenRequestDto)
+	if err := c.BodyParser(payload); err != nil {
+		log.Errorf("authapi/v1 logout handler: failed to decode request: %v", err)
+		return utils.HandleDecodeErr(c, err)
+	}
+
+	if err := api.validator.Struct(payload); err != nil {
+		log.Errorf("authapi/v1 logout handler: invalid request: %v", err)
+		return utils.WriteValidationErr(c, *payload, err)
+	}
+
+	err := api.authService.Logout(ctx, payload.RefreshToken)
+	if err != nil {
+		log.Errorf("handler: failed to log out user: %v", err)
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// HandleForgotPassword sends a password reset link to the user with the given email. The response is the same whether or not
+// the email belongs to a user
+func (api *AuthV1Api) HandleForgotPassword(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	log := logger.FromContextOr(ctx, api.logger)
+
+	payload := new(forgotPasswordRequestDto)
+	if err := c.BodyParser(payload); err != nil {
+		log.Errorf("authapi/v1 forgot password handler: failed to decode request: %v", err)
+		return utils.HandleDecodeErr(c, err)
+	}
+
+	if err := api.validator.Struct(payload); err != nil {
+		log.Errorf("authapi/v1 forgot password handler: invalid request: %v", err)
+		return utils.WriteValidationErr(c, *payload, err)
+	}
+
+	err := api.authService.ForgotPassword(ctx, payload.Email)
+	if err != nil {
+		log.Errorf("handler: failed to start password reset: %v", err)
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"Message": "If the email belongs to a user, a password reset link has been sent to it",
+	})
+}
+
+// HandleResetPassword sets a new password for a user with the password reset token sent to them
+func (api *AuthV1Api) HandleResetPassword(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	log := logger.FromContextOr(ctx, api.logger)
+
+	payload := new(resetPasswordRequestDto)
+	if err := c.BodyParser(payload); err != nil {
+		log.Errorf("authapi/v1 reset password handler: failed to decode request: %v", err)
+		return utils.HandleDecodeErr(c, err)
+	}
+
+	if err := api.validator.Struct(payload); err != nil {
+		log.Errorf("authapi/v1 reset password handler: invalid request: %v", err)
+		return utils.WriteValidationErr(c, *payload, err)
+	}
+
+	err := api.authService.ResetPassword(ctx, inbound.ResetPasswordRequest{
+		Token:    payload.Token,
+		Password: payload.Password,
+	})
+	if err != nil {
+		log.Errorf("handler: failed to reset password: %v", err)
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"Message": "Successfully reset password",
+	})
+}
@@ -0,0 +1,199 @@
+package authv1
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/app/api/rest/utils"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound"
+	mockusersvc "github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound/mocks"
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/utils/validators"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func newTestApp(t *testing.T) (*fiber.App, *mockusersvc.MockAuthService) {
+	mockCtrl := gomock.NewController(t)
+	mockAuthSvc := mockusersvc.NewMockAuthService(mockCtrl)
+
+	log, _ := logger.NewTestLogger()
+	app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler(log)})
+
+	api := NewAuthApi(mockAuthSvc, log)
+	api.RegisterHandlers(app)
+
+	return app, mockAuthSvc
+}
+
+func TestHandleLogin(t *testing.T) {
+	t.Run("reports invalid credentials fields", func(t *testing.T) {
+		app, mockAuthSvc := newTestApp(t)
+		mockAuthSvc.EXPECT().Login(gomock.Any(), gomock.Any()).Times(0)
+
+		req := httptest.NewRequest(fiber.MethodPost, "/api/v1/auth/login", strings.NewReader(`{"email": "not-an-email"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+
+		var problem utils.ProblemDetails
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+		assert.Equal(t, []validators.FieldError{
+			{Field: "email", Message: "email must be a valid email address"},
+			{Field: "password", Message: "password is a required field"},
+		}, problem.Errors)
+	})
+
+	t.Run("returns unauthorized for wrong credentials", func(t *testing.T) {
+		app, mockAuthSvc := newTestApp(t)
+		mockAuthSvc.EXPECT().Login(gomock.Any(), inbound.LoginRequest{Email: "jane@example.com", Password: "wrong"}).
+			Return(nil, errdefs.NewUnauthorizedError("invalid email or password", nil)).Times(1)
+
+		req := httptest.NewRequest(fiber.MethodPost, "/api/v1/auth/login", strings.NewReader(`{"email": "jane@example.com", "password": "wrong"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("returns the issued tokens", func(t *testing.T) {
+		app, mockAuthSvc := newTestApp(t)
+		expiresAt := time.Now().Add(time.Minute).UTC().Truncate(time.Second)
+		mockAuthSvc.EXPECT().Login(gomock.Any(), inbound.LoginRequest{Email: "jane@example.com", Password: "secret"}).
+			Return(&inbound.TokenResponse{
+				AccessToken:           "access-token",
+				AccessTokenExpiresAt:  expiresAt,
+				RefreshToken:          "refresh-token",
+				RefreshTokenExpiresAt: expiresAt,
+			}, nil).Times(1)
+
+		req := httptest.NewRequest(fiber.MethodPost, "/api/v1/auth/login", strings.NewReader(`{"email": "jane@example.com", "password": "secret"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var body tokenResponseDto
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, tokenResponseDto{
+			TokenType:             "Bearer",
+			AccessToken:           "access-token",
+			AccessTokenExpiresAt:  expiresAt,
+			RefreshToken:          "refresh-token",
+			RefreshTokenExpiresAt: expiresAt,
+		}, body)
+	})
+}
+
+func TestHandleRefresh(t *testing.T) {
+	app, mockAuthSvc := newTestApp(t)
+	mockAuthSvc.EXPECT().Refresh(gomock.Any(), "refresh-token").Return(&inbound.TokenResponse{
+		AccessToken:  "new-access-token",
+		RefreshToken: "new-refresh-token",
+	}, nil).Times(1)
+
+	req := httptest.NewRequest(fiber.MethodPost, "/api/v1/auth/refresh", strings.NewReader(`{"refreshToken": "refresh-token"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body tokenResponseDto
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "new-refresh-token", body.RefreshToken)
+}
+
+func TestHandleLogout(t *testing.T) {
+	app, mockAuthSvc := newTestApp(t)
+	mockAuthSvc.EXPECT().Logout(gomock.Any(), "refresh-token").Return(nil).Times(1)
+
+	req := httptest.NewRequest(fiber.MethodPost, "/api/v1/auth/logout", strings.NewReader(`{"refreshToken": "refresh-token"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+}
+
+func TestHandleForgotPassword(t *testing.T) {
+	t.Run("reports an invalid email", func(t *testing.T) {
+		app, mockAuthSvc := newTestApp(t)
+		mockAuthSvc.EXPECT().ForgotPassword(gomock.Any(), gomock.Any()).Times(0)
+
+		req := httptest.NewRequest(fiber.MethodPost, "/api/v1/auth/forgot-password", strings.NewReader(`{"email": "not-an-email"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+	})
+
+	t.Run("accepts the request", func(t *testing.T) {
+		app, mockAuthSvc := newTestApp(t)
+		mockAuthSvc.EXPECT().ForgotPassword(gomock.Any(), "jane@example.com").Return(nil).Times(1)
+
+		req := httptest.NewRequest(fiber.MethodPost, "/api/v1/auth/forgot-password", strings.NewReader(`{"email": "jane@example.com"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusAccepted, resp.StatusCode)
+	})
+}
+
+func TestHandleResetPassword(t *testing.T) {
+	t.Run("reports missing fields", func(t *testing.T) {
+		app, mockAuthSvc := newTestApp(t)
+		mockAuthSvc.EXPECT().ResetPassword(gomock.Any(), gomock.Any()).Times(0)
+
+		req := httptest.NewRequest(fiber.MethodPost, "/api/v1/auth/reset-password", strings.NewReader(`{}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+
+		var problem utils.ProblemDetails
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+		assert.Equal(t, []validators.FieldError{
+			{Field: "token", Message: "token is a required field"},
+			{Field: "password", Message: "password is a required field"},
+		}, problem.Errors)
+	})
+
+	t.Run("returns the error of an invalid token", func(t *testing.T) {
+		app, mockAuthSvc := newTestApp(t)
+		mockAuthSvc.EXPECT().ResetPassword(gomock.Any(), inbound.ResetPasswordRequest{Token: "reset-token", Password: "new-secret"}).
+			Return(errdefs.NewValidationError("invalid password reset token", nil)).Times(1)
+
+		req := httptest.NewRequest(fiber.MethodPost, "/api/v1/auth/reset-password", strings.NewReader(`{"token": "reset-token", "password": "new-secret"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+	})
+
+	t.Run("resets the password", func(t *testing.T) {
+		app, mockAuthSvc := newTestApp(t)
+		mockAuthSvc.EXPECT().ResetPassword(gomock.Any(), inbound.ResetPasswordRequest{Token: "reset-token", Password: "new-secret"}).
+			Return(nil).Times(1)
+
+		req := httptest.NewRequest(fiber.MethodPost, "/api/v1/auth/reset-password", strings.NewReader(`{"token": "reset-token", "password": "new-secret"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+}
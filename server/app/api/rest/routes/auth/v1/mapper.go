@@ -0,0 +1,17 @@
+package authv1
+
+import "github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound"
+
+// tokenTypeBearer is the type of the issued access tokens
+const tokenTypeBearer = "Bearer"
+
+// mapTokenResponseToDto maps a token response to a token response DTO
+func mapTokenResponseToDto(tokens inbound.TokenResponse) tokenResponseDto {
+	return tokenResponseDto{
+		TokenType:             tokenTypeBearer,
+		AccessToken:           tokens.AccessToken,
+		AccessTokenExpiresAt:  tokens.AccessTokenExpiresAt,
+		RefreshToken:          tokens.RefreshToken,
+		RefreshTokenExpiresAt: tokens.RefreshTokenExpiresAt,
+	}
+}
@@ -0,0 +1,25 @@
+package authv1
+
+import (
+	"testing"
+
+	openapispec "github.com/BrianLusina/skillq/server/app/api/openapi-spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenApiSpecDescribesDtos(t *testing.T) {
+	doc, err := openapispec.Load()
+	assert.NoError(t, err)
+
+	schemas := map[string]any{
+		"loginRequest":          loginRequestDto{},
+		"refreshTokenRequest":   refreshTokenRequestDto{},
+		"forgotPasswordRequest": forgotPasswordRequestDto{},
+		"resetPasswordRequest":  resetPasswordRequestDto{},
+		"tokenResponse":         tokenResponseDto{},
+	}
+
+	for schema, dto := range schemas {
+		assert.ElementsMatch(t, openapispec.JSONFieldNames(dto), doc.SchemaPropertyNames(schema), "schema %s does not match its DTO", schema)
+	}
+}
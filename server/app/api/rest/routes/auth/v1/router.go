@@ -0,0 +1,14 @@
+package authv1
+
+import "github.com/gofiber/fiber/v2"
+
+// RegisterHandlers registers all the handlers for the auth v1 endpoint
+func (api *AuthV1Api) RegisterHandlers(app *fiber.App) {
+	authApiGroup := app.Group("/api/v1/auth")
+
+	authApiGroup.Post("/login", api.HandleLogin)
+	authApiGroup.Post("/refresh", api.HandleRefresh)
+	authApiGroup.Post("/logout", api.HandleLogout)
+	authApiGroup.Post("/forgot-password", api.HandleForgotPassword)
+	authApiGroup.Post("/reset-password", api.HandleResetPassword)
+}
@@ -0,0 +1,20 @@
+package docs
+
+import openapispec "github.com/BrianLusina/skillq/server/app/api/openapi-spec"
+
+// DocsApi serves the OpenAPI document of the REST API & the documentation rendered from it
+type DocsApi struct {
+	spec []byte
+}
+
+// NewDocsApi creates a new DocsApi structure, encoding the OpenAPI document as JSON once
+func NewDocsApi() (DocsApi, error) {
+	spec, err := openapispec.JSON()
+	if err != nil {
+		return DocsApi{}, err
+	}
+
+	return DocsApi{
+		spec: spec,
+	}, nil
+}
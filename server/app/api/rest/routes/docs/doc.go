@@ -0,0 +1,2 @@
+// Package docs contains the routes serving the OpenAPI document of the REST API & its documentation
+package docs
@@ -0,0 +1,18 @@
+package docs
+
+import (
+	openapispec "github.com/BrianLusina/skillq/server/app/api/openapi-spec"
+	"github.com/gofiber/fiber/v2"
+)
+
+// HandleGetSpec serves the OpenAPI document
+func (api *DocsApi) HandleGetSpec(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+	return c.Send(api.spec)
+}
+
+// HandleGetDocs serves the API documentation
+func (api *DocsApi) HandleGetDocs(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.Send(openapispec.DocsHTML())
+}
@@ -0,0 +1,9 @@
+package docs
+
+import "github.com/gofiber/fiber/v2"
+
+// RegisterHandlers registers the handlers serving the OpenAPI document & the API documentation
+func (api *DocsApi) RegisterHandlers(app *fiber.App) {
+	app.Get("/api/openapi.json", api.HandleGetSpec)
+	app.Get("/api/docs", api.HandleGetDocs)
+}
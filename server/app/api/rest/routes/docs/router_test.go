@@ -0,0 +1,96 @@
+package docs
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	openapispec "github.com/BrianLusina/skillq/server/app/api/openapi-spec"
+	authv1 "github.com/BrianLusina/skillq/server/app/api/rest/routes/auth/v1"
+	metricsroutes "github.com/BrianLusina/skillq/server/app/api/rest/routes/metrics"
+	"github.com/BrianLusina/skillq/server/app/api/rest/routes/probes"
+	userv1 "github.com/BrianLusina/skillq/server/app/api/rest/routes/users/v1"
+	"github.com/BrianLusina/skillq/server/app/api/rest/utils"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestApp boots a fiber app with every route of the REST API registered
+func newTestApp(t *testing.T) *fiber.App {
+	log, _ := logger.NewTestLogger()
+	app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler(log)})
+
+	docsApi, err := NewDocsApi()
+	assert.NoError(t, err)
+	docsApi.RegisterHandlers(app)
+
+	authApi := authv1.NewAuthApi(nil, log)
+	authApi.RegisterHandlers(app)
+
+	probesApi := probes.NewProbesApi(log)
+	probesApi.RegisterHandlers(app)
+
+	metricsApi := metricsroutes.NewMetricsApi(http.NotFoundHandler())
+	metricsApi.RegisterHandlers(app)
+
+	userApi := userv1.NewUserApi(nil, nil, log)
+	userApi.RegisterHandlers(app, func(c *fiber.Ctx) error {
+		return c.Next()
+	}, func(c *fiber.Ctx) error {
+		return c.Next()
+	})
+
+	return app
+}
+
+func TestOpenApiSpecDescribesEveryRoute(t *testing.T) {
+	app := newTestApp(t)
+
+	doc, err := openapispec.Load()
+	assert.NoError(t, err)
+
+	registered := map[string]bool{}
+	for _, route := range app.GetRoutes(true) {
+		// fiber registers a HEAD route for every GET route
+		if route.Method == fiber.MethodHead {
+			continue
+		}
+
+		assert.NotNil(t, doc.Operation(route.Method, route.Path), "route %s %s is missing from the openapi spec", route.Method, route.Path)
+
+		registered[route.Method+" "+openapispec.TemplatePath(route.Path)] = true
+	}
+
+	for operation := range doc.Operations() {
+		assert.True(t, registered[operation], "operation %s in the openapi spec is not registered", operation)
+	}
+}
+
+func TestHandleGetSpec(t *testing.T) {
+	app := newTestApp(t)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/api/openapi.json", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, fiber.MIMEApplicationJSONCharsetUTF8, resp.Header.Get(fiber.HeaderContentType))
+
+	var spec map[string]any
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&spec))
+	assert.Equal(t, "3.1.0", spec["openapi"])
+}
+
+func TestHandleGetDocs(t *testing.T) {
+	app := newTestApp(t)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/api/docs", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, fiber.MIMETextHTMLCharsetUTF8, resp.Header.Get(fiber.HeaderContentType))
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), "openapi.json")
+}
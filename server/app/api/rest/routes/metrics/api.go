@@ -0,0 +1,15 @@
+package metrics
+
+import "net/http"
+
+// MetricsApi serves the metrics of the application
+type MetricsApi struct {
+	handler http.Handler
+}
+
+// NewMetricsApi creates a new MetricsApi structure with the handler that writes the metrics
+func NewMetricsApi(handler http.Handler) MetricsApi {
+	return MetricsApi{
+		handler: handler,
+	}
+}
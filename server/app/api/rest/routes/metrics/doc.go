@@ -0,0 +1,2 @@
+// Package metrics exposes the Prometheus metrics of the application for scraping
+package metrics
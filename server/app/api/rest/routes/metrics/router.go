@@ -0,0 +1,11 @@
+package metrics
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+)
+
+// RegisterHandlers registers the handler of the metrics endpoint
+func (api *MetricsApi) RegisterHandlers(app *fiber.App) {
+	app.Get("/metrics", adaptor.HTTPHandler(api.handler))
+}
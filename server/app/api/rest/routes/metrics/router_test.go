@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/metrics"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	registry := metrics.NewRegistry()
+	metrics.NewHTTPMetrics(registry).Observe(fiber.MethodGet, "/api/v1/users/:id", fiber.StatusOK, time.Millisecond)
+
+	app := fiber.New()
+	metricsApi := NewMetricsApi(metrics.Handler(registry))
+	metricsApi.RegisterHandlers(app)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/metrics", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.True(t, strings.HasPrefix(resp.Header.Get(fiber.HeaderContentType), "text/plain"))
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), `skillq_http_requests_total{method="GET",route="/api/v1/users/:id",status="200"} 1`)
+}
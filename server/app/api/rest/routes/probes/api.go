@@ -0,0 +1,20 @@
+package probes
+
+import (
+	"github.com/BrianLusina/skillq/server/infra/health"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+)
+
+// ProbesApi serves the liveness & readiness probes of the application
+type ProbesApi struct {
+	logger logger.Logger
+	checks []health.Check
+}
+
+// NewProbesApi creates a new ProbesApi structure with the checks of the dependencies the application needs to be ready
+func NewProbesApi(log logger.Logger, checks ...health.Check) ProbesApi {
+	return ProbesApi{
+		logger: log,
+		checks: checks,
+	}
+}
@@ -0,0 +1,3 @@
+// Package probes contains the routes an orchestrator probes to find out whether the application is live & ready to serve
+// requests
+package probes
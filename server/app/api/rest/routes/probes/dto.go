@@ -0,0 +1,27 @@
+package probes
+
+import "github.com/BrianLusina/skillq/server/infra/health"
+
+// checkStatusDto is the status of a check. The probes are not authenticated, so why a check failed is only logged
+type checkStatusDto struct {
+	Status health.Status `json:"status"`
+}
+
+// reportDto is the status of the application & of each dependency that was checked
+type reportDto struct {
+	Status health.Status             `json:"status"`
+	Checks map[string]checkStatusDto `json:"checks"`
+}
+
+// mapReportToDto maps a health report to its response, leaving out the duration & error of each check
+func mapReportToDto(report health.Report) reportDto {
+	checks := make(map[string]checkStatusDto, len(report.Checks))
+	for name, result := range report.Checks {
+		checks[name] = checkStatusDto{Status: result.Status}
+	}
+
+	return reportDto{
+		Status: report.Status,
+		Checks: checks,
+	}
+}
@@ -0,0 +1,36 @@
+package probes
+
+import (
+	"github.com/BrianLusina/skillq/server/infra/health"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// HandleLiveness reports that the process is up, without checking any dependency
+func (api *ProbesApi) HandleLiveness(c *fiber.Ctx) error {
+	return c.JSON(reportDto{
+		Status: health.StatusUp,
+		Checks: map[string]checkStatusDto{},
+	})
+}
+
+// HandleReadiness checks the dependencies of the application, responding with service unavailable if any of them is down.
+// Only the status of each check is responded with, the errors of the checks that are down are logged
+func (api *ProbesApi) HandleReadiness(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	log := logger.FromContextOr(ctx, api.logger)
+
+	report := health.Run(ctx, api.checks...)
+
+	if report.Status != health.StatusUp {
+		for name, result := range report.Checks {
+			if result.Status != health.StatusUp {
+				log.Errorf("probes readiness handler: check %s is down after %dms: %s", name, result.DurationMs, result.Error)
+			}
+		}
+
+		c.Status(fiber.StatusServiceUnavailable)
+	}
+
+	return c.JSON(mapReportToDto(report))
+}
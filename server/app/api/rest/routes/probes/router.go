@@ -0,0 +1,9 @@
+package probes
+
+import "github.com/gofiber/fiber/v2"
+
+// RegisterHandlers registers the handlers of the liveness & readiness probes
+func (api *ProbesApi) RegisterHandlers(app *fiber.App) {
+	app.Get("/healthz", api.HandleLiveness)
+	app.Get("/readyz", api.HandleReadiness)
+}
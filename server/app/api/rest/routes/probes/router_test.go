@@ -0,0 +1,90 @@
+package probes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/health"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newTestApp(checks ...health.Check) (*fiber.App, *observer.ObservedLogs) {
+	app := fiber.New()
+	log, logs := logger.NewTestLogger()
+	probesApi := NewProbesApi(log, checks...)
+	probesApi.RegisterHandlers(app)
+	return app, logs
+}
+
+func decodeReport(t *testing.T, app *fiber.App, path string) (int, health.Report) {
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	var report health.Report
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+
+	return resp.StatusCode, report
+}
+
+func TestProbes(t *testing.T) {
+	up := health.CheckerFunc(func(ctx context.Context) error {
+		return nil
+	})
+	down := health.CheckerFunc(func(ctx context.Context) error {
+		return errors.New("connection to rabbit is closed")
+	})
+
+	t.Run("liveness is up even if a dependency is down", func(t *testing.T) {
+		app, _ := newTestApp(health.Check{Name: "rabbitmq", Checker: down})
+
+		status, report := decodeReport(t, app, "/healthz")
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, health.StatusUp, report.Status)
+	})
+
+	t.Run("readiness is up when every dependency is up", func(t *testing.T) {
+		app, _ := newTestApp(health.Check{Name: "mongodb", Checker: up}, health.Check{Name: "rabbitmq", Checker: up})
+
+		status, report := decodeReport(t, app, "/readyz")
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, health.StatusUp, report.Status)
+		assert.Equal(t, health.StatusUp, report.Checks["mongodb"].Status)
+		assert.Equal(t, health.StatusUp, report.Checks["rabbitmq"].Status)
+	})
+
+	t.Run("readiness is unavailable with only the status of a dependency that is down & logs its error", func(t *testing.T) {
+		app, logs := newTestApp(health.Check{Name: "mongodb", Checker: up}, health.Check{Name: "rabbitmq", Checker: down})
+
+		status, report := decodeReport(t, app, "/readyz")
+		assert.Equal(t, fiber.StatusServiceUnavailable, status)
+		assert.Equal(t, health.StatusDown, report.Status)
+		assert.Equal(t, health.StatusUp, report.Checks["mongodb"].Status)
+		assert.Equal(t, health.StatusDown, report.Checks["rabbitmq"].Status)
+		assert.Empty(t, report.Checks["rabbitmq"].Error)
+
+		errorLogs := logs.FilterMessageSnippet("connection to rabbit is closed").All()
+		if assert.Len(t, errorLogs, 1) {
+			assert.Contains(t, errorLogs[0].Message, "rabbitmq")
+		}
+	})
+
+	t.Run("readiness is unavailable when a dependency does not respond in time", func(t *testing.T) {
+		slow := health.CheckerFunc(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		app, _ := newTestApp(health.Check{Name: "storage", Checker: slow, Timeout: 20 * time.Millisecond})
+
+		status, report := decodeReport(t, app, "/readyz")
+		assert.Equal(t, fiber.StatusServiceUnavailable, status)
+		assert.Equal(t, health.StatusDown, report.Checks["storage"].Status)
+	})
+}
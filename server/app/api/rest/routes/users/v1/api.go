@@ -3,18 +3,27 @@ package userv1
 import (
 	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound"
 	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/utils/validators"
+	"github.com/go-playground/validator/v10"
 )
 
 type UserV1Api struct {
 	logger                  logger.Logger
+	validator               *validator.Validate
 	userService             inbound.UserService
 	userVerificationService inbound.UserVerificationService
 }
 
-// NewUserApi creates a new UserV1Api structure
-func NewUserApi(userService inbound.UserService, userVerificationService inbound.UserVerificationService, log logger.Logger) UserV1Api {
+// NewUserApi creates a new UserV1Api structure. The user service is expected to authorize the operations of the
+// authenticated user in the context of a request
+func NewUserApi(
+	userService inbound.UserService,
+	userVerificationService inbound.UserVerificationService,
+	log logger.Logger,
+) UserV1Api {
 	return UserV1Api{
 		logger:                  log,
+		validator:               validators.NewStructValidator(),
 		userService:             userService,
 		userVerificationService: userVerificationService,
 	}
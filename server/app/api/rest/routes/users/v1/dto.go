@@ -4,37 +4,68 @@ import "time"
 
 // userResponseDto is the DTO for a response on a user request
 type userResponseDto struct {
-	UUID      string     `json:"uuid"`
-	XID       string     `json:"xid"`
-	KeyID     string     `json:"keyId"`
-	CreatedAt time.Time  `json:"createdAt"`
-	UpdatedAt time.Time  `json:"updatedAt"`
-	DeletedAt *time.Time `json:"deletedAt,omitempty"`
-	Name      string     `json:"name"`
-	Email     string     `json:"email"`
-	JobTitle  string     `json:"jobTitle"`
-	Skills    []string   `json:"skills"`
-	ImageUrl  string     `json:"imageUrl"`
+	UUID          string     `json:"uuid"`
+	XID           string     `json:"xid"`
+	KeyID         string     `json:"keyId"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+	DeletedAt     *time.Time `json:"deletedAt,omitempty"`
+	Name          string     `json:"name"`
+	Email         string     `json:"email"`
+	EmailVerified bool       `json:"emailVerified"`
+	PendingEmail  string     `json:"pendingEmail,omitempty"`
+	JobTitle      string     `json:"jobTitle"`
+	Skills        []string   `json:"skills"`
+	ImageUrl      string     `json:"imageUrl"`
+	Role          string     `json:"role"`
+}
+
+// pageDto is the DTO for the pagination details of a paginated response
+type pageDto struct {
+	// Next is the opaque cursor to the next page, null if this is the last page
+	Next *string `json:"next"`
+
+	// Prev is the opaque cursor to the previous page, null if this is the first page
+	Prev *string `json:"prev"`
+
+	// Limit is the page size
+	Limit int `json:"limit"`
+
+	// Total is the total number of records, only set when requested
+	Total *int64 `json:"total,omitempty"`
+}
+
+// paginatedResponseDto is the DTO for a page retrieved with cursor pagination. Pages retrieved with offset pagination are
+// returned as a bare array of records to keep the shape existing clients parse
+type paginatedResponseDto[T any] struct {
+	Data []T     `json:"data"`
+	Page pageDto `json:"page"`
 }
 
 // userRequestDto is the DTO for a user request
 type userRequestDto struct {
-	Name     string       `json:"name" binding:"required" validate:"required,min=2,max=24"`
-	Email    string       `json:"email" binding:"required" validate:"email,required"`
-	Password string       `json:"password" binding:"required" validate:"required"`
-	Skills   []string     `json:"skills" binding:"required"`
-	Image    userImageDto `json:"image"`
-	JobTitle string       `json:"jobTitle" binding:"required"`
+	Name     string   `json:"name" validate:"required,min=2,max=24"`
+	Email    string   `json:"email" validate:"required,email"`
+	Password string   `json:"password" validate:"required"`
+	Skills   []string `json:"skills" validate:"required,min=1,dive,required"`
+	JobTitle string   `json:"jobTitle" validate:"required"`
+}
+
+// userRoleRequestDto is the DTO for a request to assign a role to a user
+type userRoleRequestDto struct {
+	Role string `json:"role" validate:"required,oneof=admin manager member"`
 }
 
-// userImageDto is the DTO for a user image
-type userImageDto struct {
-	ImageType string `json:"type" validate:"required"`
-	Content   string `json:"content" validate:"required"`
+// userUpdateRequestDto is the DTO for a partial user update request. Fields that are not sent are left unchanged
+type userUpdateRequestDto struct {
+	Name     *string   `json:"name" validate:"omitempty,min=2,max=24"`
+	Email    *string   `json:"email" validate:"omitempty,email"`
+	Skills   *[]string `json:"skills" validate:"omitempty,dive,required"`
+	JobTitle *string   `json:"jobTitle" validate:"omitempty,min=1"`
 }
 
 // verifyEmailDto defines the structure for verifying a user's email.
 type verifyEmailDto struct {
 	Code   string `json:"code" validate:"required,min=4,max=24"`
-	UserID string `json:"userId" validate:"omitempty,required"`
+	UserID string `json:"userId" validate:"required,uuid"`
 }
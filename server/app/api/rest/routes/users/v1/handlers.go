@@ -2,40 +2,45 @@ package userv1
 
 import (
 	"fmt"
+	"strconv"
 
+	"github.com/BrianLusina/skillq/server/infra/logger"
+
+	"github.com/BrianLusina/skillq/server/app/api/rest/utils"
 	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound"
 	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound/common"
-	"github.com/BrianLusina/skillq/server/utils/tools"
 	"github.com/gofiber/fiber/v2"
 )
 
+// headerTotalCount is the header the total number of users is written in when offset pagination is used
+const headerTotalCount = "X-Total-Count"
+
 // HandleCreateUser create a user
 func (api *UserV1Api) HandleCreateUser(c *fiber.Ctx) error {
-	ctx := c.Context()
+	ctx := c.UserContext()
+	log := logger.FromContextOr(ctx, api.logger)
 
 	payload := new(userRequestDto)
 	if err := c.BodyParser(payload); err != nil {
-		api.logger.Errorf("userapi/v1 handler: failed to decode request: %v", err)
-		// TODO: format error to DTO
-		return err
+		log.Errorf("userapi/v1 handler: failed to decode request: %v", err)
+		return utils.HandleDecodeErr(c, err)
+	}
+
+	if err := api.validator.Struct(payload); err != nil {
+		log.Errorf("userapi/v1 handler: invalid request: %v", err)
+		return utils.WriteValidationErr(c, *payload, err)
 	}
 
 	userRequest := inbound.UserRequest{
 		Name:     payload.Name,
 		Email:    payload.Email,
 		Password: payload.Password,
-		Image: inbound.UserImageRequest{
-			Type:    payload.Image.ImageType,
-			Content: payload.Image.Content,
-		},
 		Skills:   payload.Skills,
 		JobTitle: payload.JobTitle,
 	}
 	user, err := api.userService.CreateUser(ctx, userRequest)
 	if err != nil {
-		// TODO: handle different types of error
-		api.logger.Errorf("handler: failed to create user: %v", err)
-		// utils.WriteWithError(w, http.StatusInternalServerError, "failed to create user")
+		log.Errorf("handler: failed to create user: %v", err)
 		return err
 	}
 
@@ -46,14 +51,13 @@ func (api *UserV1Api) HandleCreateUser(c *fiber.Ctx) error {
 
 // HandleGetUserById gets a user by an ID
 func (api *UserV1Api) HandleGetUserById(c *fiber.Ctx) error {
-	ctx := c.Context()
+	ctx := c.UserContext()
+	log := logger.FromContextOr(ctx, api.logger)
 	userId := c.Params("id")
 
 	user, err := api.userService.GetUserByUUID(ctx, userId)
 	if err != nil {
-		// TODO: handle different types of error
-		api.logger.Errorf("handler: failed to fetch user: %v", err)
-		// utils.WriteWithError(w, http.StatusInternalServerError, "failed to create user")
+		log.Errorf("handler: failed to fetch user: %v", err)
 		return err
 	}
 
@@ -62,79 +66,116 @@ func (api *UserV1Api) HandleGetUserById(c *fiber.Ctx) error {
 	return c.JSON(response)
 }
 
-// HandleGetAllUsers gets all users
+// HandleGetAllUsers gets a page of all users. Offset pagination is used unless a cursor is provided
 func (api *UserV1Api) HandleGetAllUsers(c *fiber.Ctx) error {
-	ctx := c.Context()
-	order := c.Query("order", string(common.CREATED_AT))
-	sort := c.Query("sortby", string(common.DESC))
-	limit := c.QueryInt("limit", 100)
-	offset := c.QueryInt("offset", 0)
-
-	params := common.NewRequestParams(
-		common.WithRequestLimit(limit),
-		common.WithOffset(offset),
-		common.WithOrderBy(common.OrderBy(order)),
-		common.WithSortOrder(common.SortOrder(sort)),
-	)
+	ctx := c.UserContext()
+	log := logger.FromContextOr(ctx, api.logger)
 
-	users, err := api.userService.GetAllUsers(ctx, params)
+	params, err := parseRequestParams(c)
 	if err != nil {
-		// TODO: handle different types of error
-		api.logger.Errorf("handler: failed to fetch users: %v", err)
-		// utils.WriteWithError(w, http.StatusInternalServerError, "failed to create user")
+		log.Errorf("handler: invalid pagination parameters: %v", err)
 		return err
 	}
 
-	response := tools.Map(users, func(u inbound.UserResponse, _ int) userResponseDto {
-		return mapUserToUserResponse(u)
-	})
+	users, err := api.userService.GetAllUsers(ctx, params)
+	if err != nil {
+		log.Errorf("handler: failed to fetch users: %v", err)
+		return err
+	}
 
-	return c.JSON(response)
+	return writeUserPage(c, users, params)
 }
 
-// HandleGetAllUsersBySkill gets all users with a given skill
+// HandleGetAllUsersBySkill gets a page of all users with a given skill. Offset pagination is used unless a cursor is provided
 func (api *UserV1Api) HandleGetAllUsersBySkill(c *fiber.Ctx) error {
-	ctx := c.Context()
+	ctx := c.UserContext()
+	log := logger.FromContextOr(ctx, api.logger)
 
 	skill := c.Params("skill")
-	order := c.Query("order", string(common.CREATED_AT))
-	sort := c.Query("sortby", string(common.DESC))
-	limit := c.QueryInt("limit", 100)
-	offset := c.QueryInt("offset", 0)
-
-	params := common.NewRequestParams(
-		common.WithRequestLimit(limit),
-		common.WithOffset(offset),
-		common.WithOrderBy(common.OrderBy(order)),
-		common.WithSortOrder(common.SortOrder(sort)),
-	)
+
+	params, err := parseRequestParams(c)
+	if err != nil {
+		log.Errorf("handler: invalid pagination parameters: %v", err)
+		return err
+	}
 
 	users, err := api.userService.GetAllUsersBySkill(ctx, skill, params)
 	if err != nil {
-		// TODO: handle different types of error
-		api.logger.Errorf("handler: failed to fetch users by skill %s, err: %v", skill, err)
-		// utils.WriteWithError(w, http.StatusInternalServerError, "failed to create user")
+		log.Errorf("handler: failed to fetch users by skill %s, err: %v", skill, err)
 		return err
 	}
 
-	response := tools.Map(users, func(u inbound.UserResponse, _ int) userResponseDto {
-		return mapUserToUserResponse(u)
-	})
+	return writeUserPage(c, users, params)
+}
+
+// HandleAssignUserRole assigns a role to a user, which only admins are allowed to do
+func (api *UserV1Api) HandleAssignUserRole(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	log := logger.FromContextOr(ctx, api.logger)
+
+	userId := c.Params("id")
+
+	payload := new(userRoleRequestDto)
+	if err := c.BodyParser(payload); err != nil {
+		log.Errorf("userapi/v1 assign role handler: failed to decode request: %v", err)
+		return utils.HandleDecodeErr(c, err)
+	}
+
+	if err := api.validator.Struct(payload); err != nil {
+		log.Errorf("userapi/v1 assign role handler: invalid request: %v", err)
+		return utils.WriteValidationErr(c, *payload, err)
+	}
+
+	user, err := api.userService.AssignUserRole(ctx, userId, payload.Role)
+	if err != nil {
+		log.Errorf("handler: failed to assign role %s to user with ID %s, err: %v", payload.Role, userId, err)
+		return err
+	}
+
+	response := mapUserToUserResponse(*user)
+
+	return c.JSON(response)
+}
+
+// HandleUpdateUser partially updates a user, only changing the fields that are sent in the request
+func (api *UserV1Api) HandleUpdateUser(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	log := logger.FromContextOr(ctx, api.logger)
+
+	userId := c.Params("id")
+
+	payload := new(userUpdateRequestDto)
+	if err := c.BodyParser(payload); err != nil {
+		log.Errorf("userapi/v1 update handler: failed to decode request: %v", err)
+		return utils.HandleDecodeErr(c, err)
+	}
+
+	if err := api.validator.Struct(payload); err != nil {
+		log.Errorf("userapi/v1 update handler: invalid request: %v", err)
+		return utils.WriteValidationErr(c, *payload, err)
+	}
+
+	user, err := api.userService.UpdateUser(ctx, userId, mapUserUpdateRequestDtoToRequest(*payload))
+	if err != nil {
+		log.Errorf("handler: failed to update user with ID %s, err: %v", userId, err)
+		return err
+	}
+
+	response := mapUserToUserResponse(*user)
 
 	return c.JSON(response)
 }
 
-// HandleGetAllUsersBySkill gets all users with a given skill
+// HandleDeleteUser deletes a user given their ID
 func (api *UserV1Api) HandleDeleteUser(c *fiber.Ctx) error {
-	ctx := c.Context()
+	ctx := c.UserContext()
+	log := logger.FromContextOr(ctx, api.logger)
 
 	userId := c.Params("id")
 
 	err := api.userService.DeleteUser(ctx, userId)
 	if err != nil {
-		// TODO: handle different types of error
-		api.logger.Errorf("handler: failed to delete user with ID %s, err: %v", userId, err)
-		// utils.WriteWithError(w, http.StatusInternalServerError, "failed to create user")
+		log.Errorf("handler: failed to delete user with ID %s, err: %v", userId, err)
 		return err
 	}
 
@@ -145,12 +186,18 @@ func (api *UserV1Api) HandleDeleteUser(c *fiber.Ctx) error {
 
 // HandleVerifyUserEmail create a user
 func (api *UserV1Api) HandleVerifyUserEmail(c *fiber.Ctx) error {
-	ctx := c.Context()
+	ctx := c.UserContext()
+	log := logger.FromContextOr(ctx, api.logger)
 
 	payload := new(verifyEmailDto)
 	if err := c.BodyParser(payload); err != nil {
-		api.logger.Errorf("userapi/v1 verify email handler: failed to decode request: %v", err)
-		return err
+		log.Errorf("userapi/v1 verify email handler: failed to decode request: %v", err)
+		return utils.HandleDecodeErr(c, err)
+	}
+
+	if err := api.validator.Struct(payload); err != nil {
+		log.Errorf("userapi/v1 verify email handler: invalid request: %v", err)
+		return utils.WriteValidationErr(c, *payload, err)
 	}
 
 	userVerificationRequest := inbound.VerifyEmailRequest{
@@ -160,9 +207,7 @@ func (api *UserV1Api) HandleVerifyUserEmail(c *fiber.Ctx) error {
 
 	err := api.userVerificationService.VerifyEmail(ctx, userVerificationRequest)
 	if err != nil {
-		// TODO: handle different types of error
-		api.logger.Errorf("handler: failed to verify user email: %v", err)
-
+		log.Errorf("handler: failed to verify user email: %v", err)
 		return err
 	}
 
@@ -170,3 +215,59 @@ func (api *UserV1Api) HandleVerifyUserEmail(c *fiber.Ctx) error {
 		"Message": "Successfully verified user email",
 	})
 }
+
+// HandleResendUserEmailVerification invalidates the verification codes previously sent to a user & sends them a new one
+func (api *UserV1Api) HandleResendUserEmailVerification(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	log := logger.FromContextOr(ctx, api.logger)
+	userID := c.Params("id")
+
+	if err := api.userVerificationService.ResendEmailVerification(ctx, userID); err != nil {
+		log.Errorf("handler: failed to resend user email verification: %v", err)
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"Message": "Email verification sent",
+	})
+}
+
+// HandleUploadUserImage streams the image uploaded in the image field of a multipart/form-data request to storage &
+// updates the user's image url
+func (api *UserV1Api) HandleUploadUserImage(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	log := logger.FromContextOr(ctx, api.logger)
+
+	userId := c.Params("id")
+
+	image, err := formFileReader(c, userImageFormField)
+	if err != nil {
+		log.Errorf("userapi/v1 upload image handler: failed to read image: %v", err)
+		return err
+	}
+
+	user, err := api.userService.UpdateUserImage(ctx, userId, inbound.UserImageUpload{Content: image})
+	if err != nil {
+		log.Errorf("handler: failed to upload image of user with ID %s, err: %v", userId, err)
+		return err
+	}
+
+	response := mapUserToUserResponse(*user)
+
+	return c.JSON(response)
+}
+
+// writeUserPage writes a page of users to the response. Pages retrieved with cursor pagination are written in a paginated
+// envelope, while pages retrieved with offset pagination are written as a bare array with the total, if it was requested,
+// in the X-Total-Count header
+func writeUserPage(c *fiber.Ctx, users common.Page[inbound.UserResponse], params common.RequestParams) error {
+	if params.Cursor != nil {
+		return c.JSON(mapUserPageToPaginatedResponse(users))
+	}
+
+	if users.Total != nil {
+		c.Set(headerTotalCount, strconv.FormatInt(*users.Total, 10))
+	}
+
+	return c.JSON(mapUsersToUserResponses(users.Items))
+}
@@ -0,0 +1,491 @@
+package userv1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/app/api/rest/middleware"
+	"github.com/BrianLusina/skillq/server/app/api/rest/utils"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/auth"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound/common"
+	mockusersvc "github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound/mocks"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/services/usersvc"
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/domain/id"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/ratelimit/memory"
+	"github.com/BrianLusina/skillq/server/utils/validators"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func newTestApp(t *testing.T) (*fiber.App, *mockusersvc.MockUserService) {
+	mockCtrl := gomock.NewController(t)
+	mockUserSvc := mockusersvc.NewMockUserService(mockCtrl)
+
+	log, _ := logger.NewTestLogger()
+	app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler(log)})
+
+	api := NewUserApi(mockUserSvc, nil, log)
+	api.RegisterHandlers(app, func(c *fiber.Ctx) error {
+		return c.Next()
+	}, func(c *fiber.Ctx) error {
+		return c.Next()
+	})
+
+	return app, mockUserSvc
+}
+
+func TestRegisterHandlersAuthentication(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockUserSvc := mockusersvc.NewMockUserService(mockCtrl)
+	mockAuthSvc := mockusersvc.NewMockAuthService(mockCtrl)
+	mockUserAuthorizer := mockusersvc.NewMockUserAuthorizer(mockCtrl)
+	mockUserVerificationSvc := mockusersvc.NewMockUserVerificationService(mockCtrl)
+
+	log, _ := logger.NewTestLogger()
+	app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler(log)})
+
+	api := NewUserApi(usersvc.NewAuthorized(mockUserSvc, mockUserAuthorizer), mockUserVerificationSvc, log)
+	api.RegisterHandlers(app, middleware.Authenticate(mockAuthSvc), middleware.RateLimit(memory.NewStore(), middleware.RateLimitConfig{
+		Name:   "resend-verification",
+		Limit:  1,
+		Window: time.Hour,
+		Keys:   []middleware.RateLimitKey{middleware.ByParam("id")},
+	}))
+
+	t.Run("rejects unauthenticated requests to delete a user", func(t *testing.T) {
+		mockUserSvc.EXPECT().DeleteUser(gomock.Any(), gomock.Any()).Times(0)
+
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodDelete, "/api/v1/users/123", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("rejects unauthenticated requests to upload a user image", func(t *testing.T) {
+		mockUserSvc.EXPECT().UpdateUserImage(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/api/v1/users/123/image", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("passes the authenticated user to the service", func(t *testing.T) {
+		mockAuthSvc.EXPECT().Authenticate(gomock.Any(), "access-token").Return("user-id", nil).Times(1)
+		mockUserAuthorizer.EXPECT().Authorize(gomock.Any(), inbound.UserActionDelete, "123").Return(nil).Times(1)
+		mockUserSvc.EXPECT().DeleteUser(gomock.Any(), "123").DoAndReturn(func(ctx context.Context, _ string) error {
+			userID, ok := auth.UserIDFromContext(ctx)
+			assert.True(t, ok)
+			assert.Equal(t, "user-id", userID)
+			return nil
+		}).Times(1)
+
+		req := httptest.NewRequest(fiber.MethodDelete, "/api/v1/users/123", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer access-token")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("rejects unauthenticated requests to read a user", func(t *testing.T) {
+		mockUserSvc.EXPECT().GetUserByUUID(gomock.Any(), gomock.Any()).Times(0)
+
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/api/v1/users/123", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("leaves listing users public", func(t *testing.T) {
+		mockUserSvc.EXPECT().GetAllUsers(gomock.Any(), gomock.Any()).Return(common.Page[inbound.UserResponse]{Limit: 100}, nil).Times(1)
+		mockUserSvc.EXPECT().GetAllUsersBySkill(gomock.Any(), "go", gomock.Any()).Return(common.Page[inbound.UserResponse]{Limit: 100}, nil).Times(1)
+
+		for _, path := range []string{"/api/v1/users/", "/api/v1/users/skill/go"} {
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode, path)
+		}
+	})
+
+	t.Run("rejects assigning a role when the authenticated user is not an admin", func(t *testing.T) {
+		mockAuthSvc.EXPECT().Authenticate(gomock.Any(), "access-token").Return("user-id", nil).Times(1)
+		mockUserAuthorizer.EXPECT().Authorize(gomock.Any(), inbound.UserActionAssignRole, "123").
+			Return(errdefs.NewForbiddenError("not allowed to perform this action", nil)).Times(1)
+		mockUserSvc.EXPECT().AssignUserRole(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		req := httptest.NewRequest(fiber.MethodPut, "/api/v1/users/123/role", strings.NewReader(`{"role": "admin"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer access-token")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("assigns a role when the authenticated user is an admin", func(t *testing.T) {
+		mockAuthSvc.EXPECT().Authenticate(gomock.Any(), "access-token").Return("admin-id", nil).Times(1)
+		mockUserAuthorizer.EXPECT().Authorize(gomock.Any(), inbound.UserActionAssignRole, "123").Return(nil).Times(1)
+		mockUserSvc.EXPECT().AssignUserRole(gomock.Any(), "123", "manager").Return(&inbound.UserResponse{UUID: "123", Role: "manager"}, nil).Times(1)
+
+		req := httptest.NewRequest(fiber.MethodPut, "/api/v1/users/123/role", strings.NewReader(`{"role": "manager"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer access-token")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var body userResponseDto
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, "manager", body.Role)
+	})
+
+	t.Run("rejects requests the authenticated user is not allowed to make", func(t *testing.T) {
+		mockAuthSvc.EXPECT().Authenticate(gomock.Any(), "access-token").Return("user-id", nil).Times(1)
+		mockUserAuthorizer.EXPECT().Authorize(gomock.Any(), inbound.UserActionUpdate, "123").
+			Return(errdefs.NewForbiddenError("not allowed to perform this action", nil)).Times(1)
+		mockUserSvc.EXPECT().UpdateUser(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		req := httptest.NewRequest(fiber.MethodPatch, "/api/v1/users/123", strings.NewReader(`{"name": "Jane"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer access-token")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("leaves registering users public", func(t *testing.T) {
+		mockUserSvc.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(&inbound.UserResponse{UUID: "123"}, nil).Times(1)
+
+		body := `{"name": "Jane", "email": "jane@example.com", "password": "secret", "skills": ["go"], "jobTitle": "Engineer"}`
+		req := httptest.NewRequest(fiber.MethodPost, "/api/v1/users/", strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("leaves resending an email verification public but throttled", func(t *testing.T) {
+		mockUserVerificationSvc.EXPECT().ResendEmailVerification(gomock.Any(), "123").Return(nil).Times(1)
+
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/api/v1/users/123/verification/resend", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusAccepted, resp.StatusCode)
+
+		resp, err = app.Test(httptest.NewRequest(fiber.MethodPost, "/api/v1/users/123/verification/resend", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+		assert.NotEmpty(t, resp.Header.Get(fiber.HeaderRetryAfter))
+	})
+}
+
+func TestHandleCreateUserValidation(t *testing.T) {
+	testCases := []struct {
+		name           string
+		body           string
+		expectedErrors []validators.FieldError
+	}{
+		{
+			name: "reports every invalid field by its JSON name",
+			body: `{"name": "", "email": "not-an-email", "password": "", "skills": []}`,
+			expectedErrors: []validators.FieldError{
+				{Field: "name", Message: "name is a required field"},
+				{Field: "email", Message: "email must be a valid email address"},
+				{Field: "password", Message: "password is a required field"},
+				{Field: "skills", Message: "skills must contain at least 1 items"},
+				{Field: "jobTitle", Message: "jobTitle is a required field"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app, mockUserSvc := newTestApp(t)
+			mockUserSvc.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(0)
+
+			req := httptest.NewRequest(fiber.MethodPost, "/api/v1/users/", strings.NewReader(tc.body))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+
+			var problem utils.ProblemDetails
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+			assert.Equal(t, tc.expectedErrors, problem.Errors)
+			assert.Equal(t, tc.expectedErrors[0].Message, problem.Detail)
+		})
+	}
+}
+
+func TestHandleCreateUserValidRequest(t *testing.T) {
+	app, mockUserSvc := newTestApp(t)
+
+	mockUserSvc.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(&inbound.UserResponse{
+		Name:  "Jane",
+		Email: "jane@example.com",
+	}, nil).Times(1)
+
+	body := `{"name": "Jane", "email": "jane@example.com", "password": "secret", "skills": ["go"], "jobTitle": "Engineer"}`
+	req := httptest.NewRequest(fiber.MethodPost, "/api/v1/users/", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+func TestHandleUpdateUserValidation(t *testing.T) {
+	app, mockUserSvc := newTestApp(t)
+	mockUserSvc.EXPECT().UpdateUser(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	body := `{"name": "J", "email": "not-an-email"}`
+	req := httptest.NewRequest(fiber.MethodPatch, "/api/v1/users/123", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+
+	var problem utils.ProblemDetails
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+	assert.Equal(t, []validators.FieldError{
+		{Field: "name", Message: "name must be at least 2 characters long"},
+		{Field: "email", Message: "email must be a valid email address"},
+	}, problem.Errors)
+}
+
+func TestHandleGetAllUsersPagination(t *testing.T) {
+	keyID := id.NewKeyID().String()
+	users := []inbound.UserResponse{{UUID: "user-1", KeyID: keyID, Name: "Jane"}}
+
+	t.Run("uses offset pagination by default & returns a bare array", func(t *testing.T) {
+		app, mockUserSvc := newTestApp(t)
+
+		isFirstOffsetPage := gomock.Cond(func(x any) bool {
+			params, ok := x.(common.RequestParams)
+			return ok && params.Cursor == nil && params.Offset == 0 && params.Limit == 100
+		})
+		mockUserSvc.EXPECT().GetAllUsers(gomock.Any(), isFirstOffsetPage).Return(common.Page[inbound.UserResponse]{
+			Items: users,
+			Limit: 100,
+		}, nil).Times(1)
+
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/api/v1/users/", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get(headerTotalCount))
+
+		var body []userResponseDto
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Len(t, body, 1)
+		assert.Equal(t, "user-1", body[0].UUID)
+	})
+
+	t.Run("writes the total of an offset page in a header", func(t *testing.T) {
+		app, mockUserSvc := newTestApp(t)
+
+		total := int64(21)
+		isOffsetPage := gomock.Cond(func(x any) bool {
+			params, ok := x.(common.RequestParams)
+			return ok && params.Cursor == nil && params.Offset == 20 && params.IncludeTotal
+		})
+		mockUserSvc.EXPECT().GetAllUsers(gomock.Any(), isOffsetPage).Return(common.Page[inbound.UserResponse]{
+			Items:  users,
+			Limit:  100,
+			Offset: 20,
+			Total:  &total,
+		}, nil).Times(1)
+
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/api/v1/users/?offset=20&total=true", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "21", resp.Header.Get(headerTotalCount))
+
+		var body []userResponseDto
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Len(t, body, 1)
+	})
+
+	t.Run("uses cursor pagination when a cursor is provided & returns a paginated envelope", func(t *testing.T) {
+		app, mockUserSvc := newTestApp(t)
+
+		next := common.Cursor{KeyID: keyID, Direction: common.NEXT}
+		isFirstCursorPage := gomock.Cond(func(x any) bool {
+			params, ok := x.(common.RequestParams)
+			return ok && params.Cursor != nil && *params.Cursor == common.Cursor{Direction: common.NEXT} && params.Limit == 1
+		})
+		mockUserSvc.EXPECT().GetAllUsers(gomock.Any(), isFirstCursorPage).Return(common.Page[inbound.UserResponse]{
+			Items: users,
+			Limit: 1,
+			Next:  &next,
+		}, nil).Times(1)
+
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/api/v1/users/?limit=1&cursor=", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var body paginatedResponseDto[userResponseDto]
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Len(t, body.Data, 1)
+		assert.Equal(t, "user-1", body.Data[0].UUID)
+		assert.Equal(t, next.Encode(), *body.Page.Next)
+		assert.Nil(t, body.Page.Prev)
+		assert.Equal(t, 1, body.Page.Limit)
+	})
+
+	t.Run("passes a decoded cursor to the service", func(t *testing.T) {
+		app, mockUserSvc := newTestApp(t)
+
+		cursor := common.Cursor{KeyID: keyID, Direction: common.PREV}
+		isCursorPage := gomock.Cond(func(x any) bool {
+			params, ok := x.(common.RequestParams)
+			return ok && params.Cursor != nil && *params.Cursor == cursor
+		})
+		mockUserSvc.EXPECT().GetAllUsers(gomock.Any(), isCursorPage).Return(common.Page[inbound.UserResponse]{Items: users, Limit: 100}, nil).Times(1)
+
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/api/v1/users/?cursor="+cursor.Encode(), nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("rejects invalid pagination parameters", func(t *testing.T) {
+		for _, query := range []string{"cursor=invalid!", "limit=0", "limit=1000", "offset=-1", "offset=1&cursor="} {
+			app, mockUserSvc := newTestApp(t)
+			mockUserSvc.EXPECT().GetAllUsers(gomock.Any(), gomock.Any()).Times(0)
+
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/api/v1/users/?"+query, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode, query)
+		}
+	})
+}
+
+func TestHandleUploadUserImage(t *testing.T) {
+	image := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{1}, 64*1024)...)
+
+	newMultipartRequest := func(t *testing.T, field string) *http.Request {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		assert.NoError(t, writer.WriteField("description", "profile photo"))
+		part, err := writer.CreateFormFile(field, "avatar.png")
+		assert.NoError(t, err)
+		_, err = part.Write(image)
+		assert.NoError(t, err)
+		assert.NoError(t, writer.Close())
+
+		req := httptest.NewRequest(fiber.MethodPost, "/api/v1/users/123/image", body)
+		req.Header.Set(fiber.HeaderContentType, writer.FormDataContentType())
+		return req
+	}
+
+	expectImage := func(t *testing.T, mockUserSvc *mockusersvc.MockUserService) {
+		mockUserSvc.EXPECT().UpdateUserImage(gomock.Any(), "123", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, upload inbound.UserImageUpload) (*inbound.UserResponse, error) {
+				content, err := io.ReadAll(upload.Content)
+				assert.NoError(t, err)
+				assert.Equal(t, image, content)
+				return &inbound.UserResponse{UUID: "123", ImageUrl: "http://localhost:9001/123-image.png"}, nil
+			}).Times(1)
+	}
+
+	t.Run("passes the image field of a buffered request to the service", func(t *testing.T) {
+		app, mockUserSvc := newTestApp(t)
+		expectImage(t, mockUserSvc)
+
+		resp, err := app.Test(newMultipartRequest(t, "image"))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var body userResponseDto
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, "http://localhost:9001/123-image.png", body.ImageUrl)
+	})
+
+	t.Run("passes the image field of a streamed request to the service", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockUserSvc := mockusersvc.NewMockUserService(mockCtrl)
+		expectImage(t, mockUserSvc)
+
+		log, _ := logger.NewTestLogger()
+		// the body is larger than the body limit so it is streamed
+		app := fiber.New(fiber.Config{
+			ErrorHandler:                 utils.ErrorHandler(log),
+			StreamRequestBody:            true,
+			DisablePreParseMultipartForm: true,
+			BodyLimit:                    1024,
+		})
+		api := NewUserApi(mockUserSvc, nil, log)
+		api.RegisterHandlers(app, func(c *fiber.Ctx) error {
+			return c.Next()
+		}, func(c *fiber.Ctx) error {
+			return c.Next()
+		})
+
+		resp, err := app.Test(newMultipartRequest(t, "image"))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("rejects requests without an image", func(t *testing.T) {
+		app, mockUserSvc := newTestApp(t)
+		mockUserSvc.EXPECT().UpdateUserImage(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		resp, err := app.Test(newMultipartRequest(t, "avatar"))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+	})
+
+	t.Run("rejects requests that are not multipart forms", func(t *testing.T) {
+		app, mockUserSvc := newTestApp(t)
+		mockUserSvc.EXPECT().UpdateUserImage(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		req := httptest.NewRequest(fiber.MethodPost, "/api/v1/users/123/image", strings.NewReader(`{"image": "aGV5YQ=="}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+	})
+}
+
+func TestStreamsRequestBody(t *testing.T) {
+	testCases := []struct {
+		method   string
+		path     string
+		expected bool
+	}{
+		{method: fiber.MethodPost, path: "/api/v1/users/123/image", expected: true},
+		{method: fiber.MethodPatch, path: "/api/v1/users/123/image", expected: false},
+		{method: fiber.MethodPost, path: "/api/v1/users/image", expected: false},
+		{method: fiber.MethodPost, path: "/api/v1/users/123/skills/image", expected: false},
+		{method: fiber.MethodPost, path: "/api/v1/users/", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			app := fiber.New()
+			streams := false
+			app.Use(func(c *fiber.Ctx) error {
+				streams = StreamsRequestBody(c)
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			_, err := app.Test(httptest.NewRequest(tc.method, tc.path, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, streams)
+		})
+	}
+}
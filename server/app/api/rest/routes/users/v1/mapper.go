@@ -1,20 +1,68 @@
 package userv1
 
-import "github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound"
+import (
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound/common"
+)
 
 // mapUserToUserResponse maps a user response to a user response dto
 func mapUserToUserResponse(user inbound.UserResponse) userResponseDto {
 	return userResponseDto{
-		UUID:      user.UUID,
-		KeyID:     user.KeyID,
-		XID:       user.XID,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-		DeletedAt: user.DeletedAt,
-		Name:      user.Name,
-		Email:     user.Email,
-		JobTitle:  user.JobTitle,
-		Skills:    user.Skills,
-		ImageUrl:  user.ImageUrl,
+		UUID:          user.UUID,
+		KeyID:         user.KeyID,
+		XID:           user.XID,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+		DeletedAt:     user.DeletedAt,
+		Name:          user.Name,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		PendingEmail:  user.PendingEmail,
+		JobTitle:      user.JobTitle,
+		Skills:        user.Skills,
+		ImageUrl:      user.ImageUrl,
+		Role:          user.Role,
+	}
+}
+
+// mapUserUpdateRequestDtoToRequest maps a user update request dto to a user update request
+func mapUserUpdateRequestDtoToRequest(payload userUpdateRequestDto) inbound.UserUpdateRequest {
+	return inbound.UserUpdateRequest{
+		Name:     payload.Name,
+		Email:    payload.Email,
+		Skills:   payload.Skills,
+		JobTitle: payload.JobTitle,
+	}
+}
+
+// mapUsersToUserResponses maps user responses to user response dtos
+func mapUsersToUserResponses(users []inbound.UserResponse) []userResponseDto {
+	data := make([]userResponseDto, 0, len(users))
+	for _, user := range users {
+		data = append(data, mapUserToUserResponse(user))
+	}
+	return data
+}
+
+// mapUserPageToPaginatedResponse maps a page of user responses retrieved with cursor pagination to a paginated response dto
+func mapUserPageToPaginatedResponse(page common.Page[inbound.UserResponse]) paginatedResponseDto[userResponseDto] {
+	pageDetails := pageDto{
+		Limit: page.Limit,
+		Total: page.Total,
+	}
+
+	if page.Next != nil {
+		next := page.Next.Encode()
+		pageDetails.Next = &next
+	}
+
+	if page.Prev != nil {
+		prev := page.Prev.Encode()
+		pageDetails.Prev = &prev
+	}
+
+	return paginatedResponseDto[userResponseDto]{
+		Data: mapUsersToUserResponses(page.Items),
+		Page: pageDetails,
 	}
 }
@@ -0,0 +1,47 @@
+package userv1
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/gofiber/fiber/v2"
+)
+
+// userImageFormField is the field of the multipart form the user image is uploaded in
+const userImageFormField = "image"
+
+// formFileReader returns a reader over the content of the given field of a multipart/form-data request. When the server
+// streams request bodies, the content is read straight off the connection rather than being buffered in memory first
+func formFileReader(c *fiber.Ctx, field string) (io.Reader, error) {
+	mediaType, params, err := mime.ParseMediaType(c.Get(fiber.HeaderContentType))
+	if err != nil || mediaType != fiber.MIMEMultipartForm || params["boundary"] == "" {
+		return nil, errdefs.NewValidationError("request must be multipart/form-data", err)
+	}
+
+	var body io.Reader
+	if c.Request().IsBodyStream() {
+		body = c.Context().RequestBodyStream()
+	} else {
+		body = bytes.NewReader(c.Body())
+	}
+
+	reader := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, errdefs.NewValidationError(fmt.Sprintf("%s is a required field", field), nil)
+		}
+		if err != nil {
+			return nil, errdefs.NewValidationError("malformed multipart/form-data request", err)
+		}
+
+		// parts that come before the field are skipped without being read into memory
+		if part.FormName() == field && part.FileName() != "" {
+			return part, nil
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package userv1
+
+import (
+	"testing"
+
+	openapispec "github.com/BrianLusina/skillq/server/app/api/openapi-spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenApiSpecDescribesDtos(t *testing.T) {
+	doc, err := openapispec.Load()
+	assert.NoError(t, err)
+
+	schemas := map[string]any{
+		"userRequest":           userRequestDto{},
+		"userUpdateRequest":     userUpdateRequestDto{},
+		"userRoleRequest":       userRoleRequestDto{},
+		"verifyEmailRequest":    verifyEmailDto{},
+		"userResponse":          userResponseDto{},
+		"page":                  pageDto{},
+		"paginatedUserResponse": paginatedResponseDto[userResponseDto]{},
+	}
+
+	for schema, dto := range schemas {
+		assert.ElementsMatch(t, openapispec.JSONFieldNames(dto), doc.SchemaPropertyNames(schema), "schema %s does not match its DTO", schema)
+	}
+}
@@ -0,0 +1,60 @@
+package userv1
+
+import (
+	"fmt"
+
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound/common"
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// defaultPageLimit is the page size used if no limit is provided
+	defaultPageLimit = 100
+
+	// maxPageLimit is the largest page size that can be requested
+	maxPageLimit = 100
+)
+
+// parseRequestParams parses the pagination & ordering query parameters of a request. Cursor pagination is used if a
+// cursor is provided, starting from the first page if it is empty. Otherwise offset pagination is used, starting from the
+// first record if no offset is provided, which is how the users were listed before cursors were added
+func parseRequestParams(c *fiber.Ctx) (common.RequestParams, error) {
+	order := c.Query("order", string(common.CREATED_AT))
+	sort := c.Query("sortby", string(common.DESC))
+	limit := c.QueryInt("limit", defaultPageLimit)
+	includeTotal := c.QueryBool("total", false)
+
+	if limit < 1 || limit > maxPageLimit {
+		return common.RequestParams{}, errdefs.NewValidationError(fmt.Sprintf("limit must be between 1 and %d", maxPageLimit), nil)
+	}
+
+	opts := []common.RequestParamOptions{
+		common.WithRequestLimit(limit),
+		common.WithOrderBy(common.OrderBy(order)),
+		common.WithSortOrder(common.SortOrder(sort)),
+		common.WithIncludeTotal(includeTotal),
+	}
+
+	if c.Request().URI().QueryArgs().Has("cursor") {
+		if c.Query("offset") != "" {
+			return common.RequestParams{}, errdefs.NewValidationError("offset & cursor cannot both be provided", nil)
+		}
+
+		cursor, err := common.DecodeCursor(c.Query("cursor"))
+		if err != nil {
+			return common.RequestParams{}, err
+		}
+
+		opts = append(opts, common.WithCursor(cursor))
+	} else {
+		offset := c.QueryInt("offset", 0)
+		if offset < 0 {
+			return common.RequestParams{}, errdefs.NewValidationError("offset must not be negative", nil)
+		}
+
+		opts = append(opts, common.WithOffset(offset))
+	}
+
+	return common.NewRequestParams(opts...), nil
+}
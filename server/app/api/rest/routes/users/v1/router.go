@@ -1,15 +1,42 @@
 package userv1
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"strings"
 
-// RegisterHandlers registers all the handlers for the user v1 endpoint
-func (api *UserV1Api) RegisterHandlers(app *fiber.App) {
+	"github.com/gofiber/fiber/v2"
+)
+
+// RegisterHandlers registers all the handlers for the user v1 endpoint. The authenticated handler is run before the
+// handlers of every route except registering a user, verifying their email & listing users, rejecting requests that are
+// not authenticated.
+// The throttleResend handler is run before resending an email verification, rejecting requests over its rate limit
+func (api *UserV1Api) RegisterHandlers(app *fiber.App, authenticated fiber.Handler, throttleResend fiber.Handler) {
 	userApiGroup := app.Group("/api/v1/users")
 
 	userApiGroup.Post("/", api.HandleCreateUser)
 	userApiGroup.Post("/verify-email", api.HandleVerifyUserEmail)
-	userApiGroup.Get("/:id", api.HandleGetUserById)
+	userApiGroup.Post("/:id/verification/resend", throttleResend, api.HandleResendUserEmailVerification)
+	userApiGroup.Get("/:id", authenticated, api.HandleGetUserById)
 	userApiGroup.Get("/", api.HandleGetAllUsers)
 	userApiGroup.Get("/skill/:skill", api.HandleGetAllUsersBySkill)
-	userApiGroup.Delete("/:id", api.HandleDeleteUser)
+	userApiGroup.Patch("/:id", authenticated, api.HandleUpdateUser)
+	userApiGroup.Put("/:id/role", authenticated, api.HandleAssignUserRole)
+	userApiGroup.Post("/:id/image", authenticated, api.HandleUploadUserImage)
+	userApiGroup.Delete("/:id", authenticated, api.HandleDeleteUser)
+}
+
+// StreamsRequestBody reports whether the request is for a route that streams its request body, which is uploading a user
+// image. These routes read their bodies as they go & enforce their own size limits
+func StreamsRequestBody(c *fiber.Ctx) bool {
+	if c.Method() != fiber.MethodPost {
+		return false
+	}
+
+	path, ok := strings.CutPrefix(c.Path(), "/api/v1/users/")
+	if !ok {
+		return false
+	}
+
+	userID, ok := strings.CutSuffix(path, "/image")
+	return ok && userID != "" && !strings.Contains(userID, "/")
 }
@@ -2,52 +2,133 @@ package utils
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
-	"net/http"
 
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/infra/logger"
 	"github.com/BrianLusina/skillq/server/utils/validators"
+	"github.com/gofiber/fiber/v2"
 )
 
 const (
 	errMsgInvalidReq = "Invalid request"
 	// ErrMsgJSONDecode is an error message displayed to the API consumer when the server fails to decode the JSON body.
 	ErrMsgJSONDecode = "Failed to decode json request"
+	// errMsgInternal is displayed to the API consumer when an unexpected error occurs, so as not to leak internal details
+	errMsgInternal = "An unexpected error occurred"
+
+	// ProblemContentType is the content type of an RFC 7807 problem details response
+	ProblemContentType = "application/problem+json"
 )
 
-type errResponse struct {
-	Status  int    `json:"status"`
-	Message string `json:"message"`
+// ProblemDetails is an RFC 7807 problem details response body
+type ProblemDetails struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	// Errors lists the fields of the request that failed validation
+	Errors []validators.FieldError `json:"errors,omitempty"`
 }
 
-// WriteWithError sets the response header to application/json, writes the header
-// with a status code, and returns an error response with a status and mesage
-func WriteWithError(w http.ResponseWriter, statusCode int, errMsg string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
+// ErrorHandler is a fiber error handler that maps errors returned from handlers to problem details responses. Typed
+// domain errors are mapped to their matching status code, fiber errors keep their code & any other error is treated
+// as an internal server error whose details are not exposed to the API consumer
+func ErrorHandler(log logger.Logger) fiber.ErrorHandler {
+	return func(c *fiber.Ctx, err error) error {
+		status := statusCode(err)
 
-	errResponse := errResponse{
-		Status:  statusCode,
-		Message: errMsg,
+		detail := errMsgInternal
+		if msg, ok := errdefs.Message(err); ok {
+			detail = msg
+		} else {
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				detail = fiberErr.Message
+			}
+		}
+
+		log := logger.FromContextOr(c.UserContext(), log)
+		if status >= fiber.StatusInternalServerError {
+			log.Errorf("%s %s failed with status %d: %v", c.Method(), c.Path(), status, err)
+		} else {
+			log.Infof("%s %s failed with status %d: %v", c.Method(), c.Path(), status, err)
+		}
+
+		if status == fiber.StatusUnauthorized {
+			c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+		}
+
+		return WriteWithError(c, status, detail)
 	}
-	if err := json.NewEncoder(w).Encode(errResponse); err != nil {
-		log.Printf("Failed to encode error response into JSON: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
+}
+
+// statusCode maps an error to an HTTP status code
+func statusCode(err error) int {
+	switch {
+	case errdefs.IsNotFound(err):
+		return fiber.StatusNotFound
+	case errdefs.IsConflict(err):
+		return fiber.StatusConflict
+	case errdefs.IsValidation(err):
+		return fiber.StatusUnprocessableEntity
+	case errdefs.IsUnauthorized(err):
+		return fiber.StatusUnauthorized
+	case errdefs.IsForbidden(err):
+		return fiber.StatusForbidden
+	case errdefs.IsTooManyRequests(err):
+		return fiber.StatusTooManyRequests
+	case errdefs.IsUnavailable(err):
+		return fiber.StatusServiceUnavailable
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Code
 	}
+
+	return fiber.StatusInternalServerError
 }
 
-// WriteWithStatus sets the response header to application/json, write the header
-// with a status code, and encodes and writes the data json.NewEncoder()
-func WriteWithStatus(w http.ResponseWriter, statusCode int, data any) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
+// WriteWithError writes a problem details response with the given status code & detail message
+func WriteWithError(c *fiber.Ctx, statusCode int, errMsg string) error {
+	return writeProblem(c, newProblem(c, statusCode, errMsg))
+}
 
-	if data != nil {
-		if err := json.NewEncoder(w).Encode(data); err != nil {
-			log.Printf("Failed to encode API response into JSON: %v", err)
-			w.WriteHeader(http.StatusInternalServerError)
-		}
+// newProblem creates problem details for the current request with the given status code & detail message
+func newProblem(c *fiber.Ctx, statusCode int, errMsg string) ProblemDetails {
+	problem := ProblemDetails{
+		Type:     "about:blank",
+		Title:    fiber.ErrInternalServerError.Message,
+		Status:   statusCode,
+		Detail:   errMsg,
+		Instance: c.OriginalURL(),
+	}
+
+	if title := fiber.NewError(statusCode).Message; title != "" {
+		problem.Title = title
 	}
+
+	return problem
+}
+
+// writeProblem writes the problem details as the response
+func writeProblem(c *fiber.Ctx, problem ProblemDetails) error {
+	return c.Status(problem.Status).JSON(problem, ProblemContentType)
+}
+
+// WriteWithStatus sets the status code of the response & writes the data as JSON
+func WriteWithStatus(c *fiber.Ctx, statusCode int, data any) error {
+	c.Status(statusCode)
+
+	if data == nil {
+		return nil
+	}
+
+	return c.JSON(data)
 }
 
 // buildDecodeErrorMsg formats the decode error and returns it as a string
@@ -57,23 +138,42 @@ func buildDecodeErrorMsg(field string, want string, got string) string {
 
 // HandleDecodeErr responds with the appropriate decode error msg and sets
 // the http status to 400
-func HandleDecodeErr(w http.ResponseWriter, err error) {
+func HandleDecodeErr(c *fiber.Ctx, err error) error {
 	errMsg := ErrMsgJSONDecode
-	if err, ok := err.(*json.UnmarshalTypeError); ok {
-		errMsg = buildDecodeErrorMsg(err.Field, err.Type.String(), err.Value)
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		errMsg = buildDecodeErrorMsg(typeErr.Field, typeErr.Type.String(), typeErr.Value)
 	}
 
-	WriteWithError(w, http.StatusBadRequest, errMsg)
+	return WriteWithError(c, fiber.StatusBadRequest, errMsg)
 }
 
-// WriteValidationErr responds with the appropriate validation error msg and
-// sets the http status to 400
-func WriteValidationErr(w http.ResponseWriter, s any, err error) {
+// WriteValidationErr responds with the appropriate validation error msg, listing
+// every field that failed validation and sets the http status to 422
+func WriteValidationErr(c *fiber.Ctx, s any, err error) error {
 	errMsg := errMsgInvalidReq
 	validationErrMsg := validators.GetValidationErrMsg(s, err)
 	if validationErrMsg != "" {
 		errMsg = validationErrMsg
 	}
 
-	WriteWithError(w, http.StatusBadRequest, errMsg)
+	problem := newProblem(c, fiber.StatusUnprocessableEntity, errMsg)
+	problem.Errors = validators.GetValidationErrMsgs(s, err)
+
+	return writeProblem(c, problem)
+}
+
+// WriteFieldErrs responds with the given field errors, using the message of the first as the detail & sets the http
+// status to 422
+func WriteFieldErrs(c *fiber.Ctx, fieldErrs []validators.FieldError) error {
+	errMsg := errMsgInvalidReq
+	if len(fieldErrs) > 0 {
+		errMsg = fieldErrs[0].Message
+	}
+
+	problem := newProblem(c, fiber.StatusUnprocessableEntity, errMsg)
+	problem.Errors = fieldErrs
+
+	return writeProblem(c, problem)
 }
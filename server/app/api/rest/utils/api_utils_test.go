@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorHandler(t *testing.T) {
+	testCases := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedDetail string
+	}{
+		{
+			name:           "not found error",
+			err:            errdefs.NewNotFoundError("user not found", errors.New("no documents")),
+			expectedStatus: fiber.StatusNotFound,
+			expectedDetail: "user not found",
+		},
+		{
+			name:           "wrapped conflict error",
+			err:            errors.Join(errors.New("failed to create user"), errdefs.NewConflictError("user already exists", nil)),
+			expectedStatus: fiber.StatusConflict,
+			expectedDetail: "user already exists",
+		},
+		{
+			name:           "validation error",
+			err:            errdefs.NewValidationError("invalid email", nil),
+			expectedStatus: fiber.StatusUnprocessableEntity,
+			expectedDetail: "invalid email",
+		},
+		{
+			name:           "unauthorized error",
+			err:            errdefs.NewUnauthorizedError("invalid credentials", nil),
+			expectedStatus: fiber.StatusUnauthorized,
+			expectedDetail: "invalid credentials",
+		},
+		{
+			name:           "forbidden error",
+			err:            errdefs.NewForbiddenError("not allowed to delete user", nil),
+			expectedStatus: fiber.StatusForbidden,
+			expectedDetail: "not allowed to delete user",
+		},
+		{
+			name:           "too many requests error",
+			err:            errdefs.NewTooManyRequestsError("too many failed attempts", nil),
+			expectedStatus: fiber.StatusTooManyRequests,
+			expectedDetail: "too many failed attempts",
+		},
+		{
+			name:           "unavailable error",
+			err:            errdefs.NewUnavailableError("database unavailable", errors.New("connection refused")),
+			expectedStatus: fiber.StatusServiceUnavailable,
+			expectedDetail: "database unavailable",
+		},
+		{
+			name:           "fiber error",
+			err:            fiber.NewError(fiber.StatusBadRequest, "bad request body"),
+			expectedStatus: fiber.StatusBadRequest,
+			expectedDetail: "bad request body",
+		},
+		{
+			name:           "unknown error does not leak details",
+			err:            errors.New("secret database failure"),
+			expectedStatus: fiber.StatusInternalServerError,
+			expectedDetail: errMsgInternal,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			log, _ := logger.NewTestLogger()
+			app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(log)})
+			app.Get("/users/:id", func(c *fiber.Ctx) error {
+				return tc.err
+			})
+
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/users/123", nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, resp.StatusCode)
+			assert.Equal(t, ProblemContentType, resp.Header.Get(fiber.HeaderContentType))
+
+			var problem ProblemDetails
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+			assert.Equal(t, tc.expectedStatus, problem.Status)
+			assert.Equal(t, tc.expectedDetail, problem.Detail)
+			assert.Equal(t, "/users/123", problem.Instance)
+			assert.NotEmpty(t, problem.Title)
+		})
+	}
+}
+
+func TestHandleDecodeErr(t *testing.T) {
+	log, _ := logger.NewTestLogger()
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(log)})
+	app.Post("/users", func(c *fiber.Ctx) error {
+		payload := struct {
+			Name string `json:"name"`
+		}{}
+		if err := c.BodyParser(&payload); err != nil {
+			return HandleDecodeErr(c, err)
+		}
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	req := httptest.NewRequest(fiber.MethodPost, "/users", strings.NewReader(`{"name": 1}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	var problem ProblemDetails
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+	assert.Equal(t, "Expected name to be string, got number", problem.Detail)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/BrianLusina/skillq/server/app/pkg/configs"
 	"github.com/ilyakaznacheev/cleanenv"
@@ -15,9 +16,22 @@ type (
 		configs.HTTP `yaml:"http"`
 		configs.Log  `yaml:"logger"`
 		MongoDB      `yaml:"mongodb"`
+		Messaging    `yaml:"messaging"`
 		RabbitMQ     `yaml:"rabbitmq"`
+		Nats         `yaml:"nats"`
 		MinioConfig  `yaml:"minio"`
 		EmailConfig  `yaml:"email"`
+		Auth         `yaml:"auth"`
+		Verification `yaml:"verification"`
+		RateLimit    `yaml:"rateLimit"`
+		Health       `yaml:"health"`
+		Tracing      `yaml:"tracing"`
+		Retry        `yaml:"retry"`
+		Outbox       `yaml:"outbox"`
+	}
+
+	Messaging struct {
+		Broker string `env-description:"Broker tasks are published to & consumed from, one of rabbitmq, nats or memory" yaml:"broker" env:"MESSAGING_BROKER"`
 	}
 
 	MongoDB struct {
@@ -35,11 +49,100 @@ type (
 	}
 
 	RabbitMQ struct {
-		Username string `yaml:"username" env:"RABBITMQ_USERNAME"`
-		Password string `yaml:"password" env:"RABBITMQ_PASSWORD"`
-		Host     string `yaml:"host" env:"RABBITMQ_HOST"`
-		Port     string `yaml:"port" env:"RABBITMQ_PORT"`
-		URL      string `yaml:"url" env:"RABBITMQ_URL"`
+		Username       string            `yaml:"username" env:"RABBITMQ_USERNAME"`
+		Password       string            `yaml:"password" env:"RABBITMQ_PASSWORD"`
+		Host           string            `yaml:"host" env:"RABBITMQ_HOST"`
+		Port           string            `yaml:"port" env:"RABBITMQ_PORT"`
+		URL            string            `env-description:"AMQP URI of RabbitMQ, which takes precedence over the username, password, host & port" yaml:"url" env:"RABBITMQ_URL"`
+		VHost          string            `env-description:"Virtual host of RabbitMQ, which overrides the virtual host of the URL" yaml:"vhost" env:"RABBITMQ_VHOST"`
+		TLS            RabbitMQTLS       `yaml:"tls"`
+		Reconnect      RabbitMQReconnect `yaml:"reconnect"`
+		ChannelTimeout time.Duration     `env-description:"How long publishing waits for the connection to RabbitMQ to be re-established while it is down" yaml:"channelTimeout" env:"RABBITMQ_CHANNEL_TIMEOUT"`
+		Publish        RabbitMQPublish   `yaml:"publish"`
+		Topology       RabbitMQTopology  `yaml:"topology"`
+	}
+
+	RabbitMQTopology struct {
+		Exchanges []RabbitMQExchange       `env-description:"Exchanges declared on RabbitMQ at startup" yaml:"exchanges"`
+		Queues    []RabbitMQQueue          `env-description:"Queues declared on RabbitMQ at startup & consumed from" yaml:"queues"`
+		Routes    map[string]RabbitMQRoute `env-description:"Exchanges & routing keys tasks are published to by task type" yaml:"routes"`
+	}
+
+	RabbitMQExchange struct {
+		Name       string `yaml:"name"`
+		Kind       string `env-description:"Type of the exchange, one of direct, fanout, topic or headers" yaml:"kind"`
+		Durable    bool   `yaml:"durable"`
+		AutoDelete bool   `yaml:"autoDelete"`
+		Internal   bool   `yaml:"internal"`
+	}
+
+	RabbitMQQueue struct {
+		Name          string            `yaml:"name"`
+		Durable       bool              `yaml:"durable"`
+		AutoDelete    bool              `yaml:"autoDelete"`
+		Exclusive     bool              `yaml:"exclusive"`
+		Bindings      []RabbitMQBinding `env-description:"Exchanges the queue is bound to" yaml:"bindings"`
+		ConsumerTag   string            `env-description:"Tag of the consumer of the queue, which defaults to the name of the queue with a -consumer suffix" yaml:"consumerTag"`
+		PrefetchCount int               `env-description:"Deliveries RabbitMQ sends before they are acknowledged, which defaults to the number of workers" yaml:"prefetchCount"`
+		Workers       int               `env-description:"Number of workers that handle the deliveries of the queue concurrently" yaml:"workers"`
+		Tasks         []string          `env-description:"Types of the tasks handled from the queue, all types if none are set" yaml:"tasks"`
+	}
+
+	RabbitMQBinding struct {
+		Exchange   string `yaml:"exchange"`
+		RoutingKey string `yaml:"routingKey"`
+	}
+
+	RabbitMQRoute struct {
+		Exchange   string `yaml:"exchange"`
+		RoutingKey string `yaml:"routingKey"`
+	}
+
+	RabbitMQPublish struct {
+		Mandatory       bool          `env-description:"Fail publishing messages that are not routed to any queue" yaml:"mandatory" env:"RABBITMQ_PUBLISH_MANDATORY"`
+		ConfirmTimeout  time.Duration `env-description:"How long publishing a message waits for RabbitMQ to confirm it" yaml:"confirmTimeout" env:"RABBITMQ_PUBLISH_CONFIRM_TIMEOUT"`
+		ChannelPoolSize int           `env-description:"Number of idle channels kept open to publish messages on" yaml:"channelPoolSize" env:"RABBITMQ_PUBLISH_CHANNEL_POOL_SIZE"`
+	}
+
+	RabbitMQTLS struct {
+		Enabled            bool   `env-description:"Connect to RabbitMQ over TLS" yaml:"enabled" env:"RABBITMQ_TLS_ENABLED"`
+		CAFile             string `env-description:"Path of the CA certificate the certificate of RabbitMQ is verified with" yaml:"caFile" env:"RABBITMQ_TLS_CA_FILE"`
+		CertFile           string `env-description:"Path of the client certificate to authenticate with RabbitMQ" yaml:"certFile" env:"RABBITMQ_TLS_CERT_FILE"`
+		KeyFile            string `env-description:"Path of the key of the client certificate" yaml:"keyFile" env:"RABBITMQ_TLS_KEY_FILE"`
+		ServerName         string `env-description:"Name the certificate of RabbitMQ is verified against" yaml:"serverName" env:"RABBITMQ_TLS_SERVER_NAME"`
+		InsecureSkipVerify bool   `env-description:"Skip verifying the certificate of RabbitMQ" yaml:"insecureSkipVerify" env:"RABBITMQ_TLS_INSECURE_SKIP_VERIFY"`
+	}
+
+	RabbitMQReconnect struct {
+		InitialDelay time.Duration `env-description:"Delay before reconnecting to RabbitMQ the first time once the connection is lost" yaml:"initialDelay" env:"RABBITMQ_RECONNECT_INITIAL_DELAY"`
+		MaxDelay     time.Duration `env-description:"Maximum delay between attempts to reconnect to RabbitMQ" yaml:"maxDelay" env:"RABBITMQ_RECONNECT_MAX_DELAY"`
+	}
+
+	Nats struct {
+		URL           string        `env-description:"URL of NATS, or a comma separated list of the URLs of a cluster" yaml:"url" env:"NATS_URL"`
+		ReconnectWait time.Duration `env-description:"Delay between attempts to reconnect to NATS once the connection is lost" yaml:"reconnectWait" env:"NATS_RECONNECT_WAIT"`
+		AckWait       time.Duration `env-description:"How long NATS waits for a task to be acknowledged before it redelivers it" yaml:"ackWait" env:"NATS_ACK_WAIT"`
+		Topology      NatsTopology  `yaml:"topology"`
+	}
+
+	NatsTopology struct {
+		Streams   []NatsStream      `env-description:"JetStream streams declared on NATS at startup" yaml:"streams"`
+		Consumers []NatsConsumer    `env-description:"Durable consumers declared on NATS at startup & consumed from" yaml:"consumers"`
+		Routes    map[string]string `env-description:"Subjects tasks are published to by task type" yaml:"routes"`
+	}
+
+	NatsStream struct {
+		Name     string        `yaml:"name"`
+		Subjects []string      `yaml:"subjects"`
+		MaxAge   time.Duration `yaml:"maxAge"`
+	}
+
+	NatsConsumer struct {
+		Durable  string   `yaml:"durable"`
+		Stream   string   `yaml:"stream"`
+		Subjects []string `env-description:"Subjects of the stream the durable consumer is filtered to" yaml:"subjects"`
+		Workers  int      `env-description:"Workers that handle the tasks of the durable consumer concurrently" yaml:"workers"`
+		Tasks    []string `env-description:"Task types handled from the durable consumer, which are all the task types if it is empty" yaml:"tasks"`
 	}
 
 	MinioConfig struct {
@@ -59,6 +162,78 @@ type (
 		Password string `yaml:"password" env:"EMAIL_CLIENT_PASSWORD"`
 		From     string `yaml:"from" env:"EMAIL_CLIENT_FROM"`
 	}
+
+	Auth struct {
+		JWTSecret           string        `env-required:"true" env-description:"Secret used to sign access tokens, which is not committed to the config file" yaml:"jwtSecret" env:"AUTH_JWT_SECRET"`
+		Issuer              string        `env-description:"Issuer of access tokens" yaml:"issuer" env:"AUTH_ISSUER"`
+		AccessTokenTTL      time.Duration `env-description:"How long access tokens are valid for" yaml:"accessTokenTTL" env:"AUTH_ACCESS_TOKEN_TTL"`
+		RefreshTokenTTL     time.Duration `env-description:"How long refresh tokens are valid for" yaml:"refreshTokenTTL" env:"AUTH_REFRESH_TOKEN_TTL"`
+		PasswordResetTTL    time.Duration `env-description:"How long password reset tokens are valid for" yaml:"passwordResetTTL" env:"AUTH_PASSWORD_RESET_TTL"`
+		BootstrapAdminEmail string        `env-description:"Email of the user who is made an admin once they have verified it, so that there is an admin to assign roles" yaml:"bootstrapAdminEmail" env:"AUTH_BOOTSTRAP_ADMIN_EMAIL"`
+	}
+
+	Verification struct {
+		CodeLength     int           `env-description:"Number of digits in email verification codes" yaml:"codeLength" env:"VERIFICATION_CODE_LENGTH"`
+		CodeTTL        time.Duration `env-description:"How long email verification codes are valid for" yaml:"codeTTL" env:"VERIFICATION_CODE_TTL"`
+		CodeSecret     string        `env-required:"true" env-description:"Secret used to hash email verification codes, which is not committed to the config file" yaml:"codeSecret" env:"VERIFICATION_CODE_SECRET"`
+		MaxAttempts    int           `env-description:"Attempts at verifying an email after which a user is locked out for the rest of the attempts window" yaml:"maxAttempts" env:"VERIFICATION_MAX_ATTEMPTS"`
+		AttemptsWindow time.Duration `env-description:"How long the attempts of a user at verifying their email are counted for" yaml:"attemptsWindow" env:"VERIFICATION_ATTEMPTS_WINDOW"`
+	}
+
+	RateLimit struct {
+		Store              string           `env-description:"Store used to rate limit requests, either redis or memory" yaml:"store" env:"RATE_LIMIT_STORE"`
+		Redis              RateLimitRedis   `yaml:"redis"`
+		ResendVerification RateLimitRequest `yaml:"resendVerification"`
+	}
+
+	RateLimitRedis struct {
+		Address  string `env-description:"Address of the redis rate limit store" yaml:"address" env:"RATE_LIMIT_REDIS_ADDRESS"`
+		Password string `env-description:"Password of the redis rate limit store" yaml:"password" env:"RATE_LIMIT_REDIS_PASSWORD"`
+		DB       int    `env-description:"Database of the redis rate limit store" yaml:"db" env:"RATE_LIMIT_REDIS_DB"`
+	}
+
+	RateLimitRequest struct {
+		Limit  int           `env-description:"Number of requests allowed per user & per IP address in a window" yaml:"limit" env:"RATE_LIMIT_RESEND_VERIFICATION_LIMIT"`
+		Window time.Duration `env-description:"Window requests are counted in" yaml:"window" env:"RATE_LIMIT_RESEND_VERIFICATION_WINDOW"`
+	}
+
+	Health struct {
+		MongoDBTimeout  time.Duration `env-description:"How long the readiness check of MongoDB is given" yaml:"mongodbTimeout" env:"HEALTH_MONGODB_TIMEOUT"`
+		RabbitMQTimeout time.Duration `env-description:"How long the readiness check of RabbitMQ is given" yaml:"rabbitmqTimeout" env:"HEALTH_RABBITMQ_TIMEOUT"`
+		NatsTimeout     time.Duration `env-description:"How long the readiness check of NATS is given" yaml:"natsTimeout" env:"HEALTH_NATS_TIMEOUT"`
+		StorageTimeout  time.Duration `env-description:"How long the readiness check of the object store is given" yaml:"storageTimeout" env:"HEALTH_STORAGE_TIMEOUT"`
+	}
+
+	Tracing struct {
+		Exporter    string  `env-description:"Where spans are exported to, one of otlp, stdout or none" yaml:"exporter" env:"TRACING_EXPORTER"`
+		Endpoint    string  `env-description:"Host & port of the OTLP receiver spans are exported to" yaml:"endpoint" env:"TRACING_ENDPOINT"`
+		Insecure    bool    `env-description:"Export spans to the OTLP receiver over plain HTTP" yaml:"insecure" env:"TRACING_INSECURE"`
+		SampleRatio float64 `env-description:"Ratio of traces started by the service that are sampled, from 0 to 1" yaml:"sampleRatio" env:"TRACING_SAMPLE_RATIO"`
+	}
+
+	Retry struct {
+		Default RetryPolicy            `env-description:"Retry policy of tasks that have no retry policy of their own" yaml:"default"`
+		Tasks   map[string]RetryPolicy `env-description:"Retry policies of tasks by task type" yaml:"tasks"`
+	}
+
+	Outbox struct {
+		PollInterval time.Duration `env-description:"How often the outbox is checked for tasks to publish" yaml:"pollInterval" env:"OUTBOX_POLL_INTERVAL"`
+		BatchSize    int           `env-description:"Maximum number of tasks published from the outbox per check" yaml:"batchSize" env:"OUTBOX_BATCH_SIZE"`
+		Lease        time.Duration `env-description:"How long a task claimed from the outbox is held before it can be claimed again" yaml:"lease" env:"OUTBOX_LEASE"`
+		Retry        OutboxRetry   `yaml:"retry"`
+	}
+
+	OutboxRetry struct {
+		InitialDelay time.Duration `env-description:"Delay before a task that failed to be published from the outbox is published again the first time" yaml:"initialDelay" env:"OUTBOX_RETRY_INITIAL_DELAY"`
+		MaxDelay     time.Duration `env-description:"Maximum delay before a task that failed to be published from the outbox is published again" yaml:"maxDelay" env:"OUTBOX_RETRY_MAX_DELAY"`
+	}
+
+	RetryPolicy struct {
+		MaxRetries   int           `env-description:"Number of times a failed task is retried before it is dead-lettered" yaml:"maxRetries"`
+		InitialDelay time.Duration `env-description:"Delay before a failed task is retried the first time" yaml:"initialDelay"`
+		MaxDelay     time.Duration `env-description:"Maximum delay before a failed task is retried" yaml:"maxDelay"`
+		Multiplier   float64       `env-description:"Factor the delay before a failed task is retried grows by with every retry" yaml:"multiplier"`
+	}
 )
 
 func NewConfig() (*Config, error) {
@@ -3,21 +3,42 @@ package main
 import (
 	"context"
 	"fmt"
-	"log/slog"
 	"os"
 	"os/signal"
+	"slices"
 	"syscall"
 
+	openapispec "github.com/BrianLusina/skillq/server/app/api/openapi-spec"
+	"github.com/BrianLusina/skillq/server/app/api/rest/middleware"
+	authv1 "github.com/BrianLusina/skillq/server/app/api/rest/routes/auth/v1"
+	"github.com/BrianLusina/skillq/server/app/api/rest/routes/docs"
+	metricsroutes "github.com/BrianLusina/skillq/server/app/api/rest/routes/metrics"
+	"github.com/BrianLusina/skillq/server/app/api/rest/routes/probes"
 	userv1 "github.com/BrianLusina/skillq/server/app/api/rest/routes/users/v1"
+	"github.com/BrianLusina/skillq/server/app/api/rest/utils"
 	"github.com/BrianLusina/skillq/server/app/cmd/config"
-	"github.com/BrianLusina/skillq/server/app/internal/app"
+	"github.com/BrianLusina/skillq/server/app/di"
+	skillqapp "github.com/BrianLusina/skillq/server/app/internal/app"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/services/authsvc"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/services/usersvc"
+	"github.com/BrianLusina/skillq/server/app/internal/outboxrelay"
 	"github.com/BrianLusina/skillq/server/infra/clients/email"
+	"github.com/BrianLusina/skillq/server/infra/health"
+	"github.com/BrianLusina/skillq/server/infra/lifecycle"
 	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/messaging"
 	"github.com/BrianLusina/skillq/server/infra/messaging/amqp"
 	amqpconsumer "github.com/BrianLusina/skillq/server/infra/messaging/amqp/consumer"
 	amqppublisher "github.com/BrianLusina/skillq/server/infra/messaging/amqp/publisher"
+	memorybroker "github.com/BrianLusina/skillq/server/infra/messaging/memory"
+	natsbroker "github.com/BrianLusina/skillq/server/infra/messaging/nats"
+	"github.com/BrianLusina/skillq/server/infra/metrics"
 	"github.com/BrianLusina/skillq/server/infra/mongodb"
+	ratelimitredis "github.com/BrianLusina/skillq/server/infra/ratelimit/redis"
+	"github.com/BrianLusina/skillq/server/infra/requestid"
 	"github.com/BrianLusina/skillq/server/infra/storage/minio"
+	"github.com/BrianLusina/skillq/server/infra/tracing"
+	"github.com/BrianLusina/skillq/server/utils/security"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"go.uber.org/automaxprocs/maxprocs"
@@ -32,48 +53,92 @@ func main() {
 		appLogger.Error("failed set max procs", err)
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	// the app is shut down when it is interrupted or terminated
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	cfg, err := config.NewConfig()
 	if err != nil {
-		appLogger.Error("failed get config", err)
+		appLogger.Fatalf("failed to get config: %v", err)
 	}
 
-	slog.Info("⚡ init app", "name", cfg.Name, "version", cfg.Version)
+	appLogger.Infof("⚡ init app %s, version %s", cfg.Name, cfg.Version)
+
+	// tracing is set up before the app, so that the spans of every request & task are exported
+	tracerProvider, err := tracing.Setup(ctx, tracing.Config{
+		ServiceName:    cfg.Name,
+		ServiceVersion: cfg.Version,
+		Exporter:       cfg.Tracing.Exporter,
+		Endpoint:       cfg.Tracing.Endpoint,
+		Insecure:       cfg.Tracing.Insecure,
+		SampleRatio:    cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		appLogger.Fatalf("failed to set up tracing: %v", err)
+	}
+
+	// prepare and setup app
+	skillQApp := setupApp(cfg, appLogger)
 
 	// TODO: setup config
 	app := fiber.New(fiber.Config{
 		ServerHeader: "SkillQ",
 		AppName:      "SkillQ",
+		ErrorHandler: utils.ErrorHandler(appLogger),
+		// request bodies larger than the body limit are streamed, which allows user images to be streamed to storage. The
+		// multipart form of these requests is read by the handlers as a stream instead of being parsed up front
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
+		// the IP address of the client, which requests are rate limited by, is only read from the proxy header on requests
+		// sent by trusted proxies, so that clients can not pick the address they are counted against
+		ProxyHeader:             cfg.HTTP.ProxyHeader,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          cfg.HTTP.TrustedProxies,
+		EnableIPValidation:      true,
 	})
 
-	go func() {
-		defer app.Server().Shutdown()
-		<-ctx.Done()
-	}()
-
 	// middleware
-	app.Use(cors.New())
+	app.Use(middleware.RequestID(appLogger))
+	app.Use(middleware.Tracing(tracerProvider))
+	app.Use(middleware.Metrics(metrics.NewHTTPMetrics(skillQApp.MetricsRegistry)))
+	app.Use(cors.New(cors.Config{
+		// clients can read the request ID of a response to report it
+		ExposeHeaders: requestid.Header,
+	}))
+	app.Use(middleware.LimitBody(fiber.DefaultBodyLimit, userv1.StreamsRequestBody))
 
-	// prepare and setup app
-	setupApp(ctx, cancel, app, cfg, appLogger)
+	if cfg.HTTP.ValidateRequests {
+		spec, err := openapispec.Load()
+		if err != nil {
+			appLogger.Fatalf("failed to load openapi spec: %v", err)
+		}
+		app.Use(middleware.ValidateRequest(spec))
+	}
 
-	// Start the server
-	err = app.Listen(fmt.Sprintf(":%d", cfg.HTTP.Port))
-	appLogger.Fatalf("Failed to start application: %v", err)
+	registerRoutes(app, skillQApp, cfg, appLogger)
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	// the server is started last & stopped first, so that in flight requests are drained while the connections they use
+	// are still open. The tracer provider is stopped last, so that the spans of the requests & tasks drained are exported
+	manager := lifecycle.New(cfg.App.ShutdownTimeout, appLogger).
+		Add(lifecycle.Component{
+			Name: "tracing",
+			Stop: tracerProvider.Shutdown,
+		}).
+		Add(skillQApp.Components()...).
+		Add(lifecycle.Component{
+			Name: "http",
+			Run: func() error {
+				return app.Listen(fmt.Sprintf(":%d", cfg.HTTP.Port))
+			},
+			Stop: app.ShutdownWithContext,
+		})
 
-	select {
-	case v := <-quit:
-		appLogger.Info("signal.Notify", v)
-	case done := <-ctx.Done():
-		appLogger.Info("ctx.Done", "app done", done)
+	if err := manager.Run(ctx); err != nil {
+		appLogger.Fatalf("Failed to run application: %v", err)
 	}
 }
 
-func setupApp(ctx context.Context, cancel context.CancelFunc, app *fiber.App, cfg *config.Config, appLogger logger.Logger) {
+func setupApp(cfg *config.Config, appLogger logger.Logger) *skillqapp.App {
 	// configuration
 	mongoDbConfig := mongodb.MongoDBConfig{
 		Client: mongodb.ClientOptions{
@@ -100,6 +165,19 @@ func setupApp(ctx context.Context, cancel context.CancelFunc, app *fiber.App, cf
 		Password: cfg.RabbitMQ.Password,
 		Host:     cfg.RabbitMQ.Host,
 		Port:     cfg.RabbitMQ.Port,
+		URL:      cfg.RabbitMQ.URL,
+		VHost:    cfg.RabbitMQ.VHost,
+		TLS: amqp.TLSConfig{
+			Enabled:            cfg.RabbitMQ.TLS.Enabled,
+			CAFile:             cfg.RabbitMQ.TLS.CAFile,
+			CertFile:           cfg.RabbitMQ.TLS.CertFile,
+			KeyFile:            cfg.RabbitMQ.TLS.KeyFile,
+			ServerName:         cfg.RabbitMQ.TLS.ServerName,
+			InsecureSkipVerify: cfg.RabbitMQ.TLS.InsecureSkipVerify,
+		},
+		ReconnectInitialDelay: cfg.RabbitMQ.Reconnect.InitialDelay,
+		ReconnectMaxDelay:     cfg.RabbitMQ.Reconnect.MaxDelay,
+		ChannelTimeout:        cfg.RabbitMQ.ChannelTimeout,
 	}
 
 	minioConfig := minio.Config{
@@ -118,93 +196,286 @@ func setupApp(ctx context.Context, cancel context.CancelFunc, app *fiber.App, cf
 		From:     cfg.EmailConfig.From,
 	}
 
-	skillQApp := prepareApp(ctx, cancel, mongodbConfig, amqpConfig, minioConfig, emailConfig)
+	authConfig := authsvc.Config{
+		JWT: security.JWTConfig{
+			Secret: cfg.Auth.JWTSecret,
+			Issuer: cfg.Auth.Issuer,
+			TTL:    cfg.Auth.AccessTokenTTL,
+		},
+		RefreshTokenTTL:  cfg.Auth.RefreshTokenTTL,
+		PasswordResetTTL: cfg.Auth.PasswordResetTTL,
+	}
 
-	// routing
-	userApi := userv1.NewUserApi(skillQApp.UserSvc, skillQApp.UserVerificationSvc, appLogger)
-	userApi.RegisterHandlers(app)
-}
+	userConfig := usersvc.Config{
+		BootstrapAdminEmail: cfg.Auth.BootstrapAdminEmail,
+	}
+
+	verificationConfig := usersvc.VerificationConfig{
+		CodeLength:     cfg.Verification.CodeLength,
+		CodeTTL:        cfg.Verification.CodeTTL,
+		CodeSecret:     cfg.Verification.CodeSecret,
+		MaxAttempts:    cfg.Verification.MaxAttempts,
+		AttemptsWindow: cfg.Verification.AttemptsWindow,
+	}
+
+	rateLimitConfig := di.RateLimitConfig{
+		Store: cfg.RateLimit.Store,
+		Redis: ratelimitredis.Config{
+			Address:   cfg.RateLimit.Redis.Address,
+			Password:  cfg.RateLimit.Redis.Password,
+			DB:        cfg.RateLimit.Redis.DB,
+			KeyPrefix: "skillq:ratelimit:",
+		},
+	}
+
+	// the consumer declares the topology & consumes from its queues, & failed tasks are retried by the retry policy of
+	// their type
+	consumerOptions := toAmqpConsumerOptions(cfg.RabbitMQ.Topology)
+	consumerOptions = append(consumerOptions, amqpconsumer.DefaultRetryPolicy(toRetryPolicy(cfg.Retry.Default)))
+	for taskType, policy := range cfg.Retry.Tasks {
+		consumerOptions = append(consumerOptions, amqpconsumer.TaskRetryPolicy(taskType, toRetryPolicy(policy)))
+	}
+
+	// messages are published once the broker confirms them, failing if they are not routed to any queue when mandatory
+	publisherOptions := []amqppublisher.Option{
+		amqppublisher.PublishConfig(amqp.PublishOptionsParams{PublishMandatory: cfg.RabbitMQ.Publish.Mandatory}),
+		amqppublisher.ConfirmTimeout(cfg.RabbitMQ.Publish.ConfirmTimeout),
+		amqppublisher.ChannelPoolSize(cfg.RabbitMQ.Publish.ChannelPoolSize),
+	}
+
+	// tasks are published to the exchange their type is routed to
+	for taskType, route := range cfg.RabbitMQ.Topology.Routes {
+		publisherOptions = append(publisherOptions, amqppublisher.Route(taskType, route.Exchange, route.RoutingKey))
+	}
 
-func prepareApp(ctx context.Context, cancel context.CancelFunc, mongoDbConfig mongodb.MongoDBConfig, amqpConfig amqp.Config, minioConfig minio.Config, emailConfig email.EmailClientConfig) *app.App {
-	app, err := app.InitApp(mongoDbConfig, amqpConfig, minioConfig, emailConfig)
+	// tasks added to the outbox are published until the broker confirms them, backing off after every failure
+	outboxConfig := outboxrelay.Config{
+		PollInterval: cfg.Outbox.PollInterval,
+		BatchSize:    cfg.Outbox.BatchSize,
+		Lease:        cfg.Outbox.Lease,
+		Backoff: messaging.Backoff{
+			InitialDelay: cfg.Outbox.Retry.InitialDelay,
+			MaxDelay:     cfg.Outbox.Retry.MaxDelay,
+		},
+	}
+
+	// tasks are published to & consumed from the broker selected by the configuration. The in-memory broker has the same
+	// topology as RabbitMQ, while NATS has streams & durable consumers of its own
+	messagingConfig := di.MessagingConfig{
+		Broker: cfg.Messaging.Broker,
+		Amqp: di.AmqpMessagingConfig{
+			Publisher: publisherOptions,
+			Consumer:  consumerOptions,
+		},
+		Nats:   toNatsMessagingConfig(cfg.Nats, cfg.App.Name, cfg.Retry),
+		Memory: toMemoryMessagingConfig(cfg.RabbitMQ.Topology, cfg.Retry),
+	}
+
+	skillQApp, err := prepareApp(mongodbConfig, amqpConfig, minioConfig, emailConfig, authConfig, userConfig, verificationConfig, rateLimitConfig, outboxConfig, messagingConfig)
 	if err != nil {
-		slog.Error("failed init app", err)
-		cancel()
-		<-ctx.Done()
+		appLogger.Fatalf("failed init app: %v", err)
 	}
 
-	app.StoreImageTaskPublisher.Configure(
-		amqppublisher.Exchange(
-			amqp.ExchangeOptionParams{
-				Name:    "store-image-exchange",
-				Kind:    "fanout",
-				Durable: true,
-			},
-		),
-		amqppublisher.BindingKey("store-image-routing-key"),
-	)
-
-	app.SendEmailTaskPublisher.Configure(
-		amqppublisher.Exchange(
-			amqp.ExchangeOptionParams{
-				Name:    "send-email-exchange",
-				Kind:    "fanout",
-				Durable: true,
-			},
-		),
-		amqppublisher.BindingKey("send-email-routing-key"),
-	)
-
-	app.AmqpEventConsumer.Configure(
-		amqpconsumer.Exchange(
-			amqp.ExchangeOptionParams{
-				Name:    "store-image-exchange",
-				Kind:    "fanout",
-				Durable: true,
-			},
-		),
-		amqpconsumer.Queue(
-			amqp.QueueOptionParams{
-				Name: "store-image-queue",
-			},
-		),
-		amqpconsumer.BindingKey("store-image-routing-key"),
-		amqpconsumer.Consumer(
-			amqp.ConsumerOptionParams{
-				Tag: "store-image-consumer",
-			},
-		),
-	)
-
-	app.AmqpEventConsumer.Configure(
-		amqpconsumer.Exchange(
-			amqp.ExchangeOptionParams{
-				Name:    "send-email-exchange",
-				Kind:    "fanout",
-				Durable: true,
-			},
-		),
-		amqpconsumer.Queue(
-			amqp.QueueOptionParams{
-				Name: "send-email-queue",
-			},
-		),
-		amqpconsumer.BindingKey("send-email-routing-key"),
-		amqpconsumer.Consumer(
-			amqp.ConsumerOptionParams{
-				Tag: "send-email-consumer",
+	return skillQApp
+}
+
+// toAmqpConsumerOptions converts the configuration of the topology of RabbitMQ to the options of the consumer, which
+// declares the exchanges & queues & consumes from every queue
+func toAmqpConsumerOptions(topology config.RabbitMQTopology) []amqpconsumer.Option {
+	var options []amqpconsumer.Option
+
+	for _, exchange := range topology.Exchanges {
+		options = append(options, amqpconsumer.Exchange(amqp.ExchangeOptionParams{
+			Name:       exchange.Name,
+			Kind:       exchange.Kind,
+			Durable:    exchange.Durable,
+			AutoDelete: exchange.AutoDelete,
+			Internal:   exchange.Internal,
+		}))
+	}
+
+	for _, queue := range topology.Queues {
+		bindings := make([]amqp.BindingOptionParams, 0, len(queue.Bindings))
+		for _, binding := range queue.Bindings {
+			bindings = append(bindings, amqp.BindingOptionParams{Exchange: binding.Exchange, RoutingKey: binding.RoutingKey})
+		}
+
+		options = append(options, amqpconsumer.Subscribe(amqpconsumer.SubscriptionParams{
+			Queue: amqp.QueueOptionParams{
+				Name:       queue.Name,
+				Durable:    queue.Durable,
+				AutoDelete: queue.AutoDelete,
+				Exclusive:  queue.Exclusive,
 			},
-		),
-	)
-
-	go func() {
-		err1 := app.AmqpEventConsumer.StartConsumer(app.Worker)
-		if err1 != nil {
-			slog.Error("Failed to start app Consumer", err1)
-			cancel()
-			<-ctx.Done()
+			Bindings: bindings,
+			Consumer: amqp.ConsumerOptionParams{Tag: queue.ConsumerTag},
+			Qos:      amqp.QosOptionParams{PrefetchCount: queue.PrefetchCount},
+			Workers:  queue.Workers,
+			Topics:   queue.Tasks,
+		}))
+	}
+
+	return options
+}
+
+// toMemoryMessagingConfig converts the configuration of the topology to the options of the in-memory publisher & consumer.
+// Failed tasks are redelivered by the retry policy of their type, or by the default retry policy if their type has none
+func toMemoryMessagingConfig(topology config.RabbitMQTopology, retry config.Retry) di.MemoryMessagingConfig {
+	memoryConfig := di.MemoryMessagingConfig{
+		Consumer: []memorybroker.ConsumerOption{
+			memorybroker.DefaultRetryPolicy(toRetryPolicy(retry.Default)),
+		},
+	}
+
+	for taskType, policy := range retry.Tasks {
+		memoryConfig.Consumer = append(memoryConfig.Consumer, memorybroker.TopicRetryPolicy(taskType, toRetryPolicy(policy)))
+	}
+
+	for _, exchange := range topology.Exchanges {
+		memoryConfig.Consumer = append(memoryConfig.Consumer, memorybroker.Exchange(memorybroker.ExchangeParams{
+			Name: exchange.Name,
+			Kind: exchange.Kind,
+		}))
+	}
+
+	for _, queue := range topology.Queues {
+		bindings := make([]memorybroker.BindingParams, 0, len(queue.Bindings))
+		for _, binding := range queue.Bindings {
+			bindings = append(bindings, memorybroker.BindingParams{Exchange: binding.Exchange, RoutingKey: binding.RoutingKey})
 		}
-	}()
 
-	return app
+		memoryConfig.Consumer = append(memoryConfig.Consumer, memorybroker.Subscribe(memorybroker.SubscriptionParams{
+			Queue:    queue.Name,
+			Bindings: bindings,
+			Workers:  queue.Workers,
+			Topics:   queue.Tasks,
+		}))
+	}
+
+	for taskType, route := range topology.Routes {
+		memoryConfig.Publisher = append(memoryConfig.Publisher, memorybroker.Route(taskType, route.Exchange, route.RoutingKey))
+	}
+
+	return memoryConfig
+}
+
+// toNatsMessagingConfig converts the configuration of NATS to the connection, which is named after the service, & the
+// options of the NATS publisher & consumer. Failed tasks are redelivered by the retry policy of their type, or by the
+// default retry policy if their type has none
+func toNatsMessagingConfig(natsConfig config.Nats, name string, retry config.Retry) di.NatsMessagingConfig {
+	messagingConfig := di.NatsMessagingConfig{
+		Client: natsbroker.Config{
+			URL:           natsConfig.URL,
+			Name:          name,
+			ReconnectWait: natsConfig.ReconnectWait,
+		},
+		Consumer: []natsbroker.ConsumerOption{
+			natsbroker.DefaultRetryPolicy(toRetryPolicy(retry.Default)),
+		},
+	}
+
+	for taskType, policy := range retry.Tasks {
+		messagingConfig.Consumer = append(messagingConfig.Consumer, natsbroker.TopicRetryPolicy(taskType, toRetryPolicy(policy)))
+	}
+
+	if natsConfig.AckWait > 0 {
+		messagingConfig.Consumer = append(messagingConfig.Consumer, natsbroker.AckWait(natsConfig.AckWait))
+	}
+
+	for _, stream := range natsConfig.Topology.Streams {
+		messagingConfig.Consumer = append(messagingConfig.Consumer, natsbroker.Stream(natsbroker.StreamParams{
+			Name:     stream.Name,
+			Subjects: stream.Subjects,
+			MaxAge:   stream.MaxAge,
+		}))
+	}
+
+	for _, consumer := range natsConfig.Topology.Consumers {
+		messagingConfig.Consumer = append(messagingConfig.Consumer, natsbroker.Subscribe(natsbroker.SubscriptionParams{
+			Durable:  consumer.Durable,
+			Stream:   consumer.Stream,
+			Subjects: consumer.Subjects,
+			Workers:  consumer.Workers,
+			Topics:   consumer.Tasks,
+		}))
+	}
+
+	for taskType, subject := range natsConfig.Topology.Routes {
+		messagingConfig.Publisher = append(messagingConfig.Publisher, natsbroker.Route(taskType, subject))
+	}
+
+	return messagingConfig
+}
+
+// toRetryPolicy converts the configuration of a retry policy to the retry policy of the consumers
+func toRetryPolicy(policy config.RetryPolicy) messaging.RetryPolicy {
+	return messaging.RetryPolicy{
+		MaxRetries:   policy.MaxRetries,
+		InitialDelay: policy.InitialDelay,
+		MaxDelay:     policy.MaxDelay,
+		Multiplier:   policy.Multiplier,
+	}
+}
+
+func registerRoutes(app *fiber.App, skillQApp *skillqapp.App, cfg *config.Config, appLogger logger.Logger) {
+	checks := []health.Check{
+		{Name: "mongodb", Checker: skillQApp.UsersMongoDbClient, Timeout: cfg.Health.MongoDBTimeout},
+		{Name: "storage", Checker: skillQApp.StorageClient, Timeout: cfg.Health.StorageTimeout},
+	}
+
+	// RabbitMQ is only checked when it is the broker
+	if skillQApp.AmqpClient != nil {
+		checks = slices.Insert(checks, 1, health.Check{Name: "rabbitmq", Checker: skillQApp.AmqpClient, Timeout: cfg.Health.RabbitMQTimeout})
+	}
+
+	// NATS is likewise only checked when it is the broker
+	if skillQApp.NatsClient != nil {
+		checks = slices.Insert(checks, 1, health.Check{Name: "nats", Checker: skillQApp.NatsClient, Timeout: cfg.Health.NatsTimeout})
+	}
+
+	probesApi := probes.NewProbesApi(appLogger, checks...)
+	probesApi.RegisterHandlers(app)
+
+	metricsApi := metricsroutes.NewMetricsApi(metrics.Handler(skillQApp.MetricsRegistry))
+	metricsApi.RegisterHandlers(app)
+
+	docsApi, err := docs.NewDocsApi()
+	if err != nil {
+		appLogger.Fatalf("failed to load openapi spec: %v", err)
+	}
+	docsApi.RegisterHandlers(app)
+
+	authenticated := middleware.Authenticate(skillQApp.AuthSvc)
+
+	authApi := authv1.NewAuthApi(skillQApp.AuthSvc, appLogger)
+	authApi.RegisterHandlers(app)
+
+	// resending an email verification is public, so it is throttled per user & per client to stop emails being sent in bulk
+	throttleResend := middleware.RateLimit(skillQApp.RateLimitStore, middleware.RateLimitConfig{
+		Name:   "resend-verification",
+		Limit:  cfg.RateLimit.ResendVerification.Limit,
+		Window: cfg.RateLimit.ResendVerification.Window,
+		Keys:   []middleware.RateLimitKey{middleware.ByIP, middleware.ByParam("id")},
+	})
+
+	userApi := userv1.NewUserApi(usersvc.NewAuthorized(skillQApp.UserSvc, skillQApp.UserAuthorizer), skillQApp.UserVerificationSvc, appLogger)
+	userApi.RegisterHandlers(app, authenticated, throttleResend)
+}
+
+func prepareApp(mongoDbConfig mongodb.MongoDBConfig, amqpConfig amqp.Config, minioConfig minio.Config, emailConfig email.EmailClientConfig, authConfig authsvc.Config, userConfig usersvc.Config, verificationConfig usersvc.VerificationConfig, rateLimitConfig di.RateLimitConfig, outboxConfig outboxrelay.Config, messagingConfig di.MessagingConfig) (*skillqapp.App, error) {
+	app, err := skillqapp.InitApp(mongoDbConfig, amqpConfig, minioConfig, emailConfig, authConfig, userConfig, verificationConfig, rateLimitConfig, outboxConfig, messagingConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// the topology is declared before tasks are published or consumed
+	if err := app.EventConsumer.Declare(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to declare messaging topology: %w", err)
+	}
+
+	if err := app.UserSvc.BootstrapAdmin(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to bootstrap admin: %w", err)
+	}
+
+	return app, nil
 }
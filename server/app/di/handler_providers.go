@@ -8,21 +8,8 @@ import (
 	"github.com/BrianLusina/skillq/server/app/pkg/tasks"
 	"github.com/BrianLusina/skillq/server/infra/clients/email"
 	"github.com/BrianLusina/skillq/server/infra/logger"
-	amqppublisher "github.com/BrianLusina/skillq/server/infra/messaging/amqp/publisher"
-	"github.com/BrianLusina/skillq/server/infra/storage"
 )
 
-func ProvideStoreImageTaskHandler(
-	storageClient storage.StorageClient,
-	userRepo repositories.UserRepoPort,
-	publisher amqppublisher.AmqpEventPublisher,
-) handlers.EventHandler[tasks.StoreUserImage] {
-	log := logger.New()
-	storeImageTaskHandler := taskhandlers.NewStoreImageTaskHandler(storageClient, userRepo, publisher, log)
-
-	return storeImageTaskHandler
-}
-
 func ProvideSendEmailVerificationTaskHandler(
 	emailClient email.EmailClient,
 	userVerificationSvc inbound.UserVerificationService,
@@ -32,3 +19,15 @@ func ProvideSendEmailVerificationTaskHandler(
 	sendEmailVerificationTaskHandler := taskhandlers.NewSendEmailVerificationTaskHandler(emailClient, userVerificationSvc, userRepo, log)
 	return sendEmailVerificationTaskHandler
 }
+
+func ProvideSendPasswordResetTaskHandler(emailClient email.EmailClient, authSvc inbound.AuthService) handlers.EventHandler[tasks.SendPasswordReset] {
+	log := logger.New()
+	sendPasswordResetTaskHandler := taskhandlers.NewSendPasswordResetTaskHandler(emailClient, authSvc, log)
+	return sendPasswordResetTaskHandler
+}
+
+func ProvideSendEmailChangeNoticeTaskHandler(emailClient email.EmailClient) handlers.EventHandler[tasks.SendEmailChangeNotice] {
+	log := logger.New()
+	sendEmailChangeNoticeTaskHandler := taskhandlers.NewSendEmailChangeNoticeTaskHandler(emailClient, log)
+	return sendEmailChangeNoticeTaskHandler
+}
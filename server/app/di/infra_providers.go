@@ -1,24 +1,33 @@
 package di
 
 import (
+	"context"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/app/internal/database/migrations"
 	"github.com/BrianLusina/skillq/server/app/internal/database/models"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/services/usersvc"
+	"github.com/BrianLusina/skillq/server/app/internal/outboxrelay"
 	"github.com/BrianLusina/skillq/server/infra/clients/email"
 	"github.com/BrianLusina/skillq/server/infra/logger"
-	"github.com/BrianLusina/skillq/server/infra/messaging/amqp"
-	amqpconsumer "github.com/BrianLusina/skillq/server/infra/messaging/amqp/consumer"
-	amqppublisher "github.com/BrianLusina/skillq/server/infra/messaging/amqp/publisher"
+	"github.com/BrianLusina/skillq/server/infra/messaging"
 	"github.com/BrianLusina/skillq/server/infra/mongodb"
 	"github.com/BrianLusina/skillq/server/infra/storage/minio"
 	"github.com/google/wire"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// _migrationTimeout is how long the migrations of the database are given to be applied at startup
+const _migrationTimeout = 5 * time.Minute
+
 // Logger
 var LoggerSet = wire.NewSet(logger.New)
 
-// AMQP Client provider and AMQP Event publisher set
-var AmqpClientSet = wire.NewSet(amqp.NewAmqpClient)
-var AmqpEventPublisherSet = wire.NewSet(amqppublisher.NewPublisher)
-var AmqpEventConsumerSet = wire.NewSet(amqpconsumer.NewConsumer)
+// ProvideOutboxRelay creates a relay of the outbox that publishes its messages with the event publisher for injection
+func ProvideOutboxRelay(outboxRepo repositories.OutboxRepoPort, publisher messaging.EventPublisher, config outboxrelay.Config, log logger.Logger) *outboxrelay.Relay {
+	return outboxrelay.New(outboxRepo, publisher, config, log)
+}
 
 // Storage clients
 var StorageMinioClientSet = wire.NewSet(minio.NewClient)
@@ -27,24 +36,82 @@ var EmailClientSet = wire.NewSet(email.New)
 
 var UserVerificationMongoDbClient = wire.NewSet(mongodb.New[models.UserVerificationModel])
 
-func ProvideUserVerificationMongoDbClient(cfg mongodb.MongoDBConfig) mongodb.MongoDBClient[models.UserVerificationModel] {
+func ProvideUserVerificationMongoDbClient(cfg mongodb.MongoDBConfig, metrics *mongodb.Metrics, tracerProvider trace.TracerProvider) mongodb.MongoDBClient[models.UserVerificationModel] {
 	cfg.DBConfig.CollectionName = "user_verifications"
 	log := logger.New()
 	userVerificationMongoDbClient, err := mongodb.New[models.UserVerificationModel](cfg, log)
 	if err != nil {
 		panic(err)
 	}
-	return userVerificationMongoDbClient
+	return mongodb.NewTracedClient(mongodb.NewInstrumentedClient(userVerificationMongoDbClient, cfg.DBConfig.CollectionName, metrics), cfg.DBConfig.CollectionName, tracerProvider)
+}
+
+// ProvideUserVerificationAttemptsMongoDbClient creates a client of the collection of the attempts users have made at
+// verifying their email on the shared MongoDB connection for injection
+func ProvideUserVerificationAttemptsMongoDbClient(conn *mongodb.Connection, metrics *mongodb.Metrics, tracerProvider trace.TracerProvider) mongodb.MongoDBClient[models.UserVerificationAttemptsModel] {
+	collectionName := "user_verification_attempts"
+	attemptsMongoDbClient := mongodb.NewCollectionClient[models.UserVerificationAttemptsModel](conn, collectionName)
+	return mongodb.NewTracedClient(mongodb.NewInstrumentedClient(attemptsMongoDbClient, collectionName, metrics), collectionName, tracerProvider)
 }
 
 var UserMongoDbClientSet = wire.NewSet(mongodb.New[models.UserModel])
 
-func ProvideUserMongoDbClient(cfg mongodb.MongoDBConfig) mongodb.MongoDBClient[models.UserModel] {
+// ProvideMongoDbConnection connects to MongoDB for the collections that are written in the same transaction, which have to
+// share a connection, for injection. The migrations of the database are applied once it is connected, before the clients
+// of its collections create their indexes
+func ProvideMongoDbConnection(cfg mongodb.MongoDBConfig, verificationConfig usersvc.VerificationConfig) (*mongodb.Connection, error) {
+	log := logger.New()
+	conn, err := mongodb.Connect(cfg, log)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), _migrationTimeout)
+	defer cancel()
+
+	if err := conn.Migrate(ctx, migrations.All(migrations.Config{
+		VerificationCodeSecret: verificationConfig.CodeSecret,
+		VerificationCodeTTL:    verificationConfig.CodeTTL,
+	})...); err != nil {
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// ProvideMongoDbTransactor provides the transactions of the shared MongoDB connection for injection
+func ProvideMongoDbTransactor(conn *mongodb.Connection) repositories.TransactorPort {
+	return conn
+}
+
+func ProvideUserMongoDbClient(conn *mongodb.Connection, metrics *mongodb.Metrics, tracerProvider trace.TracerProvider) mongodb.MongoDBClient[models.UserModel] {
+	collectionName := "users"
+	userMongoDbClient := mongodb.NewCollectionClient[models.UserModel](conn, collectionName)
+	return mongodb.NewTracedClient(mongodb.NewInstrumentedClient(userMongoDbClient, collectionName, metrics), collectionName, tracerProvider)
+}
+
+// ProvideOutboxMongoDbClient creates a client of the outbox collection on the shared MongoDB connection, so that messages
+// are added to the outbox in the transactions of the users they follow from, for injection
+func ProvideOutboxMongoDbClient(conn *mongodb.Connection, metrics *mongodb.Metrics, tracerProvider trace.TracerProvider) mongodb.MongoDBClient[models.OutboxMessageModel] {
+	collectionName := "outbox"
+	outboxMongoDbClient := mongodb.NewCollectionClient[models.OutboxMessageModel](conn, collectionName)
+	return mongodb.NewTracedClient(mongodb.NewInstrumentedClient(outboxMongoDbClient, collectionName, metrics), collectionName, tracerProvider)
+}
+
+// ProvidePasswordResetMongoDbClient creates a client of the password reset collection on the shared MongoDB connection, so
+// that password resets are used in the transactions that update the passwords of their users, for injection
+func ProvidePasswordResetMongoDbClient(conn *mongodb.Connection, metrics *mongodb.Metrics, tracerProvider trace.TracerProvider) mongodb.MongoDBClient[models.PasswordResetModel] {
+	collectionName := "password_resets"
+	passwordResetMongoDbClient := mongodb.NewCollectionClient[models.PasswordResetModel](conn, collectionName)
+	return mongodb.NewTracedClient(mongodb.NewInstrumentedClient(passwordResetMongoDbClient, collectionName, metrics), collectionName, tracerProvider)
+}
+
+func ProvideRefreshTokenMongoDbClient(cfg mongodb.MongoDBConfig, metrics *mongodb.Metrics, tracerProvider trace.TracerProvider) mongodb.MongoDBClient[models.RefreshTokenModel] {
+	cfg.DBConfig.CollectionName = "refresh_tokens"
 	log := logger.New()
-	cfg.DBConfig.CollectionName = "users"
-	userMongoDbClient, err := mongodb.New[models.UserModel](cfg, log)
+	refreshTokenMongoDbClient, err := mongodb.New[models.RefreshTokenModel](cfg, log)
 	if err != nil {
 		panic(err)
 	}
-	return userMongoDbClient
+	return mongodb.NewTracedClient(mongodb.NewInstrumentedClient(refreshTokenMongoDbClient, cfg.DBConfig.CollectionName, metrics), cfg.DBConfig.CollectionName, tracerProvider)
 }
@@ -0,0 +1,136 @@
+package di
+
+import (
+	"fmt"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/messaging"
+	"github.com/BrianLusina/skillq/server/infra/messaging/amqp"
+	amqpconsumer "github.com/BrianLusina/skillq/server/infra/messaging/amqp/consumer"
+	amqppublisher "github.com/BrianLusina/skillq/server/infra/messaging/amqp/publisher"
+	memorybroker "github.com/BrianLusina/skillq/server/infra/messaging/memory"
+	natsbroker "github.com/BrianLusina/skillq/server/infra/messaging/nats"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// MessagingBrokerRabbitMQ selects RabbitMQ as the broker tasks are published to & consumed from
+	MessagingBrokerRabbitMQ = "rabbitmq"
+
+	// MessagingBrokerNats selects NATS JetStream as the broker tasks are published to & consumed from
+	MessagingBrokerNats = "nats"
+
+	// MessagingBrokerMemory selects the in-memory broker, which is only suitable for tests & a single instance of the
+	// service, as tasks are lost when it exits
+	MessagingBrokerMemory = "memory"
+)
+
+// MessagingConfig is the configuration of the broker tasks are published to & consumed from
+type MessagingConfig struct {
+	// Broker is the kind of broker to use, one of rabbitmq, nats or memory
+	Broker string
+
+	// Amqp is the configuration of the RabbitMQ publisher & consumer
+	Amqp AmqpMessagingConfig
+
+	// Nats is the configuration of the connection to NATS & of the NATS publisher & consumer
+	Nats NatsMessagingConfig
+
+	// Memory is the configuration of the in-memory publisher & consumer
+	Memory MemoryMessagingConfig
+}
+
+// AmqpMessagingConfig are the options the RabbitMQ publisher & consumer are configured with
+type AmqpMessagingConfig struct {
+	Publisher []amqppublisher.Option
+	Consumer  []amqpconsumer.Option
+}
+
+// NatsMessagingConfig is the connection to NATS & the options the NATS publisher & consumer are configured with
+type NatsMessagingConfig struct {
+	Client    natsbroker.Config
+	Publisher []natsbroker.PublisherOption
+	Consumer  []natsbroker.ConsumerOption
+}
+
+// MemoryMessagingConfig are the options the in-memory publisher & consumer are configured with
+type MemoryMessagingConfig struct {
+	Publisher []memorybroker.PublisherOption
+	Consumer  []memorybroker.ConsumerOption
+}
+
+// ProvideAmqpClient connects to RabbitMQ when it is the broker selected by the configuration for injection. There is no
+// client when another broker is selected
+func ProvideAmqpClient(config MessagingConfig, amqpConfig amqp.Config, log logger.Logger) (*amqp.AmqpClient, error) {
+	if config.Broker != MessagingBrokerRabbitMQ {
+		return nil, nil
+	}
+	return amqp.NewAmqpClient(amqpConfig, log)
+}
+
+// ProvideNatsClient connects to NATS when it is the broker selected by the configuration for injection. There is no client
+// when another broker is selected
+func ProvideNatsClient(config MessagingConfig, log logger.Logger) (*natsbroker.Client, error) {
+	if config.Broker != MessagingBrokerNats {
+		return nil, nil
+	}
+	return natsbroker.NewClient(config.Nats.Client, log)
+}
+
+// ProvideMemoryBroker creates the in-memory broker the in-memory publisher & consumer share for injection
+func ProvideMemoryBroker() *memorybroker.Broker {
+	return memorybroker.NewBroker()
+}
+
+// ProvideEventPublisher creates the publisher of the broker selected by the configuration for injection. The RabbitMQ
+// publisher records metrics & traces of the messages it publishes
+func ProvideEventPublisher(
+	config MessagingConfig,
+	client *amqp.AmqpClient,
+	natsClient *natsbroker.Client,
+	broker *memorybroker.Broker,
+	log logger.Logger,
+	metrics *amqppublisher.Metrics,
+	tracerProvider trace.TracerProvider,
+) (messaging.EventPublisher, error) {
+	switch config.Broker {
+	case MessagingBrokerRabbitMQ:
+		publisher, err := amqppublisher.NewPublisher(client, log)
+		if err != nil {
+			return nil, err
+		}
+		publisher.Configure(config.Amqp.Publisher...)
+		return amqppublisher.NewTracedPublisher(amqppublisher.NewInstrumentedPublisher(publisher, metrics), tracerProvider), nil
+	case MessagingBrokerNats:
+		return natsbroker.NewPublisher(natsClient, log, config.Nats.Publisher...), nil
+	case MessagingBrokerMemory:
+		return memorybroker.NewPublisher(broker, log, config.Memory.Publisher...), nil
+	default:
+		return nil, fmt.Errorf("unknown messaging broker %q", config.Broker)
+	}
+}
+
+// ProvideEventConsumer creates the consumer of the broker selected by the configuration for injection
+func ProvideEventConsumer(
+	config MessagingConfig,
+	client *amqp.AmqpClient,
+	natsClient *natsbroker.Client,
+	broker *memorybroker.Broker,
+	log logger.Logger,
+	metrics *amqpconsumer.Metrics,
+) (messaging.EventSubscriber, error) {
+	switch config.Broker {
+	case MessagingBrokerRabbitMQ:
+		consumer, err := amqpconsumer.NewConsumer(client, log, metrics)
+		if err != nil {
+			return nil, err
+		}
+		return consumer.Configure(config.Amqp.Consumer...), nil
+	case MessagingBrokerNats:
+		return natsbroker.NewConsumer(natsClient, log, config.Nats.Consumer...), nil
+	case MessagingBrokerMemory:
+		return memorybroker.NewConsumer(broker, log, config.Memory.Consumer...), nil
+	default:
+		return nil, fmt.Errorf("unknown messaging broker %q", config.Broker)
+	}
+}
@@ -0,0 +1,22 @@
+package di
+
+import (
+	amqpconsumer "github.com/BrianLusina/skillq/server/infra/messaging/amqp/consumer"
+	amqppublisher "github.com/BrianLusina/skillq/server/infra/messaging/amqp/publisher"
+	"github.com/BrianLusina/skillq/server/infra/metrics"
+	"github.com/BrianLusina/skillq/server/infra/mongodb"
+	"github.com/google/wire"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// MetricsRegistrySet provides the registry every metric of the application is registered with
+var MetricsRegistrySet = wire.NewSet(metrics.NewRegistry, wire.Bind(new(prometheus.Registerer), new(*prometheus.Registry)))
+
+// MongoDBMetricsSet provides the metrics shared by the mongo DB clients of every collection
+var MongoDBMetricsSet = wire.NewSet(mongodb.NewMetrics)
+
+// AmqpPublisherMetricsSet provides the metrics of published messages
+var AmqpPublisherMetricsSet = wire.NewSet(amqppublisher.NewMetrics)
+
+// AmqpConsumerMetricsSet provides the metrics of consumed deliveries
+var AmqpConsumerMetricsSet = wire.NewSet(amqpconsumer.NewMetrics)
@@ -2,19 +2,25 @@ package di
 
 import (
 	publisherPort "github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/publishers"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories"
 	"github.com/BrianLusina/skillq/server/app/internal/publishers"
 	"github.com/BrianLusina/skillq/server/app/pkg/tasks"
-	amqppublisher "github.com/BrianLusina/skillq/server/infra/messaging/amqp/publisher"
 )
 
-// ProvideSendEmailTaskPublisher is used to create a send email verification task publisher for dependency injection
-func ProvideSendEmailTaskPublisher(pub amqppublisher.AmqpEventPublisher) publisherPort.TaskPublisher[tasks.SendEmailVerification] {
-	sendEmailTaskPublisher := publishers.NewSendEmailTaskPublisher(pub)
-	return sendEmailTaskPublisher
+// ProvideSendEmailTaskPublisher is used to create a send email verification task publisher, which adds the tasks to the
+// outbox, for dependency injection
+func ProvideSendEmailTaskPublisher(outboxRepo repositories.OutboxRepoPort) publisherPort.TaskPublisher[tasks.SendEmailVerification] {
+	return publishers.NewOutboxTaskPublisher[tasks.SendEmailVerification](outboxRepo, tasks.SendEmailVerificationName)
 }
 
-// ProvideStoreImageTaskPublisher creates a store user image task publisher for injection
-func ProvideStoreImageTaskPublisher(pub amqppublisher.AmqpEventPublisher) publisherPort.TaskPublisher[tasks.StoreUserImage] {
-	storeImageTaskPublisher := publishers.NewStoreImageTaskPublisher(pub)
-	return storeImageTaskPublisher
+// ProvideSendPasswordResetTaskPublisher creates a send password reset task publisher, which adds the tasks to the outbox,
+// for injection
+func ProvideSendPasswordResetTaskPublisher(outboxRepo repositories.OutboxRepoPort) publisherPort.TaskPublisher[tasks.SendPasswordReset] {
+	return publishers.NewOutboxTaskPublisher[tasks.SendPasswordReset](outboxRepo, tasks.SendPasswordResetName)
+}
+
+// ProvideSendEmailChangeNoticeTaskPublisher creates a send email change notice task publisher, which adds the tasks to the
+// outbox, for injection
+func ProvideSendEmailChangeNoticeTaskPublisher(outboxRepo repositories.OutboxRepoPort) publisherPort.TaskPublisher[tasks.SendEmailChangeNotice] {
+	return publishers.NewOutboxTaskPublisher[tasks.SendEmailChangeNotice](outboxRepo, tasks.SendEmailChangeNoticeName)
 }
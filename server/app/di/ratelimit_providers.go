@@ -0,0 +1,39 @@
+package di
+
+import (
+	"fmt"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/ratelimit"
+	"github.com/BrianLusina/skillq/server/infra/ratelimit/memory"
+	ratelimitredis "github.com/BrianLusina/skillq/server/infra/ratelimit/redis"
+)
+
+const (
+	// RateLimitStoreRedis selects the redis rate limit store, which is shared between instances of the service
+	RateLimitStoreRedis = "redis"
+
+	// RateLimitStoreMemory selects the in-memory rate limit store, which is only suitable for a single instance of the service
+	RateLimitStoreMemory = "memory"
+)
+
+// RateLimitConfig is the configuration of the store used to rate limit requests
+type RateLimitConfig struct {
+	// Store is the kind of store to use, either redis or memory
+	Store string
+
+	// Redis is the configuration of the redis store
+	Redis ratelimitredis.Config
+}
+
+// ProvideRateLimitStore creates the rate limit store selected by the configuration for injection
+func ProvideRateLimitStore(config RateLimitConfig, log logger.Logger) (ratelimit.Store, error) {
+	switch config.Store {
+	case RateLimitStoreRedis:
+		return ratelimitredis.NewStore(config.Redis, log)
+	case RateLimitStoreMemory:
+		return memory.NewStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q", config.Store)
+	}
+}
@@ -0,0 +1,16 @@
+package di
+
+import (
+	"github.com/google/wire"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ProvideTracerProvider provides the global tracer provider for injection. Tracers created from it before tracing is set
+// up create their spans with the tracer provider that is set up later
+func ProvideTracerProvider() trace.TracerProvider {
+	return otel.GetTracerProvider()
+}
+
+// TracingSet provides the tracer provider the spans of the application are created with
+var TracingSet = wire.NewSet(ProvideTracerProvider)
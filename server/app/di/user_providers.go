@@ -1,14 +1,24 @@
 package di
 
 import (
+	outboxrepo "github.com/BrianLusina/skillq/server/app/internal/database/repositories/outbox"
+	passwordresetrepo "github.com/BrianLusina/skillq/server/app/internal/database/repositories/passwordreset"
+	refreshtokenrepo "github.com/BrianLusina/skillq/server/app/internal/database/repositories/refreshtoken"
 	"github.com/BrianLusina/skillq/server/app/internal/database/repositories/userrepo"
 	userverificationrepo "github.com/BrianLusina/skillq/server/app/internal/database/repositories/userverification"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/services/authsvc"
 	"github.com/BrianLusina/skillq/server/app/internal/domain/services/usersvc"
 	"github.com/google/wire"
 )
 
 var UserServiceSet = wire.NewSet(usersvc.New)
 var UserRepositoryAdapterSet = wire.NewSet(userrepo.New)
+var OutboxRepositoryAdapterSet = wire.NewSet(outboxrepo.New)
 
 var UserVerificationServiceSet = wire.NewSet(usersvc.NewVerification)
 var UserVerificationRepositoryAdapterSet = wire.NewSet(userverificationrepo.New)
+
+var AuthServiceSet = wire.NewSet(authsvc.New)
+var UserAuthorizerSet = wire.NewSet(authsvc.NewUserAuthorizer)
+var RefreshTokenRepositoryAdapterSet = wire.NewSet(refreshtokenrepo.New)
+var PasswordResetRepositoryAdapterSet = wire.NewSet(passwordresetrepo.New)
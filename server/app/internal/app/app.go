@@ -1,25 +1,23 @@
 package app
 
 import (
-	"context"
-	"encoding/json"
-
 	"github.com/BrianLusina/skillq/server/app/internal/database/models"
 	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound"
 	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/publishers"
 	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories"
 	"github.com/BrianLusina/skillq/server/app/internal/handlers"
+	"github.com/BrianLusina/skillq/server/app/internal/outboxrelay"
 	"github.com/BrianLusina/skillq/server/app/pkg/tasks"
 	"github.com/BrianLusina/skillq/server/infra/clients/email"
 	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/messaging"
 	"github.com/BrianLusina/skillq/server/infra/messaging/amqp"
-	amqpconsumer "github.com/BrianLusina/skillq/server/infra/messaging/amqp/consumer"
-	amqppublisher "github.com/BrianLusina/skillq/server/infra/messaging/amqp/publisher"
+	natsbroker "github.com/BrianLusina/skillq/server/infra/messaging/nats"
 	"github.com/BrianLusina/skillq/server/infra/mongodb"
+	"github.com/BrianLusina/skillq/server/infra/ratelimit"
 	"github.com/BrianLusina/skillq/server/infra/storage"
 	"github.com/BrianLusina/skillq/server/infra/storage/minio"
-
-	rabbitmq "github.com/rabbitmq/amqp091-go"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 type (
@@ -35,12 +33,16 @@ type (
 
 		UserRepo repositories.UserRepoPort
 
-		AmqpClient         *amqp.AmqpClient
-		AmqpEventPublisher amqppublisher.AmqpEventPublisher
-		AmqpEventConsumer  amqpconsumer.AmqpEventConsumer
+		// AmqpClient is the connection to RabbitMQ, which there is none of unless it is the broker tasks are published to
+		AmqpClient *amqp.AmqpClient
+
+		// NatsClient is the connection to NATS, which there is none of unless it is the broker tasks are published to
+		NatsClient *natsbroker.Client
+
+		EventPublisher messaging.EventPublisher
+		EventConsumer  messaging.EventSubscriber
 
-		SendEmailTaskPublisher  publishers.TaskPublisher[tasks.SendEmailVerification]
-		StoreImageTaskPublisher publishers.TaskPublisher[tasks.StoreUserImage]
+		SendEmailTaskPublisher publishers.TaskPublisher[tasks.SendEmailVerification]
 
 		StorageClient storage.StorageClient
 
@@ -51,9 +53,30 @@ type (
 		UserVerificationSvc           inbound.UserVerificationService
 
 		SendEmailVerificationTaskHandler handlers.EventHandler[tasks.SendEmailVerification]
-		StoreImageTaskHandler            handlers.EventHandler[tasks.StoreUserImage]
 
 		EmailClient email.EmailClient
+
+		RefreshTokenMongoDbClient mongodb.MongoDBClient[models.RefreshTokenModel]
+		RefreshTokenRepo          repositories.RefreshTokenRepoPort
+		AuthSvc                   inbound.AuthService
+		UserAuthorizer            inbound.UserAuthorizer
+
+		PasswordResetMongoDbClient     mongodb.MongoDBClient[models.PasswordResetModel]
+		PasswordResetRepo              repositories.PasswordResetRepoPort
+		SendPasswordResetTaskPublisher publishers.TaskPublisher[tasks.SendPasswordReset]
+		SendPasswordResetTaskHandler   handlers.EventHandler[tasks.SendPasswordReset]
+
+		SendEmailChangeNoticeTaskPublisher publishers.TaskPublisher[tasks.SendEmailChangeNotice]
+		SendEmailChangeNoticeTaskHandler   handlers.EventHandler[tasks.SendEmailChangeNotice]
+
+		RateLimitStore ratelimit.Store
+
+		MetricsRegistry *prometheus.Registry
+
+		MongoDbConnection   *mongodb.Connection
+		OutboxMongoDbClient mongodb.MongoDBClient[models.OutboxMessageModel]
+		OutboxRepo          repositories.OutboxRepoPort
+		OutboxRelay         *outboxrelay.Relay
 	}
 )
 
@@ -67,11 +90,11 @@ func New(
 	logger logger.Logger,
 
 	amqpClient *amqp.AmqpClient,
-	amqpEventPublisher amqppublisher.AmqpEventPublisher,
-	amqpEventConsumer amqpconsumer.AmqpEventConsumer,
+	natsClient *natsbroker.Client,
+	eventPublisher messaging.EventPublisher,
+	eventConsumer messaging.EventSubscriber,
 
 	sendEmailEventPublisher publishers.TaskPublisher[tasks.SendEmailVerification],
-	storeImageEventPublisher publishers.TaskPublisher[tasks.StoreUserImage],
 
 	storageClient storage.StorageClient,
 
@@ -85,23 +108,43 @@ func New(
 
 	sendEmailVerificationHandler handlers.EventHandler[tasks.SendEmailVerification],
 
-	storeImageTaskHandler handlers.EventHandler[tasks.StoreUserImage],
-
 	emailClient email.EmailClient,
+
+	refreshTokenMongoDbClient mongodb.MongoDBClient[models.RefreshTokenModel],
+	refreshTokenRepo repositories.RefreshTokenRepoPort,
+	authSvc inbound.AuthService,
+	userAuthorizer inbound.UserAuthorizer,
+
+	passwordResetMongoDbClient mongodb.MongoDBClient[models.PasswordResetModel],
+	passwordResetRepo repositories.PasswordResetRepoPort,
+	sendPasswordResetTaskPublisher publishers.TaskPublisher[tasks.SendPasswordReset],
+	sendPasswordResetTaskHandler handlers.EventHandler[tasks.SendPasswordReset],
+
+	sendEmailChangeNoticeTaskPublisher publishers.TaskPublisher[tasks.SendEmailChangeNotice],
+	sendEmailChangeNoticeTaskHandler handlers.EventHandler[tasks.SendEmailChangeNotice],
+
+	rateLimitStore ratelimit.Store,
+
+	metricsRegistry *prometheus.Registry,
+
+	mongoDbConnection *mongodb.Connection,
+	outboxMongoDbClient mongodb.MongoDBClient[models.OutboxMessageModel],
+	outboxRepo repositories.OutboxRepoPort,
+	outboxRelay *outboxrelay.Relay,
 ) *App {
-	return &App{
+	app := &App{
 		MongoDbConfig:      mongodbConfig,
 		AmqpConfig:         amqpConfig,
 		MinioConfig:        minioConfig,
 		Logger:             logger,
 		UsersMongoDbClient: usersMongoDbClient,
 
-		AmqpClient:         amqpClient,
-		AmqpEventPublisher: amqpEventPublisher,
-		AmqpEventConsumer:  amqpEventConsumer,
+		AmqpClient:     amqpClient,
+		NatsClient:     natsClient,
+		EventPublisher: eventPublisher,
+		EventConsumer:  eventConsumer,
 
-		SendEmailTaskPublisher:  sendEmailEventPublisher,
-		StoreImageTaskPublisher: storeImageEventPublisher,
+		SendEmailTaskPublisher: sendEmailEventPublisher,
 
 		StorageClient: storageClient,
 
@@ -113,64 +156,41 @@ func New(
 		UserVerificationSvc:           userVerificationService,
 
 		SendEmailVerificationTaskHandler: sendEmailVerificationHandler,
-		StoreImageTaskHandler:            storeImageTaskHandler,
 
 		EmailClient: emailClient,
+
+		RefreshTokenMongoDbClient: refreshTokenMongoDbClient,
+		RefreshTokenRepo:          refreshTokenRepo,
+		AuthSvc:                   authSvc,
+		UserAuthorizer:            userAuthorizer,
+
+		PasswordResetMongoDbClient:     passwordResetMongoDbClient,
+		PasswordResetRepo:              passwordResetRepo,
+		SendPasswordResetTaskPublisher: sendPasswordResetTaskPublisher,
+		SendPasswordResetTaskHandler:   sendPasswordResetTaskHandler,
+
+		SendEmailChangeNoticeTaskPublisher: sendEmailChangeNoticeTaskPublisher,
+		SendEmailChangeNoticeTaskHandler:   sendEmailChangeNoticeTaskHandler,
+
+		RateLimitStore: rateLimitStore,
+
+		MetricsRegistry: metricsRegistry,
+
+		MongoDbConnection:   mongoDbConnection,
+		OutboxMongoDbClient: outboxMongoDbClient,
+		OutboxRepo:          outboxRepo,
+		OutboxRelay:         outboxRelay,
 	}
+
+	app.registerTaskHandlers()
+
+	return app
 }
 
-func (app *App) Worker(ctx context.Context, messages <-chan rabbitmq.Delivery) {
-	for message := range messages {
-		app.Logger.Infof("Processing message with Tag %d & Type %s", message.DeliveryTag, message.Type)
-
-		switch message.Type {
-		case string(tasks.SendEmailVerificationName):
-			var payload tasks.SendEmailVerification
-
-			err := json.Unmarshal(message.Body, &payload)
-			if err != nil {
-				app.Logger.Errorf("Failed to Unmarshal message: %s", err)
-			}
-
-			err = app.SendEmailVerificationTaskHandler.Handle(ctx, &payload)
-
-			if err != nil {
-				if err = message.Reject(false); err != nil {
-					app.Logger.Error("Failed to delivery.Reject with error %s", err.Error())
-				}
-
-				app.Logger.Error("Failed to process delivery with error %s", err.Error())
-			} else {
-				err = message.Ack(false)
-				if err != nil {
-					app.Logger.Error("Failed to acknowledge delivery with error %s", err.Error())
-				}
-			}
-
-		case string(tasks.StoreUserImageTaskName):
-			var payload tasks.StoreUserImage
-
-			err := json.Unmarshal(message.Body, &payload)
-			if err != nil {
-				app.Logger.Errorf("Failed to Unmarshal message: %s", err)
-			}
-
-			err = app.StoreImageTaskHandler.Handle(ctx, &payload)
-
-			if err != nil {
-				if err = message.Reject(false); err != nil {
-					app.Logger.Errorf("Failed to delivery.Reject with err: %s", err)
-				}
-
-				app.Logger.Error("Failed to process delivery with err: %s", err)
-			} else {
-				err = message.Ack(false)
-				if err != nil {
-					app.Logger.Errorf("Failed to acknowledge delivery with err: %s", err)
-				}
-			}
-		default:
-			app.Logger.Info("default")
-		}
-	}
+// registerTaskHandlers registers the handlers of the tasks the app consumes with the consumer, which parks tasks that have
+// no handler registered. A new task only needs its handler registered here to be consumed
+func (app *App) registerTaskHandlers() {
+	handlers.Register(app.EventConsumer, string(tasks.SendEmailVerificationName), app.SendEmailVerificationTaskHandler)
+	handlers.Register(app.EventConsumer, string(tasks.SendPasswordResetName), app.SendPasswordResetTaskHandler)
+	handlers.Register(app.EventConsumer, string(tasks.SendEmailChangeNoticeName), app.SendEmailChangeNoticeTaskHandler)
 }
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+
+	"github.com/BrianLusina/skillq/server/infra/lifecycle"
+)
+
+// Components are the components of the app in the order they are started. They are stopped in reverse order, so the
+// consumer & the outbox relay stop before the broker connection is closed & the connections to the databases are closed
+// last
+func (app *App) Components() []lifecycle.Component {
+	components := []lifecycle.Component{
+		{
+			Name: "mongodb",
+			Stop: func(ctx context.Context) error {
+				// the users, outbox, password reset & verification attempts collections share the connection, which is
+				// disconnected once
+				return errors.Join(
+					app.MongoDbConnection.Disconnect(ctx),
+					app.UserVerificationMongoDbClient.Disconnect(ctx),
+					app.RefreshTokenMongoDbClient.Disconnect(ctx),
+				)
+			},
+		},
+		{
+			Name: "outbox relay",
+			Run:  app.OutboxRelay.Run,
+			Stop: app.OutboxRelay.Stop,
+		},
+		{
+			Name: "consumer",
+			Run:  app.EventConsumer.Start,
+			Stop: app.EventConsumer.Stop,
+		},
+	}
+
+	// the connection to RabbitMQ is only opened when it is the broker, & is closed after the consumer & the outbox relay
+	if app.AmqpClient != nil {
+		components = slices.Insert(components, 1, lifecycle.Component{
+			Name: "rabbitmq",
+			Stop: func(ctx context.Context) error {
+				return app.AmqpClient.Close()
+			},
+		})
+	}
+
+	// likewise for the connection to NATS
+	if app.NatsClient != nil {
+		components = slices.Insert(components, 1, lifecycle.Component{
+			Name: "nats",
+			Stop: func(ctx context.Context) error {
+				return app.NatsClient.Close()
+			},
+		})
+	}
+
+	// only some rate limit stores hold a connection that has to be closed
+	if closer, ok := app.RateLimitStore.(io.Closer); ok {
+		components = append([]lifecycle.Component{{
+			Name: "rate limit store",
+			Stop: func(ctx context.Context) error {
+				return closer.Close()
+			},
+		}}, components...)
+	}
+
+	return components
+}
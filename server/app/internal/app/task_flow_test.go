@@ -0,0 +1,259 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/app/di"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/outbox"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/user"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/services/usersvc"
+	"github.com/BrianLusina/skillq/server/app/internal/handlers/taskhandlers"
+	"github.com/BrianLusina/skillq/server/app/internal/outboxrelay"
+	"github.com/BrianLusina/skillq/server/app/pkg/tasks"
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/domain/id"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	memorybroker "github.com/BrianLusina/skillq/server/infra/messaging/memory"
+	"github.com/BrianLusina/skillq/server/infra/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// fakeUserRepo keeps users in memory
+type fakeUserRepo struct {
+	repositories.UserRepoPort
+
+	mu    sync.Mutex
+	users map[id.UUID]user.User
+}
+
+func (f *fakeUserRepo) CreateUser(_ context.Context, u user.User) (*user.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.users[u.UUID()] = u
+	return &u, nil
+}
+
+func (f *fakeUserRepo) GetUserByUUID(_ context.Context, userID id.UUID) (*user.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	u, ok := f.users[userID]
+	if !ok {
+		return nil, errors.New("user not found")
+	}
+	return &u, nil
+}
+
+func (f *fakeUserRepo) UpdateUser(ctx context.Context, request repositories.UpdateUserRequest) (*user.User, error) {
+	return f.GetUserByUUID(ctx, request.UserID)
+}
+
+// fakeTransactor runs transactions without a database
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// fakeOutboxRepo keeps the outbox in memory, where messages are due until they are dispatched, unless they are claimed
+// for a lease that has not passed
+type fakeOutboxRepo struct {
+	mu         sync.Mutex
+	messages   []outbox.Message
+	dispatched map[string]bool
+	leases     map[string]time.Time
+}
+
+func (f *fakeOutboxRepo) AddMessage(_ context.Context, message outbox.Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.messages = append(f.messages, message)
+	return nil
+}
+
+func (f *fakeOutboxRepo) ClaimDueMessage(_ context.Context, now time.Time, lease time.Duration) (outbox.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, message := range f.messages {
+		if f.dispatched[message.ID()] || now.Before(f.leases[message.ID()]) {
+			continue
+		}
+		f.leases[message.ID()] = now.Add(lease)
+		return message, nil
+	}
+	return outbox.Message{}, errdefs.NewNotFoundError("no outbox message is due", nil)
+}
+
+func (f *fakeOutboxRepo) MarkMessageDispatched(_ context.Context, messageID string, _ time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.dispatched[messageID] = true
+	return nil
+}
+
+func (f *fakeOutboxRepo) MarkMessageFailed(context.Context, string, time.Time, string) error {
+	return nil
+}
+
+// fakeUserVerificationRepo keeps the verifications it creates in memory
+type fakeUserVerificationRepo struct {
+	repositories.UserVerificationRepoPort
+
+	mu            sync.Mutex
+	verifications []user.UserVerification
+}
+
+func (f *fakeUserVerificationRepo) CreateUserVerification(_ context.Context, verification user.UserVerification) (*user.UserVerification, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.verifications = append(f.verifications, verification)
+	return &verification, nil
+}
+
+func (f *fakeUserVerificationRepo) codes() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var codes []string
+	for _, verification := range f.verifications {
+		codes = append(codes, verification.Code())
+	}
+	return codes
+}
+
+// sentEmail is an email sent by the fake email client
+type sentEmail struct {
+	to   string
+	body string
+}
+
+// fakeEmailClient sends emails to a channel
+type fakeEmailClient struct {
+	sent chan sentEmail
+}
+
+func (f *fakeEmailClient) Send(to string, body []byte) error {
+	f.sent <- sentEmail{to: to, body: string(body)}
+	return nil
+}
+
+// fakeStorageClient stores nothing, as no image is uploaded in the flow
+type fakeStorageClient struct {
+	storage.StorageClient
+}
+
+func TestTaskFlow(t *testing.T) {
+	log, _ := logger.NewTestLogger()
+
+	// the topology of the in-memory broker is the topology tasks are routed by on RabbitMQ
+	messagingConfig := di.MessagingConfig{
+		Broker: di.MessagingBrokerMemory,
+		Memory: di.MemoryMessagingConfig{
+			Publisher: []memorybroker.PublisherOption{
+				memorybroker.Route(string(tasks.SendEmailVerificationName), "send-email-exchange", "send-email-routing-key"),
+			},
+			Consumer: []memorybroker.ConsumerOption{
+				memorybroker.Exchange(memorybroker.ExchangeParams{Name: "send-email-exchange", Kind: memorybroker.ExchangeFanout}),
+				memorybroker.Subscribe(memorybroker.SubscriptionParams{
+					Queue:    "send-email-queue",
+					Bindings: []memorybroker.BindingParams{{Exchange: "send-email-exchange", RoutingKey: "send-email-routing-key"}},
+					Workers:  2,
+					Topics:   []string{string(tasks.SendEmailVerificationName)},
+				}),
+			},
+		},
+	}
+
+	broker := di.ProvideMemoryBroker()
+	eventPublisher, err := di.ProvideEventPublisher(messagingConfig, nil, nil, broker, log, nil, nil)
+	require.NoError(t, err)
+	eventConsumer, err := di.ProvideEventConsumer(messagingConfig, nil, nil, broker, log, nil)
+	require.NoError(t, err)
+
+	userRepo := &fakeUserRepo{users: map[id.UUID]user.User{}}
+	outboxRepo := &fakeOutboxRepo{dispatched: map[string]bool{}, leases: map[string]time.Time{}}
+	userVerificationRepo := &fakeUserVerificationRepo{}
+	emailClient := &fakeEmailClient{sent: make(chan sentEmail, 1)}
+
+	sendEmailTaskPublisher := di.ProvideSendEmailTaskPublisher(outboxRepo)
+	userSvc := usersvc.New(usersvc.Config{}, userRepo, fakeTransactor{}, sendEmailTaskPublisher, di.ProvideSendEmailChangeNoticeTaskPublisher(outboxRepo), fakeStorageClient{}, noop.NewTracerProvider())
+	userVerificationSvc, err := usersvc.NewVerification(usersvc.VerificationConfig{CodeLength: 6, CodeTTL: time.Hour, CodeSecret: "secret", MaxAttempts: 5, AttemptsWindow: time.Hour}, userSvc, userVerificationRepo, sendEmailTaskPublisher)
+	require.NoError(t, err)
+
+	app := &App{
+		EventPublisher:                   eventPublisher,
+		EventConsumer:                    eventConsumer,
+		UserSvc:                          userSvc,
+		UserVerificationSvc:              userVerificationSvc,
+		SendEmailVerificationTaskHandler: taskhandlers.NewSendEmailVerificationTaskHandler(emailClient, userVerificationSvc, userRepo, log),
+		SendPasswordResetTaskHandler:     taskhandlers.NewSendPasswordResetTaskHandler(emailClient, nil, log),
+		SendEmailChangeNoticeTaskHandler: taskhandlers.NewSendEmailChangeNoticeTaskHandler(emailClient, log),
+		OutboxRelay:                      outboxrelay.New(outboxRepo, eventPublisher, outboxrelay.Config{PollInterval: 10 * time.Millisecond, BatchSize: 10}, log),
+	}
+	app.registerTaskHandlers()
+
+	require.NoError(t, app.EventConsumer.Declare(context.Background()))
+
+	consumed, relayed := make(chan error, 1), make(chan error, 1)
+	go func() { consumed <- app.EventConsumer.Start() }()
+	go func() { relayed <- app.OutboxRelay.Run() }()
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		require.NoError(t, app.OutboxRelay.Stop(ctx))
+		require.NoError(t, app.EventConsumer.Stop(ctx))
+		require.NoError(t, <-relayed)
+		require.NoError(t, <-consumed)
+	})
+
+	t.Run("sends an email verification to a user that is created", func(t *testing.T) {
+		created, err := app.UserSvc.CreateUser(context.Background(), inbound.UserRequest{
+			Name:     "Jane Doe",
+			Email:    "jane@example.com",
+			Password: "S3cure-password",
+			Skills:   []string{"go"},
+			JobTitle: "Engineer",
+		})
+		require.NoError(t, err)
+
+		select {
+		case sent := <-emailClient.sent:
+			assert.Equal(t, "jane@example.com", sent.to)
+
+			codes := userVerificationRepo.codes()
+			require.Len(t, codes, 1)
+			assert.True(t, strings.Contains(sent.body, codes[0]), "email has the verification code")
+			assert.True(t, strings.Contains(sent.body, created.UUID), "email has the user ID")
+		case <-time.After(5 * time.Second):
+			t.Fatal("email verification was not sent")
+		}
+
+		// the task of the user is relayed & handled, leaving nothing to dead-letter
+		assert.Eventually(t, func() bool {
+			outboxRepo.mu.Lock()
+			defer outboxRepo.mu.Unlock()
+			return len(outboxRepo.dispatched) == 1
+		}, time.Second, 10*time.Millisecond)
+		for _, queue := range []string{"send-email-queue", "send-email-queue.dlq"} {
+			n, err := broker.Len(queue)
+			require.NoError(t, err)
+			assert.Zero(t, n, "deliveries in queue %s", queue)
+		}
+	})
+}
@@ -5,6 +5,9 @@ package app
 
 import (
 	"github.com/BrianLusina/skillq/server/app/di"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/services/authsvc"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/services/usersvc"
+	"github.com/BrianLusina/skillq/server/app/internal/outboxrelay"
 	"github.com/BrianLusina/skillq/server/infra/clients/email"
 	"github.com/BrianLusina/skillq/server/infra/messaging/amqp"
 	"github.com/BrianLusina/skillq/server/infra/mongodb"
@@ -18,24 +21,52 @@ func InitApp(
 	amqpConfig amqp.Config,
 	minioConfig minio.Config,
 	emailConfig email.EmailClientConfig,
+	authConfig authsvc.Config,
+	userConfig usersvc.Config,
+	verificationConfig usersvc.VerificationConfig,
+	rateLimitConfig di.RateLimitConfig,
+	outboxConfig outboxrelay.Config,
+	messagingConfig di.MessagingConfig,
 ) (*App, error) {
 	panic(wire.Build(
 		New,
 		di.LoggerSet,
+		di.ProvideMongoDbConnection,
+		di.ProvideMongoDbTransactor,
 		di.ProvideUserMongoDbClient,
+		di.ProvideOutboxMongoDbClient,
+		di.OutboxRepositoryAdapterSet,
+		di.ProvideOutboxRelay,
 		di.UserRepositoryAdapterSet,
-		di.AmqpClientSet,
-		di.AmqpEventPublisherSet,
+		di.ProvideAmqpClient,
+		di.ProvideNatsClient,
+		di.ProvideMemoryBroker,
+		di.ProvideEventPublisher,
 		di.ProvideSendEmailTaskPublisher,
-		di.ProvideStoreImageTaskPublisher,
+		di.ProvideSendEmailChangeNoticeTaskPublisher,
+		di.ProvideSendEmailChangeNoticeTaskHandler,
 		di.StorageMinioClientSet,
 		di.UserServiceSet,
 		di.ProvideUserVerificationMongoDbClient,
+		di.ProvideUserVerificationAttemptsMongoDbClient,
 		di.UserVerificationRepositoryAdapterSet,
-		di.AmqpEventConsumerSet,
+		di.ProvideEventConsumer,
 		di.UserVerificationServiceSet,
 		di.ProvideSendEmailVerificationTaskHandler,
 		di.EmailClientSet,
-		di.ProvideStoreImageTaskHandler,
+		di.ProvideRefreshTokenMongoDbClient,
+		di.RefreshTokenRepositoryAdapterSet,
+		di.ProvidePasswordResetMongoDbClient,
+		di.PasswordResetRepositoryAdapterSet,
+		di.ProvideSendPasswordResetTaskPublisher,
+		di.ProvideSendPasswordResetTaskHandler,
+		di.AuthServiceSet,
+		di.UserAuthorizerSet,
+		di.ProvideRateLimitStore,
+		di.MetricsRegistrySet,
+		di.MongoDBMetricsSet,
+		di.AmqpPublisherMetricsSet,
+		di.AmqpConsumerMetricsSet,
+		di.TracingSet,
 	))
 }
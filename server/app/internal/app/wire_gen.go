@@ -8,14 +8,20 @@ package app
 
 import (
 	"github.com/BrianLusina/skillq/server/app/di"
+	"github.com/BrianLusina/skillq/server/app/internal/database/repositories/outbox"
+	"github.com/BrianLusina/skillq/server/app/internal/database/repositories/passwordreset"
+	"github.com/BrianLusina/skillq/server/app/internal/database/repositories/refreshtoken"
 	"github.com/BrianLusina/skillq/server/app/internal/database/repositories/userrepo"
 	"github.com/BrianLusina/skillq/server/app/internal/database/repositories/userverification"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/services/authsvc"
 	"github.com/BrianLusina/skillq/server/app/internal/domain/services/usersvc"
+	"github.com/BrianLusina/skillq/server/app/internal/outboxrelay"
 	"github.com/BrianLusina/skillq/server/infra/clients/email"
 	"github.com/BrianLusina/skillq/server/infra/logger"
 	"github.com/BrianLusina/skillq/server/infra/messaging/amqp"
 	"github.com/BrianLusina/skillq/server/infra/messaging/amqp/consumer"
 	"github.com/BrianLusina/skillq/server/infra/messaging/amqp/publisher"
+	"github.com/BrianLusina/skillq/server/infra/metrics"
 	"github.com/BrianLusina/skillq/server/infra/mongodb"
 	"github.com/BrianLusina/skillq/server/infra/storage/minio"
 )
@@ -23,35 +29,72 @@ import (
 // Injectors from wire.go:
 
 // InitApp initializes the user application
-func InitApp(mongodbConfig mongodb.MongoDBConfig, amqpConfig amqp.Config, minioConfig minio.Config, emailConfig email.EmailClientConfig) (*App, error) {
+func InitApp(mongodbConfig mongodb.MongoDBConfig, amqpConfig amqp.Config, minioConfig minio.Config, emailConfig email.EmailClientConfig, authConfig authsvc.Config, userConfig usersvc.Config, verificationConfig usersvc.VerificationConfig, rateLimitConfig di.RateLimitConfig, outboxConfig outboxrelay.Config, messagingConfig di.MessagingConfig) (*App, error) {
 	loggerLogger := logger.New()
-	amqpClient, err := amqp.NewAmqpClient(amqpConfig, loggerLogger)
+	amqpClient, err := di.ProvideAmqpClient(messagingConfig, amqpConfig, loggerLogger)
 	if err != nil {
 		return nil, err
 	}
-	amqpEventPublisher, err := amqppublisher.NewPublisher(amqpClient, loggerLogger)
+	client, err := di.ProvideNatsClient(messagingConfig, loggerLogger)
 	if err != nil {
 		return nil, err
 	}
-	amqpEventConsumer, err := amqpconsumer.NewConsumer(amqpClient, loggerLogger)
+	broker := di.ProvideMemoryBroker()
+	registry := metrics.NewRegistry()
+	amqppublisherMetrics := amqppublisher.NewMetrics(registry)
+	tracerProvider := di.ProvideTracerProvider()
+	eventPublisher, err := di.ProvideEventPublisher(messagingConfig, amqpClient, client, broker, loggerLogger, amqppublisherMetrics, tracerProvider)
 	if err != nil {
 		return nil, err
 	}
-	taskPublisher := di.ProvideSendEmailTaskPublisher(amqpEventPublisher)
-	publishersTaskPublisher := di.ProvideStoreImageTaskPublisher(amqpEventPublisher)
+	amqpconsumerMetrics := amqpconsumer.NewMetrics(registry)
+	eventSubscriber, err := di.ProvideEventConsumer(messagingConfig, amqpClient, client, broker, loggerLogger, amqpconsumerMetrics)
+	if err != nil {
+		return nil, err
+	}
+	connection, err := di.ProvideMongoDbConnection(mongodbConfig, verificationConfig)
+	if err != nil {
+		return nil, err
+	}
+	mongodbMetrics := mongodb.NewMetrics(registry)
+	mongoDBClient := di.ProvideOutboxMongoDbClient(connection, mongodbMetrics, tracerProvider)
+	outboxRepoPort := outboxrepo.New(mongoDBClient)
+	taskPublisher := di.ProvideSendEmailTaskPublisher(outboxRepoPort)
+	taskPublisher2 := di.ProvideSendEmailChangeNoticeTaskPublisher(outboxRepoPort)
 	storageClient, err := minio.NewClient(minioConfig, loggerLogger)
 	if err != nil {
 		return nil, err
 	}
-	mongoDBClient := di.ProvideUserMongoDbClient(mongodbConfig)
-	userRepoPort := userrepo.New(mongoDBClient)
-	userService := usersvc.New(userRepoPort, taskPublisher, publishersTaskPublisher, storageClient)
-	mongodbMongoDBClient := di.ProvideUserVerificationMongoDbClient(mongodbConfig)
-	userVerificationRepoPort := userverificationrepo.New(mongodbMongoDBClient)
-	userVerificationService := usersvc.NewVerification(userService, userVerificationRepoPort)
+	mongodbMongoDBClient := di.ProvideUserMongoDbClient(connection, mongodbMetrics, tracerProvider)
+	userRepoPort := userrepo.New(mongodbMongoDBClient)
+	transactorPort := di.ProvideMongoDbTransactor(connection)
+	userService := usersvc.New(userConfig, userRepoPort, transactorPort, taskPublisher, taskPublisher2, storageClient, tracerProvider)
+	mongoDBClient2 := di.ProvideUserVerificationMongoDbClient(mongodbConfig, mongodbMetrics, tracerProvider)
+	mongoDBClient3 := di.ProvideUserVerificationAttemptsMongoDbClient(connection, mongodbMetrics, tracerProvider)
+	userVerificationRepoPort := userverificationrepo.New(mongoDBClient2, mongoDBClient3)
+	userVerificationService, err := usersvc.NewVerification(verificationConfig, userService, userVerificationRepoPort, taskPublisher)
+	if err != nil {
+		return nil, err
+	}
 	emailClient := email.New(emailConfig, loggerLogger)
 	eventHandler := di.ProvideSendEmailVerificationTaskHandler(emailClient, userVerificationService, userRepoPort)
-	handlersEventHandler := di.ProvideStoreImageTaskHandler(storageClient, userRepoPort, amqpEventPublisher)
-	app := New(mongodbConfig, amqpConfig, minioConfig, emailConfig, loggerLogger, amqpClient, amqpEventPublisher, amqpEventConsumer, taskPublisher, publishersTaskPublisher, storageClient, userRepoPort, mongoDBClient, userService, mongodbMongoDBClient, userVerificationRepoPort, userVerificationService, eventHandler, handlersEventHandler, emailClient)
+	mongoDBClient4 := di.ProvideRefreshTokenMongoDbClient(mongodbConfig, mongodbMetrics, tracerProvider)
+	refreshTokenRepoPort := refreshtokenrepo.New(mongoDBClient4)
+	mongoDBClient5 := di.ProvidePasswordResetMongoDbClient(connection, mongodbMetrics, tracerProvider)
+	passwordResetRepoPort := passwordresetrepo.New(mongoDBClient5)
+	taskPublisher3 := di.ProvideSendPasswordResetTaskPublisher(outboxRepoPort)
+	authService, err := authsvc.New(authConfig, userRepoPort, refreshTokenRepoPort, passwordResetRepoPort, transactorPort, taskPublisher3)
+	if err != nil {
+		return nil, err
+	}
+	userAuthorizer := authsvc.NewUserAuthorizer(userRepoPort)
+	eventHandler2 := di.ProvideSendPasswordResetTaskHandler(emailClient, authService)
+	eventHandler3 := di.ProvideSendEmailChangeNoticeTaskHandler(emailClient)
+	store, err := di.ProvideRateLimitStore(rateLimitConfig, loggerLogger)
+	if err != nil {
+		return nil, err
+	}
+	relay := di.ProvideOutboxRelay(outboxRepoPort, eventPublisher, outboxConfig, loggerLogger)
+	app := New(mongodbConfig, amqpConfig, minioConfig, emailConfig, loggerLogger, amqpClient, client, eventPublisher, eventSubscriber, taskPublisher, storageClient, userRepoPort, mongodbMongoDBClient, userService, mongoDBClient2, userVerificationRepoPort, userVerificationService, eventHandler, emailClient, mongoDBClient4, refreshTokenRepoPort, authService, userAuthorizer, mongoDBClient5, passwordResetRepoPort, taskPublisher3, eventHandler2, taskPublisher2, eventHandler3, store, registry, connection, mongoDBClient, outboxRepoPort, relay)
 	return app, nil
 }
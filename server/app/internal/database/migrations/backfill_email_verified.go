@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/BrianLusina/skillq/server/infra/mongodb"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// backfillEmailVerified marks the emails of users created before the verified state was kept on users as verified. These
+// users had no emailVerified field, which is read as false, so they were asked to verify the email they already use & could
+// not be made the bootstrap admin
+var backfillEmailVerified = mongodb.Migration{
+	ID: "0003_backfill_email_verified",
+	Up: func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("users").UpdateMany(ctx,
+			bson.M{"emailVerified": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"emailVerified": true}},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to backfill the verified state of user emails: %w", err)
+		}
+
+		return nil
+	},
+}
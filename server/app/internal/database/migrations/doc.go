@@ -0,0 +1,2 @@
+// Package migrations contains the migrations of the documents of the MongoDB database, which are applied in order at startup
+package migrations
@@ -0,0 +1,87 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/mongodb"
+	"github.com/BrianLusina/skillq/server/utils/security"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// legacyVerification is a user verification written before only the hash of its code was stored
+type legacyVerification struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	UserId    string             `bson:"user_id"`
+	Code      string             `bson:"code"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	ExpiresAt *time.Time         `bson:"expires_at"`
+}
+
+// hashVerificationCodes brings the user verifications written by earlier versions in line with how their codes are checked
+// now:
+//   - verifications that stored their plain code, which can no longer be matched since codes are looked up by their hash,
+//     get the hash of their code & an expiry from when they were created, & their plain code is removed
+//   - codes that were hashed without a secret can not be rehashed, so the verifications that have not been verified with
+//     them are expired & their users have to request a new code
+//   - failed attempts are no longer counted per verification, so their attempts are removed
+func hashVerificationCodes(config Config) mongodb.Migration {
+	return mongodb.Migration{
+		ID: "0002_hash_verification_codes",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			collection := db.Collection("user_verifications")
+			now := time.Now()
+
+			// codes hashed without a secret are expired first, before the plain codes are hashed with it
+			_, err := collection.UpdateMany(ctx,
+				bson.M{"code_hash": bson.M{"$exists": true}, "is_verified": false, "expires_at": bson.M{"$gt": now}},
+				bson.M{"$set": bson.M{"expires_at": now, "updatedAt": now}},
+			)
+			if err != nil {
+				return fmt.Errorf("failed to expire verification codes hashed without a secret: %w", err)
+			}
+
+			cursor, err := collection.Find(ctx, bson.M{"code": bson.M{"$exists": true}})
+			if err != nil {
+				return fmt.Errorf("failed to find verifications with plain codes: %w", err)
+			}
+			defer cursor.Close(ctx)
+
+			for cursor.Next(ctx) {
+				var verification legacyVerification
+				if err := cursor.Decode(&verification); err != nil {
+					return fmt.Errorf("failed to decode verification with a plain code: %w", err)
+				}
+
+				expiresAt := verification.CreatedAt.Add(config.VerificationCodeTTL)
+				if verification.ExpiresAt != nil {
+					expiresAt = *verification.ExpiresAt
+				}
+
+				_, err := collection.UpdateByID(ctx, verification.ID, bson.M{
+					"$set": bson.M{
+						"code_hash":  security.HashCode(config.VerificationCodeSecret, verification.UserId, verification.Code),
+						"expires_at": expiresAt,
+					},
+					"$unset": bson.M{"code": ""},
+				})
+				if err != nil {
+					return fmt.Errorf("failed to hash the code of verification %s: %w", verification.ID.Hex(), err)
+				}
+			}
+			if err := cursor.Err(); err != nil {
+				return fmt.Errorf("failed to iterate verifications with plain codes: %w", err)
+			}
+
+			_, err = collection.UpdateMany(ctx, bson.M{"attempts": bson.M{"$exists": true}}, bson.M{"$unset": bson.M{"attempts": ""}})
+			if err != nil {
+				return fmt.Errorf("failed to remove the attempts of verifications: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/BrianLusina/skillq/server/infra/mongodb"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// liftInlineBaseModel moves the fields of the base model of users & user verifications to the top level of their
+// documents. The base model used to be embedded under an "inline" key, so documents written before it was inlined keep
+// their keyId, uuid & timestamps there, where they are neither read nor matched by the key ID pagination
+var liftInlineBaseModel = mongodb.Migration{
+	ID: "0001_lift_inline_base_model",
+	Up: func(ctx context.Context, db *mongo.Database) error {
+		filter := bson.M{"inline": bson.M{"$exists": true}}
+		// fields already at the top level take precedence over the ones under the inline key, which keeps the _id
+		pipeline := mongo.Pipeline{
+			{{Key: "$replaceRoot", Value: bson.M{"newRoot": bson.M{"$mergeObjects": bson.A{"$inline", "$$ROOT"}}}}},
+			{{Key: "$unset", Value: "inline"}},
+		}
+
+		for _, collection := range []string{"users", "user_verifications"} {
+			if _, err := db.Collection(collection).UpdateMany(ctx, filter, pipeline); err != nil {
+				return fmt.Errorf("failed to lift base model of %s: %w", collection, err)
+			}
+		}
+
+		return nil
+	},
+}
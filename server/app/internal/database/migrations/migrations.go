@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/mongodb"
+)
+
+// Config is the configuration of the application that migrations rewrite documents with
+type Config struct {
+	// VerificationCodeSecret is the secret the codes of user verifications are hashed with
+	VerificationCodeSecret string
+
+	// VerificationCodeTTL is how long the codes of user verifications are valid for after they are issued
+	VerificationCodeTTL time.Duration
+}
+
+// All returns the migrations of the database in the order they are applied. New migrations are appended, existing ones
+// are never reordered or removed
+func All(config Config) []mongodb.Migration {
+	return []mongodb.Migration{
+		liftInlineBaseModel,
+		hashVerificationCodes(config),
+		backfillEmailVerified,
+	}
+}
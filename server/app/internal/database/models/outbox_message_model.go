@@ -0,0 +1,37 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// OutboxMessagePending is the status of an outbox message that has not been relayed yet
+	OutboxMessagePending = "pending"
+
+	// OutboxMessageInFlight is the status of an outbox message that a relay has claimed & is relaying. Its next attempt
+	// time is when the lease of the relay passes, after which it can be claimed again
+	OutboxMessageInFlight = "in_flight"
+
+	// OutboxMessageDispatched is the status of an outbox message that has been relayed
+	OutboxMessageDispatched = "dispatched"
+)
+
+// OutboxMessageModel represents the model of an outbox message as stored in a database
+type OutboxMessageModel struct {
+	BaseModel     BaseModel         `bson:",inline"`
+	Topic         string            `bson:"topic"`
+	ContentType   string            `bson:"contentType"`
+	Payload       []byte            `bson:"payload"`
+	Headers       map[string]string `bson:"headers,omitempty"`
+	Status        string            `bson:"status"`
+	Attempts      int               `bson:"attempts"`
+	NextAttemptAt time.Time         `bson:"nextAttemptAt"`
+	LastError     string            `bson:"lastError,omitempty"`
+	DispatchedAt  *time.Time        `bson:"dispatchedAt,omitempty"`
+}
+
+func (m *OutboxMessageModel) String() string {
+	return fmt.Sprintf("OutboxMessageModel(base=%s, topic=%s, status=%s, attempts=%d, nextAttemptAt=%s, dispatchedAt=%v)",
+		m.BaseModel.String(), m.Topic, m.Status, m.Attempts, m.NextAttemptAt, m.DispatchedAt)
+}
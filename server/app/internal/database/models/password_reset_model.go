@@ -0,0 +1,20 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// PasswordResetModel represents the model of a password reset as stored in a database. Only the hash of the token is stored
+type PasswordResetModel struct {
+	BaseModel BaseModel  `bson:",inline"`
+	UserId    string     `bson:"user_id"`
+	TokenHash string     `bson:"token_hash"`
+	ExpiresAt time.Time  `bson:"expires_at"`
+	UsedAt    *time.Time `bson:"used_at"`
+}
+
+func (r *PasswordResetModel) String() string {
+	return fmt.Sprintf("PasswordResetModel(base=%s, userId=%s, expiresAt=%s, usedAt=%v)",
+		r.BaseModel.String(), r.UserId, r.ExpiresAt, r.UsedAt)
+}
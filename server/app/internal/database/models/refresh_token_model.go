@@ -0,0 +1,20 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// RefreshTokenModel represents the model of a refresh token as stored in a database
+type RefreshTokenModel struct {
+	BaseModel BaseModel  `bson:",inline"`
+	UserId    string     `bson:"user_id"`
+	TokenHash string     `bson:"token_hash"`
+	ExpiresAt time.Time  `bson:"expires_at"`
+	RevokedAt *time.Time `bson:"revoked_at"`
+}
+
+func (r *RefreshTokenModel) String() string {
+	return fmt.Sprintf("RefreshTokenModel(base=%s, userId=%s, expiresAt=%s, revokedAt=%v)",
+		r.BaseModel.String(), r.UserId, r.ExpiresAt, r.RevokedAt)
+}
@@ -2,17 +2,33 @@ package models
 
 import (
 	"fmt"
+	"time"
 )
 
-// UserVerificationModel represents the model of a user verification as stored in a database
+// UserVerificationModel represents the model of a user verification as stored in a database. Only the hash of the code is
+// stored
 type UserVerificationModel struct {
-	BaseModel  BaseModel `bson:"inline"`
-	Code       string    `bson:"code"`
+	BaseModel  BaseModel `bson:",inline"`
+	CodeHash   string    `bson:"code_hash"`
 	UserId     string    `bson:"user_id"`
+	Email      string    `bson:"email"`
 	IsVerified bool      `bson:"is_verified"`
+	ExpiresAt  time.Time `bson:"expires_at"`
 }
 
 func (u *UserVerificationModel) String() string {
-	return fmt.Sprintf("UserVerificationModel(base=%s, code=%s, userId=%s, isVerified=%v)",
-		u.BaseModel.String(), u.Code, u.UserId, u.IsVerified)
+	return fmt.Sprintf("UserVerificationModel(base=%s, userId=%s, email=%s, isVerified=%v, expiresAt=%s)",
+		u.BaseModel.String(), u.UserId, u.Email, u.IsVerified, u.ExpiresAt)
+}
+
+// UserVerificationAttemptsModel represents the attempts a user has made at verifying their email as stored in a database.
+// There is one document per user, keyed by the user ID, which is removed once it expires
+type UserVerificationAttemptsModel struct {
+	UserId    string    `bson:"_id"`
+	Attempts  int       `bson:"attempts"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+func (u *UserVerificationAttemptsModel) String() string {
+	return fmt.Sprintf("UserVerificationAttemptsModel(userId=%s, attempts=%d, expiresAt=%s)", u.UserId, u.Attempts, u.ExpiresAt)
 }
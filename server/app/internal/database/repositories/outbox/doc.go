@@ -0,0 +1,2 @@
+// Package outboxrepo contains repo adapter implementation for the outbox
+package outboxrepo
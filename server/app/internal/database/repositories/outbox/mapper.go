@@ -0,0 +1,48 @@
+package outboxrepo
+
+import (
+	"github.com/BrianLusina/skillq/server/app/internal/database/models"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/outbox"
+)
+
+// mapMessageToModel maps an outbox message to an outbox message model
+func mapMessageToModel(message outbox.Message) models.OutboxMessageModel {
+	status := models.OutboxMessagePending
+	if message.IsDispatched() {
+		status = models.OutboxMessageDispatched
+	}
+
+	return models.OutboxMessageModel{
+		BaseModel: models.BaseModel{
+			UUID:      message.ID(),
+			CreatedAt: message.CreatedAt(),
+			UpdatedAt: message.UpdatedAt(),
+		},
+		Topic:         message.Topic(),
+		ContentType:   message.ContentType(),
+		Payload:       message.Payload(),
+		Headers:       message.Headers(),
+		Status:        status,
+		Attempts:      message.Attempts(),
+		NextAttemptAt: message.NextAttemptAt(),
+		LastError:     message.LastError(),
+		DispatchedAt:  message.DispatchedAt(),
+	}
+}
+
+// mapModelToMessage maps an outbox message model to an outbox message
+func mapModelToMessage(model models.OutboxMessageModel) outbox.Message {
+	return outbox.NewMessage(outbox.MessageParams{
+		ID:            model.BaseModel.UUID,
+		Topic:         model.Topic,
+		ContentType:   model.ContentType,
+		Payload:       model.Payload,
+		Headers:       model.Headers,
+		Attempts:      model.Attempts,
+		NextAttemptAt: model.NextAttemptAt,
+		LastError:     model.LastError,
+		DispatchedAt:  model.DispatchedAt,
+		CreatedAt:     model.BaseModel.CreatedAt,
+		UpdatedAt:     model.BaseModel.UpdatedAt,
+	})
+}
@@ -0,0 +1,172 @@
+package outboxrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/app/internal/database/models"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/outbox"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories"
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/mongodb"
+	"github.com/pkg/errors"
+)
+
+// outboxRepoAdapter is the outbox repository adapter structure for managing outbox messages
+type outboxRepoAdapter struct {
+	// dbClient is the database client used to handle connections to the database
+	dbClient mongodb.MongoDBClient[models.OutboxMessageModel]
+}
+
+var _ repositories.OutboxRepoPort = (*outboxRepoAdapter)(nil)
+
+// dispatchedRetention is how long messages are kept in the outbox after they are dispatched, so that they can be looked
+// into for a while before they are removed
+const dispatchedRetention = 7 * 24 * time.Hour
+
+// New creates a new outbox repository adapter
+func New(dbClient mongodb.MongoDBClient[models.OutboxMessageModel]) repositories.OutboxRepoPort {
+	defer func() {
+		ctx := context.Background()
+		name, err := dbClient.CreateIndex(ctx, mongodb.IndexParam{
+			Keys: []mongodb.KeyParam{
+				{
+					Key:   "uuid",
+					Value: 1,
+				},
+			},
+			Name:   "outbox_uuid_idx",
+			Unique: true,
+		})
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Failed to create index 'outbox_uuid_idx': %v", err)
+			return
+		}
+		logger.FromContext(ctx).Infof("Successfully created index %s", name)
+	}()
+
+	defer func() {
+		// due messages are claimed by their status & next attempt time
+		ctx := context.Background()
+		name, err := dbClient.CreateIndex(ctx, mongodb.IndexParam{
+			Keys: []mongodb.KeyParam{
+				{
+					Key:   "status",
+					Value: 1,
+				},
+				{
+					Key:   "nextAttemptAt",
+					Value: 1,
+				},
+			},
+			Name: "outbox_status_next_attempt_at_idx",
+		})
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Failed to create index 'outbox_status_next_attempt_at_idx': %v", err)
+			return
+		}
+		logger.FromContext(ctx).Infof("Successfully created index %s", name)
+	}()
+
+	defer func() {
+		// dispatched messages are removed once they have been kept for the retention, while pending messages, which have
+		// no dispatched at time, are kept until they are dispatched
+		ctx := context.Background()
+		expireAfter := dispatchedRetention
+		name, err := dbClient.CreateIndex(ctx, mongodb.IndexParam{
+			Keys: []mongodb.KeyParam{
+				{
+					Key:   "dispatchedAt",
+					Value: 1,
+				},
+			},
+			Name:        "outbox_dispatched_at_idx",
+			ExpireAfter: &expireAfter,
+		})
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Failed to create index 'outbox_dispatched_at_idx': %v", err)
+			return
+		}
+		logger.FromContext(ctx).Infof("Successfully created index %s", name)
+	}()
+
+	return &outboxRepoAdapter{
+		dbClient: dbClient,
+	}
+}
+
+// AddMessage adds a pending message to the outbox
+func (repo *outboxRepoAdapter) AddMessage(ctx context.Context, message outbox.Message) error {
+	if _, err := repo.dbClient.Insert(ctx, mapMessageToModel(message)); err != nil {
+		return errors.Wrapf(err, "failed to add message %s to outbox", message.ID())
+	}
+
+	return nil
+}
+
+// ClaimDueMessage claims the pending message that has been due to be relayed the longest at the given time for the lease,
+// by atomically marking it in flight until the lease passes. Messages that are in flight are due again once their lease
+// passes, so that messages claimed by a relay that stopped are relayed by another
+func (repo *outboxRepoAdapter) ClaimDueMessage(ctx context.Context, now time.Time, lease time.Duration) (outbox.Message, error) {
+	messageModel, err := repo.dbClient.FindOneAndUpdate(ctx, mongodb.UpdateOptions{
+		FilterParams: mongodb.FilterParams{
+			Key:   "status",
+			Value: map[string]any{"$in": []string{models.OutboxMessagePending, models.OutboxMessageInFlight}},
+		},
+		Conditions: []mongodb.FilterParams{
+			{Key: "nextAttemptAt", Value: map[string]any{"$lte": now}},
+		},
+		FieldOptions: map[string]any{
+			"status":        models.OutboxMessageInFlight,
+			"nextAttemptAt": now.Add(lease),
+			"updatedAt":     now,
+		},
+		Sort: []mongodb.KeyParam{{Key: "nextAttemptAt", Value: 1}},
+	})
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return outbox.Message{}, errdefs.NewNotFoundError("no outbox message is due", err)
+		}
+		return outbox.Message{}, errors.Wrapf(err, "failed to claim due outbox message")
+	}
+
+	return mapModelToMessage(messageModel), nil
+}
+
+// MarkMessageDispatched marks a message as relayed at the given time, so that it is not relayed again
+func (repo *outboxRepoAdapter) MarkMessageDispatched(ctx context.Context, messageID string, dispatchedAt time.Time) error {
+	err := repo.dbClient.Update(ctx, models.OutboxMessageModel{}, mongodb.UpdateOptions{
+		FilterParams: mongodb.FilterParams{Key: "uuid", Value: messageID},
+		FieldOptions: map[string]any{
+			"status":       models.OutboxMessageDispatched,
+			"dispatchedAt": dispatchedAt,
+			"updatedAt":    dispatchedAt,
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to mark outbox message %s dispatched", messageID)
+	}
+
+	return nil
+}
+
+// MarkMessageFailed records a failed attempt to relay a message, which is pending again & relayed from the next attempt
+// time
+func (repo *outboxRepoAdapter) MarkMessageFailed(ctx context.Context, messageID string, nextAttemptAt time.Time, reason string) error {
+	err := repo.dbClient.Update(ctx, models.OutboxMessageModel{}, mongodb.UpdateOptions{
+		FilterParams: mongodb.FilterParams{Key: "uuid", Value: messageID},
+		FieldOptions: map[string]any{
+			"status":        models.OutboxMessagePending,
+			"nextAttemptAt": nextAttemptAt,
+			"lastError":     reason,
+			"updatedAt":     time.Now(),
+		},
+		IncOptions: map[string]int{"attempts": 1},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to mark outbox message %s failed", messageID)
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package outboxrepo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/app/internal/database/models"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/outbox"
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/infra/mongodb"
+	mockmongodb "github.com/BrianLusina/skillq/server/infra/mongodb/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOutboxRepoAdapter(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockDbClient := mockmongodb.NewMockMongoDBClient[models.OutboxMessageModel](mockCtrl)
+	outboxRepositoryAdapter := outboxRepoAdapter{dbClient: mockDbClient}
+	mockDbClient.EXPECT().CreateIndex(gomock.Any(), gomock.Any()).Return("outbox_idx", nil).Times(3)
+	adapter := New(mockDbClient)
+	assert.NotNil(t, adapter)
+
+	ctx := context.Background()
+	now := time.Now()
+
+	newMessage := func(id string, nextAttemptAt time.Time) outbox.Message {
+		return outbox.NewMessage(outbox.MessageParams{
+			ID:            id,
+			Topic:         "SendEmailVerification",
+			ContentType:   "text/plain",
+			Payload:       []byte(`{"email":"jane@example.com"}`),
+			NextAttemptAt: nextAttemptAt,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+	}
+
+	t.Run("adding a message", func(t *testing.T) {
+		t.Run("should insert the message as pending", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			message := newMessage("message-1", now)
+			mockDbClient.EXPECT().Insert(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, model models.OutboxMessageModel) (primitive.ObjectID, error) {
+				assert.Equal(t, "message-1", model.BaseModel.UUID)
+				assert.Equal(t, models.OutboxMessagePending, model.Status)
+				assert.Nil(t, model.DispatchedAt)
+				return primitive.NewObjectID(), nil
+			}).Times(1)
+
+			assert.NoError(t, outboxRepositoryAdapter.AddMessage(ctx, message))
+		})
+
+		t.Run("should return error when there is a failure to insert the message", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			mockDbClient.EXPECT().Insert(ctx, gomock.Any()).Return(primitive.ObjectID{}, errors.New("failed")).Times(1)
+
+			assert.Error(t, outboxRepositoryAdapter.AddMessage(ctx, newMessage("message-1", now)))
+		})
+	})
+
+	t.Run("claiming a due message", func(t *testing.T) {
+		t.Run("should mark the message that has been due the longest in flight for the lease", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			due := mapMessageToModel(newMessage("message-1", now.Add(-time.Minute)))
+
+			mockDbClient.EXPECT().FindOneAndUpdate(ctx, mongodb.UpdateOptions{
+				FilterParams: mongodb.FilterParams{
+					Key:   "status",
+					Value: map[string]any{"$in": []string{models.OutboxMessagePending, models.OutboxMessageInFlight}},
+				},
+				Conditions: []mongodb.FilterParams{
+					{Key: "nextAttemptAt", Value: map[string]any{"$lte": now}},
+				},
+				FieldOptions: map[string]any{
+					"status":        models.OutboxMessageInFlight,
+					"nextAttemptAt": now.Add(time.Minute),
+					"updatedAt":     now,
+				},
+				Sort: []mongodb.KeyParam{{Key: "nextAttemptAt", Value: 1}},
+			}).Return(due, nil).Times(1)
+
+			message, err := outboxRepositoryAdapter.ClaimDueMessage(ctx, now, time.Minute)
+			assert.NoError(t, err)
+			assert.Equal(t, "message-1", message.ID())
+		})
+
+		t.Run("should return not found error when no message is due", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			mockDbClient.EXPECT().FindOneAndUpdate(ctx, gomock.Any()).Return(models.OutboxMessageModel{}, errdefs.NewNotFoundError("no documents", nil)).Times(1)
+
+			_, err := outboxRepositoryAdapter.ClaimDueMessage(ctx, now, time.Minute)
+			assert.True(t, errdefs.IsNotFound(err))
+		})
+
+		t.Run("should return error when there is a failure to claim a message", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			mockDbClient.EXPECT().FindOneAndUpdate(ctx, gomock.Any()).Return(models.OutboxMessageModel{}, errors.New("failed")).Times(1)
+
+			_, err := outboxRepositoryAdapter.ClaimDueMessage(ctx, now, time.Minute)
+			assert.Error(t, err)
+			assert.False(t, errdefs.IsNotFound(err))
+		})
+	})
+
+	t.Run("marking a message dispatched", func(t *testing.T) {
+		defer mockCtrl.Finish()
+
+		mockDbClient.EXPECT().Update(ctx, models.OutboxMessageModel{}, mongodb.UpdateOptions{
+			FilterParams: mongodb.FilterParams{Key: "uuid", Value: "message-1"},
+			FieldOptions: map[string]any{
+				"status":       models.OutboxMessageDispatched,
+				"dispatchedAt": now,
+				"updatedAt":    now,
+			},
+		}).Return(nil).Times(1)
+
+		assert.NoError(t, outboxRepositoryAdapter.MarkMessageDispatched(ctx, "message-1", now))
+	})
+
+	t.Run("marking a message failed", func(t *testing.T) {
+		defer mockCtrl.Finish()
+
+		nextAttemptAt := now.Add(time.Minute)
+		mockDbClient.EXPECT().Update(ctx, models.OutboxMessageModel{}, gomock.Any()).DoAndReturn(func(_ context.Context, _ models.OutboxMessageModel, options mongodb.UpdateOptions) error {
+			assert.Equal(t, mongodb.FilterParams{Key: "uuid", Value: "message-1"}, options.FilterParams)
+			assert.Equal(t, models.OutboxMessagePending, options.FieldOptions["status"])
+			assert.Equal(t, nextAttemptAt, options.FieldOptions["nextAttemptAt"])
+			assert.Equal(t, "broker unavailable", options.FieldOptions["lastError"])
+			assert.Equal(t, map[string]int{"attempts": 1}, options.IncOptions)
+			return nil
+		}).Times(1)
+
+		assert.NoError(t, outboxRepositoryAdapter.MarkMessageFailed(ctx, "message-1", nextAttemptAt, "broker unavailable"))
+	})
+}
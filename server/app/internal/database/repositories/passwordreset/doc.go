@@ -0,0 +1,2 @@
+// Package passwordresetrepo contains repo adapter implementation for password resets
+package passwordresetrepo
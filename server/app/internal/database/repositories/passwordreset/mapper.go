@@ -0,0 +1,45 @@
+package passwordresetrepo
+
+import (
+	"github.com/BrianLusina/skillq/server/app/internal/database/models"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/auth"
+	"github.com/BrianLusina/skillq/server/domain/id"
+)
+
+// mapPasswordResetToModel maps a password reset entity to a password reset model
+func mapPasswordResetToModel(passwordReset auth.PasswordReset) models.PasswordResetModel {
+	return models.PasswordResetModel{
+		BaseModel: models.BaseModel{
+			UUID:      passwordReset.ID().String(),
+			CreatedAt: passwordReset.CreatedAt(),
+			UpdatedAt: passwordReset.UpdatedAt(),
+		},
+		UserId:    passwordReset.UserID().String(),
+		TokenHash: passwordReset.TokenHash(),
+		ExpiresAt: passwordReset.ExpiresAt(),
+		UsedAt:    passwordReset.UsedAt(),
+	}
+}
+
+// mapPasswordResetModelToEntity maps a password reset model to a password reset entity
+func mapPasswordResetModelToEntity(passwordResetModel models.PasswordResetModel) (auth.PasswordReset, error) {
+	uuid, err := id.StringToUUID(passwordResetModel.BaseModel.UUID)
+	if err != nil {
+		return auth.PasswordReset{}, err
+	}
+
+	userId, err := id.StringToUUID(passwordResetModel.UserId)
+	if err != nil {
+		return auth.PasswordReset{}, err
+	}
+
+	return auth.NewPasswordReset(auth.PasswordResetParams{
+		ID:        uuid,
+		UserId:    userId,
+		TokenHash: passwordResetModel.TokenHash,
+		ExpiresAt: passwordResetModel.ExpiresAt,
+		UsedAt:    passwordResetModel.UsedAt,
+		CreatedAt: passwordResetModel.BaseModel.CreatedAt,
+		UpdatedAt: passwordResetModel.BaseModel.UpdatedAt,
+	}), nil
+}
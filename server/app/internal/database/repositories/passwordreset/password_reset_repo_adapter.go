@@ -0,0 +1,107 @@
+package passwordresetrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/app/internal/database/models"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/auth"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories"
+	"github.com/BrianLusina/skillq/server/domain/id"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/mongodb"
+	"github.com/pkg/errors"
+)
+
+// passwordResetRepoAdapter is the password reset repository adapter structure for managing password reset data
+type passwordResetRepoAdapter struct {
+	// dbClient is the database client used to handle connections to the database
+	dbClient mongodb.MongoDBClient[models.PasswordResetModel]
+}
+
+var _ repositories.PasswordResetRepoPort = (*passwordResetRepoAdapter)(nil)
+
+// New creates a new password reset repository adapter
+func New(dbClient mongodb.MongoDBClient[models.PasswordResetModel]) repositories.PasswordResetRepoPort {
+	defer func() {
+		ctx := context.Background()
+		name, err := dbClient.CreateIndex(ctx, mongodb.IndexParam{
+			Keys: []mongodb.KeyParam{
+				{
+					Key:   "token_hash",
+					Value: 1,
+				},
+			},
+			Name:   "password_reset_token_hash_idx",
+			Unique: true,
+		})
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Failed to create index 'password_reset_token_hash_idx': %v", err)
+			return
+		}
+		logger.FromContext(ctx).Infof("Successfully created index %s", name)
+	}()
+
+	return &passwordResetRepoAdapter{
+		dbClient: dbClient,
+	}
+}
+
+// CreatePasswordReset creates a password reset in the repository
+func (repo *passwordResetRepoAdapter) CreatePasswordReset(ctx context.Context, passwordReset auth.PasswordReset) (*auth.PasswordReset, error) {
+	passwordResetModel := mapPasswordResetToModel(passwordReset)
+	_, err := repo.dbClient.Insert(ctx, passwordResetModel)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create password reset")
+	}
+
+	r, err := mapPasswordResetModelToEntity(passwordResetModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// GetPasswordResetByHash retrieves a password reset given the hash of its token
+func (repo *passwordResetRepoAdapter) GetPasswordResetByHash(ctx context.Context, tokenHash string) (*auth.PasswordReset, error) {
+	passwordResetModel, err := repo.dbClient.FindById(ctx, "token_hash", tokenHash)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve password reset by hash")
+	}
+
+	r, err := mapPasswordResetModelToEntity(passwordResetModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// UsePasswordReset marks a password reset as used given its ID if it has not already been used
+func (repo *passwordResetRepoAdapter) UsePasswordReset(ctx context.Context, passwordResetID id.UUID) error {
+	now := time.Now()
+
+	err := repo.dbClient.Update(ctx, models.PasswordResetModel{}, mongodb.UpdateOptions{
+		Upsert: false,
+		FieldOptions: map[string]any{
+			"used_at":   now,
+			"updatedAt": now,
+		},
+		FilterParams: mongodb.FilterParams{
+			Key:   "uuid",
+			Value: passwordResetID.String(),
+		},
+		Conditions: []mongodb.FilterParams{
+			{
+				Key:   "used_at",
+				Value: nil,
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to use password reset %s", passwordResetID)
+	}
+
+	return nil
+}
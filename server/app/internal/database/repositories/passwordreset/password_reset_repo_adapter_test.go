@@ -0,0 +1,114 @@
+package passwordresetrepo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/app/internal/database/models"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/auth"
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/domain/id"
+	"github.com/BrianLusina/skillq/server/infra/mongodb"
+	mockmongodb "github.com/BrianLusina/skillq/server/infra/mongodb/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPasswordResetRepoAdapter(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockDbClient := mockmongodb.NewMockMongoDBClient[models.PasswordResetModel](mockCtrl)
+	passwordResetRepositoryAdapter := passwordResetRepoAdapter{dbClient: mockDbClient}
+	mockDbClient.EXPECT().CreateIndex(gomock.Any(), gomock.Any()).Return("password_reset_token_hash_idx", nil).Times(1)
+	adapter := New(mockDbClient)
+	assert.NotNil(t, adapter)
+
+	ctx := context.Background()
+
+	testPasswordReset := auth.NewPasswordReset(auth.PasswordResetParams{
+		ID:        id.NewUUID(),
+		UserId:    id.NewUUID(),
+		TokenHash: "token-hash",
+		ExpiresAt: time.Now().Add(time.Hour),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+
+	t.Run("creating a password reset", func(t *testing.T) {
+		t.Run("should return error when there is a failure to create password reset", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			mockDbClient.EXPECT().Insert(ctx, gomock.Any()).Return(primitive.ObjectID{}, errors.New("failed")).Times(1)
+
+			actual, err := passwordResetRepositoryAdapter.CreatePasswordReset(ctx, testPasswordReset)
+			assert.Error(t, err)
+			assert.Nil(t, actual)
+		})
+
+		t.Run("should return created password reset when there is a success in creating password reset", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			mockDbClient.EXPECT().Insert(ctx, mapPasswordResetToModel(testPasswordReset)).Return(primitive.ObjectID{}, nil).Times(1)
+
+			actual, err := passwordResetRepositoryAdapter.CreatePasswordReset(ctx, testPasswordReset)
+			assert.NoError(t, err)
+			assert.Equal(t, testPasswordReset.ID(), actual.ID())
+			assert.Equal(t, testPasswordReset.TokenHash(), actual.TokenHash())
+		})
+	})
+
+	t.Run("get password reset by hash", func(t *testing.T) {
+		t.Run("should return not found error when the password reset does not exist", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			mockDbClient.EXPECT().FindById(ctx, "token_hash", "token-hash").
+				Return(models.PasswordResetModel{}, errdefs.NewNotFoundError("not found", nil)).Times(1)
+
+			actual, err := passwordResetRepositoryAdapter.GetPasswordResetByHash(ctx, "token-hash")
+			assert.True(t, errdefs.IsNotFound(err))
+			assert.Nil(t, actual)
+		})
+
+		t.Run("should return password reset when it exists", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			mockDbClient.EXPECT().FindById(ctx, "token_hash", "token-hash").
+				Return(mapPasswordResetToModel(testPasswordReset), nil).Times(1)
+
+			actual, err := passwordResetRepositoryAdapter.GetPasswordResetByHash(ctx, "token-hash")
+			assert.NoError(t, err)
+			assert.Equal(t, testPasswordReset.ID(), actual.ID())
+			assert.Equal(t, testPasswordReset.UserID(), actual.UserID())
+			assert.Nil(t, actual.UsedAt())
+		})
+	})
+
+	t.Run("using a password reset", func(t *testing.T) {
+		isConditionalUpdate := gomock.Cond(func(x any) bool {
+			options, ok := x.(mongodb.UpdateOptions)
+			return ok && options.FilterParams.Value == testPasswordReset.ID().String() &&
+				len(options.Conditions) == 1 && options.Conditions[0].Key == "used_at" && options.Conditions[0].Value == nil
+		})
+
+		t.Run("should only use a password reset that has not been used", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			mockDbClient.EXPECT().Update(ctx, gomock.Any(), isConditionalUpdate).Return(nil).Times(1)
+
+			err := passwordResetRepositoryAdapter.UsePasswordReset(ctx, testPasswordReset.ID())
+			assert.NoError(t, err)
+		})
+
+		t.Run("should return not found error when the password reset has already been used", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			mockDbClient.EXPECT().Update(ctx, gomock.Any(), isConditionalUpdate).
+				Return(errdefs.NewNotFoundError("not found", nil)).Times(1)
+
+			err := passwordResetRepositoryAdapter.UsePasswordReset(ctx, testPasswordReset.ID())
+			assert.True(t, errdefs.IsNotFound(err))
+		})
+	})
+}
@@ -0,0 +1,2 @@
+// Package refreshtokenrepo contains repo adapter implementation for refresh tokens
+package refreshtokenrepo
@@ -0,0 +1,45 @@
+package refreshtokenrepo
+
+import (
+	"github.com/BrianLusina/skillq/server/app/internal/database/models"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/auth"
+	"github.com/BrianLusina/skillq/server/domain/id"
+)
+
+// mapRefreshTokenToModel maps a refresh token entity to a refresh token model
+func mapRefreshTokenToModel(refreshToken auth.RefreshToken) models.RefreshTokenModel {
+	return models.RefreshTokenModel{
+		BaseModel: models.BaseModel{
+			UUID:      refreshToken.ID().String(),
+			CreatedAt: refreshToken.CreatedAt(),
+			UpdatedAt: refreshToken.UpdatedAt(),
+		},
+		UserId:    refreshToken.UserID().String(),
+		TokenHash: refreshToken.TokenHash(),
+		ExpiresAt: refreshToken.ExpiresAt(),
+		RevokedAt: refreshToken.RevokedAt(),
+	}
+}
+
+// mapRefreshTokenModelToEntity maps a refresh token model to a refresh token entity
+func mapRefreshTokenModelToEntity(refreshTokenModel models.RefreshTokenModel) (auth.RefreshToken, error) {
+	uuid, err := id.StringToUUID(refreshTokenModel.BaseModel.UUID)
+	if err != nil {
+		return auth.RefreshToken{}, err
+	}
+
+	userId, err := id.StringToUUID(refreshTokenModel.UserId)
+	if err != nil {
+		return auth.RefreshToken{}, err
+	}
+
+	return auth.NewRefreshToken(auth.RefreshTokenParams{
+		ID:        uuid,
+		UserId:    userId,
+		TokenHash: refreshTokenModel.TokenHash,
+		ExpiresAt: refreshTokenModel.ExpiresAt,
+		RevokedAt: refreshTokenModel.RevokedAt,
+		CreatedAt: refreshTokenModel.BaseModel.CreatedAt,
+		UpdatedAt: refreshTokenModel.BaseModel.UpdatedAt,
+	}), nil
+}
@@ -0,0 +1,134 @@
+package refreshtokenrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/app/internal/database/models"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/auth"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories"
+	"github.com/BrianLusina/skillq/server/domain/id"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/mongodb"
+	"github.com/pkg/errors"
+)
+
+// refreshTokenRepoAdapter is the refresh token repository adapter structure for managing refresh token data
+type refreshTokenRepoAdapter struct {
+	// dbClient is the database client used to handle connections to the database
+	dbClient mongodb.MongoDBClient[models.RefreshTokenModel]
+}
+
+var _ repositories.RefreshTokenRepoPort = (*refreshTokenRepoAdapter)(nil)
+
+// New creates a new refresh token repository adapter
+func New(dbClient mongodb.MongoDBClient[models.RefreshTokenModel]) repositories.RefreshTokenRepoPort {
+	defer func() {
+		ctx := context.Background()
+		name, err := dbClient.CreateIndex(ctx, mongodb.IndexParam{
+			Keys: []mongodb.KeyParam{
+				{
+					Key:   "token_hash",
+					Value: 1,
+				},
+			},
+			Name:   "refresh_token_token_hash_idx",
+			Unique: true,
+		})
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Failed to create index 'refresh_token_token_hash_idx': %v", err)
+			return
+		}
+		logger.FromContext(ctx).Infof("Successfully created index %s", name)
+	}()
+
+	return &refreshTokenRepoAdapter{
+		dbClient: dbClient,
+	}
+}
+
+// CreateRefreshToken creates a refresh token in the repository
+func (repo *refreshTokenRepoAdapter) CreateRefreshToken(ctx context.Context, refreshToken auth.RefreshToken) (*auth.RefreshToken, error) {
+	refreshTokenModel := mapRefreshTokenToModel(refreshToken)
+	_, err := repo.dbClient.Insert(ctx, refreshTokenModel)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create refresh token")
+	}
+
+	t, err := mapRefreshTokenModelToEntity(refreshTokenModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// GetRefreshTokenByHash retrieves a refresh token given the hash of the token
+func (repo *refreshTokenRepoAdapter) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*auth.RefreshToken, error) {
+	refreshTokenModel, err := repo.dbClient.FindById(ctx, "token_hash", tokenHash)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve refresh token by hash")
+	}
+
+	t, err := mapRefreshTokenModelToEntity(refreshTokenModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// RevokeRefreshToken revokes a refresh token given its ID if it has not already been revoked
+func (repo *refreshTokenRepoAdapter) RevokeRefreshToken(ctx context.Context, tokenID id.UUID) error {
+	now := time.Now()
+
+	err := repo.dbClient.Update(ctx, models.RefreshTokenModel{}, mongodb.UpdateOptions{
+		Upsert: false,
+		FieldOptions: map[string]any{
+			"revoked_at": now,
+			"updatedAt":  now,
+		},
+		FilterParams: mongodb.FilterParams{
+			Key:   "uuid",
+			Value: tokenID.String(),
+		},
+		Conditions: []mongodb.FilterParams{
+			{
+				Key:   "revoked_at",
+				Value: nil,
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to revoke refresh token %s", tokenID)
+	}
+
+	return nil
+}
+
+// RevokeUserRefreshTokens revokes all the refresh tokens of a given user that have not already been revoked
+func (repo *refreshTokenRepoAdapter) RevokeUserRefreshTokens(ctx context.Context, userID id.UUID) error {
+	now := time.Now()
+
+	_, err := repo.dbClient.UpdateMany(ctx, mongodb.UpdateOptions{
+		FieldOptions: map[string]any{
+			"revoked_at": now,
+			"updatedAt":  now,
+		},
+		FilterParams: mongodb.FilterParams{
+			Key:   "user_id",
+			Value: userID.String(),
+		},
+		Conditions: []mongodb.FilterParams{
+			{
+				Key:   "revoked_at",
+				Value: nil,
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to revoke refresh tokens of user %s", userID)
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package refreshtokenrepo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/app/internal/database/models"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/auth"
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/domain/id"
+	"github.com/BrianLusina/skillq/server/infra/mongodb"
+	mockmongodb "github.com/BrianLusina/skillq/server/infra/mongodb/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRefreshTokenRepoAdapter(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockDbClient := mockmongodb.NewMockMongoDBClient[models.RefreshTokenModel](mockCtrl)
+	refreshTokenRepositoryAdapter := refreshTokenRepoAdapter{dbClient: mockDbClient}
+	mockDbClient.EXPECT().CreateIndex(gomock.Any(), gomock.Any()).Return("refresh_token_token_hash_idx", nil).Times(1)
+	adapter := New(mockDbClient)
+	assert.NotNil(t, adapter)
+
+	ctx := context.Background()
+
+	testRefreshToken := auth.NewRefreshToken(auth.RefreshTokenParams{
+		ID:        id.NewUUID(),
+		UserId:    id.NewUUID(),
+		TokenHash: "token-hash",
+		ExpiresAt: time.Now().Add(time.Hour),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+
+	t.Run("creating a refresh token", func(t *testing.T) {
+		t.Run("should return error when there is a failure to create refresh token", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			mockDbClient.EXPECT().Insert(ctx, gomock.Any()).Return(primitive.ObjectID{}, errors.New("failed")).Times(1)
+
+			actual, err := refreshTokenRepositoryAdapter.CreateRefreshToken(ctx, testRefreshToken)
+			assert.Error(t, err)
+			assert.Nil(t, actual)
+		})
+
+		t.Run("should return created refresh token when there is a success in creating refresh token", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			mockDbClient.EXPECT().Insert(ctx, mapRefreshTokenToModel(testRefreshToken)).Return(primitive.ObjectID{}, nil).Times(1)
+
+			actual, err := refreshTokenRepositoryAdapter.CreateRefreshToken(ctx, testRefreshToken)
+			assert.NoError(t, err)
+			assert.Equal(t, testRefreshToken.ID(), actual.ID())
+			assert.Equal(t, testRefreshToken.TokenHash(), actual.TokenHash())
+		})
+	})
+
+	t.Run("get refresh token by hash", func(t *testing.T) {
+		t.Run("should return not found error when the refresh token does not exist", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			mockDbClient.EXPECT().FindById(ctx, "token_hash", "token-hash").
+				Return(models.RefreshTokenModel{}, errdefs.NewNotFoundError("not found", nil)).Times(1)
+
+			actual, err := refreshTokenRepositoryAdapter.GetRefreshTokenByHash(ctx, "token-hash")
+			assert.True(t, errdefs.IsNotFound(err))
+			assert.Nil(t, actual)
+		})
+
+		t.Run("should return refresh token when it exists", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			mockDbClient.EXPECT().FindById(ctx, "token_hash", "token-hash").
+				Return(mapRefreshTokenToModel(testRefreshToken), nil).Times(1)
+
+			actual, err := refreshTokenRepositoryAdapter.GetRefreshTokenByHash(ctx, "token-hash")
+			assert.NoError(t, err)
+			assert.Equal(t, testRefreshToken.ID(), actual.ID())
+			assert.Equal(t, testRefreshToken.UserID(), actual.UserID())
+			assert.False(t, actual.IsRevoked())
+		})
+	})
+
+	t.Run("revoking refresh tokens", func(t *testing.T) {
+		t.Run("should only revoke a refresh token that has not been revoked", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			isConditionalUpdate := gomock.Cond(func(x any) bool {
+				options, ok := x.(mongodb.UpdateOptions)
+				return ok && options.FilterParams.Value == testRefreshToken.ID().String() &&
+					len(options.Conditions) == 1 && options.Conditions[0].Key == "revoked_at" && options.Conditions[0].Value == nil
+			})
+			mockDbClient.EXPECT().Update(ctx, gomock.Any(), isConditionalUpdate).
+				Return(errdefs.NewNotFoundError("not found", nil)).Times(1)
+
+			err := refreshTokenRepositoryAdapter.RevokeRefreshToken(ctx, testRefreshToken.ID())
+			assert.True(t, errdefs.IsNotFound(err))
+		})
+
+		t.Run("should revoke every refresh token of a user", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			isUserUpdate := gomock.Cond(func(x any) bool {
+				options, ok := x.(mongodb.UpdateOptions)
+				return ok && options.FilterParams.Key == "user_id" && options.FilterParams.Value == testRefreshToken.UserID().String()
+			})
+			mockDbClient.EXPECT().UpdateMany(ctx, isUserUpdate).Return(int64(2), nil).Times(1)
+
+			err := refreshTokenRepositoryAdapter.RevokeUserRefreshTokens(ctx, testRefreshToken.UserID())
+			assert.NoError(t, err)
+		})
+	})
+}
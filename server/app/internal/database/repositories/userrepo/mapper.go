@@ -19,12 +19,15 @@ func mapUserToModel(userEntity user.User) models.UserModel {
 			UpdatedAt: userEntity.UpdatedAt(),
 			DeletedAt: userEntity.DeletedAt(),
 		},
-		Name:         userEntity.Name(),
-		Email:        userEntity.Email(),
-		ImageUrl:     userEntity.ImageUrl(),
-		JobTitle:     userEntity.JobTitle(),
-		Skills:       userEntity.Skills(),
-		PasswordHash: userEntity.Password(),
+		Name:          userEntity.Name(),
+		Email:         userEntity.Email(),
+		PendingEmail:  userEntity.PendingEmail(),
+		EmailVerified: userEntity.EmailVerified(),
+		ImageUrl:      userEntity.ImageUrl(),
+		JobTitle:      userEntity.JobTitle(),
+		Skills:        userEntity.Skills(),
+		PasswordHash:  userEntity.Password(),
+		Role:          userEntity.Role().String(),
 	}
 }
 
@@ -59,11 +62,15 @@ func mapModelToUser(userModel models.UserModel) (user.User, error) {
 			},
 			Metadata: userModel.BaseModel.Metadata,
 		},
-		Name:     userModel.Name,
-		Email:    userModel.Email,
-		Password: userModel.PasswordHash,
-		JobTitle: userModel.JobTitle,
-		Skills:   userModel.Skills,
-		ImageUrl: userModel.ImageUrl,
+		Name:          userModel.Name,
+		Email:         userModel.Email,
+		PendingEmail:  userModel.PendingEmail,
+		EmailVerified: userModel.EmailVerified,
+		Password:      userModel.PasswordHash,
+		JobTitle:      userModel.JobTitle,
+		Skills:        userModel.Skills,
+		ImageUrl:      userModel.ImageUrl,
+		// users stored before roles were introduced have no role & are treated as members
+		Role: user.Role(userModel.Role),
 	})
 }
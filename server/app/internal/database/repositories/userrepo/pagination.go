@@ -0,0 +1,58 @@
+package userrepo
+
+import (
+	"github.com/BrianLusina/skillq/server/app/internal/database/models"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound/common"
+)
+
+// mapOrderBy maps the field to order users by to the name of the field in the user document
+func mapOrderBy(orderBy common.OrderBy) string {
+	switch orderBy {
+	case common.UPDATED_AT:
+		return "updatedAt"
+	case common.DELETED_AT:
+		return "deletedAt"
+	default:
+		return "createdAt"
+	}
+}
+
+// paginate trims the user models retrieved with a cursor to the page limit & returns the cursors to the next & previous pages.
+// The user models are expected to contain one record more than the limit if there are more records in the direction of the cursor
+func paginate(userModels []models.UserModel, cursor common.Cursor, limit int) ([]models.UserModel, *common.Cursor, *common.Cursor) {
+	hasMore := limit > 0 && len(userModels) > limit
+	if hasMore {
+		if cursor.IsBackwards() {
+			// records retrieved backwards are in the sort order, so the extra record is the first one
+			userModels = userModels[len(userModels)-limit:]
+		} else {
+			userModels = userModels[:limit]
+		}
+	}
+
+	if len(userModels) == 0 {
+		return userModels, nil, nil
+	}
+
+	first := &common.Cursor{KeyID: userModels[0].BaseModel.KeyID, Direction: common.PREV}
+	last := &common.Cursor{KeyID: userModels[len(userModels)-1].BaseModel.KeyID, Direction: common.NEXT}
+	hasCursor := cursor.KeyID != ""
+
+	if cursor.IsBackwards() {
+		if !hasCursor {
+			last = nil
+		}
+		if !hasMore {
+			first = nil
+		}
+	} else {
+		if !hasMore {
+			last = nil
+		}
+		if !hasCursor {
+			first = nil
+		}
+	}
+
+	return userModels, last, first
+}
@@ -3,14 +3,15 @@ package userrepo
 import (
 	"context"
 	"fmt"
-	"log/slog"
-	"strings"
+	"regexp"
+	"time"
 
 	"github.com/BrianLusina/skillq/server/app/internal/database/models"
 	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/user"
 	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound/common"
 	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories"
 	"github.com/BrianLusina/skillq/server/domain/id"
+	"github.com/BrianLusina/skillq/server/infra/logger"
 	"github.com/BrianLusina/skillq/server/infra/mongodb"
 	"github.com/BrianLusina/skillq/server/utils/tools"
 	"github.com/pkg/errors"
@@ -31,7 +32,8 @@ func New(dbClient mongodb.MongoDBClient[models.UserModel]) repositories.UserRepo
 	}
 
 	defer func() {
-		name, err := dbClient.CreateIndex(context.Background(), mongodb.IndexParam{
+		ctx := context.Background()
+		name, err := dbClient.CreateIndex(ctx, mongodb.IndexParam{
 			Keys: []mongodb.KeyParam{
 				{
 					Key:   "email",
@@ -42,12 +44,14 @@ func New(dbClient mongodb.MongoDBClient[models.UserModel]) repositories.UserRepo
 					Value: 1,
 				},
 			},
-			Name: "user_email_name_idx",
+			Name:   "user_email_name_idx",
+			Unique: true,
 		})
 		if err != nil {
-			slog.Error("Failed to create index 'user_email_name_idx' with error ", err)
+			logger.FromContext(ctx).Errorf("Failed to create index 'user_email_name_idx': %v", err)
+			return
 		}
-		slog.Info("Successfully created", "index", name)
+		logger.FromContext(ctx).Infof("Successfully created index %s", name)
 	}()
 
 	return repoAdapter
@@ -83,81 +87,170 @@ func (repo *userRepoAdapter) GetUserByUUID(ctx context.Context, userUUID id.UUID
 	return &u, nil
 }
 
-func (repo *userRepoAdapter) GetAllUsers(ctx context.Context, params common.RequestParams) ([]user.User, error) {
-	users, err := repo.dbClient.FindAll(ctx, mongodb.FilterOptions{
-		Limit:     params.Limit,
-		Offset:    params.Offset,
-		SortOrder: mongodb.SortOrder(params.OrderOption.SortOrder),
-		OrderBy:   string(params.OrderOption.OrderBy),
-	})
+// GetUserByEmail retrieves a user given their email address
+func (repo *userRepoAdapter) GetUserByEmail(ctx context.Context, email string) (*user.User, error) {
+	existingUser, err := repo.dbClient.FindById(ctx, "email", email)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to retrieve all users")
+		return nil, errors.Wrapf(err, "failed to retrieve user by email %s", email)
 	}
 
-	return tools.MapWithError(users, func(u models.UserModel, _ int) (user.User, error) {
-		return mapModelToUser(u)
-	})
+	u, err := mapModelToUser(existingUser)
+	if err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+// GetAllUsers retrieves a page of all users
+func (repo *userRepoAdapter) GetAllUsers(ctx context.Context, params common.RequestParams) (common.Page[user.User], error) {
+	page, err := repo.findPage(ctx, params, nil)
+	if err != nil {
+		return common.Page[user.User]{}, errors.Wrapf(err, "failed to retrieve all users")
+	}
+
+	return page, nil
 }
 
-func (repo *userRepoAdapter) GetAllUsersBySkill(ctx context.Context, skill string, params common.RequestParams) ([]user.User, error) {
+// GetAllUsersBySkill retrieves a page of all users that have the given skill, ignoring case
+func (repo *userRepoAdapter) GetAllUsersBySkill(ctx context.Context, skill string, params common.RequestParams) (common.Page[user.User], error) {
 	filterValues := map[string]map[string]string{
 		"skills": {
-			"$regex":   fmt.Sprintf("(?i)%s", skill),
+			"$regex":   fmt.Sprintf("^%s$", regexp.QuoteMeta(skill)),
 			"$options": "i",
 		},
 	}
 
-	users, err := repo.dbClient.FindAll(ctx, mongodb.FilterOptions{
+	page, err := repo.findPage(ctx, params, filterValues)
+	if err != nil {
+		return common.Page[user.User]{}, errors.Wrapf(err, "failed to retrieve all users by skill %s", skill)
+	}
+
+	return page, nil
+}
+
+// findPage retrieves a page of users matching the field filter. Cursor based pagination is used if the params have a cursor,
+// otherwise offset based pagination is used
+func (repo *userRepoAdapter) findPage(ctx context.Context, params common.RequestParams, fieldFilter map[string]map[string]string) (common.Page[user.User], error) {
+	filterOptions := mongodb.FilterOptions{
 		Limit:       params.Limit,
 		Offset:      params.Offset,
 		SortOrder:   mongodb.SortOrder(params.OrderOption.SortOrder),
-		OrderBy:     string(params.OrderOption.OrderBy),
-		FieldFilter: filterValues,
-	})
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to retrieve all users by skill %s", skill)
+		OrderBy:     mapOrderBy(params.OrderOption.OrderBy),
+		FieldFilter: fieldFilter,
 	}
 
-	usersWithSkill := tools.Filter(users, func(user models.UserModel) bool {
-		hasSkill := false
-		for _, userSkill := range user.Skills {
-			if strings.ToLower(userSkill) == skill {
-				hasSkill = true
-				break
-			}
-			hasSkill = false
+	cursor := params.Cursor
+	if cursor != nil {
+		filterOptions.Offset = 0
+		filterOptions.Cursor = &mongodb.CursorOptions{
+			Key:       "keyId",
+			Backwards: cursor.IsBackwards(),
 		}
-		return hasSkill
-	})
+		if cursor.KeyID != "" {
+			filterOptions.Cursor.Value = cursor.KeyID
+		}
+
+		// an extra record is retrieved to find out whether there are more records after the page
+		if params.Limit > 0 {
+			filterOptions.Limit = params.Limit + 1
+		}
+	}
+
+	userModels, err := repo.dbClient.FindAll(ctx, filterOptions)
+	if err != nil {
+		return common.Page[user.User]{}, err
+	}
+
+	page := common.Page[user.User]{
+		Limit:  params.Limit,
+		Offset: filterOptions.Offset,
+	}
 
-	return tools.MapWithError(usersWithSkill, func(u models.UserModel, _ int) (user.User, error) {
+	if cursor != nil {
+		userModels, page.Next, page.Prev = paginate(userModels, *cursor, params.Limit)
+	}
+
+	users, err := tools.MapWithError(userModels, func(u models.UserModel, _ int) (user.User, error) {
 		return mapModelToUser(u)
 	})
+	if err != nil {
+		return common.Page[user.User]{}, err
+	}
+	page.Items = users
+
+	if params.IncludeTotal {
+		total, err := repo.dbClient.Count(ctx, filterOptions)
+		if err != nil {
+			return common.Page[user.User]{}, err
+		}
+		page.Total = &total
+	}
+
+	return page, nil
 }
 
-// UpdateUser updates a user given their ID
-func (repo *userRepoAdapter) UpdateUser(ctx context.Context, userToUpdate user.User) (*user.User, error) {
-	userModel := mapUserToModel(userToUpdate)
+// UpdateUser updates the fields of a user that are set in the request & returns the updated user
+func (repo *userRepoAdapter) UpdateUser(ctx context.Context, request repositories.UpdateUserRequest) (*user.User, error) {
+	fieldOptions := map[string]any{
+		"updatedAt": time.Now(),
+	}
 
-	err := repo.dbClient.Update(ctx, userModel, mongodb.UpdateOptions{
-		Upsert: false,
-		FieldOptions: map[string]any{
-			"name":     userToUpdate.Name(),
-			"email":    userToUpdate.Email(),
-			"jobTitle": userToUpdate.JobTitle(),
-			"imageUrl": userToUpdate.ImageUrl(),
-			"skills":   userToUpdate.Skills(),
+	if request.Name != nil {
+		fieldOptions["name"] = *request.Name
+	}
+
+	if request.Email != nil {
+		fieldOptions["email"] = *request.Email
+	}
+
+	if request.JobTitle != nil {
+		fieldOptions["jobTitle"] = *request.JobTitle
+	}
+
+	if request.ImageUrl != nil {
+		fieldOptions["imageUrl"] = *request.ImageUrl
+	}
+
+	if request.Skills != nil {
+		fieldOptions["skills"] = *request.Skills
+	}
+
+	if request.PasswordHash != nil {
+		fieldOptions["passwordHash"] = *request.PasswordHash
+	}
+
+	if request.PendingEmail != nil {
+		fieldOptions["pendingEmail"] = *request.PendingEmail
+	}
+
+	if request.EmailVerified != nil {
+		fieldOptions["emailVerified"] = *request.EmailVerified
+	}
+
+	if request.Role != nil {
+		fieldOptions["role"] = request.Role.String()
+	}
+
+	userModel := models.UserModel{
+		BaseModel: models.BaseModel{
+			UUID: request.UserID.String(),
 		},
+	}
+
+	err := repo.dbClient.Update(ctx, userModel, mongodb.UpdateOptions{
+		Upsert:       false,
+		FieldOptions: fieldOptions,
 		FilterParams: mongodb.FilterParams{
 			Key:   "uuid",
-			Value: userToUpdate.UUID().String(),
+			Value: request.UserID.String(),
 		},
 	})
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to update user %v", userToUpdate)
+		return nil, errors.Wrapf(err, "failed to update user %s", request.UserID)
 	}
 
-	return &userToUpdate, nil
+	return repo.GetUserByUUID(ctx, request.UserID)
 }
 
 // DeleteUserById deletes a given user by the ID
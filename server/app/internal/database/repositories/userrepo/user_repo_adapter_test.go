@@ -10,6 +10,8 @@ import (
 	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/user"
 	mockuser "github.com/BrianLusina/skillq/server/app/internal/domain/entities/user/mocks"
 	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound/common"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories"
+	"github.com/BrianLusina/skillq/server/infra/mongodb"
 	mockmongodb "github.com/BrianLusina/skillq/server/infra/mongodb/mocks"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -20,6 +22,7 @@ func TestUserRepoAdapter(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	mockDbClient := mockmongodb.NewMockMongoDBClient[models.UserModel](mockCtrl)
 	userRepositoryAdapter := userRepoAdapter{dbClient: mockDbClient}
+	mockDbClient.EXPECT().CreateIndex(gomock.Any(), gomock.Any()).Return("user_email_name_idx", nil).Times(1)
 	adapter := New(mockDbClient)
 	assert.NotNil(t, adapter)
 
@@ -88,6 +91,30 @@ func TestUserRepoAdapter(t *testing.T) {
 				assert.NotNil(t, actualUser)
 			})
 		})
+
+		t.Run("by email", func(t *testing.T) {
+
+			t.Run("should return nil & error when there is a failure to retrieve user by email", func(t *testing.T) {
+				defer mockCtrl.Finish()
+
+				dbError := errors.New("failed to retrieve user")
+				mockDbClient.EXPECT().FindById(ctx, "email", testUser.Email()).Return(models.UserModel{}, dbError).Times(1)
+
+				actualUser, err := userRepositoryAdapter.GetUserByEmail(ctx, testUser.Email())
+				assert.Error(t, err)
+				assert.Nil(t, actualUser)
+			})
+
+			t.Run("should return user when there is a success in retrieving a user by email", func(t *testing.T) {
+				defer mockCtrl.Finish()
+
+				mockDbClient.EXPECT().FindById(ctx, "email", testUser.Email()).Return(testUserModel, nil).Times(1)
+
+				actualUser, err := userRepositoryAdapter.GetUserByEmail(ctx, testUser.Email())
+				assert.NoError(t, err)
+				assert.Equal(t, testUser.UUID(), actualUser.UUID())
+			})
+		})
 	})
 
 	t.Run("Get all users", func(t *testing.T) {
@@ -106,26 +133,37 @@ func TestUserRepoAdapter(t *testing.T) {
 		testUsers := []user.User{*testUserOne, *testUserTwo, *testUserThree}
 		testUserModels := []models.UserModel{testUserOneModel, testUserTwoModel, testUserThreeModel}
 
+		filterOptions := mongodb.FilterOptions{
+			Limit:     100,
+			Offset:    0,
+			SortOrder: mongodb.DESC,
+			OrderBy:   "createdAt",
+		}
+
 		t.Run("should return nil & error when there is a failure to retrieve all users", func(t *testing.T) {
 			defer mockCtrl.Finish()
 
 			dbError := errors.New("failed to retrieve users")
-			mockDbClient.EXPECT().FindAll(ctx, map[string]map[string]string{}).Return(nil, dbError).Times(1)
+			mockDbClient.EXPECT().FindAll(ctx, filterOptions).Return(nil, dbError).Times(1)
 
 			actualUsers, err := userRepositoryAdapter.GetAllUsers(ctx, common.NewRequestParams())
 			assert.Error(t, err)
-			assert.Nil(t, actualUsers)
+			assert.Nil(t, actualUsers.Items)
 		})
 
 		t.Run("should return users & nil error when there is a success in retrieving a users", func(t *testing.T) {
 			defer mockCtrl.Finish()
 
-			mockDbClient.EXPECT().FindAll(ctx, map[string]map[string]string{}).Return(testUserModels, nil).Times(1)
+			mockDbClient.EXPECT().FindAll(ctx, filterOptions).Return(testUserModels, nil).Times(1)
 
 			actualUsers, err := userRepositoryAdapter.GetAllUsers(ctx, common.NewRequestParams())
 			assert.NoError(t, err)
 			assert.NotNil(t, actualUsers)
-			assert.ElementsMatch(t, testUsers, actualUsers)
+			assert.ElementsMatch(t, testUsers, actualUsers.Items)
+			assert.Equal(t, 100, actualUsers.Limit)
+			assert.Nil(t, actualUsers.Next)
+			assert.Nil(t, actualUsers.Prev)
+			assert.Nil(t, actualUsers.Total)
 		})
 
 		t.Run("by a skill", func(t *testing.T) {
@@ -133,39 +171,219 @@ func TestUserRepoAdapter(t *testing.T) {
 				defer mockCtrl.Finish()
 
 				skill := "hunter"
-				filterValues := map[string]map[string]string{
+				skillFilterOptions := filterOptions
+				skillFilterOptions.FieldFilter = map[string]map[string]string{
 					"skills": {
-						"$regex":   fmt.Sprintf("(?i)%s", skill),
+						"$regex":   fmt.Sprintf("^%s$", skill),
 						"$options": "i",
 					},
 				}
 
 				dbError := errors.New("failed to retrieve users")
-				mockDbClient.EXPECT().FindAll(ctx, filterValues).Return(nil, dbError).Times(1)
+				mockDbClient.EXPECT().FindAll(ctx, skillFilterOptions).Return(nil, dbError).Times(1)
 
 				actualUsers, err := userRepositoryAdapter.GetAllUsersBySkill(ctx, skill, common.NewRequestParams())
 				assert.Error(t, err)
-				assert.Nil(t, actualUsers)
+				assert.Nil(t, actualUsers.Items)
 			})
 
 			t.Run("should return users & nil error when there is a success in retrieving users", func(t *testing.T) {
 				defer mockCtrl.Finish()
 
 				skill := "hunter"
-				filterValues := map[string]map[string]string{
+				skillFilterOptions := filterOptions
+				skillFilterOptions.FieldFilter = map[string]map[string]string{
 					"skills": {
-						"$regex":   fmt.Sprintf("(?i)%s", skill),
+						"$regex":   fmt.Sprintf("^%s$", skill),
 						"$options": "i",
 					},
 				}
 
-				mockDbClient.EXPECT().FindAll(ctx, filterValues).Return(testUserModels, nil).Times(1)
+				mockDbClient.EXPECT().FindAll(ctx, skillFilterOptions).Return(testUserModels, nil).Times(1)
 
 				actualUsers, err := userRepositoryAdapter.GetAllUsersBySkill(ctx, skill, common.NewRequestParams())
 				assert.NoError(t, err)
-				assert.NotNil(t, actualUsers)
+				assert.ElementsMatch(t, testUsers, actualUsers.Items)
+			})
+		})
+
+		t.Run("with a cursor", func(t *testing.T) {
+			cursorFilterOptions := func(value any, backwards bool) mongodb.FilterOptions {
+				return mongodb.FilterOptions{
+					Limit:     3,
+					Offset:    0,
+					SortOrder: mongodb.DESC,
+					OrderBy:   "createdAt",
+					Cursor: &mongodb.CursorOptions{
+						Key:       "keyId",
+						Value:     value,
+						Backwards: backwards,
+					},
+				}
+			}
+
+			t.Run("should return the first page with a cursor to the next page when there are more users", func(t *testing.T) {
+				defer mockCtrl.Finish()
+
+				mockDbClient.EXPECT().FindAll(ctx, cursorFilterOptions(nil, false)).Return(testUserModels, nil).Times(1)
+
+				params := common.NewRequestParams(common.WithRequestLimit(2), common.WithCursor(common.Cursor{Direction: common.NEXT}))
+				page, err := userRepositoryAdapter.GetAllUsers(ctx, params)
+				assert.NoError(t, err)
+				assert.Equal(t, testUsers[:2], page.Items)
+				assert.Equal(t, &common.Cursor{KeyID: testUserTwoModel.BaseModel.KeyID, Direction: common.NEXT}, page.Next)
+				assert.Nil(t, page.Prev)
+			})
+
+			t.Run("should return the last page with a cursor to the previous page", func(t *testing.T) {
+				defer mockCtrl.Finish()
+
+				keyID := testUserOneModel.BaseModel.KeyID
+				mockDbClient.EXPECT().FindAll(ctx, cursorFilterOptions(keyID, false)).Return(testUserModels[1:], nil).Times(1)
+
+				params := common.NewRequestParams(common.WithRequestLimit(2), common.WithCursor(common.Cursor{KeyID: keyID, Direction: common.NEXT}))
+				page, err := userRepositoryAdapter.GetAllUsers(ctx, params)
+				assert.NoError(t, err)
+				assert.Equal(t, testUsers[1:], page.Items)
+				assert.Nil(t, page.Next)
+				assert.Equal(t, &common.Cursor{KeyID: testUserTwoModel.BaseModel.KeyID, Direction: common.PREV}, page.Prev)
+			})
+
+			t.Run("should return the previous page keeping the sort order", func(t *testing.T) {
+				defer mockCtrl.Finish()
+
+				keyID := testUserThreeModel.BaseModel.KeyID
+				mockDbClient.EXPECT().FindAll(ctx, cursorFilterOptions(keyID, true)).Return(testUserModels[:2], nil).Times(1)
+
+				params := common.NewRequestParams(common.WithRequestLimit(2), common.WithCursor(common.Cursor{KeyID: keyID, Direction: common.PREV}))
+				page, err := userRepositoryAdapter.GetAllUsers(ctx, params)
+				assert.NoError(t, err)
+				assert.Equal(t, testUsers[:2], page.Items)
+				assert.Equal(t, &common.Cursor{KeyID: testUserTwoModel.BaseModel.KeyID, Direction: common.NEXT}, page.Next)
+				assert.Nil(t, page.Prev)
+			})
+
+			t.Run("should count the total number of users when requested", func(t *testing.T) {
+				defer mockCtrl.Finish()
+
+				mockDbClient.EXPECT().FindAll(ctx, cursorFilterOptions(nil, false)).Return(testUserModels[:2], nil).Times(1)
+				mockDbClient.EXPECT().Count(ctx, cursorFilterOptions(nil, false)).Return(int64(2), nil).Times(1)
+
+				params := common.NewRequestParams(
+					common.WithRequestLimit(2),
+					common.WithCursor(common.Cursor{Direction: common.NEXT}),
+					common.WithIncludeTotal(true),
+				)
+				page, err := userRepositoryAdapter.GetAllUsers(ctx, params)
+				assert.NoError(t, err)
+				assert.Equal(t, testUsers[:2], page.Items)
+				assert.Nil(t, page.Next)
+				assert.Equal(t, int64(2), *page.Total)
 			})
 		})
 	})
 
+	t.Run("Update user", func(t *testing.T) {
+		testUser, err := mockuser.MockUser()
+		assert.NoError(t, err)
+
+		testUserModel := mapUserToModel(*testUser)
+		jobTitle := "Head of Engineering"
+
+		request := repositories.UpdateUserRequest{
+			UserID:   testUser.UUID(),
+			JobTitle: &jobTitle,
+		}
+
+		isPatchOfJobTitle := gomock.Cond(func(x any) bool {
+			options, ok := x.(mongodb.UpdateOptions)
+			if !ok {
+				return false
+			}
+			_, hasName := options.FieldOptions["name"]
+			_, hasImageUrl := options.FieldOptions["imageUrl"]
+			return options.FieldOptions["jobTitle"] == jobTitle && !hasName && !hasImageUrl &&
+				options.FilterParams.Value == testUser.UUID().String()
+		})
+
+		t.Run("should return nil & error when there is a failure to update the user", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			dbError := errors.New("failed to update user")
+			mockDbClient.EXPECT().Update(ctx, gomock.Any(), isPatchOfJobTitle).Return(dbError).Times(1)
+			mockDbClient.EXPECT().FindById(ctx, "uuid", testUser.UUID().String()).Times(0)
+
+			actualUser, err := userRepositoryAdapter.UpdateUser(ctx, request)
+			assert.Error(t, err)
+			assert.Nil(t, actualUser)
+		})
+
+		t.Run("should only set the provided fields & return the updated user", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			mockDbClient.EXPECT().Update(ctx, gomock.Any(), isPatchOfJobTitle).Return(nil).Times(1)
+			mockDbClient.EXPECT().FindById(ctx, "uuid", testUser.UUID().String()).Return(testUserModel, nil).Times(1)
+
+			actualUser, err := userRepositoryAdapter.UpdateUser(ctx, request)
+			assert.NoError(t, err)
+			assert.NotNil(t, actualUser)
+		})
+
+		t.Run("should set the password hash of the user", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			passwordHash := "password-hash"
+			mockDbClient.EXPECT().Update(ctx, gomock.Any(), gomock.Cond(func(x any) bool {
+				options, ok := x.(mongodb.UpdateOptions)
+				return ok && options.FieldOptions["passwordHash"] == passwordHash && len(options.FieldOptions) == 2
+			})).Return(nil).Times(1)
+			mockDbClient.EXPECT().FindById(ctx, "uuid", testUser.UUID().String()).Return(testUserModel, nil).Times(1)
+
+			_, err := userRepositoryAdapter.UpdateUser(ctx, repositories.UpdateUserRequest{
+				UserID:       testUser.UUID(),
+				PasswordHash: &passwordHash,
+			})
+			assert.NoError(t, err)
+		})
+
+		t.Run("should swap a confirmed pending email & clear the pending email", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			email, pendingEmail, emailVerified := "jane.doe@example.com", "", true
+			mockDbClient.EXPECT().Update(ctx, gomock.Any(), gomock.Cond(func(x any) bool {
+				options, ok := x.(mongodb.UpdateOptions)
+				return ok &&
+					options.FieldOptions["email"] == email &&
+					options.FieldOptions["pendingEmail"] == pendingEmail &&
+					options.FieldOptions["emailVerified"] == emailVerified &&
+					len(options.FieldOptions) == 4
+			})).Return(nil).Times(1)
+			mockDbClient.EXPECT().FindById(ctx, "uuid", testUser.UUID().String()).Return(testUserModel, nil).Times(1)
+
+			_, err := userRepositoryAdapter.UpdateUser(ctx, repositories.UpdateUserRequest{
+				UserID:        testUser.UUID(),
+				Email:         &email,
+				PendingEmail:  &pendingEmail,
+				EmailVerified: &emailVerified,
+			})
+			assert.NoError(t, err)
+		})
+
+		t.Run("updates the role of the user", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			role := user.RoleAdmin
+			mockDbClient.EXPECT().Update(ctx, gomock.Any(), gomock.Cond(func(x any) bool {
+				options, ok := x.(mongodb.UpdateOptions)
+				return ok && options.FieldOptions["role"] == "admin" && len(options.FieldOptions) == 2
+			})).Return(nil).Times(1)
+			mockDbClient.EXPECT().FindById(ctx, "uuid", testUser.UUID().String()).Return(testUserModel, nil).Times(1)
+
+			_, err := userRepositoryAdapter.UpdateUser(ctx, repositories.UpdateUserRequest{
+				UserID: testUser.UUID(),
+				Role:   &role,
+			})
+			assert.NoError(t, err)
+		})
+	})
 }
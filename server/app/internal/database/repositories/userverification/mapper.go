@@ -15,8 +15,10 @@ func mapUserVerificationToModel(userVerificationEntity user.UserVerification) mo
 			UpdatedAt: userVerificationEntity.UpdatedAt(),
 		},
 		UserId:     userVerificationEntity.UserID().String(),
-		Code:       userVerificationEntity.Code(),
+		Email:      userVerificationEntity.Email(),
+		CodeHash:   userVerificationEntity.CodeHash(),
 		IsVerified: userVerificationEntity.IsVerified(),
+		ExpiresAt:  userVerificationEntity.ExpiresAt(),
 	}
 }
 
@@ -35,9 +37,11 @@ func mapUserVerificationModelToEntity(userVerificationModel models.UserVerificat
 	return user.NewVerification(user.UserVerificationParams{
 		ID:         uuid,
 		UserId:     userId,
+		Email:      userVerificationModel.Email,
 		CreatedAt:  userVerificationModel.BaseModel.CreatedAt,
 		UpdatedAt:  userVerificationModel.BaseModel.UpdatedAt,
-		Code:       userVerificationModel.Code,
+		CodeHash:   userVerificationModel.CodeHash,
 		IsVerified: userVerificationModel.IsVerified,
+		ExpiresAt:  userVerificationModel.ExpiresAt,
 	}), nil
 }
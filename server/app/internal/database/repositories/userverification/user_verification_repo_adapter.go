@@ -2,12 +2,14 @@ package userverificationrepo
 
 import (
 	"context"
-	"log/slog"
+	"time"
 
 	"github.com/BrianLusina/skillq/server/app/internal/database/models"
 	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/user"
 	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories"
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
 	"github.com/BrianLusina/skillq/server/domain/id"
+	"github.com/BrianLusina/skillq/server/infra/logger"
 	"github.com/BrianLusina/skillq/server/infra/mongodb"
 	"github.com/pkg/errors"
 )
@@ -16,39 +18,66 @@ import (
 type userVerificationRepoAdapter struct {
 	// dbClient is the database client used to handle connections to the database
 	dbClient mongodb.MongoDBClient[models.UserVerificationModel]
+
+	// attemptsDbClient is the database client of the attempts users have made at verifying their email
+	attemptsDbClient mongodb.MongoDBClient[models.UserVerificationAttemptsModel]
 }
 
 var _ repositories.UserVerificationRepoPort = (*userVerificationRepoAdapter)(nil)
 
 // New creates a new user verification repository adapter
-func New(dbClient mongodb.MongoDBClient[models.UserVerificationModel]) repositories.UserVerificationRepoPort {
+func New(
+	dbClient mongodb.MongoDBClient[models.UserVerificationModel],
+	attemptsDbClient mongodb.MongoDBClient[models.UserVerificationAttemptsModel],
+) repositories.UserVerificationRepoPort {
 
 	defer func() {
-		name, err := dbClient.CreateIndex(context.Background(), mongodb.IndexParam{
+		ctx := context.Background()
+		name, err := dbClient.CreateIndex(ctx, mongodb.IndexParam{
 			Keys: []mongodb.KeyParam{
 				{
-					Key:   "uuid",
+					Key:   "user_id",
 					Value: 1,
 				},
 				{
-					Key:   "code",
+					Key:   "code_hash",
 					Value: 1,
 				},
+			},
+			Name:   "user_verification_user_id_code_hash_idx",
+			Unique: true,
+		})
+		if err != nil {
+			logger.FromContext(ctx).Errorf("Failed to create index 'user_verification_user_id_code_hash_idx': %v", err)
+			return
+		}
+		logger.FromContext(ctx).Infof("Successfully created index %s", name)
+	}()
+
+	defer func() {
+		// the attempts of a user are removed once their window has passed, resetting them
+		ctx := context.Background()
+		expireAfter := time.Duration(0)
+		name, err := attemptsDbClient.CreateIndex(ctx, mongodb.IndexParam{
+			Keys: []mongodb.KeyParam{
 				{
-					Key:   "user_id",
+					Key:   "expires_at",
 					Value: 1,
 				},
 			},
-			Name: "user_verification_uuid_code_user_id_idx",
+			Name:        "user_verification_attempts_expires_at_idx",
+			ExpireAfter: &expireAfter,
 		})
 		if err != nil {
-			slog.Error("Failed to create index 'user_verification_uuid_code_user_id_idx' with error ", err)
+			logger.FromContext(ctx).Errorf("Failed to create index 'user_verification_attempts_expires_at_idx': %v", err)
+			return
 		}
-		slog.Info("Successfully created", "index", name)
+		logger.FromContext(ctx).Infof("Successfully created index %s", name)
 	}()
 
 	return &userVerificationRepoAdapter{
-		dbClient: dbClient,
+		dbClient:         dbClient,
+		attemptsDbClient: attemptsDbClient,
 	}
 }
 
@@ -82,14 +111,31 @@ func (repo *userVerificationRepoAdapter) GetUserVerificationByUUID(ctx context.C
 	return &u, nil
 }
 
-// GetUserVerificationByCode retrieves a user verification given the code
-func (repo *userVerificationRepoAdapter) GetUserVerificationByCode(ctx context.Context, code string) (*user.UserVerification, error) {
-	userVerificationModel, err := repo.dbClient.FindById(ctx, "code", code)
+// GetUserVerificationByCode retrieves the latest verification of a user with the given code hash
+func (repo *userVerificationRepoAdapter) GetUserVerificationByCode(ctx context.Context, userID id.UUID, codeHash string) (*user.UserVerification, error) {
+	return repo.findLatest(ctx, map[string]map[string]string{
+		"user_id":   {"$eq": userID.String()},
+		"code_hash": {"$eq": codeHash},
+	})
+}
+
+// findLatest retrieves the last created user verification matching the field filter
+func (repo *userVerificationRepoAdapter) findLatest(ctx context.Context, fieldFilter map[string]map[string]string) (*user.UserVerification, error) {
+	userVerificationModels, err := repo.dbClient.FindAll(ctx, mongodb.FilterOptions{
+		Limit:       1,
+		OrderBy:     "createdAt",
+		SortOrder:   mongodb.DESC,
+		FieldFilter: fieldFilter,
+	})
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to retrieve user verification by code %v", code)
+		return nil, errors.Wrapf(err, "failed to retrieve user verification")
 	}
 
-	u, err := mapUserVerificationModelToEntity(userVerificationModel)
+	if len(userVerificationModels) == 0 {
+		return nil, errdefs.NewNotFoundError("user verification does not exist", nil)
+	}
+
+	u, err := mapUserVerificationModelToEntity(userVerificationModels[0])
 	if err != nil {
 		return nil, err
 	}
@@ -97,28 +143,93 @@ func (repo *userVerificationRepoAdapter) GetUserVerificationByCode(ctx context.C
 	return &u, nil
 }
 
-// UpdateUserVerification updates the user verification
-func (repo *userVerificationRepoAdapter) UpdateUserVerification(ctx context.Context, request repositories.UpdateUserVerificationRequest) error {
-	userVerification, err := repo.dbClient.FindById(ctx, "user_id", request.UserID.String())
+// RecordUserVerificationAttempt atomically records an attempt of a user at verifying their email, returning the number of
+// attempts they have made in the window that started with their first attempt. The attempts of the user are only
+// incremented while they are below maxAttempts, so concurrent attempts can not go over it
+func (repo *userVerificationRepoAdapter) RecordUserVerificationAttempt(ctx context.Context, userID id.UUID, maxAttempts int, window time.Duration) (int, error) {
+	attempts, err := repo.attemptsDbClient.FindOneAndUpdate(ctx, mongodb.UpdateOptions{
+		Upsert: true,
+		IncOptions: map[string]int{
+			"attempts": 1,
+		},
+		SetOnInsertOptions: map[string]any{
+			"expires_at": time.Now().Add(window),
+		},
+		FilterParams: mongodb.FilterParams{
+			Key:   "_id",
+			Value: userID.String(),
+		},
+		Conditions: []mongodb.FilterParams{
+			{
+				Key:   "attempts",
+				Value: map[string]any{"$lt": maxAttempts},
+			},
+		},
+	})
+	if err != nil {
+		// the attempts of the user are only left unmatched once they have made maxAttempts attempts, in which case the upsert
+		// conflicts with their existing attempts
+		if errdefs.IsConflict(err) {
+			return maxAttempts, user.ErrVerificationLocked
+		}
+		return 0, errors.Wrapf(err, "failed to record verification attempt of user %s", userID)
+	}
+
+	return attempts.Attempts, nil
+}
+
+// ResetUserVerificationAttempts clears the attempts a user has made at verifying their email
+func (repo *userVerificationRepoAdapter) ResetUserVerificationAttempts(ctx context.Context, userID id.UUID) error {
+	if err := repo.attemptsDbClient.Delete(ctx, "_id", userID.String()); err != nil && !errdefs.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to reset verification attempts of user %s", userID)
+	}
+
+	return nil
+}
+
+// ExpireUserVerifications expires the verifications of a user that have not been verified
+func (repo *userVerificationRepoAdapter) ExpireUserVerifications(ctx context.Context, userID id.UUID) error {
+	now := time.Now()
+
+	_, err := repo.dbClient.UpdateMany(ctx, mongodb.UpdateOptions{
+		FieldOptions: map[string]any{
+			"expires_at": now,
+			"updatedAt":  now,
+		},
+		FilterParams: mongodb.FilterParams{
+			Key:   "user_id",
+			Value: userID.String(),
+		},
+		Conditions: []mongodb.FilterParams{
+			{
+				Key:   "is_verified",
+				Value: false,
+			},
+		},
+	})
 	if err != nil {
-		return errors.Wrapf(err, "failed to retrieve user verification for user id %s", request.UserID)
+		return errors.Wrapf(err, "failed to expire user verifications of user %s", userID)
 	}
 
-	userVerification.IsVerified = request.IsVerified
+	return nil
+}
 
-	err = repo.dbClient.Update(ctx, userVerification, mongodb.UpdateOptions{
+// UpdateUserVerification updates the user verification
+func (repo *userVerificationRepoAdapter) UpdateUserVerification(ctx context.Context, request repositories.UpdateUserVerificationRequest) error {
+	err := repo.dbClient.Update(ctx, models.UserVerificationModel{}, mongodb.UpdateOptions{
 		Upsert: false,
 		FieldOptions: map[string]any{
 			"is_verified": request.IsVerified,
+			"updatedAt":   time.Now(),
 		},
 		FilterParams: mongodb.FilterParams{
 			Key:   "uuid",
-			Value: userVerification.BaseModel.UUID,
+			Value: request.ID.String(),
 		},
 	})
-
 	if err != nil {
-		return errors.Wrapf(err, "failed to update user verification for user %s", request.UserID)
+		return errors.Wrapf(err, "failed to update user verification %s", request.ID)
 	}
+
 	return nil
 }
@@ -4,9 +4,15 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/BrianLusina/skillq/server/app/internal/database/models"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/user"
 	mockuser "github.com/BrianLusina/skillq/server/app/internal/domain/entities/user/mocks"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories"
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/domain/id"
+	"github.com/BrianLusina/skillq/server/infra/mongodb"
 	mockmongodb "github.com/BrianLusina/skillq/server/infra/mongodb/mocks"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -16,8 +22,14 @@ import (
 func TestUserVerificationRepoAdapter(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	mockDbClient := mockmongodb.NewMockMongoDBClient[models.UserVerificationModel](mockCtrl)
-	userVerificationRepositoryAdapter := userVerificationRepoAdapter{dbClient: mockDbClient}
-	adapter := New(mockDbClient)
+	mockAttemptsDbClient := mockmongodb.NewMockMongoDBClient[models.UserVerificationAttemptsModel](mockCtrl)
+	userVerificationRepositoryAdapter := userVerificationRepoAdapter{dbClient: mockDbClient, attemptsDbClient: mockAttemptsDbClient}
+	mockDbClient.EXPECT().CreateIndex(gomock.Any(), gomock.Any()).Return("user_verification_user_id_code_hash_idx", nil).Times(1)
+	mockAttemptsDbClient.EXPECT().CreateIndex(gomock.Any(), gomock.Cond(func(x any) bool {
+		index, ok := x.(mongodb.IndexParam)
+		return ok && !index.Unique && index.ExpireAfter != nil && *index.ExpireAfter == 0
+	})).Return("user_verification_attempts_expires_at_idx", nil).Times(1)
+	adapter := New(mockDbClient, mockAttemptsDbClient)
 	assert.NotNil(t, adapter)
 
 	ctx := context.Background()
@@ -84,31 +96,135 @@ func TestUserVerificationRepoAdapter(t *testing.T) {
 		})
 
 		t.Run("by a code", func(t *testing.T) {
+			userID := testUserVerification.UserID()
+			codeHash := testUserVerification.CodeHash()
+			isCodeFilter := gomock.Cond(func(x any) bool {
+				options, ok := x.(mongodb.FilterOptions)
+				return ok && options.Limit == 1 && options.SortOrder == mongodb.DESC &&
+					options.FieldFilter["user_id"]["$eq"] == userID.String() &&
+					options.FieldFilter["code_hash"]["$eq"] == codeHash
+			})
+
 			t.Run("should return nil & error when there is a failure to retrieve verification for a given code", func(t *testing.T) {
 				defer mockCtrl.Finish()
 
-				code := "somecode"
-
 				dbError := errors.New("failed to retrieve user verification")
-				mockDbClient.EXPECT().FindById(ctx, "code", code).Return(models.UserVerificationModel{}, dbError).Times(1)
+				mockDbClient.EXPECT().FindAll(ctx, isCodeFilter).Return(nil, dbError).Times(1)
 
-				actualUserVerification, err := userVerificationRepositoryAdapter.GetUserVerificationByCode(ctx, code)
+				actualUserVerification, err := userVerificationRepositoryAdapter.GetUserVerificationByCode(ctx, userID, codeHash)
 				assert.Error(t, err)
 				assert.Nil(t, actualUserVerification)
 			})
 
-			t.Run("should return user verification & nil error when there is a success in retrieving user verification", func(t *testing.T) {
+			t.Run("should return a not found error when the user has no verification with the code", func(t *testing.T) {
 				defer mockCtrl.Finish()
 
-				code := "somecode"
+				mockDbClient.EXPECT().FindAll(ctx, isCodeFilter).Return([]models.UserVerificationModel{}, nil).Times(1)
 
-				mockDbClient.EXPECT().FindById(ctx, "code", code).Return(testUserVerificationModel, nil).Times(1)
+				actualUserVerification, err := userVerificationRepositoryAdapter.GetUserVerificationByCode(ctx, userID, codeHash)
+				assert.True(t, errdefs.IsNotFound(err))
+				assert.Nil(t, actualUserVerification)
+			})
 
-				actualUsers, err := userVerificationRepositoryAdapter.GetUserVerificationByCode(ctx, code)
+			t.Run("should return user verification & nil error when there is a success in retrieving user verification", func(t *testing.T) {
+				defer mockCtrl.Finish()
+
+				mockDbClient.EXPECT().FindAll(ctx, isCodeFilter).Return([]models.UserVerificationModel{testUserVerificationModel}, nil).Times(1)
+
+				actualUserVerification, err := userVerificationRepositoryAdapter.GetUserVerificationByCode(ctx, userID, codeHash)
 				assert.NoError(t, err)
-				assert.NotNil(t, actualUsers)
+				assert.Equal(t, testUserVerification.ID(), actualUserVerification.ID())
+				assert.Equal(t, codeHash, actualUserVerification.CodeHash())
+				assert.Empty(t, actualUserVerification.Code())
+				assert.WithinDuration(t, testUserVerification.ExpiresAt(), actualUserVerification.ExpiresAt(), 0)
 			})
 		})
 	})
 
+	t.Run("updating a user verification", func(t *testing.T) {
+		testUserVerification := mockuser.MockUserVerification("somecode")
+		verificationID := testUserVerification.ID()
+
+		t.Run("should mark the verification as verified", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			mockDbClient.EXPECT().Update(ctx, gomock.Any(), gomock.Cond(func(x any) bool {
+				options, ok := x.(mongodb.UpdateOptions)
+				return ok && options.FieldOptions["is_verified"] == true && options.FilterParams.Key == "uuid" &&
+					options.FilterParams.Value == verificationID.String()
+			})).Return(nil).Times(1)
+
+			err := userVerificationRepositoryAdapter.UpdateUserVerification(ctx, repositories.UpdateUserVerificationRequest{
+				ID:         verificationID,
+				IsVerified: true,
+			})
+			assert.NoError(t, err)
+		})
+
+		t.Run("should expire the unverified verifications of the user", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			userID := testUserVerification.UserID()
+			mockDbClient.EXPECT().UpdateMany(ctx, gomock.Cond(func(x any) bool {
+				options, ok := x.(mongodb.UpdateOptions)
+				return ok && options.FilterParams.Key == "user_id" && options.FilterParams.Value == userID.String() &&
+					len(options.Conditions) == 1 && options.Conditions[0].Key == "is_verified" && options.Conditions[0].Value == false &&
+					options.FieldOptions["expires_at"] != nil
+			})).Return(int64(2), nil).Times(1)
+
+			err := userVerificationRepositoryAdapter.ExpireUserVerifications(ctx, userID)
+			assert.NoError(t, err)
+		})
+	})
+
+	t.Run("verification attempts", func(t *testing.T) {
+		userID := id.NewUUID()
+
+		t.Run("should increment the attempts of the user while they are below the max", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			mockAttemptsDbClient.EXPECT().FindOneAndUpdate(ctx, gomock.Cond(func(x any) bool {
+				options, ok := x.(mongodb.UpdateOptions)
+				return ok && options.Upsert && options.IncOptions["attempts"] == 1 &&
+					options.FilterParams.Key == "_id" && options.FilterParams.Value == userID.String() &&
+					len(options.Conditions) == 1 && options.Conditions[0].Key == "attempts" &&
+					assert.ObjectsAreEqual(map[string]any{"$lt": 5}, options.Conditions[0].Value) &&
+					options.SetOnInsertOptions["expires_at"] != nil
+			})).Return(models.UserVerificationAttemptsModel{UserId: userID.String(), Attempts: 2}, nil).Times(1)
+
+			attempts, err := userVerificationRepositoryAdapter.RecordUserVerificationAttempt(ctx, userID, 5, time.Hour)
+			assert.NoError(t, err)
+			assert.Equal(t, 2, attempts)
+		})
+
+		t.Run("should return a locked error once the user is out of attempts", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			mockAttemptsDbClient.EXPECT().FindOneAndUpdate(ctx, gomock.Any()).
+				Return(models.UserVerificationAttemptsModel{}, errdefs.NewConflictError("duplicate key", nil)).Times(1)
+
+			_, err := userVerificationRepositoryAdapter.RecordUserVerificationAttempt(ctx, userID, 5, time.Hour)
+			assert.ErrorIs(t, err, user.ErrVerificationLocked)
+		})
+
+		t.Run("should return an error when the attempt can not be recorded", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			mockAttemptsDbClient.EXPECT().FindOneAndUpdate(ctx, gomock.Any()).
+				Return(models.UserVerificationAttemptsModel{}, errors.New("failed to update")).Times(1)
+
+			_, err := userVerificationRepositoryAdapter.RecordUserVerificationAttempt(ctx, userID, 5, time.Hour)
+			assert.Error(t, err)
+			assert.NotErrorIs(t, err, user.ErrVerificationLocked)
+		})
+
+		t.Run("should reset the attempts of a user who has none", func(t *testing.T) {
+			defer mockCtrl.Finish()
+
+			mockAttemptsDbClient.EXPECT().Delete(ctx, "_id", userID.String()).
+				Return(errdefs.NewNotFoundError("no attempts", nil)).Times(1)
+
+			assert.NoError(t, userVerificationRepositoryAdapter.ResetUserVerificationAttempts(ctx, userID))
+		})
+	})
 }
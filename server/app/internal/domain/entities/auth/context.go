@@ -0,0 +1,22 @@
+package auth
+
+import (
+	"context"
+)
+
+type keyUserID int
+
+// userIDKey is the key used to store the ID of the authenticated user in a context
+const userIDKey keyUserID = 0
+
+// WithUserID returns a copy of the context that carries the ID of the authenticated user
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+// UserIDFromContext retrieves the ID of the authenticated user from the context. The second return value is false if the
+// context has no authenticated user
+func UserIDFromContext(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(userIDKey).(string)
+	return userID, ok && userID != ""
+}
@@ -0,0 +1,2 @@
+// Package auth contains the entities used to authenticate users & helpers to carry the authenticated user in a context
+package auth
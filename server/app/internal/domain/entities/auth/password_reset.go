@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/domain/id"
+)
+
+var (
+	// ErrPasswordResetUsed is returned when a password reset token is used after it has already been used
+	ErrPasswordResetUsed = errors.New("password reset token has already been used")
+
+	// ErrPasswordResetExpired is returned when a password reset token is used after it has expired
+	ErrPasswordResetExpired = errors.New("password reset token has expired")
+)
+
+// PasswordReset is a structure that contains the details of a token issued to a user to reset their password. Only the
+// hash of the token is kept, the token itself is only ever sent to the user
+type PasswordReset struct {
+	id        id.UUID
+	userId    id.UUID
+	tokenHash string
+	expiresAt time.Time
+	usedAt    *time.Time
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+// PasswordResetParams defines a structure with fields used to create a password reset
+type PasswordResetParams struct {
+	ID        id.UUID
+	UserId    id.UUID
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewPasswordReset creates a new password reset from the given params
+func NewPasswordReset(params PasswordResetParams) PasswordReset {
+	return PasswordReset{
+		id:        params.ID,
+		userId:    params.UserId,
+		tokenHash: params.TokenHash,
+		expiresAt: params.ExpiresAt,
+		usedAt:    params.UsedAt,
+		createdAt: params.CreatedAt,
+		updatedAt: params.UpdatedAt,
+	}
+}
+
+// ID retrieves the ID of the password reset
+func (r *PasswordReset) ID() id.UUID {
+	return r.id
+}
+
+// UserID retrieves the ID of the user the password reset was issued to
+func (r *PasswordReset) UserID() id.UUID {
+	return r.userId
+}
+
+// TokenHash retrieves the hash of the password reset token
+func (r *PasswordReset) TokenHash() string {
+	return r.tokenHash
+}
+
+// ExpiresAt retrieves the time the password reset expires at
+func (r *PasswordReset) ExpiresAt() time.Time {
+	return r.expiresAt
+}
+
+// UsedAt retrieves the time the password reset was used at, nil if it has not been used
+func (r *PasswordReset) UsedAt() *time.Time {
+	return r.usedAt
+}
+
+// CanUse checks if the password reset can be used to reset a password at the given time, returning the reason it cannot
+func (r *PasswordReset) CanUse(now time.Time) error {
+	if r.usedAt != nil {
+		return ErrPasswordResetUsed
+	}
+
+	if !now.Before(r.expiresAt) {
+		return ErrPasswordResetExpired
+	}
+
+	return nil
+}
+
+// CreatedAt retrieves the created at timestamp of the password reset
+func (r *PasswordReset) CreatedAt() time.Time {
+	return r.createdAt
+}
+
+// UpdatedAt retrieves the updated at timestamp of the password reset
+func (r *PasswordReset) UpdatedAt() time.Time {
+	return r.updatedAt
+}
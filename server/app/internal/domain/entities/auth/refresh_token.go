@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/BrianLusina/skillq/server/domain/id"
+)
+
+// RefreshToken is a structure that contains the details of a refresh token issued to a user. Only the hash of the token is
+// kept, the token itself is only ever handed to the user
+type RefreshToken struct {
+	id        id.UUID
+	userId    id.UUID
+	tokenHash string
+	expiresAt time.Time
+	revokedAt *time.Time
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+// RefreshTokenParams defines a structure with fields used to create a refresh token
+type RefreshTokenParams struct {
+	ID        id.UUID
+	UserId    id.UUID
+	TokenHash string
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewRefreshToken creates a new refresh token from the given params
+func NewRefreshToken(params RefreshTokenParams) RefreshToken {
+	return RefreshToken{
+		id:        params.ID,
+		userId:    params.UserId,
+		tokenHash: params.TokenHash,
+		expiresAt: params.ExpiresAt,
+		revokedAt: params.RevokedAt,
+		createdAt: params.CreatedAt,
+		updatedAt: params.UpdatedAt,
+	}
+}
+
+// ID retrieves the ID of the refresh token
+func (t *RefreshToken) ID() id.UUID {
+	return t.id
+}
+
+// UserID retrieves the ID of the user the refresh token was issued to
+func (t *RefreshToken) UserID() id.UUID {
+	return t.userId
+}
+
+// TokenHash retrieves the hash of the refresh token
+func (t *RefreshToken) TokenHash() string {
+	return t.tokenHash
+}
+
+// ExpiresAt retrieves the time the refresh token expires at
+func (t *RefreshToken) ExpiresAt() time.Time {
+	return t.expiresAt
+}
+
+// RevokedAt retrieves the time the refresh token was revoked at, nil if it has not been revoked
+func (t *RefreshToken) RevokedAt() *time.Time {
+	return t.revokedAt
+}
+
+// IsRevoked checks if the refresh token has been revoked
+func (t *RefreshToken) IsRevoked() bool {
+	return t.revokedAt != nil
+}
+
+// IsExpired checks if the refresh token has expired at the given time
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.expiresAt)
+}
+
+// CreatedAt retrieves the created at timestamp of the refresh token
+func (t *RefreshToken) CreatedAt() time.Time {
+	return t.createdAt
+}
+
+// UpdatedAt retrieves the updated at timestamp of the refresh token
+func (t *RefreshToken) UpdatedAt() time.Time {
+	return t.updatedAt
+}
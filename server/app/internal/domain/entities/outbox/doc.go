@@ -0,0 +1,3 @@
+// Package outbox contains the messages of the outbox, which keeps the tasks published in a transaction until they are
+// relayed to the broker
+package outbox
@@ -0,0 +1,118 @@
+package outbox
+
+import (
+	"time"
+)
+
+// Message is a message in the outbox. It is added in the same transaction as the changes it follows from & is pending
+// until it is relayed to the broker, after which it is dispatched
+type Message struct {
+	id            string
+	topic         string
+	contentType   string
+	payload       []byte
+	headers       map[string]string
+	attempts      int
+	nextAttemptAt time.Time
+	lastError     string
+	dispatchedAt  *time.Time
+	createdAt     time.Time
+	updatedAt     time.Time
+}
+
+// MessageParams defines a structure with fields used to create a message
+type MessageParams struct {
+	ID            string
+	Topic         string
+	ContentType   string
+	Payload       []byte
+	Headers       map[string]string
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	DispatchedAt  *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// NewMessage creates a new message from the given params
+func NewMessage(params MessageParams) Message {
+	return Message{
+		id:            params.ID,
+		topic:         params.Topic,
+		contentType:   params.ContentType,
+		payload:       params.Payload,
+		headers:       params.Headers,
+		attempts:      params.Attempts,
+		nextAttemptAt: params.NextAttemptAt,
+		lastError:     params.LastError,
+		dispatchedAt:  params.DispatchedAt,
+		createdAt:     params.CreatedAt,
+		updatedAt:     params.UpdatedAt,
+	}
+}
+
+// ID retrieves the ID of the message, which it is published with every time it is relayed
+func (m *Message) ID() string {
+	return m.id
+}
+
+// Topic retrieves the topic the message is published to
+func (m *Message) Topic() string {
+	return m.topic
+}
+
+// ContentType retrieves the content type of the payload of the message
+func (m *Message) ContentType() string {
+	return m.contentType
+}
+
+// Payload retrieves the payload of the message
+func (m *Message) Payload() []byte {
+	return m.payload
+}
+
+// Headers retrieves the headers of the message, which carry the request ID & trace context it was added in
+func (m *Message) Headers() map[string]string {
+	return m.headers
+}
+
+// Attempts retrieves the number of failed attempts to relay the message
+func (m *Message) Attempts() int {
+	return m.attempts
+}
+
+// NextAttemptAt retrieves the time from which the message is relayed
+func (m *Message) NextAttemptAt() time.Time {
+	return m.nextAttemptAt
+}
+
+// LastError retrieves the error the last failed attempt to relay the message failed with
+func (m *Message) LastError() string {
+	return m.lastError
+}
+
+// DispatchedAt retrieves the time the message was relayed at, nil if it is pending
+func (m *Message) DispatchedAt() *time.Time {
+	return m.dispatchedAt
+}
+
+// IsDispatched checks if the message has been relayed
+func (m *Message) IsDispatched() bool {
+	return m.dispatchedAt != nil
+}
+
+// IsDue checks if a pending message is due to be relayed at the given time
+func (m *Message) IsDue(now time.Time) bool {
+	return !m.IsDispatched() && !now.Before(m.nextAttemptAt)
+}
+
+// CreatedAt retrieves the created at timestamp of the message
+func (m *Message) CreatedAt() time.Time {
+	return m.createdAt
+}
+
+// UpdatedAt retrieves the updated at timestamp of the message
+func (m *Message) UpdatedAt() time.Time {
+	return m.updatedAt
+}
@@ -5,17 +5,24 @@ import (
 
 	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/user"
 	"github.com/BrianLusina/skillq/server/domain/id"
+	"github.com/BrianLusina/skillq/server/utils/security"
 )
 
+// MockVerificationCodeSecret is the secret the codes of mock user verifications are hashed with
+const MockVerificationCodeSecret = "verification-code-secret"
+
 func MockUserVerification(code string) user.UserVerification {
 	createdAt := time.Now()
 	updatedAt := time.Now()
+	userId := id.NewUUID()
 
 	verification := user.NewVerification(user.UserVerificationParams{
 		ID:         id.NewUUID(),
 		Code:       code,
-		UserId:     id.NewUUID(),
+		CodeHash:   security.HashCode(MockVerificationCodeSecret, userId.String(), code),
+		UserId:     userId,
 		IsVerified: false,
+		ExpiresAt:  createdAt.Add(15 * time.Minute),
 		CreatedAt:  createdAt,
 		UpdatedAt:  updatedAt,
 	})
@@ -0,0 +1,34 @@
+package user
+
+import "fmt"
+
+// Role is the role of a user which determines the operations the user is allowed to perform
+type Role string
+
+const (
+	// RoleAdmin is the role of a user who can manage every user
+	RoleAdmin Role = "admin"
+
+	// RoleManager is the role of a user who can read every user
+	RoleManager Role = "manager"
+
+	// RoleMember is the default role of a user who can only manage their own profile
+	RoleMember Role = "member"
+)
+
+// ParseRole parses a role from the given string. An empty string is parsed as a member
+func ParseRole(role string) (Role, error) {
+	switch r := Role(role); r {
+	case RoleAdmin, RoleManager, RoleMember:
+		return r, nil
+	case "":
+		return RoleMember, nil
+	default:
+		return "", fmt.Errorf("invalid role %s provided", role)
+	}
+}
+
+// String returns the string representation of the role
+func (r Role) String() string {
+	return string(r)
+}
@@ -8,6 +8,11 @@ import (
 	"github.com/pkg/errors"
 )
 
+var (
+	// ErrEmailNotPending is returned when confirming an email that is neither the user's email nor their pending email
+	ErrEmailNotPending = errors.New("email is not pending confirmation")
+)
+
 // User structure represents a user entity in the system
 type User struct {
 	entity.Entity
@@ -18,6 +23,12 @@ type User struct {
 	// email of the user
 	email values.Email
 
+	// pendingEmail is an email the user has asked to change to, which replaces their email once it is confirmed
+	pendingEmail *values.Email
+
+	// emailVerified is whether the user has confirmed they own their email
+	emailVerified bool
+
 	// hashedPassword is the user's hashed password
 	hashedPassword string
 
@@ -32,6 +43,9 @@ type User struct {
 
 	// jobTitle is the user's job title
 	jobTitle string
+
+	// role is the user's role
+	role Role
 }
 
 type UserParams struct {
@@ -44,6 +58,12 @@ type UserParams struct {
 	// Email is the user's email address
 	Email string
 
+	// PendingEmail is an email address the user has asked to change to & has not confirmed yet
+	PendingEmail string
+
+	// EmailVerified is whether the user has confirmed they own their email address
+	EmailVerified bool
+
 	// Password is the hashed password when creating a user
 	Password string
 
@@ -58,6 +78,9 @@ type UserParams struct {
 
 	// JobTitle is a user's job title
 	JobTitle string
+
+	// Role is the user's role, defaults to a member if not set
+	Role Role
 }
 
 // New creates a new user entity & potentially an error
@@ -68,6 +91,19 @@ func New(params UserParams) (User, error) {
 		return User{}, err
 	}
 
+	var pendingEmail *values.Email
+	if params.PendingEmail != "" {
+		pendingEmail, err = values.NewEmail(params.PendingEmail)
+		if err != nil {
+			return User{}, err
+		}
+	}
+
+	role, err := ParseRole(string(params.Role))
+	if err != nil {
+		return User{}, err
+	}
+
 	skillSet := map[string]bool{}
 
 	skills := params.Skills
@@ -82,11 +118,14 @@ func New(params UserParams) (User, error) {
 		Entity:         entity,
 		name:           params.Name,
 		email:          *email,
+		pendingEmail:   pendingEmail,
+		emailVerified:  params.EmailVerified,
 		imageData:      params.ImageData,
 		imageUrl:       params.ImageUrl,
 		skillSet:       skillSet,
 		jobTitle:       params.JobTitle,
 		hashedPassword: params.Password,
+		role:           role,
 	}, nil
 }
 
@@ -114,13 +153,49 @@ func (u *User) Email() string {
 	return u.email.Get()
 }
 
-// SetEmail updates the user's email address
-func (u *User) SetEmail(email string) (*User, error) {
-	err := u.email.Set(email)
+// PendingEmail returns the email address the user has asked to change to, which is empty if there is no pending change
+func (u *User) PendingEmail() string {
+	if u.pendingEmail == nil {
+		return ""
+	}
+	return u.pendingEmail.Get()
+}
+
+// EmailVerified returns whether the user has confirmed they own their email address
+func (u *User) EmailVerified() bool {
+	return u.emailVerified
+}
+
+// RequestEmailChange sets an email address the user wants to change to as pending. The user's email is only changed once
+// the pending email is confirmed. Requesting the user's current email cancels a pending change
+func (u *User) RequestEmailChange(email string) (*User, error) {
+	if email == u.email.Get() {
+		u.pendingEmail = nil
+		return u, nil
+	}
+
+	pendingEmail, err := values.NewEmail(email)
 	if err != nil {
 		return nil, errors.Wrapf(err, "invalid email %s provided", email)
 	}
 
+	u.pendingEmail = pendingEmail
+	return u, nil
+}
+
+// ConfirmEmail marks an email address of the user as verified. Confirming the pending email makes it the user's email,
+// while any other email than the user's email or pending email returns ErrEmailNotPending
+func (u *User) ConfirmEmail(email string) (*User, error) {
+	switch {
+	case email == u.email.Get():
+	case u.pendingEmail != nil && email == u.pendingEmail.Get():
+		u.email = *u.pendingEmail
+		u.pendingEmail = nil
+	default:
+		return nil, ErrEmailNotPending
+	}
+
+	u.emailVerified = true
 	return u, nil
 }
 
@@ -168,6 +243,18 @@ func (u *User) SetSkills(skills []string) *User {
 	return u
 }
 
+// ReplaceSkills replaces the user's current skills with the given skills
+func (u *User) ReplaceSkills(skills []string) *User {
+	skillSet := map[string]bool{}
+	for _, skill := range skills {
+		skillSet[skill] = true
+	}
+
+	u.skillSet = skillSet
+
+	return u
+}
+
 // JobTitle is the user's job title
 func (u *User) JobTitle() string {
 	return u.jobTitle
@@ -181,3 +268,19 @@ func (u *User) SetJobTitle(title string) (*User, error) {
 	}
 	return u, fmt.Errorf("invalid job title provided: %s", title)
 }
+
+// Role is the user's role
+func (u *User) Role() Role {
+	return u.role
+}
+
+// SetRole sets the role of the user. Unlike the role a user is created with, an empty role is rejected
+func (u *User) SetRole(role Role) (*User, error) {
+	switch role {
+	case RoleAdmin, RoleManager, RoleMember:
+		u.role = role
+		return u, nil
+	default:
+		return nil, fmt.Errorf("invalid role %s provided", role)
+	}
+}
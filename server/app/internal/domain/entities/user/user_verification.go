@@ -1,29 +1,59 @@
 package user
 
 import (
+	"errors"
 	"time"
 
 	"github.com/BrianLusina/skillq/server/domain/id"
 )
 
+var (
+	// ErrVerificationCodeInvalid is returned when a verification code does not match the code issued to a user
+	ErrVerificationCodeInvalid = errors.New("verification code is invalid")
+
+	// ErrVerificationCodeExpired is returned when a verification code is used after it has expired
+	ErrVerificationCodeExpired = errors.New("verification code has expired")
+
+	// ErrVerificationLocked is returned when a user has had too many failed attempts at verifying their email
+	ErrVerificationLocked = errors.New("verification is locked after too many failed attempts")
+)
+
 // UserVerification is a structure that contains user verification details
 type UserVerification struct {
 	id         id.UUID
 	code       string
+	codeHash   string
 	userId     id.UUID
+	email      string
 	isVerified bool
+	expiresAt  time.Time
 	createdAt  time.Time
 	updatedAt  time.Time
 }
 
 // UserVerificationParams defines a structure with fields used to create a user verification struct
 type UserVerificationParams struct {
-	ID         id.UUID
-	Code       string
-	UserId     id.UUID
+	ID id.UUID
+
+	// Code is the plain code, which is only known when the verification is issued. Verifications are stored & retrieved
+	// with only the hash of their code
+	Code string
+
+	// CodeHash is the hash of the code
+	CodeHash string
+
+	UserId id.UUID
+
+	// Email is the email address the code was sent to, which is the address that is verified with the code
+	Email string
+
 	IsVerified bool
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+
+	// ExpiresAt is when the code of the verification expires
+	ExpiresAt time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 // NewVerification creates a new user verification structure from a given params
@@ -31,8 +61,11 @@ func NewVerification(params UserVerificationParams) UserVerification {
 	return UserVerification{
 		id:         params.ID,
 		code:       params.Code,
+		codeHash:   params.CodeHash,
 		userId:     params.UserId,
+		email:      params.Email,
 		isVerified: params.IsVerified,
+		expiresAt:  params.ExpiresAt,
 		createdAt:  params.CreatedAt,
 		updatedAt:  params.UpdatedAt,
 	}
@@ -43,21 +76,36 @@ func (v *UserVerification) ID() id.UUID {
 	return v.id
 }
 
-// Code retrieves the code of the verification
+// Code retrieves the plain code of the verification, which is empty unless the verification has just been issued
 func (v *UserVerification) Code() string {
 	return v.code
 }
 
+// CodeHash retrieves the hash of the code of the verification
+func (v *UserVerification) CodeHash() string {
+	return v.codeHash
+}
+
 // UserID retrieves the user ID of the verification
 func (v *UserVerification) UserID() id.UUID {
 	return v.userId
 }
 
+// Email retrieves the email address the code of the verification was sent to
+func (v *UserVerification) Email() string {
+	return v.email
+}
+
 // IsVerified retrieves the verification status
 func (v *UserVerification) IsVerified() bool {
 	return v.isVerified
 }
 
+// ExpiresAt retrieves when the code of the verification expires
+func (v *UserVerification) ExpiresAt() time.Time {
+	return v.expiresAt
+}
+
 // CreatedAt retrieves the created at timestamp of the verification
 func (v *UserVerification) CreatedAt() time.Time {
 	return v.createdAt
@@ -67,3 +115,14 @@ func (v *UserVerification) CreatedAt() time.Time {
 func (v *UserVerification) UpdatedAt() time.Time {
 	return v.updatedAt
 }
+
+// CanVerify checks whether the verification can still be used at the given time, returning ErrVerificationCodeExpired once
+// its code has expired. Failed attempts are counted per user rather than per verification, so that issuing a new code does
+// not reset them
+func (v *UserVerification) CanVerify(now time.Time) error {
+	if !now.Before(v.expiresAt) {
+		return ErrVerificationCodeExpired
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package inbound
+
+import (
+	"context"
+	"time"
+)
+
+// LoginRequest to authenticate a user with their credentials
+type LoginRequest struct {
+	Email    string
+	Password string
+}
+
+// TokenResponse is the pair of tokens issued to an authenticated user. The access token is a short lived JWT used to
+// authenticate requests while the refresh token is a long lived opaque token used to get a new pair of tokens
+type TokenResponse struct {
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+}
+
+// ResetPasswordRequest to reset a user's password with the token sent to them
+type ResetPasswordRequest struct {
+	Token    string
+	Password string
+}
+
+// AuthService contains a method set defining the logic to handle authentication in the system
+type AuthService interface {
+	// Login authenticates a user with their credentials & issues a pair of tokens
+	Login(context.Context, LoginRequest) (*TokenResponse, error)
+
+	// Refresh exchanges a refresh token for a new pair of tokens, revoking the given refresh token
+	Refresh(ctx context.Context, refreshToken string) (*TokenResponse, error)
+
+	// Logout revokes the given refresh token
+	Logout(ctx context.Context, refreshToken string) error
+
+	// Authenticate verifies an access token returning the ID of the user it was issued to
+	Authenticate(ctx context.Context, accessToken string) (string, error)
+
+	// ForgotPassword sends the user with the given email a token to reset their password
+	ForgotPassword(ctx context.Context, email string) error
+
+	// IssuePasswordReset issues a token to the user with the given ID to reset their password with, returning the token
+	IssuePasswordReset(ctx context.Context, userID string) (string, error)
+
+	// ResetPassword sets a new password for the user a password reset token was sent to, revoking their refresh tokens
+	ResetPassword(context.Context, ResetPasswordRequest) error
+}
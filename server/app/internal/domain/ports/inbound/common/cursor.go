@@ -0,0 +1,66 @@
+package common
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/domain/id"
+)
+
+// CursorDirection is the direction to page through records from a cursor
+type CursorDirection string
+
+const (
+	// NEXT retrieves the records after the cursor
+	NEXT CursorDirection = "next"
+
+	// PREV retrieves the records before the cursor
+	PREV CursorDirection = "prev"
+)
+
+// Cursor is a position in a collection of records used for cursor based pagination. Records are keyed on their KeyID which
+// is sortable, so pages stay stable while new records are being added
+type Cursor struct {
+	// KeyID is the key ID of the record to page from. The record itself is not included in the page. An empty KeyID starts
+	// from the first page
+	KeyID string
+
+	// Direction is the direction to page in from the KeyID
+	Direction CursorDirection
+}
+
+// IsBackwards checks if the cursor pages through the records before its key ID
+func (c Cursor) IsBackwards() bool {
+	return c.Direction == PREV
+}
+
+// Encode encodes the cursor into an opaque string that can be handed to clients
+func (c Cursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", c.Direction, c.KeyID)))
+}
+
+// DecodeCursor decodes an opaque cursor string created with Cursor.Encode. An empty string decodes to a cursor for the first
+// page. A validation error is returned if the cursor is malformed
+func DecodeCursor(value string) (Cursor, error) {
+	if value == "" {
+		return Cursor{Direction: NEXT}, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return Cursor{}, errdefs.NewValidationError(fmt.Sprintf("invalid cursor %s", value), err)
+	}
+
+	direction, keyID, found := strings.Cut(string(decoded), ":")
+	if !found || (CursorDirection(direction) != NEXT && CursorDirection(direction) != PREV) {
+		return Cursor{}, errdefs.NewValidationError(fmt.Sprintf("invalid cursor %s", value), nil)
+	}
+
+	if _, err := id.StringToKeyID(keyID); err != nil {
+		return Cursor{}, errdefs.NewValidationError(fmt.Sprintf("invalid cursor %s", value), err)
+	}
+
+	return Cursor{KeyID: keyID, Direction: CursorDirection(direction)}, nil
+}
@@ -0,0 +1,40 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/domain/id"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	keyID := id.NewKeyID().String()
+
+	t.Run("encoded cursors decode to the same cursor", func(t *testing.T) {
+		for _, cursor := range []Cursor{{KeyID: keyID, Direction: NEXT}, {KeyID: keyID, Direction: PREV}} {
+			decoded, err := DecodeCursor(cursor.Encode())
+			assert.NoError(t, err)
+			assert.Equal(t, cursor, decoded)
+		}
+	})
+
+	t.Run("an empty cursor decodes to the first page", func(t *testing.T) {
+		decoded, err := DecodeCursor("")
+		assert.NoError(t, err)
+		assert.Equal(t, Cursor{Direction: NEXT}, decoded)
+		assert.False(t, decoded.IsBackwards())
+	})
+
+	t.Run("malformed cursors return a validation error", func(t *testing.T) {
+		for _, value := range []string{
+			"not base64!",
+			Cursor{KeyID: keyID, Direction: "sideways"}.Encode(),
+			Cursor{KeyID: "not-a-key-id", Direction: NEXT}.Encode(),
+		} {
+			_, err := DecodeCursor(value)
+			assert.Error(t, err)
+			assert.True(t, errdefs.IsValidation(err), value)
+		}
+	})
+}
@@ -0,0 +1,39 @@
+package common
+
+// Page is a page of records retrieved with either offset or cursor based pagination
+type Page[T any] struct {
+	// Items are the records in the page
+	Items []T
+
+	// Limit is the page size that was requested
+	Limit int
+
+	// Offset is the offset of the page when offset based pagination was used
+	Offset int
+
+	// Next is the cursor to the next page when cursor based pagination was used. Nil if this is the last page
+	Next *Cursor
+
+	// Prev is the cursor to the previous page when cursor based pagination was used. Nil if this is the first page
+	Prev *Cursor
+
+	// Total is the total number of records matching the request. Only set if it was requested as counting is expensive
+	Total *int64
+}
+
+// MapPage maps the items of a page using the given function keeping the pagination details of the page
+func MapPage[T any, R any](page Page[T], fn func(item T) R) Page[R] {
+	items := make([]R, 0, len(page.Items))
+	for _, item := range page.Items {
+		items = append(items, fn(item))
+	}
+
+	return Page[R]{
+		Items:  items,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+		Next:   page.Next,
+		Prev:   page.Prev,
+		Total:  page.Total,
+	}
+}
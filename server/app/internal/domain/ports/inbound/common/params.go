@@ -8,9 +8,16 @@ type RequestParams struct {
 	// Limit is the page size
 	Limit int
 
-	// Offset is the page number or cursor
+	// Offset is the number of records to skip when using offset based pagination
 	Offset int
 
+	// Cursor is the cursor to page from when using cursor based pagination. Cursor based pagination is used if it is set,
+	// in which case records are ordered by their key ID & the Offset & OrderBy are ignored
+	Cursor *Cursor
+
+	// IncludeTotal sets whether the total number of records matching the request should be counted
+	IncludeTotal bool
+
 	// OrderOption contains ordering options for the filter
 	OrderOption RequestParamsOrderOption
 }
@@ -32,6 +39,20 @@ func WithOffset(offset int) RequestParamOptions {
 	}
 }
 
+// WithCursor sets the cursor of a pagination request, enabling cursor based pagination
+func WithCursor(cursor Cursor) RequestParamOptions {
+	return func(rp *RequestParams) {
+		rp.Cursor = &cursor
+	}
+}
+
+// WithIncludeTotal sets whether to count the total number of records
+func WithIncludeTotal(includeTotal bool) RequestParamOptions {
+	return func(rp *RequestParams) {
+		rp.IncludeTotal = includeTotal
+	}
+}
+
 // WithIncludeDeleted sets whether to include deleted records
 func WithIncludeDeleted(hasDeleted bool) RequestParamOptions {
 	return func(rp *RequestParams) {
@@ -46,7 +67,7 @@ func WithSortOrder(sortOrder SortOrder) RequestParamOptions {
 	}
 }
 
-// WithSortOrder sets how to sort records
+// WithOrderBy sets the field to order records by
 func WithOrderBy(orderBy OrderBy) RequestParamOptions {
 	return func(rp *RequestParams) {
 		rp.OrderOption.OrderBy = orderBy
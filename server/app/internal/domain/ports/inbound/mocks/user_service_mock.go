@@ -15,7 +15,6 @@ import (
 
 	inbound "github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound"
 	common "github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound/common"
-	id "github.com/BrianLusina/skillq/server/domain/id"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -42,6 +41,21 @@ func (m *MockUserService) EXPECT() *MockUserServiceMockRecorder {
 	return m.recorder
 }
 
+// ConfirmUserEmail mocks base method.
+func (m *MockUserService) ConfirmUserEmail(ctx context.Context, userID, email string) (*inbound.UserResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmUserEmail", ctx, userID, email)
+	ret0, _ := ret[0].(*inbound.UserResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmUserEmail indicates an expected call of ConfirmUserEmail.
+func (mr *MockUserServiceMockRecorder) ConfirmUserEmail(ctx, userID, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmUserEmail", reflect.TypeOf((*MockUserService)(nil).ConfirmUserEmail), ctx, userID, email)
+}
+
 // CreateUser mocks base method.
 func (m *MockUserService) CreateUser(arg0 context.Context, arg1 inbound.UserRequest) (*inbound.UserResponse, error) {
 	m.ctrl.T.Helper()
@@ -57,6 +71,35 @@ func (mr *MockUserServiceMockRecorder) CreateUser(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserService)(nil).CreateUser), arg0, arg1)
 }
 
+// AssignUserRole mocks base method.
+func (m *MockUserService) AssignUserRole(ctx context.Context, userID, role string) (*inbound.UserResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignUserRole", ctx, userID, role)
+	ret0, _ := ret[0].(*inbound.UserResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssignUserRole indicates an expected call of AssignUserRole.
+func (mr *MockUserServiceMockRecorder) AssignUserRole(ctx, userID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignUserRole", reflect.TypeOf((*MockUserService)(nil).AssignUserRole), ctx, userID, role)
+}
+
+// BootstrapAdmin mocks base method.
+func (m *MockUserService) BootstrapAdmin(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapAdmin", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BootstrapAdmin indicates an expected call of BootstrapAdmin.
+func (mr *MockUserServiceMockRecorder) BootstrapAdmin(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapAdmin", reflect.TypeOf((*MockUserService)(nil).BootstrapAdmin), ctx)
+}
+
 // DeleteUser mocks base method.
 func (m *MockUserService) DeleteUser(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -72,10 +115,10 @@ func (mr *MockUserServiceMockRecorder) DeleteUser(arg0, arg1 any) *gomock.Call {
 }
 
 // GetAllUsers mocks base method.
-func (m *MockUserService) GetAllUsers(arg0 context.Context, arg1 common.RequestParams) ([]inbound.UserResponse, error) {
+func (m *MockUserService) GetAllUsers(arg0 context.Context, arg1 common.RequestParams) (common.Page[inbound.UserResponse], error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetAllUsers", arg0, arg1)
-	ret0, _ := ret[0].([]inbound.UserResponse)
+	ret0, _ := ret[0].(common.Page[inbound.UserResponse])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -87,10 +130,10 @@ func (mr *MockUserServiceMockRecorder) GetAllUsers(arg0, arg1 any) *gomock.Call
 }
 
 // GetAllUsersBySkill mocks base method.
-func (m *MockUserService) GetAllUsersBySkill(arg0 context.Context, arg1 string, arg2 common.RequestParams) ([]inbound.UserResponse, error) {
+func (m *MockUserService) GetAllUsersBySkill(arg0 context.Context, arg1 string, arg2 common.RequestParams) (common.Page[inbound.UserResponse], error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetAllUsersBySkill", arg0, arg1, arg2)
-	ret0, _ := ret[0].([]inbound.UserResponse)
+	ret0, _ := ret[0].(common.Page[inbound.UserResponse])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -117,7 +160,7 @@ func (mr *MockUserServiceMockRecorder) GetUserByUUID(arg0, arg1 any) *gomock.Cal
 }
 
 // UpdateUser mocks base method.
-func (m *MockUserService) UpdateUser(ctx context.Context, userID string, request inbound.UserRequest) (*inbound.UserResponse, error) {
+func (m *MockUserService) UpdateUser(ctx context.Context, userID string, request inbound.UserUpdateRequest) (*inbound.UserResponse, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateUser", ctx, userID, request)
 	ret0, _ := ret[0].(*inbound.UserResponse)
@@ -131,17 +174,17 @@ func (mr *MockUserServiceMockRecorder) UpdateUser(ctx, userID, request any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserService)(nil).UpdateUser), ctx, userID, request)
 }
 
-// UploadUserImage mocks base method.
-func (m *MockUserService) UploadUserImage(arg0 context.Context, arg1 id.UUID, arg2 inbound.UserImageRequest) (string, error) {
+// UpdateUserImage mocks base method.
+func (m *MockUserService) UpdateUserImage(ctx context.Context, userID string, image inbound.UserImageUpload) (*inbound.UserResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UploadUserImage", arg0, arg1, arg2)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "UpdateUserImage", ctx, userID, image)
+	ret0, _ := ret[0].(*inbound.UserResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UploadUserImage indicates an expected call of UploadUserImage.
-func (mr *MockUserServiceMockRecorder) UploadUserImage(arg0, arg1, arg2 any) *gomock.Call {
+// UpdateUserImage indicates an expected call of UpdateUserImage.
+func (mr *MockUserServiceMockRecorder) UpdateUserImage(ctx, userID, image any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadUserImage", reflect.TypeOf((*MockUserService)(nil).UploadUserImage), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserImage", reflect.TypeOf((*MockUserService)(nil).UpdateUserImage), ctx, userID, image)
 }
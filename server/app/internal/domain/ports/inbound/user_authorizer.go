@@ -0,0 +1,28 @@
+package inbound
+
+import "context"
+
+// UserAction is an operation on users that is subject to authorization
+type UserAction string
+
+const (
+	// UserActionRead reads a single user
+	UserActionRead UserAction = "user:read"
+
+	// UserActionUpdate updates a user
+	UserActionUpdate UserAction = "user:update"
+
+	// UserActionDelete deletes a user
+	UserActionDelete UserAction = "user:delete"
+
+	// UserActionAssignRole assigns a role to a user
+	UserActionAssignRole UserAction = "user:assign-role"
+)
+
+// UserAuthorizer decides whether the authenticated user in a context may perform an action on users. It is called in
+// front of the UserService operations by every transport. Listing users is public, so it is not subject to authorization
+type UserAuthorizer interface {
+	// Authorize returns nil if the authenticated user may perform the action on the target user. An unauthorized error is returned if the
+	// context has no authenticated user & a forbidden error if the authenticated user is not allowed to perform the action
+	Authorize(ctx context.Context, action UserAction, targetUserID string) error
+}
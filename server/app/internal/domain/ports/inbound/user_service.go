@@ -2,10 +2,10 @@ package inbound
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound/common"
-	"github.com/BrianLusina/skillq/server/domain/id"
 )
 
 // UserRequest to create a new user
@@ -14,16 +14,25 @@ type UserRequest struct {
 	Email    string
 	Password string
 	Skills   []string
-	Image    UserImageRequest
 	JobTitle string
 }
 
-// UserImageRequest is the user image data
-type UserImageRequest struct {
-	// Type is the type of the image
-	Type string
-	// Content is the content of the image
-	Content string
+// UserImageUpload is a user image that is streamed to storage as it is read
+type UserImageUpload struct {
+	// Content is the reader the raw bytes of the image are read from
+	Content io.Reader
+}
+
+// UserUpdateRequest to partially update an existing user. Only the fields that are set are changed, a nil field leaves the
+// existing value of the user untouched
+type UserUpdateRequest struct {
+	Name *string
+
+	// Email is a new email of the user, which is pending until the user confirms it with a code sent to it
+	Email *string
+
+	Skills   *[]string
+	JobTitle *string
 }
 
 // UserResponse for returning a user
@@ -40,6 +49,13 @@ type UserResponse struct {
 	Skills    []string
 	ImageUrl  string
 	JobTitle  string
+	Role      string
+
+	// PendingEmail is an email the user has asked to change to & not confirmed yet, it is empty if there is none
+	PendingEmail string
+
+	// EmailVerified is whether the user has confirmed they own their email
+	EmailVerified bool
 }
 
 // UserService contains a method set defining the logic to handle user management in the system
@@ -50,17 +66,27 @@ type UserService interface {
 	// GetUserByUUID retrieves a user given their UUID
 	GetUserByUUID(context.Context, string) (*UserResponse, error)
 
-	// GetAllUsers retrieves all users
-	GetAllUsers(context.Context, common.RequestParams) ([]UserResponse, error)
+	// GetAllUsers retrieves a page of all users
+	GetAllUsers(context.Context, common.RequestParams) (common.Page[UserResponse], error)
+
+	// GetAllUsersBySkill retrieves a page of all users with a given skill
+	GetAllUsersBySkill(context.Context, string, common.RequestParams) (common.Page[UserResponse], error)
+
+	// UpdateUserImage streams a new image for a user given their ID to blob storage & updates the user's image url
+	UpdateUserImage(ctx context.Context, userID string, image UserImageUpload) (*UserResponse, error)
+
+	// UpdateUser updates the fields of a user given their ID that are set in the request
+	UpdateUser(ctx context.Context, userID string, request UserUpdateRequest) (*UserResponse, error)
 
-	// GetAllUsersBySkill retrieves all users with a given skill
-	GetAllUsersBySkill(context.Context, string, common.RequestParams) ([]UserResponse, error)
+	// ConfirmUserEmail marks an email of a user given their ID as verified, making it the user's email if it is their
+	// pending email
+	ConfirmUserEmail(ctx context.Context, userID string, email string) (*UserResponse, error)
 
-	// UploadUserImage uploads a user image to blob storage & retrieves the image url
-	UploadUserImage(context.Context, id.UUID, UserImageRequest) (string, error)
+	// AssignUserRole assigns a role to a user given their ID
+	AssignUserRole(ctx context.Context, userID string, role string) (*UserResponse, error)
 
-	// UpdateUser updates a user given their ID
-	UpdateUser(ctx context.Context, userID string, request UserRequest) (*UserResponse, error)
+	// BootstrapAdmin makes the user with the configured bootstrap admin email an admin once they have verified it
+	BootstrapAdmin(ctx context.Context) error
 
 	// DeleteUser deletes a user given their ID
 	DeleteUser(context.Context, string) error
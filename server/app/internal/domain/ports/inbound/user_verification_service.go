@@ -20,4 +20,7 @@ type UserVerificationService interface {
 
 	// VerifyEmail verifies a user email
 	VerifyEmail(context.Context, VerifyEmailRequest) error
+
+	// ResendEmailVerification invalidates the codes previously issued to a user & sends them a new one
+	ResendEmailVerification(ctx context.Context, userUUID string) error
 }
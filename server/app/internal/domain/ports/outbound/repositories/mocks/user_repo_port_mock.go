@@ -15,6 +15,7 @@ import (
 
 	user "github.com/BrianLusina/skillq/server/app/internal/domain/entities/user"
 	common "github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound/common"
+	repositories "github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories"
 	id "github.com/BrianLusina/skillq/server/domain/id"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -72,10 +73,10 @@ func (mr *MockUserRepoPortMockRecorder) DeleteUserById(ctx, userID any) *gomock.
 }
 
 // GetAllUsers mocks base method.
-func (m *MockUserRepoPort) GetAllUsers(arg0 context.Context, arg1 common.RequestParams) ([]user.User, error) {
+func (m *MockUserRepoPort) GetAllUsers(arg0 context.Context, arg1 common.RequestParams) (common.Page[user.User], error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetAllUsers", arg0, arg1)
-	ret0, _ := ret[0].([]user.User)
+	ret0, _ := ret[0].(common.Page[user.User])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -87,10 +88,10 @@ func (mr *MockUserRepoPortMockRecorder) GetAllUsers(arg0, arg1 any) *gomock.Call
 }
 
 // GetAllUsersBySkill mocks base method.
-func (m *MockUserRepoPort) GetAllUsersBySkill(ctx context.Context, skill string, params common.RequestParams) ([]user.User, error) {
+func (m *MockUserRepoPort) GetAllUsersBySkill(ctx context.Context, skill string, params common.RequestParams) (common.Page[user.User], error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetAllUsersBySkill", ctx, skill, params)
-	ret0, _ := ret[0].([]user.User)
+	ret0, _ := ret[0].(common.Page[user.User])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -101,6 +102,21 @@ func (mr *MockUserRepoPortMockRecorder) GetAllUsersBySkill(ctx, skill, params an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUsersBySkill", reflect.TypeOf((*MockUserRepoPort)(nil).GetAllUsersBySkill), ctx, skill, params)
 }
 
+// GetUserByEmail mocks base method.
+func (m *MockUserRepoPort) GetUserByEmail(ctx context.Context, email string) (*user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", ctx, email)
+	ret0, _ := ret[0].(*user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockUserRepoPortMockRecorder) GetUserByEmail(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockUserRepoPort)(nil).GetUserByEmail), ctx, email)
+}
+
 // GetUserByUUID mocks base method.
 func (m *MockUserRepoPort) GetUserByUUID(arg0 context.Context, arg1 id.UUID) (*user.User, error) {
 	m.ctrl.T.Helper()
@@ -117,7 +133,7 @@ func (mr *MockUserRepoPortMockRecorder) GetUserByUUID(arg0, arg1 any) *gomock.Ca
 }
 
 // UpdateUser mocks base method.
-func (m *MockUserRepoPort) UpdateUser(arg0 context.Context, arg1 user.User) (*user.User, error) {
+func (m *MockUserRepoPort) UpdateUser(arg0 context.Context, arg1 repositories.UpdateUserRequest) (*user.User, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateUser", arg0, arg1)
 	ret0, _ := ret[0].(*user.User)
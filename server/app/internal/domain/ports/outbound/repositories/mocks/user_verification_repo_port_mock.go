@@ -12,8 +12,10 @@ package mockuserrepo
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	user "github.com/BrianLusina/skillq/server/app/internal/domain/entities/user"
+	repositories "github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories"
 	id "github.com/BrianLusina/skillq/server/domain/id"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -56,19 +58,33 @@ func (mr *MockUserVerificationRepoPortMockRecorder) CreateUserVerification(arg0,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserVerification", reflect.TypeOf((*MockUserVerificationRepoPort)(nil).CreateUserVerification), arg0, arg1)
 }
 
+// ExpireUserVerifications mocks base method.
+func (m *MockUserVerificationRepoPort) ExpireUserVerifications(ctx context.Context, userID id.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireUserVerifications", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExpireUserVerifications indicates an expected call of ExpireUserVerifications.
+func (mr *MockUserVerificationRepoPortMockRecorder) ExpireUserVerifications(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireUserVerifications", reflect.TypeOf((*MockUserVerificationRepoPort)(nil).ExpireUserVerifications), ctx, userID)
+}
+
 // GetUserVerificationByCode mocks base method.
-func (m *MockUserVerificationRepoPort) GetUserVerificationByCode(arg0 context.Context, arg1 string) (*user.UserVerification, error) {
+func (m *MockUserVerificationRepoPort) GetUserVerificationByCode(ctx context.Context, userID id.UUID, codeHash string) (*user.UserVerification, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserVerificationByCode", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetUserVerificationByCode", ctx, userID, codeHash)
 	ret0, _ := ret[0].(*user.UserVerification)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUserVerificationByCode indicates an expected call of GetUserVerificationByCode.
-func (mr *MockUserVerificationRepoPortMockRecorder) GetUserVerificationByCode(arg0, arg1 any) *gomock.Call {
+func (mr *MockUserVerificationRepoPortMockRecorder) GetUserVerificationByCode(ctx, userID, codeHash any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserVerificationByCode", reflect.TypeOf((*MockUserVerificationRepoPort)(nil).GetUserVerificationByCode), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserVerificationByCode", reflect.TypeOf((*MockUserVerificationRepoPort)(nil).GetUserVerificationByCode), ctx, userID, codeHash)
 }
 
 // GetUserVerificationByUUID mocks base method.
@@ -85,3 +101,46 @@ func (mr *MockUserVerificationRepoPortMockRecorder) GetUserVerificationByUUID(ar
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserVerificationByUUID", reflect.TypeOf((*MockUserVerificationRepoPort)(nil).GetUserVerificationByUUID), arg0, arg1)
 }
+
+// RecordUserVerificationAttempt mocks base method.
+func (m *MockUserVerificationRepoPort) RecordUserVerificationAttempt(ctx context.Context, userID id.UUID, maxAttempts int, window time.Duration) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordUserVerificationAttempt", ctx, userID, maxAttempts, window)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordUserVerificationAttempt indicates an expected call of RecordUserVerificationAttempt.
+func (mr *MockUserVerificationRepoPortMockRecorder) RecordUserVerificationAttempt(ctx, userID, maxAttempts, window any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordUserVerificationAttempt", reflect.TypeOf((*MockUserVerificationRepoPort)(nil).RecordUserVerificationAttempt), ctx, userID, maxAttempts, window)
+}
+
+// ResetUserVerificationAttempts mocks base method.
+func (m *MockUserVerificationRepoPort) ResetUserVerificationAttempts(ctx context.Context, userID id.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetUserVerificationAttempts", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetUserVerificationAttempts indicates an expected call of ResetUserVerificationAttempts.
+func (mr *MockUserVerificationRepoPortMockRecorder) ResetUserVerificationAttempts(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetUserVerificationAttempts", reflect.TypeOf((*MockUserVerificationRepoPort)(nil).ResetUserVerificationAttempts), ctx, userID)
+}
+
+// UpdateUserVerification mocks base method.
+func (m *MockUserVerificationRepoPort) UpdateUserVerification(arg0 context.Context, arg1 repositories.UpdateUserVerificationRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserVerification", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserVerification indicates an expected call of UpdateUserVerification.
+func (mr *MockUserVerificationRepoPortMockRecorder) UpdateUserVerification(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserVerification", reflect.TypeOf((*MockUserVerificationRepoPort)(nil).UpdateUserVerification), arg0, arg1)
+}
@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/outbox"
+)
+
+// OutboxRepoPort handles the outbox repository interface
+type OutboxRepoPort interface {
+	// AddMessage adds a pending message to the outbox
+	AddMessage(ctx context.Context, message outbox.Message) error
+
+	// ClaimDueMessage claims the pending message that has been due to be relayed the longest at the given time for the
+	// lease, so that no other relay relays it until the lease passes. A claimed message that is neither marked dispatched
+	// nor failed, such as when the relay stops while relaying it, is due again once the lease passes. A not found error
+	// is returned if no message is due
+	ClaimDueMessage(ctx context.Context, now time.Time, lease time.Duration) (outbox.Message, error)
+
+	// MarkMessageDispatched marks a message as relayed at the given time, so that it is not relayed again
+	MarkMessageDispatched(ctx context.Context, messageID string, dispatchedAt time.Time) error
+
+	// MarkMessageFailed records a failed attempt to relay a message, which is pending again & relayed from the next attempt
+	// time
+	MarkMessageFailed(ctx context.Context, messageID string, nextAttemptAt time.Time, reason string) error
+}
@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/auth"
+	"github.com/BrianLusina/skillq/server/domain/id"
+)
+
+// PasswordResetRepoPort handles password reset repository interface
+type PasswordResetRepoPort interface {
+	// CreatePasswordReset creates a password reset in the repository
+	CreatePasswordReset(context.Context, auth.PasswordReset) (*auth.PasswordReset, error)
+
+	// GetPasswordResetByHash retrieves a password reset given the hash of its token
+	GetPasswordResetByHash(ctx context.Context, tokenHash string) (*auth.PasswordReset, error)
+
+	// UsePasswordReset marks a password reset as used given its ID. A not found error is returned if the password reset does
+	// not exist or has already been used, so only one caller can use a password reset
+	UsePasswordReset(ctx context.Context, passwordResetID id.UUID) error
+}
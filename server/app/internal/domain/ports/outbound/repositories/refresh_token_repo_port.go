@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/auth"
+	"github.com/BrianLusina/skillq/server/domain/id"
+)
+
+// RefreshTokenRepoPort handles refresh token repository interface
+type RefreshTokenRepoPort interface {
+	// CreateRefreshToken creates a refresh token in the repository
+	CreateRefreshToken(context.Context, auth.RefreshToken) (*auth.RefreshToken, error)
+
+	// GetRefreshTokenByHash retrieves a refresh token given the hash of the token
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*auth.RefreshToken, error)
+
+	// RevokeRefreshToken revokes a refresh token given its ID. A not found error is returned if the token does not exist or
+	// has already been revoked, so only one caller can revoke a token
+	RevokeRefreshToken(ctx context.Context, tokenID id.UUID) error
+
+	// RevokeUserRefreshTokens revokes all the refresh tokens of a given user
+	RevokeUserRefreshTokens(ctx context.Context, userID id.UUID) error
+}
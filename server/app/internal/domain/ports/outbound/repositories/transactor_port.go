@@ -0,0 +1,11 @@
+package repositories
+
+import "context"
+
+// TransactorPort runs functions in transactions across repositories
+type TransactorPort interface {
+	// WithTransaction runs fn in a transaction, which is committed if fn returns no error & aborted otherwise. Only the
+	// changes repositories make with the context passed to fn are part of the transaction. fn may be called more than
+	// once if the transaction is retried
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -8,6 +8,28 @@ import (
 	"github.com/BrianLusina/skillq/server/domain/id"
 )
 
+// UpdateUserRequest represents the fields of a user to update. Only the fields that are set are updated
+type UpdateUserRequest struct {
+	UserID   id.UUID
+	Name     *string
+	Email    *string
+	Skills   *[]string
+	ImageUrl *string
+	JobTitle *string
+
+	// PasswordHash is the hash of a new password of the user
+	PasswordHash *string
+
+	// PendingEmail is an email the user has asked to change to, an empty pending email clears a pending change
+	PendingEmail *string
+
+	// EmailVerified is whether the user has confirmed they own their email
+	EmailVerified *bool
+
+	// Role is a new role of the user
+	Role *user.Role
+}
+
 // UserRepoPort handles repository interface
 type UserRepoPort interface {
 	// CreateUser creates a user in the repository
@@ -16,14 +38,17 @@ type UserRepoPort interface {
 	// GetUserByUUID retrieves a user given their UUID
 	GetUserByUUID(context.Context, id.UUID) (*user.User, error)
 
-	// GetAllUsers retrieves all users
-	GetAllUsers(context.Context, common.RequestParams) ([]user.User, error)
+	// GetUserByEmail retrieves a user given their email address
+	GetUserByEmail(ctx context.Context, email string) (*user.User, error)
+
+	// GetAllUsers retrieves a page of all users
+	GetAllUsers(context.Context, common.RequestParams) (common.Page[user.User], error)
 
-	// GetAllUsersBySkill retrieves all the users of a given skill
-	GetAllUsersBySkill(ctx context.Context, skill string, params common.RequestParams) ([]user.User, error)
+	// GetAllUsersBySkill retrieves a page of all the users of a given skill
+	GetAllUsersBySkill(ctx context.Context, skill string, params common.RequestParams) (common.Page[user.User], error)
 
-	// UpdateUser updates a user given their ID
-	UpdateUser(context.Context, user.User) (*user.User, error)
+	// UpdateUser updates the set fields of a user given their ID & returns the updated user
+	UpdateUser(context.Context, UpdateUserRequest) (*user.User, error)
 
 	// DeleteUserById deletes a given user by the ID
 	DeleteUserById(ctx context.Context, userID id.UUID) error
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/user"
 	"github.com/BrianLusina/skillq/server/domain/id"
@@ -9,7 +10,7 @@ import (
 
 // UpdateUserVerificationRequest represents the required fields to update a user verification status
 type UpdateUserVerificationRequest struct {
-	UserID     id.UUID
+	ID         id.UUID
 	IsVerified bool
 }
 
@@ -21,8 +22,20 @@ type UserVerificationRepoPort interface {
 	// GetUserVerificationByUUID retrieves a user verification given the UUID
 	GetUserVerificationByUUID(context.Context, id.UUID) (*user.UserVerification, error)
 
-	// GetUserVerificationByCode retrieves a user verification given the code
-	GetUserVerificationByCode(context.Context, string) (*user.UserVerification, error)
+	// GetUserVerificationByCode retrieves the latest verification of a user with the given code hash
+	GetUserVerificationByCode(ctx context.Context, userID id.UUID, codeHash string) (*user.UserVerification, error)
+
+	// RecordUserVerificationAttempt atomically records an attempt of a user at verifying their email & returns the number of
+	// attempts they have made in the window that started with their first attempt. Once the user has made maxAttempts attempts
+	// in the window, the attempt is not recorded & user.ErrVerificationLocked is returned
+	RecordUserVerificationAttempt(ctx context.Context, userID id.UUID, maxAttempts int, window time.Duration) (int, error)
+
+	// ResetUserVerificationAttempts clears the attempts a user has made at verifying their email
+	ResetUserVerificationAttempts(ctx context.Context, userID id.UUID) error
+
+	// ExpireUserVerifications expires the verifications of a user that have not been verified, so that the codes issued with them
+	// can no longer be used
+	ExpireUserVerifications(ctx context.Context, userID id.UUID) error
 
 	// UpdateUserVerification updates the user verification
 	UpdateUserVerification(context.Context, UpdateUserVerificationRequest) error
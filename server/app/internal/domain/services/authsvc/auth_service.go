@@ -0,0 +1,218 @@
+package authsvc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/auth"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/publishers"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories"
+	"github.com/BrianLusina/skillq/server/app/pkg/tasks"
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/domain/id"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/utils/security"
+	"github.com/pkg/errors"
+)
+
+// refreshTokenSize is the number of random bytes used to generate a refresh token
+const refreshTokenSize = 32
+
+// Config is the configuration of the auth service
+type Config struct {
+	// JWT is the configuration used to sign access tokens
+	JWT security.JWTConfig
+
+	// RefreshTokenTTL is how long a refresh token is valid for after it is issued
+	RefreshTokenTTL time.Duration
+
+	// PasswordResetTTL is how long a password reset token is valid for after it is issued
+	PasswordResetTTL time.Duration
+}
+
+// authService is the structure for the business logic handling authentication
+type authService struct {
+	jwtManager                 *security.JWTManager
+	refreshTokenTTL            time.Duration
+	passwordResetTTL           time.Duration
+	userRepo                   repositories.UserRepoPort
+	refreshTokenRepo           repositories.RefreshTokenRepoPort
+	passwordResetRepo          repositories.PasswordResetRepoPort
+	transactor                 repositories.TransactorPort
+	sendPasswordResetPublisher publishers.TaskPublisher[tasks.SendPasswordReset]
+}
+
+var _ inbound.AuthService = (*authService)(nil)
+
+// New creates a new auth service implementation of the auth use case
+func New(
+	config Config,
+	userRepo repositories.UserRepoPort,
+	refreshTokenRepo repositories.RefreshTokenRepoPort,
+	passwordResetRepo repositories.PasswordResetRepoPort,
+	transactor repositories.TransactorPort,
+	sendPasswordResetPublisher publishers.TaskPublisher[tasks.SendPasswordReset],
+) (inbound.AuthService, error) {
+	jwtManager, err := security.NewJWTManager(config.JWT)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid jwt configuration")
+	}
+
+	if config.RefreshTokenTTL <= 0 {
+		return nil, fmt.Errorf("refresh token ttl must be positive, got %s", config.RefreshTokenTTL)
+	}
+
+	if config.PasswordResetTTL <= 0 {
+		return nil, fmt.Errorf("password reset ttl must be positive, got %s", config.PasswordResetTTL)
+	}
+
+	return &authService{
+		jwtManager:                 jwtManager,
+		refreshTokenTTL:            config.RefreshTokenTTL,
+		passwordResetTTL:           config.PasswordResetTTL,
+		userRepo:                   userRepo,
+		refreshTokenRepo:           refreshTokenRepo,
+		passwordResetRepo:          passwordResetRepo,
+		transactor:                 transactor,
+		sendPasswordResetPublisher: sendPasswordResetPublisher,
+	}, nil
+}
+
+// Login authenticates a user with their credentials & issues a pair of tokens
+func (svc *authService) Login(ctx context.Context, request inbound.LoginRequest) (*inbound.TokenResponse, error) {
+	user, err := svc.userRepo.GetUserByEmail(ctx, request.Email)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, errdefs.NewUnauthorizedError("invalid email or password", nil)
+		}
+		return nil, err
+	}
+
+	if !security.CheckPasswordHash(request.Password, user.Password()) {
+		return nil, errdefs.NewUnauthorizedError("invalid email or password", nil)
+	}
+
+	return svc.issueTokens(ctx, user.UUID())
+}
+
+// Refresh exchanges a refresh token for a new pair of tokens, revoking the given refresh token. Presenting a refresh token
+// that has already been revoked indicates that it has been stolen, so every refresh token of the user is revoked
+func (svc *authService) Refresh(ctx context.Context, refreshToken string) (*inbound.TokenResponse, error) {
+	existingToken, err := svc.getRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingToken.IsRevoked() {
+		return nil, svc.handleRefreshTokenReuse(ctx, existingToken.UserID())
+	}
+
+	if existingToken.IsExpired(time.Now()) {
+		return nil, errdefs.NewUnauthorizedError("refresh token has expired", nil)
+	}
+
+	// revoking only succeeds for one caller, so a concurrent refresh with the same token is treated as reuse
+	err = svc.refreshTokenRepo.RevokeRefreshToken(ctx, existingToken.ID())
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, svc.handleRefreshTokenReuse(ctx, existingToken.UserID())
+		}
+		return nil, err
+	}
+
+	return svc.issueTokens(ctx, existingToken.UserID())
+}
+
+// Logout revokes the given refresh token
+func (svc *authService) Logout(ctx context.Context, refreshToken string) error {
+	existingToken, err := svc.getRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return err
+	}
+
+	if existingToken.IsRevoked() {
+		return nil
+	}
+
+	err = svc.refreshTokenRepo.RevokeRefreshToken(ctx, existingToken.ID())
+	if err != nil && !errdefs.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// Authenticate verifies an access token returning the ID of the user it was issued to
+func (svc *authService) Authenticate(_ context.Context, accessToken string) (string, error) {
+	claims, err := svc.jwtManager.Verify(accessToken)
+	if err != nil {
+		return "", errdefs.NewUnauthorizedError("invalid access token", err)
+	}
+
+	return claims.Subject, nil
+}
+
+// getRefreshToken retrieves the stored refresh token of the given raw refresh token
+func (svc *authService) getRefreshToken(ctx context.Context, refreshToken string) (*auth.RefreshToken, error) {
+	if refreshToken == "" {
+		return nil, errdefs.NewUnauthorizedError("invalid refresh token", nil)
+	}
+
+	existingToken, err := svc.refreshTokenRepo.GetRefreshTokenByHash(ctx, security.HashToken(refreshToken))
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, errdefs.NewUnauthorizedError("invalid refresh token", nil)
+		}
+		return nil, err
+	}
+
+	return existingToken, nil
+}
+
+// handleRefreshTokenReuse revokes every refresh token of a user whose revoked refresh token has been presented
+func (svc *authService) handleRefreshTokenReuse(ctx context.Context, userID id.UUID) error {
+	logger.FromContext(ctx).Warn("Revoked refresh token reused, revoking every refresh token of user ", userID)
+
+	if err := svc.refreshTokenRepo.RevokeUserRefreshTokens(ctx, userID); err != nil {
+		return err
+	}
+
+	return errdefs.NewUnauthorizedError("refresh token has been revoked", nil)
+}
+
+// issueTokens issues a new access token & refresh token to the given user, storing the hash of the refresh token
+func (svc *authService) issueTokens(ctx context.Context, userID id.UUID) (*inbound.TokenResponse, error) {
+	accessToken, accessTokenExpiresAt, err := svc.jwtManager.Generate(userID.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate access token")
+	}
+
+	refreshToken, err := security.GenerateToken(refreshTokenSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate refresh token")
+	}
+
+	now := time.Now()
+	refreshTokenExpiresAt := now.Add(svc.refreshTokenTTL)
+
+	_, err = svc.refreshTokenRepo.CreateRefreshToken(ctx, auth.NewRefreshToken(auth.RefreshTokenParams{
+		ID:        id.NewUUID(),
+		UserId:    userID,
+		TokenHash: security.HashToken(refreshToken),
+		ExpiresAt: refreshTokenExpiresAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	return &inbound.TokenResponse{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessTokenExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshTokenExpiresAt,
+	}, nil
+}
@@ -0,0 +1,489 @@
+package authsvc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/auth"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/user"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound"
+	mockpublishers "github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/publishers/mocks"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories"
+	mockuserrepo "github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories/mocks"
+	"github.com/BrianLusina/skillq/server/app/pkg/tasks"
+	"github.com/BrianLusina/skillq/server/domain/entity"
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/domain/id"
+	"github.com/BrianLusina/skillq/server/utils/security"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuthService(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "AuthService Suite")
+}
+
+var _ = Describe("Auth Service", func() {
+	t := GinkgoT()
+
+	var (
+		mockCtrl             *gomock.Controller
+		mockUserRepo         *mockuserrepo.MockUserRepoPort
+		mockRefreshTokenRepo *mockuserrepo.MockRefreshTokenRepoPort
+		mockPasswordReset    *mockuserrepo.MockPasswordResetRepoPort
+		mockTransactor       *mockuserrepo.MockTransactorPort
+		transactionErr       error
+		mockPublisher        *mockpublishers.MockTaskPublisher[tasks.SendPasswordReset]
+		authSvc              inbound.AuthService
+	)
+
+	config := Config{
+		JWT: security.JWTConfig{
+			Secret: "test-secret",
+			Issuer: "skillq",
+			TTL:    time.Minute,
+		},
+		RefreshTokenTTL:  time.Hour,
+		PasswordResetTTL: time.Hour,
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(t)
+		mockUserRepo = mockuserrepo.NewMockUserRepoPort(mockCtrl)
+		mockRefreshTokenRepo = mockuserrepo.NewMockRefreshTokenRepoPort(mockCtrl)
+		mockPasswordReset = mockuserrepo.NewMockPasswordResetRepoPort(mockCtrl)
+		mockPublisher = mockpublishers.NewMockTaskPublisher[tasks.SendPasswordReset](mockCtrl)
+		mockTransactor = mockuserrepo.NewMockTransactorPort(mockCtrl)
+
+		// the transaction runs the function it is given & records its error, which rolls the transaction back
+		transactionErr = nil
+		mockTransactor.EXPECT().WithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(context.Context) error) error {
+				transactionErr = fn(ctx)
+				return transactionErr
+			},
+		).AnyTimes()
+
+		var err error
+		authSvc, err = New(config, mockUserRepo, mockRefreshTokenRepo, mockPasswordReset, mockTransactor, mockPublisher)
+		assert.NoError(t, err)
+	})
+
+	ctx := context.Background()
+
+	newUser := func(password string) *user.User {
+		hashedPassword, err := security.HashPassword(password)
+		assert.NoError(t, err)
+
+		u, err := user.New(user.UserParams{
+			EntityParams: entity.EntityParams{
+				EntityIDParams: entity.EntityIDParams{
+					UUID:  id.NewUUID(),
+					KeyID: id.NewKeyID(),
+					XID:   id.NewXid(),
+				},
+				EntityTimestampParams: entity.EntityTimestampParams{
+					CreatedAt: time.Now(),
+					UpdatedAt: time.Now(),
+				},
+				Metadata: map[string]any{},
+			},
+			Name:     "John Doe",
+			Email:    "john@example.com",
+			Skills:   []string{"go"},
+			JobTitle: "The Boss",
+			Password: hashedPassword,
+		})
+		assert.NoError(t, err)
+
+		return &u
+	}
+
+	newRefreshToken := func(token string, expiresAt time.Time, revokedAt *time.Time) *auth.RefreshToken {
+		refreshToken := auth.NewRefreshToken(auth.RefreshTokenParams{
+			ID:        id.NewUUID(),
+			UserId:    id.NewUUID(),
+			TokenHash: security.HashToken(token),
+			ExpiresAt: expiresAt,
+			RevokedAt: revokedAt,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		})
+		return &refreshToken
+	}
+
+	Context("Creating the auth service", func() {
+		It("should return error when the configuration is invalid", func() {
+			_, err := New(Config{JWT: config.JWT, PasswordResetTTL: time.Hour}, mockUserRepo, mockRefreshTokenRepo, mockPasswordReset, mockTransactor, mockPublisher)
+			assert.Error(t, err)
+
+			_, err = New(Config{RefreshTokenTTL: time.Hour, PasswordResetTTL: time.Hour}, mockUserRepo, mockRefreshTokenRepo, mockPasswordReset, mockTransactor, mockPublisher)
+			assert.Error(t, err)
+
+			_, err = New(Config{JWT: config.JWT, RefreshTokenTTL: time.Hour}, mockUserRepo, mockRefreshTokenRepo, mockPasswordReset, mockTransactor, mockPublisher)
+			assert.Error(t, err)
+		})
+	})
+
+	Context("Logging in", func() {
+		It("should return unauthorized error when the user does not exist", func() {
+			defer mockCtrl.Finish()
+
+			mockUserRepo.EXPECT().GetUserByEmail(ctx, "john@example.com").
+				Return(nil, errdefs.NewNotFoundError("user not found", nil))
+			mockRefreshTokenRepo.EXPECT().CreateRefreshToken(ctx, gomock.Any()).Times(0)
+
+			actual, err := authSvc.Login(ctx, inbound.LoginRequest{Email: "john@example.com", Password: "password"})
+			assert.Nil(t, actual)
+			assert.True(t, errdefs.IsUnauthorized(err))
+		})
+
+		It("should return unauthorized error when the password is wrong", func() {
+			defer mockCtrl.Finish()
+
+			existingUser := newUser("password")
+			mockUserRepo.EXPECT().GetUserByEmail(ctx, existingUser.Email()).Return(existingUser, nil)
+			mockRefreshTokenRepo.EXPECT().CreateRefreshToken(ctx, gomock.Any()).Times(0)
+
+			actual, err := authSvc.Login(ctx, inbound.LoginRequest{Email: existingUser.Email(), Password: "wrong"})
+			assert.Nil(t, actual)
+			assert.True(t, errdefs.IsUnauthorized(err))
+		})
+
+		It("should return error when the repo fails to retrieve the user", func() {
+			defer mockCtrl.Finish()
+
+			repoErr := errors.New("failed to retrieve user")
+			mockUserRepo.EXPECT().GetUserByEmail(ctx, "john@example.com").Return(nil, repoErr)
+
+			actual, err := authSvc.Login(ctx, inbound.LoginRequest{Email: "john@example.com", Password: "password"})
+			assert.Nil(t, actual)
+			assert.ErrorIs(t, err, repoErr)
+		})
+
+		It("should issue tokens storing only the hash of the refresh token", func() {
+			defer mockCtrl.Finish()
+
+			existingUser := newUser("password")
+			mockUserRepo.EXPECT().GetUserByEmail(ctx, existingUser.Email()).Return(existingUser, nil)
+
+			var stored auth.RefreshToken
+			mockRefreshTokenRepo.EXPECT().CreateRefreshToken(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, refreshToken auth.RefreshToken) (*auth.RefreshToken, error) {
+					stored = refreshToken
+					return &refreshToken, nil
+				})
+
+			actual, err := authSvc.Login(ctx, inbound.LoginRequest{Email: existingUser.Email(), Password: "password"})
+			assert.NoError(t, err)
+			assert.NotEmpty(t, actual.AccessToken)
+			assert.NotEmpty(t, actual.RefreshToken)
+			assert.Equal(t, security.HashToken(actual.RefreshToken), stored.TokenHash())
+			assert.Equal(t, existingUser.UUID(), stored.UserID())
+			assert.WithinDuration(t, time.Now().Add(time.Hour), actual.RefreshTokenExpiresAt, time.Second)
+
+			userID, err := authSvc.Authenticate(ctx, actual.AccessToken)
+			assert.NoError(t, err)
+			assert.Equal(t, existingUser.UUID().String(), userID)
+		})
+	})
+
+	Context("Refreshing tokens", func() {
+		It("should return unauthorized error when the refresh token is unknown", func() {
+			defer mockCtrl.Finish()
+
+			mockRefreshTokenRepo.EXPECT().GetRefreshTokenByHash(ctx, security.HashToken("unknown")).
+				Return(nil, errdefs.NewNotFoundError("refresh token not found", nil))
+
+			actual, err := authSvc.Refresh(ctx, "unknown")
+			assert.Nil(t, actual)
+			assert.True(t, errdefs.IsUnauthorized(err))
+		})
+
+		It("should return unauthorized error when the refresh token has expired", func() {
+			defer mockCtrl.Finish()
+
+			existingToken := newRefreshToken("token", time.Now().Add(-time.Minute), nil)
+			mockRefreshTokenRepo.EXPECT().GetRefreshTokenByHash(ctx, existingToken.TokenHash()).Return(existingToken, nil)
+			mockRefreshTokenRepo.EXPECT().RevokeRefreshToken(ctx, gomock.Any()).Times(0)
+
+			actual, err := authSvc.Refresh(ctx, "token")
+			assert.Nil(t, actual)
+			assert.True(t, errdefs.IsUnauthorized(err))
+		})
+
+		It("should rotate the refresh token", func() {
+			defer mockCtrl.Finish()
+
+			existingToken := newRefreshToken("token", time.Now().Add(time.Hour), nil)
+			mockRefreshTokenRepo.EXPECT().GetRefreshTokenByHash(ctx, existingToken.TokenHash()).Return(existingToken, nil)
+			mockRefreshTokenRepo.EXPECT().RevokeRefreshToken(ctx, existingToken.ID()).Return(nil)
+			mockRefreshTokenRepo.EXPECT().CreateRefreshToken(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, refreshToken auth.RefreshToken) (*auth.RefreshToken, error) {
+					return &refreshToken, nil
+				})
+
+			actual, err := authSvc.Refresh(ctx, "token")
+			assert.NoError(t, err)
+			assert.NotEqual(t, "token", actual.RefreshToken)
+		})
+
+		It("should revoke every refresh token of the user when a revoked refresh token is reused", func() {
+			defer mockCtrl.Finish()
+
+			revokedAt := time.Now().Add(-time.Minute)
+			existingToken := newRefreshToken("token", time.Now().Add(time.Hour), &revokedAt)
+			mockRefreshTokenRepo.EXPECT().GetRefreshTokenByHash(ctx, existingToken.TokenHash()).Return(existingToken, nil)
+			mockRefreshTokenRepo.EXPECT().RevokeUserRefreshTokens(ctx, existingToken.UserID()).Return(nil)
+			mockRefreshTokenRepo.EXPECT().CreateRefreshToken(ctx, gomock.Any()).Times(0)
+
+			actual, err := authSvc.Refresh(ctx, "token")
+			assert.Nil(t, actual)
+			assert.True(t, errdefs.IsUnauthorized(err))
+		})
+
+		It("should treat losing a concurrent refresh as reuse", func() {
+			defer mockCtrl.Finish()
+
+			existingToken := newRefreshToken("token", time.Now().Add(time.Hour), nil)
+			mockRefreshTokenRepo.EXPECT().GetRefreshTokenByHash(ctx, existingToken.TokenHash()).Return(existingToken, nil)
+			mockRefreshTokenRepo.EXPECT().RevokeRefreshToken(ctx, existingToken.ID()).
+				Return(errdefs.NewNotFoundError("refresh token not found", nil))
+			mockRefreshTokenRepo.EXPECT().RevokeUserRefreshTokens(ctx, existingToken.UserID()).Return(nil)
+			mockRefreshTokenRepo.EXPECT().CreateRefreshToken(ctx, gomock.Any()).Times(0)
+
+			actual, err := authSvc.Refresh(ctx, "token")
+			assert.Nil(t, actual)
+			assert.True(t, errdefs.IsUnauthorized(err))
+		})
+	})
+
+	Context("Logging out", func() {
+		It("should revoke the refresh token", func() {
+			defer mockCtrl.Finish()
+
+			existingToken := newRefreshToken("token", time.Now().Add(time.Hour), nil)
+			mockRefreshTokenRepo.EXPECT().GetRefreshTokenByHash(ctx, existingToken.TokenHash()).Return(existingToken, nil)
+			mockRefreshTokenRepo.EXPECT().RevokeRefreshToken(ctx, existingToken.ID()).Return(nil)
+
+			err := authSvc.Logout(ctx, "token")
+			assert.NoError(t, err)
+		})
+
+		It("should return unauthorized error when the refresh token is empty", func() {
+			defer mockCtrl.Finish()
+
+			mockRefreshTokenRepo.EXPECT().GetRefreshTokenByHash(ctx, gomock.Any()).Times(0)
+
+			err := authSvc.Logout(ctx, "")
+			assert.True(t, errdefs.IsUnauthorized(err))
+		})
+	})
+
+	Context("Authenticating access tokens", func() {
+		It("should return unauthorized error for an invalid access token", func() {
+			userID, err := authSvc.Authenticate(ctx, "invalid")
+			assert.Empty(t, userID)
+			assert.True(t, errdefs.IsUnauthorized(err))
+		})
+	})
+
+	newPasswordReset := func(token string, expiresAt time.Time, usedAt *time.Time) *auth.PasswordReset {
+		passwordReset := auth.NewPasswordReset(auth.PasswordResetParams{
+			ID:        id.NewUUID(),
+			UserId:    id.NewUUID(),
+			TokenHash: security.HashToken(token),
+			ExpiresAt: expiresAt,
+			UsedAt:    usedAt,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		})
+		return &passwordReset
+	}
+
+	Context("Forgetting a password", func() {
+		It("should publish a task to email a token to the user without issuing the token", func() {
+			defer mockCtrl.Finish()
+
+			existingUser := newUser("secret")
+			mockUserRepo.EXPECT().GetUserByEmail(ctx, existingUser.Email()).Return(existingUser, nil).Times(1)
+			mockPasswordReset.EXPECT().CreatePasswordReset(ctx, gomock.Any()).Times(0)
+
+			var published tasks.SendPasswordReset
+			mockPublisher.EXPECT().Publish(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, task tasks.SendPasswordReset) error {
+					published = task
+					return nil
+				}).Times(1)
+
+			err := authSvc.ForgotPassword(ctx, existingUser.Email())
+			assert.NoError(t, err)
+
+			assert.Equal(t, existingUser.UUID().String(), published.UserUUID)
+			assert.Equal(t, existingUser.Email(), published.Email)
+		})
+
+		It("should not reveal that an email does not belong to a user", func() {
+			defer mockCtrl.Finish()
+
+			mockUserRepo.EXPECT().GetUserByEmail(ctx, "unknown@example.com").
+				Return(nil, errdefs.NewNotFoundError("user not found", nil)).Times(1)
+			mockPasswordReset.EXPECT().CreatePasswordReset(ctx, gomock.Any()).Times(0)
+			mockPublisher.EXPECT().Publish(ctx, gomock.Any()).Times(0)
+
+			err := authSvc.ForgotPassword(ctx, "unknown@example.com")
+			assert.NoError(t, err)
+		})
+
+		It("should return unavailable error when the task cannot be published", func() {
+			defer mockCtrl.Finish()
+
+			existingUser := newUser("secret")
+			mockUserRepo.EXPECT().GetUserByEmail(ctx, existingUser.Email()).Return(existingUser, nil).Times(1)
+			mockPublisher.EXPECT().Publish(ctx, gomock.Any()).Return(errors.New("broker is down")).Times(1)
+
+			err := authSvc.ForgotPassword(ctx, existingUser.Email())
+			assert.True(t, errdefs.IsUnavailable(err))
+		})
+	})
+
+	Context("Issuing a password reset", func() {
+		It("should store only the hash of the token & return the token", func() {
+			defer mockCtrl.Finish()
+
+			userID := id.NewUUID()
+
+			var stored auth.PasswordReset
+			mockPasswordReset.EXPECT().CreatePasswordReset(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, passwordReset auth.PasswordReset) (*auth.PasswordReset, error) {
+					stored = passwordReset
+					return &passwordReset, nil
+				}).Times(1)
+
+			token, err := authSvc.IssuePasswordReset(ctx, userID.String())
+			assert.NoError(t, err)
+
+			assert.NotEmpty(t, token)
+			assert.Equal(t, userID, stored.UserID())
+			assert.Equal(t, security.HashToken(token), stored.TokenHash())
+			assert.WithinDuration(t, time.Now().Add(config.PasswordResetTTL), stored.ExpiresAt(), time.Second)
+		})
+
+		It("should return validation error for an invalid user ID", func() {
+			defer mockCtrl.Finish()
+
+			mockPasswordReset.EXPECT().CreatePasswordReset(ctx, gomock.Any()).Times(0)
+
+			token, err := authSvc.IssuePasswordReset(ctx, "invalid")
+			assert.Empty(t, token)
+			assert.True(t, errdefs.IsValidation(err))
+		})
+
+		It("should return error when the password reset cannot be stored", func() {
+			defer mockCtrl.Finish()
+
+			mockPasswordReset.EXPECT().CreatePasswordReset(ctx, gomock.Any()).Return(nil, errors.New("database is down")).Times(1)
+
+			token, err := authSvc.IssuePasswordReset(ctx, id.NewUUID().String())
+			assert.Empty(t, token)
+			assert.Error(t, err)
+		})
+	})
+
+	Context("Resetting a password", func() {
+		const token = "reset-token"
+
+		It("should set the new password, use the token & revoke the refresh tokens of the user", func() {
+			defer mockCtrl.Finish()
+
+			passwordReset := newPasswordReset(token, time.Now().Add(time.Hour), nil)
+			mockPasswordReset.EXPECT().GetPasswordResetByHash(ctx, security.HashToken(token)).Return(passwordReset, nil).Times(1)
+
+			gomock.InOrder(
+				mockPasswordReset.EXPECT().UsePasswordReset(ctx, passwordReset.ID()).Return(nil).Times(1),
+				mockUserRepo.EXPECT().UpdateUser(ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, request repositories.UpdateUserRequest) (*user.User, error) {
+						assert.Equal(t, passwordReset.UserID(), request.UserID)
+						assert.True(t, security.CheckPasswordHash("new-secret", *request.PasswordHash))
+						return newUser("new-secret"), nil
+					}).Times(1),
+				mockRefreshTokenRepo.EXPECT().RevokeUserRefreshTokens(ctx, passwordReset.UserID()).Return(nil).Times(1),
+			)
+
+			err := authSvc.ResetPassword(ctx, inbound.ResetPasswordRequest{Token: token, Password: "new-secret"})
+			assert.NoError(t, err)
+		})
+
+		It("should return validation error when the token is unknown", func() {
+			defer mockCtrl.Finish()
+
+			mockPasswordReset.EXPECT().GetPasswordResetByHash(ctx, gomock.Any()).
+				Return(nil, errdefs.NewNotFoundError("not found", nil)).Times(1)
+			mockUserRepo.EXPECT().UpdateUser(ctx, gomock.Any()).Times(0)
+
+			err := authSvc.ResetPassword(ctx, inbound.ResetPasswordRequest{Token: token, Password: "new-secret"})
+			assert.True(t, errdefs.IsValidation(err))
+		})
+
+		It("should return validation error when the token has expired", func() {
+			defer mockCtrl.Finish()
+
+			passwordReset := newPasswordReset(token, time.Now().Add(-time.Minute), nil)
+			mockPasswordReset.EXPECT().GetPasswordResetByHash(ctx, gomock.Any()).Return(passwordReset, nil).Times(1)
+			mockPasswordReset.EXPECT().UsePasswordReset(ctx, gomock.Any()).Times(0)
+			mockUserRepo.EXPECT().UpdateUser(ctx, gomock.Any()).Times(0)
+
+			err := authSvc.ResetPassword(ctx, inbound.ResetPasswordRequest{Token: token, Password: "new-secret"})
+			assert.True(t, errdefs.IsValidation(err))
+			assert.ErrorIs(t, err, auth.ErrPasswordResetExpired)
+		})
+
+		It("should return validation error when the token has already been used", func() {
+			defer mockCtrl.Finish()
+
+			usedAt := time.Now()
+			passwordReset := newPasswordReset(token, time.Now().Add(time.Hour), &usedAt)
+			mockPasswordReset.EXPECT().GetPasswordResetByHash(ctx, gomock.Any()).Return(passwordReset, nil).Times(1)
+			mockUserRepo.EXPECT().UpdateUser(ctx, gomock.Any()).Times(0)
+
+			err := authSvc.ResetPassword(ctx, inbound.ResetPasswordRequest{Token: token, Password: "new-secret"})
+			assert.True(t, errdefs.IsValidation(err))
+			assert.ErrorIs(t, err, auth.ErrPasswordResetUsed)
+		})
+
+		It("should treat losing a concurrent reset as the token having been used", func() {
+			defer mockCtrl.Finish()
+
+			passwordReset := newPasswordReset(token, time.Now().Add(time.Hour), nil)
+			mockPasswordReset.EXPECT().GetPasswordResetByHash(ctx, gomock.Any()).Return(passwordReset, nil).Times(1)
+			mockPasswordReset.EXPECT().UsePasswordReset(ctx, passwordReset.ID()).
+				Return(errdefs.NewNotFoundError("not found", nil)).Times(1)
+			mockUserRepo.EXPECT().UpdateUser(ctx, gomock.Any()).Times(0)
+			mockRefreshTokenRepo.EXPECT().RevokeUserRefreshTokens(ctx, gomock.Any()).Times(0)
+
+			err := authSvc.ResetPassword(ctx, inbound.ResetPasswordRequest{Token: token, Password: "new-secret"})
+			assert.True(t, errdefs.IsValidation(err))
+			assert.ErrorIs(t, err, auth.ErrPasswordResetUsed)
+		})
+
+		It("should roll back using the token when the password cannot be updated", func() {
+			defer mockCtrl.Finish()
+
+			passwordReset := newPasswordReset(token, time.Now().Add(time.Hour), nil)
+			mockPasswordReset.EXPECT().GetPasswordResetByHash(ctx, gomock.Any()).Return(passwordReset, nil).Times(1)
+			mockPasswordReset.EXPECT().UsePasswordReset(ctx, passwordReset.ID()).Return(nil).Times(1)
+			updateErr := errors.New("database is down")
+			mockUserRepo.EXPECT().UpdateUser(ctx, gomock.Any()).Return(nil, updateErr).Times(1)
+			mockRefreshTokenRepo.EXPECT().RevokeUserRefreshTokens(ctx, gomock.Any()).Times(0)
+
+			err := authSvc.ResetPassword(ctx, inbound.ResetPasswordRequest{Token: token, Password: "new-secret"})
+			assert.ErrorIs(t, err, updateErr)
+			assert.ErrorIs(t, transactionErr, updateErr)
+		})
+	})
+})
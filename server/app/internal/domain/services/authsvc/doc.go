@@ -0,0 +1,2 @@
+// Package authsvc contains the business logic for authenticating users with access & refresh tokens
+package authsvc
@@ -0,0 +1,157 @@
+package authsvc
+
+import (
+	"context"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/auth"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories"
+	"github.com/BrianLusina/skillq/server/app/pkg/tasks"
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/domain/id"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/utils/security"
+	"github.com/pkg/errors"
+)
+
+// passwordResetTokenSize is the number of random bytes used to generate a password reset token
+const passwordResetTokenSize = 32
+
+// ForgotPassword publishes a task to email a password reset token to the user with the given email. The token is issued
+// when the task is handled, so that it is never stored in the task. No error is returned for an email that does not
+// belong to a user, so that the response does not reveal which emails are registered
+func (svc *authService) ForgotPassword(ctx context.Context, email string) error {
+	existingUser, err := svc.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	sendPasswordReset := tasks.SendPasswordReset{
+		UserUUID: existingUser.UUID().String(),
+		Email:    existingUser.Email(),
+		Name:     existingUser.Name(),
+	}
+
+	if err := svc.sendPasswordResetPublisher.Publish(ctx, sendPasswordReset); err != nil {
+		return errdefs.NewUnavailableError("failed to publish send password reset", err)
+	}
+
+	logger.FromContext(ctx).Infof("Published password reset for user %s", existingUser.UUID())
+
+	return nil
+}
+
+// IssuePasswordReset issues a password reset token to the user with the given ID & returns it, so that it can be emailed
+// to them. Only the hash of the token is stored
+func (svc *authService) IssuePasswordReset(ctx context.Context, userID string) (string, error) {
+	userUUID, err := id.StringToUUID(userID)
+	if err != nil {
+		return "", errdefs.NewValidationError("invalid user ID", err)
+	}
+
+	token, err := security.GenerateToken(passwordResetTokenSize)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to generate password reset token")
+	}
+
+	now := time.Now()
+
+	_, err = svc.passwordResetRepo.CreatePasswordReset(ctx, auth.NewPasswordReset(auth.PasswordResetParams{
+		ID:        id.NewUUID(),
+		UserId:    userUUID,
+		TokenHash: security.HashToken(token),
+		ExpiresAt: now.Add(svc.passwordResetTTL),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}))
+	if err != nil {
+		return "", err
+	}
+
+	logger.FromContext(ctx).Infof("Issued password reset for user %s", userUUID)
+
+	return token, nil
+}
+
+// ResetPassword sets a new password for the user a password reset token was issued to. A token can only be used once &
+// before it expires. Every refresh token of the user is revoked, so that sessions started with the old password end once
+// their access tokens expire
+func (svc *authService) ResetPassword(ctx context.Context, request inbound.ResetPasswordRequest) error {
+	passwordReset, err := svc.getPasswordReset(ctx, request.Token)
+	if err != nil {
+		return err
+	}
+
+	if err := passwordReset.CanUse(time.Now()); err != nil {
+		return mapPasswordResetErr(err)
+	}
+
+	passwordHash, err := security.HashPassword(request.Password)
+	if err != nil {
+		return errors.Wrap(err, "failed to hash password")
+	}
+
+	// the token is used in the same transaction the password is updated in, so that it is only used up once the password is
+	// updated. Using the token only succeeds for one caller, so a token cannot be used twice by concurrent requests
+	err = svc.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.passwordResetRepo.UsePasswordReset(ctx, passwordReset.ID()); err != nil {
+			if errdefs.IsNotFound(err) {
+				return mapPasswordResetErr(auth.ErrPasswordResetUsed)
+			}
+			return err
+		}
+
+		_, err := svc.userRepo.UpdateUser(ctx, repositories.UpdateUserRequest{
+			UserID:       passwordReset.UserID(),
+			PasswordHash: &passwordHash,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to update password of user %s", passwordReset.UserID())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := svc.refreshTokenRepo.RevokeUserRefreshTokens(ctx, passwordReset.UserID()); err != nil {
+		return errors.Wrapf(err, "failed to revoke refresh tokens of user %s", passwordReset.UserID())
+	}
+
+	logger.FromContext(ctx).Infof("Reset password of user %s", passwordReset.UserID())
+
+	return nil
+}
+
+// getPasswordReset retrieves the stored password reset of the given raw token
+func (svc *authService) getPasswordReset(ctx context.Context, token string) (*auth.PasswordReset, error) {
+	if token == "" {
+		return nil, errdefs.NewValidationError("invalid password reset token", nil)
+	}
+
+	passwordReset, err := svc.passwordResetRepo.GetPasswordResetByHash(ctx, security.HashToken(token))
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, errdefs.NewValidationError("invalid password reset token", nil)
+		}
+		return nil, err
+	}
+
+	return passwordReset, nil
+}
+
+// mapPasswordResetErr maps a password reset error of the auth entity to a domain error with a message that is safe to show
+// to the user
+func mapPasswordResetErr(err error) error {
+	switch {
+	case errors.Is(err, auth.ErrPasswordResetExpired):
+		return errdefs.NewValidationError("password reset token has expired, request a new password reset", err)
+	default:
+		return errdefs.NewValidationError("invalid password reset token", err)
+	}
+}
@@ -0,0 +1,77 @@
+package authsvc
+
+import (
+	"context"
+
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/auth"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/user"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories"
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/domain/id"
+	"github.com/pkg/errors"
+)
+
+// userAuthorizer authorizes operations on users based on the role of the authenticated user. The policy is:
+//   - admins can perform every action on every user, which is the only way roles are assigned
+//   - managers can read every user
+//   - every user can read, update & delete their own profile
+type userAuthorizer struct {
+	userRepo repositories.UserRepoPort
+}
+
+var _ inbound.UserAuthorizer = (*userAuthorizer)(nil)
+
+// NewUserAuthorizer creates a new user authorizer which looks up the role of the authenticated user in the user repository
+func NewUserAuthorizer(userRepo repositories.UserRepoPort) inbound.UserAuthorizer {
+	return &userAuthorizer{
+		userRepo: userRepo,
+	}
+}
+
+// Authorize returns nil if the authenticated user may perform the action on the target user
+func (a *userAuthorizer) Authorize(ctx context.Context, action inbound.UserAction, targetUserID string) error {
+	principalID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		return errdefs.NewUnauthorizedError("authentication is required", nil)
+	}
+
+	uuid, err := id.StringToUUID(principalID)
+	if err != nil {
+		return errdefs.NewUnauthorizedError("invalid authenticated user", err)
+	}
+
+	principal, err := a.userRepo.GetUserByUUID(ctx, uuid)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			// the user has been deleted since their access token was issued
+			return errdefs.NewUnauthorizedError("authenticated user no longer exists", err)
+		}
+		return errors.Wrap(err, "failed to retrieve authenticated user")
+	}
+
+	if isAllowed(principal.Role(), action, targetUserID != "" && targetUserID == principalID) {
+		return nil
+	}
+
+	return errdefs.NewForbiddenError("not allowed to perform this action", nil)
+}
+
+// isAllowed applies the authorization policy to a role & an action, isOwner is true if the action targets the user's own profile
+func isAllowed(role user.Role, action inbound.UserAction, isOwner bool) bool {
+	switch role {
+	case user.RoleAdmin:
+		return true
+	case user.RoleManager:
+		if action == inbound.UserActionRead {
+			return true
+		}
+	}
+
+	switch action {
+	case inbound.UserActionRead, inbound.UserActionUpdate, inbound.UserActionDelete:
+		return isOwner
+	default:
+		return false
+	}
+}
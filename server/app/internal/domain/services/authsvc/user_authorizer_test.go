@@ -0,0 +1,141 @@
+package authsvc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/auth"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/user"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound"
+	mockuserrepo "github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories/mocks"
+	"github.com/BrianLusina/skillq/server/domain/entity"
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/domain/id"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("User Authorizer", func() {
+	t := GinkgoT()
+
+	var (
+		mockCtrl     *gomock.Controller
+		mockUserRepo *mockuserrepo.MockUserRepoPort
+		authorizer   inbound.UserAuthorizer
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(t)
+		mockUserRepo = mockuserrepo.NewMockUserRepoPort(mockCtrl)
+		authorizer = NewUserAuthorizer(mockUserRepo)
+	})
+
+	newPrincipal := func(role user.Role) (*user.User, context.Context) {
+		u, err := user.New(user.UserParams{
+			EntityParams: entity.EntityParams{
+				EntityIDParams: entity.EntityIDParams{
+					UUID:  id.NewUUID(),
+					KeyID: id.NewKeyID(),
+					XID:   id.NewXid(),
+				},
+				EntityTimestampParams: entity.EntityTimestampParams{
+					CreatedAt: time.Now(),
+					UpdatedAt: time.Now(),
+				},
+				Metadata: map[string]any{},
+			},
+			Name:  "John Doe",
+			Email: "john@example.com",
+			Role:  role,
+		})
+		assert.NoError(t, err)
+
+		mockUserRepo.EXPECT().GetUserByUUID(gomock.Any(), u.UUID()).Return(&u, nil).AnyTimes()
+
+		return &u, auth.WithUserID(context.Background(), u.UUID().String())
+	}
+
+	otherUserID := id.NewUUID().String()
+
+	Context("Without an authenticated user", func() {
+		It("should return unauthorized error", func() {
+			defer mockCtrl.Finish()
+
+			err := authorizer.Authorize(context.Background(), inbound.UserActionRead, otherUserID)
+			assert.True(t, errdefs.IsUnauthorized(err))
+		})
+
+		It("should return unauthorized error when the authenticated user no longer exists", func() {
+			defer mockCtrl.Finish()
+
+			userID := id.NewUUID()
+			mockUserRepo.EXPECT().GetUserByUUID(gomock.Any(), userID).Return(nil, errdefs.NewNotFoundError("user not found", nil))
+
+			err := authorizer.Authorize(auth.WithUserID(context.Background(), userID.String()), inbound.UserActionRead, otherUserID)
+			assert.True(t, errdefs.IsUnauthorized(err))
+		})
+
+		It("should return error when the authenticated user can not be retrieved", func() {
+			defer mockCtrl.Finish()
+
+			userID := id.NewUUID()
+			repoErr := errors.New("failed to retrieve user")
+			mockUserRepo.EXPECT().GetUserByUUID(gomock.Any(), userID).Return(nil, repoErr)
+
+			err := authorizer.Authorize(auth.WithUserID(context.Background(), userID.String()), inbound.UserActionRead, otherUserID)
+			assert.ErrorIs(t, err, repoErr)
+		})
+	})
+
+	DescribeTable("Authorizing actions on other users",
+		func(role user.Role, action inbound.UserAction, allowed bool) {
+			defer mockCtrl.Finish()
+
+			_, ctx := newPrincipal(role)
+
+			err := authorizer.Authorize(ctx, action, otherUserID)
+			if allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errdefs.IsForbidden(err))
+			}
+		},
+		Entry("admins can read other users", user.RoleAdmin, inbound.UserActionRead, true),
+		Entry("admins can update other users", user.RoleAdmin, inbound.UserActionUpdate, true),
+		Entry("admins can delete other users", user.RoleAdmin, inbound.UserActionDelete, true),
+		Entry("admins can assign roles to other users", user.RoleAdmin, inbound.UserActionAssignRole, true),
+		Entry("managers can read other users", user.RoleManager, inbound.UserActionRead, true),
+		Entry("managers can not update other users", user.RoleManager, inbound.UserActionUpdate, false),
+		Entry("managers can not delete other users", user.RoleManager, inbound.UserActionDelete, false),
+		Entry("managers can not assign roles", user.RoleManager, inbound.UserActionAssignRole, false),
+		Entry("members can not read other users", user.RoleMember, inbound.UserActionRead, false),
+		Entry("members can not update other users", user.RoleMember, inbound.UserActionUpdate, false),
+		Entry("members can not delete other users", user.RoleMember, inbound.UserActionDelete, false),
+		Entry("members can not assign roles", user.RoleMember, inbound.UserActionAssignRole, false),
+	)
+
+	DescribeTable("Authorizing actions on their own profile",
+		func(action inbound.UserAction) {
+			defer mockCtrl.Finish()
+
+			principal, ctx := newPrincipal(user.RoleMember)
+
+			err := authorizer.Authorize(ctx, action, principal.UUID().String())
+			assert.NoError(t, err)
+		},
+		Entry("members can read their own profile", inbound.UserActionRead),
+		Entry("members can update their own profile", inbound.UserActionUpdate),
+		Entry("members can delete their own profile", inbound.UserActionDelete),
+	)
+
+	It("should not allow members to assign a role to themselves", func() {
+		defer mockCtrl.Finish()
+
+		principal, ctx := newPrincipal(user.RoleMember)
+
+		err := authorizer.Authorize(ctx, inbound.UserActionAssignRole, principal.UUID().String())
+		assert.True(t, errdefs.IsForbidden(err))
+	})
+})
@@ -0,0 +1,76 @@
+package usersvc
+
+import (
+	"context"
+
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound/common"
+)
+
+// authorizedUserService authorizes the operations of the authenticated user in the context before they are performed by the
+// user service it wraps. Registering & listing users are public, while confirming an email is proved with a code instead
+type authorizedUserService struct {
+	inbound.UserService
+	authorizer inbound.UserAuthorizer
+}
+
+var _ inbound.UserService = (*authorizedUserService)(nil)
+
+// NewAuthorized wraps a user service so that every operation on a user is authorized first. Transports use the wrapped
+// service, while tasks & other services that act on behalf of the system use the user service directly
+func NewAuthorized(svc inbound.UserService, authorizer inbound.UserAuthorizer) inbound.UserService {
+	return &authorizedUserService{
+		UserService: svc,
+		authorizer:  authorizer,
+	}
+}
+
+// GetUserByUUID retrieves a user given their UUID if the authenticated user may read them
+func (s *authorizedUserService) GetUserByUUID(ctx context.Context, userUUID string) (*inbound.UserResponse, error) {
+	if err := s.authorizer.Authorize(ctx, inbound.UserActionRead, userUUID); err != nil {
+		return nil, err
+	}
+	return s.UserService.GetUserByUUID(ctx, userUUID)
+}
+
+// GetAllUsers retrieves a page of all users, which is public
+func (s *authorizedUserService) GetAllUsers(ctx context.Context, params common.RequestParams) (common.Page[inbound.UserResponse], error) {
+	return s.UserService.GetAllUsers(ctx, params)
+}
+
+// GetAllUsersBySkill retrieves a page of all users with a given skill, which is public
+func (s *authorizedUserService) GetAllUsersBySkill(ctx context.Context, skill string, params common.RequestParams) (common.Page[inbound.UserResponse], error) {
+	return s.UserService.GetAllUsersBySkill(ctx, skill, params)
+}
+
+// UpdateUserImage streams a new image for a user if the authenticated user may update them
+func (s *authorizedUserService) UpdateUserImage(ctx context.Context, userID string, image inbound.UserImageUpload) (*inbound.UserResponse, error) {
+	if err := s.authorizer.Authorize(ctx, inbound.UserActionUpdate, userID); err != nil {
+		return nil, err
+	}
+	return s.UserService.UpdateUserImage(ctx, userID, image)
+}
+
+// UpdateUser updates a user if the authenticated user may update them
+func (s *authorizedUserService) UpdateUser(ctx context.Context, userID string, request inbound.UserUpdateRequest) (*inbound.UserResponse, error) {
+	if err := s.authorizer.Authorize(ctx, inbound.UserActionUpdate, userID); err != nil {
+		return nil, err
+	}
+	return s.UserService.UpdateUser(ctx, userID, request)
+}
+
+// AssignUserRole assigns a role to a user if the authenticated user may assign roles
+func (s *authorizedUserService) AssignUserRole(ctx context.Context, userID string, role string) (*inbound.UserResponse, error) {
+	if err := s.authorizer.Authorize(ctx, inbound.UserActionAssignRole, userID); err != nil {
+		return nil, err
+	}
+	return s.UserService.AssignUserRole(ctx, userID, role)
+}
+
+// DeleteUser deletes a user if the authenticated user may delete them
+func (s *authorizedUserService) DeleteUser(ctx context.Context, userID string) error {
+	if err := s.authorizer.Authorize(ctx, inbound.UserActionDelete, userID); err != nil {
+		return err
+	}
+	return s.UserService.DeleteUser(ctx, userID)
+}
@@ -19,5 +19,9 @@ func mapUserToUserResponse(userEntity user.User) *inbound.UserResponse {
 		ImageUrl:  userEntity.ImageUrl(),
 		Skills:    userEntity.Skills(),
 		JobTitle:  userEntity.JobTitle(),
+		Role:      userEntity.Role().String(),
+
+		PendingEmail:  userEntity.PendingEmail(),
+		EmailVerified: userEntity.EmailVerified(),
 	}
 }
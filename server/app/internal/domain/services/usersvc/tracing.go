@@ -0,0 +1,29 @@
+package usersvc
+
+import (
+	"context"
+
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the tracer of the user use cases
+const tracerName = "github.com/BrianLusina/skillq/server/app/internal/domain/services/usersvc"
+
+// startSpan starts the span of a use case of the service, such as userService.CreateUser
+func (svc *userService) startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return svc.tracer.Start(ctx, name)
+}
+
+// endSpan ends the span of a use case that returned the error. Errors of the client, such as invalid requests, missing
+// users or conflicting emails, are recorded on the span but do not fail it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		if !errdefs.IsValidation(err) && !errdefs.IsNotFound(err) && !errdefs.IsConflict(err) {
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+	span.End()
+}
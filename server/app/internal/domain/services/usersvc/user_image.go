@@ -0,0 +1,98 @@
+package usersvc
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// MaxUserImageSize is the largest user image in bytes that can be uploaded
+const MaxUserImageSize = 5 * 1024 * 1024
+
+// sniffLen is the number of bytes used to detect the content type of an image
+const sniffLen = 512
+
+// userImageExtensions maps the content types of the images users can upload to the extension they are stored with
+var userImageExtensions = map[string]string{
+	"image/png":  "png",
+	"image/jpeg": "jpg",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+// errUnsupportedUserImage is returned when the content of an image is not of a supported type
+var errUnsupportedUserImage = errors.New("unsupported image type")
+
+// userImage is an image being streamed from a reader. The content type of the image is sniffed from its first bytes rather
+// than trusting what the client claims it to be, and reading more than MaxUserImageSize bytes fails
+type userImage struct {
+	// contentType is the sniffed content type of the image
+	contentType string
+
+	// extension is the file extension the image is stored with
+	extension string
+
+	// reader reads the image, including the bytes that were sniffed
+	reader *maxSizeReader
+}
+
+// newUserImage sniffs the content type of the image read from content, returning errUnsupportedUserImage if it is not an
+// image users can upload
+func newUserImage(content io.Reader) (*userImage, error) {
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(content, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, errors.Wrap(err, "failed to read image")
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	extension, ok := userImageExtensions[contentType]
+	if !ok {
+		return nil, errors.Wrapf(errUnsupportedUserImage, "content type %s", contentType)
+	}
+
+	return &userImage{
+		contentType: contentType,
+		extension:   extension,
+		reader: &maxSizeReader{
+			reader:    io.MultiReader(bytes.NewReader(head), content),
+			remaining: MaxUserImageSize,
+		},
+	}, nil
+}
+
+// errUserImageTooLarge is returned when reading an image larger than MaxUserImageSize
+var errUserImageTooLarge = errors.New("image is too large")
+
+// maxSizeReader fails with errUserImageTooLarge once more than the remaining number of bytes are read from the reader
+type maxSizeReader struct {
+	reader    io.Reader
+	remaining int64
+
+	// exceeded is set once the reader has been read past its limit. The error returned by the reader can be wrapped beyond
+	// recognition by the storage clients, so this is what tells if an upload failed because the image was too large
+	exceeded bool
+}
+
+func (r *maxSizeReader) Read(p []byte) (int, error) {
+	if r.exceeded {
+		return 0, errUserImageTooLarge
+	}
+
+	// read a byte past the limit to tell whether there is more content than allowed
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		r.exceeded = true
+		return 0, errUserImageTooLarge
+	}
+
+	return n, err
+}
@@ -3,6 +3,7 @@ package usersvc
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/user"
@@ -12,47 +13,70 @@ import (
 	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories"
 	"github.com/BrianLusina/skillq/server/app/pkg/tasks"
 	"github.com/BrianLusina/skillq/server/domain/entity"
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
 	"github.com/BrianLusina/skillq/server/domain/id"
+	"github.com/BrianLusina/skillq/server/infra/logger"
 	"github.com/BrianLusina/skillq/server/infra/storage"
 	"github.com/BrianLusina/skillq/server/utils/security"
-	"github.com/BrianLusina/skillq/server/utils/tools"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// Config is the configuration of the user service
+type Config struct {
+	// BootstrapAdminEmail is the email of the user who is made an admin once they have verified it, so that there is an
+	// admin to assign roles to other users. No user is made an admin if it is empty
+	BootstrapAdminEmail string
+}
+
 // userService is the structure for the business logic handling user management
 type userService struct {
-	userRepo                repositories.UserRepoPort
-	sendEmailTaskPublisher  publishers.TaskPublisher[tasks.SendEmailVerification]
-	storeImageTaskPublisher publishers.TaskPublisher[tasks.StoreUserImage]
-	storageClient           storage.StorageClient
+	config                             Config
+	userRepo                           repositories.UserRepoPort
+	transactor                         repositories.TransactorPort
+	sendEmailTaskPublisher             publishers.TaskPublisher[tasks.SendEmailVerification]
+	sendEmailChangeNoticeTaskPublisher publishers.TaskPublisher[tasks.SendEmailChangeNotice]
+	storageClient                      storage.StorageClient
+	tracer                             trace.Tracer
 }
 
 var _ inbound.UserService = (*userService)(nil)
 
-// New creates a new user service implementation of the user use case
+// New creates a new user service implementation of the user use case. The task publishers are expected to publish in the
+// transaction of the context they are given, so that tasks are only published for users that are created. The spans of the
+// use cases are created by the tracer provider
 func New(
+	config Config,
 	userRepo repositories.UserRepoPort,
+	transactor repositories.TransactorPort,
 	sendEmailTaskPublisher publishers.TaskPublisher[tasks.SendEmailVerification],
-	storeImageTaskPublisher publishers.TaskPublisher[tasks.StoreUserImage],
+	sendEmailChangeNoticeTaskPublisher publishers.TaskPublisher[tasks.SendEmailChangeNotice],
 	storageClient storage.StorageClient,
+	tracerProvider trace.TracerProvider,
 ) inbound.UserService {
 	return &userService{
-		userRepo:                userRepo,
-		sendEmailTaskPublisher:  sendEmailTaskPublisher,
-		storeImageTaskPublisher: storeImageTaskPublisher,
-		storageClient:           storageClient,
+		config:                             config,
+		userRepo:                           userRepo,
+		transactor:                         transactor,
+		sendEmailTaskPublisher:             sendEmailTaskPublisher,
+		sendEmailChangeNoticeTaskPublisher: sendEmailChangeNoticeTaskPublisher,
+		storageClient:                      storageClient,
+		tracer:                             tracerProvider.Tracer(tracerName),
 	}
 }
 
-// CreateUser creates a new user in the system
-func (svc *userService) CreateUser(ctx context.Context, request inbound.UserRequest) (*inbound.UserResponse, error) {
+// CreateUser creates a new user in the system, in a span that the user insert & the tasks published for the user are part of
+func (svc *userService) CreateUser(ctx context.Context, request inbound.UserRequest) (_ *inbound.UserResponse, err error) {
+	ctx, span := svc.startSpan(ctx, "userService.CreateUser")
+	defer func() { endSpan(span, err) }()
+
 	// hash password
 	hashedPassword, err := security.HashPassword(request.Password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	user, err := user.New(user.UserParams{
+	newUser, err := user.New(user.UserParams{
 		EntityParams: entity.EntityParams{
 			EntityIDParams: entity.EntityIDParams{
 				UUID:  id.NewUUID(),
@@ -70,43 +94,40 @@ func (svc *userService) CreateUser(ctx context.Context, request inbound.UserRequ
 		Skills:   request.Skills,
 		JobTitle: request.JobTitle,
 		Password: hashedPassword,
+		Role:     user.RoleMember,
 	})
 
 	if err != nil {
-		return nil, err
+		return nil, errdefs.NewValidationError("invalid user provided", err)
 	}
 
-	// create user
-	createdUser, err := svc.userRepo.CreateUser(ctx, user)
+	// the user is created & its tasks published in one transaction, so that the user is not created without its tasks
+	var createdUser *user.User
+	err = svc.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		createdUser, err = svc.userRepo.CreateUser(ctx, newUser)
+		if err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+
+		sendEmailVerification := tasks.SendEmailVerification{
+			UserUUID: createdUser.UUID().String(),
+			Email:    createdUser.Email(),
+			Name:     createdUser.Name(),
+		}
+
+		// publish event
+		if err := svc.sendEmailTaskPublisher.Publish(ctx, sendEmailVerification); err != nil {
+			return errdefs.NewUnavailableError("failed to publish send email verification", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create user: %w", err)
-	}
-
-	sendEmailVerification := tasks.SendEmailVerification{
-		UserUUID: createdUser.UUID().String(),
-		Email:    createdUser.Email(),
-		Name:     createdUser.Name(),
-	}
-
-	// publish event
-	if err := svc.sendEmailTaskPublisher.Publish(ctx, sendEmailVerification); err != nil {
-		return nil, errors.Wrapf(err, "failed to publish send email verification: %v", sendEmailVerification)
-	}
-
-	storeUserImageTask := tasks.StoreUserImage{
-		UserUUID:    createdUser.UUID().String(),
-		ContentType: request.Image.Type,
-		Content:     request.Image.Content,
-		Name:        fmt.Sprintf("%s-image", createdUser.UUID()),
-		Bucket:      fmt.Sprintf("%s-documents", createdUser.UUID()),
+		return nil, err
 	}
 
-	// publish store image task
-	if err := svc.storeImageTaskPublisher.Publish(ctx, storeUserImageTask); err != nil {
-		return nil, errors.Wrapf(err, "failed to publish store user image task: %v", storeUserImageTask)
-	}
+	logger.FromContext(ctx).Infof("Created user %s", createdUser.UUID())
 
-	// update user image in response
 	return mapUserToUserResponse(*createdUser), nil
 }
 
@@ -114,7 +135,7 @@ func (svc *userService) CreateUser(ctx context.Context, request inbound.UserRequ
 func (svc *userService) GetUserByUUID(ctx context.Context, userUUID string) (*inbound.UserResponse, error) {
 	uuid, err := id.StringToUUID(userUUID)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to parse user UUID %s", userUUID)
+		return nil, errdefs.NewValidationError(fmt.Sprintf("invalid user UUID %s", userUUID), err)
 	}
 
 	existingUser, err := svc.userRepo.GetUserByUUID(ctx, uuid)
@@ -125,49 +146,83 @@ func (svc *userService) GetUserByUUID(ctx context.Context, userUUID string) (*in
 	return mapUserToUserResponse(*existingUser), nil
 }
 
-func (svc *userService) UploadUserImage(ctx context.Context, userUUID id.UUID, imageData inbound.UserImageRequest) (string, error) {
-	url, err := svc.storageClient.Upload(ctx, storage.StorageItem{
-		ContentType: imageData.Type,
-		Content:     imageData.Content,
-		Name:        fmt.Sprintf("%s-image", userUUID),
+// UpdateUserImage streams a new image for a user to blob storage & updates the user's image url. The image is rejected if
+// it is not a PNG, JPEG, GIF or WebP image, or is larger than MaxUserImageSize
+func (svc *userService) UpdateUserImage(ctx context.Context, userID string, image inbound.UserImageUpload) (*inbound.UserResponse, error) {
+	userUUID, err := id.StringToUUID(userID)
+	if err != nil {
+		return nil, errdefs.NewValidationError(fmt.Sprintf("invalid user ID %s", userID), err)
+	}
+
+	// ensure the user exists before storing anything for them
+	if _, err := svc.userRepo.GetUserByUUID(ctx, userUUID); err != nil {
+		return nil, fmt.Errorf("failed to retrieve user %w", err)
+	}
+
+	userImage, err := newUserImage(image.Content)
+	if err != nil {
+		if errors.Is(err, errUnsupportedUserImage) {
+			return nil, errdefs.NewValidationError("image must be a PNG, JPEG, GIF or WebP image", err)
+		}
+		return nil, err
+	}
+
+	url, err := svc.storageClient.UploadStream(ctx, storage.StorageStreamItem{
+		Name:        fmt.Sprintf("%s-image.%s", userUUID, userImage.extension),
+		Content:     userImage.reader,
+		Size:        -1,
+		ContentType: userImage.contentType,
 		Bucket:      fmt.Sprintf("%s-documents", userUUID),
 		PolicyType:  storage.PolicyTypeReadOnly,
 	})
 	if err != nil {
-		return "", errors.Wrapf(err, "failed to store user image")
+		if userImage.reader.exceeded {
+			return nil, errdefs.NewValidationError(fmt.Sprintf("image must not be larger than %d bytes", MaxUserImageSize), errUserImageTooLarge)
+		}
+		return nil, errdefs.NewUnavailableError("failed to store user image", err)
+	}
+
+	updatedUser, err := svc.userRepo.UpdateUser(ctx, repositories.UpdateUserRequest{
+		UserID:   userUUID,
+		ImageUrl: &url,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to update image of user %s", userID)
 	}
-	return url, nil
+
+	return mapUserToUserResponse(*updatedUser), nil
 }
 
-// GetAllUsers retrieves all users
-func (svc *userService) GetAllUsers(ctx context.Context, params common.RequestParams) ([]inbound.UserResponse, error) {
+// GetAllUsers retrieves a page of all users
+func (svc *userService) GetAllUsers(ctx context.Context, params common.RequestParams) (common.Page[inbound.UserResponse], error) {
 	users, err := svc.userRepo.GetAllUsers(ctx, params)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to retrieve all users")
+		return common.Page[inbound.UserResponse]{}, errors.Wrapf(err, "failed to retrieve all users")
 	}
 
-	return tools.MapWithError(users, func(u user.User, _ int) (inbound.UserResponse, error) {
-		return *mapUserToUserResponse(u), nil
-	})
+	return common.MapPage(users, func(u user.User) inbound.UserResponse {
+		return *mapUserToUserResponse(u)
+	}), nil
 }
 
-// GetAllUsersBySkill retrieves all users with a given skill
-func (svc *userService) GetAllUsersBySkill(ctx context.Context, skill string, params common.RequestParams) ([]inbound.UserResponse, error) {
+// GetAllUsersBySkill retrieves a page of all users with a given skill
+func (svc *userService) GetAllUsersBySkill(ctx context.Context, skill string, params common.RequestParams) (common.Page[inbound.UserResponse], error) {
 	users, err := svc.userRepo.GetAllUsersBySkill(ctx, skill, params)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to retrieve all users with skill %s", skill)
+		return common.Page[inbound.UserResponse]{}, errors.Wrapf(err, "failed to retrieve all users with skill %s", skill)
 	}
 
-	return tools.MapWithError(users, func(u user.User, _ int) (inbound.UserResponse, error) {
-		return *mapUserToUserResponse(u), nil
-	})
+	return common.MapPage(users, func(u user.User) inbound.UserResponse {
+		return *mapUserToUserResponse(u)
+	}), nil
 }
 
-// UpdateUser updates a user given their ID
-func (svc *userService) UpdateUser(ctx context.Context, userID string, request inbound.UserRequest) (*inbound.UserResponse, error) {
+// UpdateUser updates the fields of a user given their ID that are set in the request. A new email is stored as pending & a code to confirm it is sent to it, while the user is notified of the change at their
+// current email. The user's email is only changed once the code is confirmed
+func (svc *userService) UpdateUser(ctx context.Context, userID string, request inbound.UserUpdateRequest) (*inbound.UserResponse, error) {
 	userUUID, err := id.StringToUUID(userID)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to parse user ID %s", userUUID)
+		return nil, errdefs.NewValidationError(fmt.Sprintf("invalid user ID %s", userID), err)
 	}
 
 	// get user
@@ -176,42 +231,237 @@ func (svc *userService) UpdateUser(ctx context.Context, userID string, request i
 		return nil, fmt.Errorf("failed to retrieve user %w", err)
 	}
 
-	// update fields
-	url, err := svc.UploadUserImage(ctx, userUUID, request.Image)
+	updateRequest := repositories.UpdateUserRequest{
+		UserID: userUUID,
+	}
+
+	// update only the fields that have been provided, validating them against the existing user
+	if request.Name != nil {
+		if _, err := existingUser.SetName(*request.Name); err != nil {
+			return nil, errdefs.NewValidationError(fmt.Sprintf("invalid name %s provided", *request.Name), err)
+		}
+		name := existingUser.Name()
+		updateRequest.Name = &name
+	}
+
+	if request.Email != nil {
+		if _, err := existingUser.RequestEmailChange(*request.Email); err != nil {
+			return nil, errdefs.NewValidationError(fmt.Sprintf("invalid email %s provided", *request.Email), err)
+		}
+		pendingEmail := existingUser.PendingEmail()
+		if pendingEmail != "" {
+			if err := svc.ensureEmailAvailable(ctx, userUUID, pendingEmail); err != nil {
+				return nil, err
+			}
+		}
+		updateRequest.PendingEmail = &pendingEmail
+	}
+
+	if request.JobTitle != nil {
+		if _, err := existingUser.SetJobTitle(*request.JobTitle); err != nil {
+			return nil, errdefs.NewValidationError(fmt.Sprintf("invalid job title %s provided", *request.JobTitle), err)
+		}
+		jobTitle := existingUser.JobTitle()
+		updateRequest.JobTitle = &jobTitle
+	}
+
+	if request.Skills != nil {
+		skills := existingUser.ReplaceSkills(*request.Skills).Skills()
+		updateRequest.Skills = &skills
+	}
+
+	// the user is updated & the tasks of an email change published in one transaction, so that a pending email is not
+	// stored without its tasks
+	var updatedUser *user.User
+	err = svc.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		updatedUser, err = svc.userRepo.UpdateUser(ctx, updateRequest)
+		if err != nil {
+			return errors.Wrapf(err, "failed to update user %s", userID)
+		}
+
+		if request.Email != nil && updatedUser.PendingEmail() != "" {
+			return svc.publishEmailChange(ctx, *updatedUser)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to upload user image %s", request.Image)
+		return nil, err
 	}
 
-	existingUpdatedUser, err := existingUser.SetName(request.Name)
+	return mapUserToUserResponse(*updatedUser), nil
+}
+
+// ConfirmUserEmail marks an email of a user as verified. If the email is the user's pending email, it replaces the user's
+// email, provided no other user has taken it in the meantime
+func (svc *userService) ConfirmUserEmail(ctx context.Context, userID string, email string) (*inbound.UserResponse, error) {
+	userUUID, err := id.StringToUUID(userID)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to update user name %s", request.Name)
+		return nil, errdefs.NewValidationError(fmt.Sprintf("invalid user ID %s", userID), err)
 	}
 
-	existingUpdatedUser, err = existingUpdatedUser.SetEmail(request.Email)
+	existingUser, err := svc.userRepo.GetUserByUUID(ctx, userUUID)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to update user email %s", request.Email)
+		return nil, fmt.Errorf("failed to retrieve user %w", err)
 	}
 
-	existingUpdatedUser, err = existingUpdatedUser.SetJobTitle(request.JobTitle)
+	isEmailChange := email != existingUser.Email()
+
+	if _, err := existingUser.ConfirmEmail(email); err != nil {
+		if errors.Is(err, user.ErrEmailNotPending) {
+			return nil, errdefs.NewValidationError(fmt.Sprintf("email %s is not pending confirmation", email), err)
+		}
+		return nil, err
+	}
+
+	emailVerified := existingUser.EmailVerified()
+	updateRequest := repositories.UpdateUserRequest{
+		UserID:        userUUID,
+		EmailVerified: &emailVerified,
+	}
+
+	if svc.isBootstrapAdmin(*existingUser) {
+		role := user.RoleAdmin
+		updateRequest.Role = &role
+	}
+
+	if isEmailChange {
+		if err := svc.ensureEmailAvailable(ctx, userUUID, email); err != nil {
+			return nil, err
+		}
+		newEmail, pendingEmail := existingUser.Email(), existingUser.PendingEmail()
+		updateRequest.Email = &newEmail
+		updateRequest.PendingEmail = &pendingEmail
+	}
+
+	updatedUser, err := svc.userRepo.UpdateUser(ctx, updateRequest)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to update user job title %s", request.JobTitle)
+		return nil, errors.Wrapf(err, "failed to confirm email of user %s", userID)
 	}
 
-	existingUpdatedUser = existingUpdatedUser.SetSkills(request.Skills).SetImageUrl(url)
+	return mapUserToUserResponse(*updatedUser), nil
+}
 
-	updatedUser, err := svc.userRepo.UpdateUser(ctx, *existingUpdatedUser)
+// AssignUserRole assigns a role to a user given their ID
+func (svc *userService) AssignUserRole(ctx context.Context, userID string, role string) (*inbound.UserResponse, error) {
+	userUUID, err := id.StringToUUID(userID)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to update user %s", userID)
+		return nil, errdefs.NewValidationError(fmt.Sprintf("invalid user ID %s", userID), err)
+	}
+
+	existingUser, err := svc.userRepo.GetUserByUUID(ctx, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve user %w", err)
+	}
+
+	if _, err := existingUser.SetRole(user.Role(role)); err != nil {
+		return nil, errdefs.NewValidationError(fmt.Sprintf("invalid role %s provided", role), err)
 	}
 
+	newRole := existingUser.Role()
+	updatedUser, err := svc.userRepo.UpdateUser(ctx, repositories.UpdateUserRequest{
+		UserID: userUUID,
+		Role:   &newRole,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to assign role %s to user %s", role, userID)
+	}
+
+	logger.FromContext(ctx).Infof("Assigned role %s to user %s", newRole, userID)
+
 	return mapUserToUserResponse(*updatedUser), nil
 }
 
+// BootstrapAdmin makes the user with the bootstrap admin email an admin if they have already verified it. Users who
+// verify it later are made an admin when they do
+func (svc *userService) BootstrapAdmin(ctx context.Context) error {
+	if svc.config.BootstrapAdminEmail == "" {
+		return nil
+	}
+
+	existingUser, err := svc.userRepo.GetUserByEmail(ctx, svc.config.BootstrapAdminEmail)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			logger.FromContext(ctx).Infof("Bootstrap admin %s has not registered yet", svc.config.BootstrapAdminEmail)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to retrieve bootstrap admin %s", svc.config.BootstrapAdminEmail)
+	}
+
+	if !svc.isBootstrapAdmin(*existingUser) || existingUser.Role() == user.RoleAdmin {
+		return nil
+	}
+
+	role := user.RoleAdmin
+	if _, err := svc.userRepo.UpdateUser(ctx, repositories.UpdateUserRequest{
+		UserID: existingUser.UUID(),
+		Role:   &role,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to make bootstrap admin %s an admin", svc.config.BootstrapAdminEmail)
+	}
+
+	logger.FromContext(ctx).Infof("Made bootstrap admin %s an admin", existingUser.UUID())
+
+	return nil
+}
+
+// isBootstrapAdmin reports whether a user has verified the bootstrap admin email. An unverified email is not enough, as
+// anyone can register with it
+func (svc *userService) isBootstrapAdmin(u user.User) bool {
+	return svc.config.BootstrapAdminEmail != "" && u.EmailVerified() && strings.EqualFold(u.Email(), svc.config.BootstrapAdminEmail)
+}
+
+// ensureEmailAvailable returns a conflict error if an email belongs to another user than the given user
+func (svc *userService) ensureEmailAvailable(ctx context.Context, userUUID id.UUID, email string) error {
+	otherUser, err := svc.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to check whether email %s is in use", email)
+	}
+
+	if otherUser.UUID().String() != userUUID.String() {
+		return errdefs.NewConflictError(fmt.Sprintf("email %s is already in use", email), nil)
+	}
+
+	return nil
+}
+
+// publishEmailChange publishes the tasks to send a code to confirm the pending email of a user to the pending email & to
+// notify the user of the change at their current email
+func (svc *userService) publishEmailChange(ctx context.Context, u user.User) error {
+	sendEmailVerification := tasks.SendEmailVerification{
+		UserUUID: u.UUID().String(),
+		Email:    u.PendingEmail(),
+		Name:     u.Name(),
+	}
+
+	if err := svc.sendEmailTaskPublisher.Publish(ctx, sendEmailVerification); err != nil {
+		return errdefs.NewUnavailableError("failed to publish send email verification", err)
+	}
+
+	sendEmailChangeNotice := tasks.SendEmailChangeNotice{
+		UserUUID: u.UUID().String(),
+		Email:    u.Email(),
+		Name:     u.Name(),
+		NewEmail: u.PendingEmail(),
+	}
+
+	if err := svc.sendEmailChangeNoticeTaskPublisher.Publish(ctx, sendEmailChangeNotice); err != nil {
+		return errdefs.NewUnavailableError("failed to publish send email change notice", err)
+	}
+
+	logger.FromContext(ctx).Infof("Requested email change for user %s", u.UUID())
+
+	return nil
+}
+
 // DeleteUser deletes a given user
 func (svc *userService) DeleteUser(ctx context.Context, userId string) error {
 	uuid, err := id.StringToUUID(userId)
 	if err != nil {
-		return errors.Wrapf(err, "failed to parse user ID %s", userId)
+		return errdefs.NewValidationError(fmt.Sprintf("invalid user ID %s", userId), err)
 	}
 
 	err = svc.userRepo.DeleteUserById(ctx, uuid)
@@ -219,5 +469,7 @@ func (svc *userService) DeleteUser(ctx context.Context, userId string) error {
 		return errors.Wrapf(err, "failed to delete user with ID: %s", userId)
 	}
 
+	logger.FromContext(ctx).Infof("Deleted user %s", userId)
+
 	return nil
 }
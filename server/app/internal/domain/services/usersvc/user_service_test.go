@@ -1,22 +1,31 @@
 package usersvc
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/user"
+	mockuser "github.com/BrianLusina/skillq/server/app/internal/domain/entities/user/mocks"
 	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound"
+	mockpublishers "github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/publishers/mocks"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories"
 	mockuserrepo "github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories/mocks"
+	"github.com/BrianLusina/skillq/server/app/pkg/tasks"
 	"github.com/BrianLusina/skillq/server/domain/entity"
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
 	"github.com/BrianLusina/skillq/server/domain/id"
-	mockamqppublisher "github.com/BrianLusina/skillq/server/infra/messaging/amqp/publisher/mocks"
+	"github.com/BrianLusina/skillq/server/infra/storage"
 	mockstorageclient "github.com/BrianLusina/skillq/server/infra/storage/mocks"
 	"github.com/go-faker/faker/v4"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace/noop"
 	"go.uber.org/mock/gomock"
 )
 
@@ -29,22 +38,40 @@ var _ = Describe("User Service", func() {
 	t := GinkgoT()
 
 	var (
-		mockCtrl             *gomock.Controller
-		mockUserRepo         *mockuserrepo.MockUserRepoPort
-		mockMessagePublisher *mockamqppublisher.MockAmqpEventPublisher
-		mockStorageClient    *mockstorageclient.MockStorageClient
-		userSvc              userService
+		mockCtrl                   *gomock.Controller
+		mockUserRepo               *mockuserrepo.MockUserRepoPort
+		mockTransactor             *mockuserrepo.MockTransactorPort
+		transactionErr             error
+		mockSendEmailTaskPublisher *mockpublishers.MockTaskPublisher[tasks.SendEmailVerification]
+		mockNoticeTaskPublisher    *mockpublishers.MockTaskPublisher[tasks.SendEmailChangeNotice]
+		mockStorageClient          *mockstorageclient.MockStorageClient
+		userSvc                    userService
 	)
 
 	BeforeEach(func() {
 		mockCtrl = gomock.NewController(t)
 		mockUserRepo = mockuserrepo.NewMockUserRepoPort(mockCtrl)
-		mockMessagePublisher = mockamqppublisher.NewMockAmqpEventPublisher(mockCtrl)
+		mockTransactor = mockuserrepo.NewMockTransactorPort(mockCtrl)
+		mockSendEmailTaskPublisher = mockpublishers.NewMockTaskPublisher[tasks.SendEmailVerification](mockCtrl)
+		mockNoticeTaskPublisher = mockpublishers.NewMockTaskPublisher[tasks.SendEmailChangeNotice](mockCtrl)
 		mockStorageClient = mockstorageclient.NewMockStorageClient(mockCtrl)
+
+		// the transaction runs the function it is given & records its error, which rolls the transaction back
+		transactionErr = nil
+		mockTransactor.EXPECT().WithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(context.Context) error) error {
+				transactionErr = fn(ctx)
+				return transactionErr
+			},
+		).AnyTimes()
+
 		userSvc = userService{
-			userRepo:         mockUserRepo,
-			messagePublisher: mockMessagePublisher,
-			storageClient:    mockStorageClient,
+			userRepo:                           mockUserRepo,
+			transactor:                         mockTransactor,
+			sendEmailTaskPublisher:             mockSendEmailTaskPublisher,
+			sendEmailChangeNoticeTaskPublisher: mockNoticeTaskPublisher,
+			storageClient:                      mockStorageClient,
+			tracer:                             noop.NewTracerProvider().Tracer(tracerName),
 		}
 
 		assert.NotNil(t, userSvc)
@@ -63,7 +90,6 @@ var _ = Describe("User Service", func() {
 					Email:    "fake",
 					Password: "password",
 					Skills:   []string{},
-					Image:    inbound.UserImageRequest{},
 					JobTitle: "The Boss",
 				}
 
@@ -78,27 +104,21 @@ var _ = Describe("User Service", func() {
 				defer mockCtrl.Finish()
 
 				mockRepoError := errors.New("failed to persist user information")
-				imageRequest := inbound.UserImageRequest{
-					Type:    "image/png",
-					Content: "",
-				}
-
 				request := inbound.UserRequest{
 					Name:     "John Doe",
 					Email:    "fake@example.com",
 					Password: "password",
 					Skills:   []string{},
-					Image:    imageRequest,
 					JobTitle: "The Boss",
 				}
 
-				mockUserRepo.EXPECT().CreateUser(ctx, gomock.Any()).Return(nil, mockRepoError)
+				mockUserRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(nil, mockRepoError)
 
 				// no query to fetch user by UUID was triggered
-				mockUserRepo.EXPECT().GetUserByUUID(ctx, gomock.Any()).Times(0)
+				mockUserRepo.EXPECT().GetUserByUUID(gomock.Any(), gomock.Any()).Times(0)
 
 				// no message was published
-				mockMessagePublisher.EXPECT().Publish(ctx, gomock.Any()).Times(0)
+				mockSendEmailTaskPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
 
 				actualUser, actualErr := userSvc.CreateUser(context.Background(), request)
 				assert.Nil(t, actualUser)
@@ -115,7 +135,6 @@ var _ = Describe("User Service", func() {
 					Email:    "fake@example.com",
 					Password: "password",
 					Skills:   []string{},
-					Image:    inbound.UserImageRequest{},
 					JobTitle: "The Boss",
 				}
 
@@ -142,28 +161,26 @@ var _ = Describe("User Service", func() {
 				assert.NoError(t, err)
 
 				// no error when creating user
-				mockUserRepo.EXPECT().CreateUser(ctx, gomock.Any()).Return(&createdUser, nil).Times(1)
+				mockUserRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(&createdUser, nil).Times(1)
 
 				// message failed to publish
-				mockMessagePublisher.EXPECT().Publish(ctx, gomock.Any()).Return(mockPublisherError).Times(1)
+				mockSendEmailTaskPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(mockPublisherError).Times(1)
 
 				actualUser, actualErr := userSvc.CreateUser(context.Background(), request)
 				assert.Nil(t, actualUser)
 				assert.Error(t, actualErr)
+
+				// the user is rolled back with the transaction
+				assert.ErrorIs(t, transactionErr, mockPublisherError)
 			})
 
-			It("should return nil error when there is success creating a user, publishing user email verification event and store image task", func() {
+			It("should return nil error when there is success creating a user, publishing user email verification event", func() {
 				defer mockCtrl.Finish()
-				imageRequest := inbound.UserImageRequest{
-					Type:    "image/png",
-					Content: "",
-				}
 				request := inbound.UserRequest{
 					Name:     "John Doe",
 					Email:    "fake@example.com",
 					Password: "password",
 					Skills:   []string{},
-					Image:    imageRequest,
 					JobTitle: "The Boss",
 				}
 
@@ -190,14 +207,15 @@ var _ = Describe("User Service", func() {
 				assert.NoError(t, err)
 
 				// no error when creating user
-				mockUserRepo.EXPECT().CreateUser(ctx, gomock.Any()).Return(&createdUser, nil).Times(1)
+				mockUserRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(&createdUser, nil).Times(1)
 
-				// message failed to be published
-				mockMessagePublisher.EXPECT().Publish(ctx, gomock.Any()).Return(nil).Times(2)
+				// the email verification is published
+				mockSendEmailTaskPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 
 				actualUser, actualErr := userSvc.CreateUser(context.Background(), request)
 				assert.NotNil(t, actualUser)
 				assert.NoError(t, actualErr)
+				assert.NoError(t, transactionErr)
 			})
 		})
 	})
@@ -259,4 +277,449 @@ var _ = Describe("User Service", func() {
 			})
 		})
 	})
+	Context("Updating an existing user", func() {
+		It("should return error if the user does not exist", func() {
+			defer mockCtrl.Finish()
+
+			userUUID := id.NewUUID()
+			jobTitle := "Engineer"
+
+			mockUserRepo.EXPECT().GetUserByUUID(ctx, userUUID).Return(nil, errors.New("no document")).Times(1)
+			mockUserRepo.EXPECT().UpdateUser(ctx, gomock.Any()).Times(0)
+
+			actualUser, actualErr := userSvc.UpdateUser(ctx, userUUID.String(), inbound.UserUpdateRequest{JobTitle: &jobTitle})
+			assert.Nil(t, actualUser)
+			assert.Error(t, actualErr)
+		})
+
+		It("should return error and not update the user when a provided field is invalid", func() {
+			defer mockCtrl.Finish()
+
+			existingUser, err := mockuser.MockUser()
+			assert.NoError(t, err)
+			email := "not-an-email"
+
+			mockUserRepo.EXPECT().GetUserByUUID(ctx, existingUser.UUID()).Return(existingUser, nil).Times(1)
+			mockUserRepo.EXPECT().UpdateUser(ctx, gomock.Any()).Times(0)
+
+			actualUser, actualErr := userSvc.UpdateUser(ctx, existingUser.UUID().String(), inbound.UserUpdateRequest{Email: &email})
+			assert.Nil(t, actualUser)
+			assert.Error(t, actualErr)
+		})
+
+		It("should only update the provided fields", func() {
+			defer mockCtrl.Finish()
+
+			existingUser, err := mockuser.MockUser()
+			assert.NoError(t, err)
+			jobTitle := "Head of Engineering"
+
+			expectedRequest := repositories.UpdateUserRequest{
+				UserID:   existingUser.UUID(),
+				JobTitle: &jobTitle,
+			}
+
+			mockUserRepo.EXPECT().GetUserByUUID(ctx, existingUser.UUID()).Return(existingUser, nil).Times(1)
+			mockUserRepo.EXPECT().UpdateUser(ctx, expectedRequest).Return(existingUser, nil).Times(1)
+
+			actualUser, actualErr := userSvc.UpdateUser(ctx, existingUser.UUID().String(), inbound.UserUpdateRequest{JobTitle: &jobTitle})
+			assert.NoError(t, actualErr)
+			Expect(actualUser).NotTo(BeNil())
+			Expect(actualUser.JobTitle).To(Equal(jobTitle))
+		})
+
+		It("should store a new email as pending, send a code to it & notify the current email", func() {
+			defer mockCtrl.Finish()
+
+			existingUser, err := mockuser.MockUser()
+			assert.NoError(t, err)
+			currentEmail := existingUser.Email()
+			newEmail := "new-" + currentEmail
+
+			pendingUser := *existingUser
+			_, err = pendingUser.RequestEmailChange(newEmail)
+			assert.NoError(t, err)
+
+			expectedRequest := repositories.UpdateUserRequest{
+				UserID:       existingUser.UUID(),
+				PendingEmail: &newEmail,
+			}
+
+			mockUserRepo.EXPECT().GetUserByUUID(ctx, existingUser.UUID()).Return(existingUser, nil).Times(1)
+			mockUserRepo.EXPECT().GetUserByEmail(ctx, newEmail).Return(nil, errdefs.NewNotFoundError("no document", nil)).Times(1)
+			mockUserRepo.EXPECT().UpdateUser(ctx, expectedRequest).Return(&pendingUser, nil).Times(1)
+			mockSendEmailTaskPublisher.EXPECT().Publish(ctx, tasks.SendEmailVerification{
+				UserUUID: existingUser.UUID().String(),
+				Email:    newEmail,
+				Name:     existingUser.Name(),
+			}).Return(nil).Times(1)
+			mockNoticeTaskPublisher.EXPECT().Publish(ctx, tasks.SendEmailChangeNotice{
+				UserUUID: existingUser.UUID().String(),
+				Email:    currentEmail,
+				Name:     existingUser.Name(),
+				NewEmail: newEmail,
+			}).Return(nil).Times(1)
+
+			actualUser, actualErr := userSvc.UpdateUser(ctx, existingUser.UUID().String(), inbound.UserUpdateRequest{Email: &newEmail})
+			assert.NoError(t, actualErr)
+			assert.NoError(t, transactionErr)
+			Expect(actualUser.Email).To(Equal(currentEmail))
+			Expect(actualUser.PendingEmail).To(Equal(newEmail))
+		})
+
+		It("should roll back the pending email when the tasks of the email change cannot be published", func() {
+			defer mockCtrl.Finish()
+
+			existingUser, err := mockuser.MockUser()
+			assert.NoError(t, err)
+			newEmail := "new-" + existingUser.Email()
+
+			pendingUser := *existingUser
+			_, err = pendingUser.RequestEmailChange(newEmail)
+			assert.NoError(t, err)
+
+			mockPublisherError := errors.New("failed to add task to outbox")
+
+			mockUserRepo.EXPECT().GetUserByUUID(ctx, existingUser.UUID()).Return(existingUser, nil).Times(1)
+			mockUserRepo.EXPECT().GetUserByEmail(ctx, newEmail).Return(nil, errdefs.NewNotFoundError("no document", nil)).Times(1)
+			mockUserRepo.EXPECT().UpdateUser(ctx, gomock.Any()).Return(&pendingUser, nil).Times(1)
+			mockSendEmailTaskPublisher.EXPECT().Publish(ctx, gomock.Any()).Return(nil).Times(1)
+			mockNoticeTaskPublisher.EXPECT().Publish(ctx, gomock.Any()).Return(mockPublisherError).Times(1)
+
+			actualUser, actualErr := userSvc.UpdateUser(ctx, existingUser.UUID().String(), inbound.UserUpdateRequest{Email: &newEmail})
+			assert.Nil(t, actualUser)
+			assert.True(t, errdefs.IsUnavailable(actualErr))
+			assert.ErrorIs(t, transactionErr, mockPublisherError)
+		})
+
+		It("should cancel a pending email when the current email is requested", func() {
+			defer mockCtrl.Finish()
+
+			existingUser, err := mockuser.MockUser()
+			assert.NoError(t, err)
+			currentEmail := existingUser.Email()
+			_, err = existingUser.RequestEmailChange("new-" + currentEmail)
+			assert.NoError(t, err)
+
+			noPendingEmail := ""
+			expectedRequest := repositories.UpdateUserRequest{
+				UserID:       existingUser.UUID(),
+				PendingEmail: &noPendingEmail,
+			}
+
+			mockUserRepo.EXPECT().GetUserByUUID(ctx, existingUser.UUID()).Return(existingUser, nil).Times(1)
+			mockUserRepo.EXPECT().UpdateUser(ctx, expectedRequest).Return(existingUser, nil).Times(1)
+			mockSendEmailTaskPublisher.EXPECT().Publish(ctx, gomock.Any()).Times(0)
+			mockNoticeTaskPublisher.EXPECT().Publish(ctx, gomock.Any()).Times(0)
+
+			_, actualErr := userSvc.UpdateUser(ctx, existingUser.UUID().String(), inbound.UserUpdateRequest{Email: &currentEmail})
+			assert.NoError(t, actualErr)
+		})
+
+		It("should return a conflict when the new email belongs to another user", func() {
+			defer mockCtrl.Finish()
+
+			existingUser, err := mockuser.MockUser()
+			assert.NoError(t, err)
+			otherUser, err := mockuser.MockUser()
+			assert.NoError(t, err)
+			newEmail := otherUser.Email()
+
+			mockUserRepo.EXPECT().GetUserByUUID(ctx, existingUser.UUID()).Return(existingUser, nil).Times(1)
+			mockUserRepo.EXPECT().GetUserByEmail(ctx, newEmail).Return(otherUser, nil).Times(1)
+			mockUserRepo.EXPECT().UpdateUser(ctx, gomock.Any()).Times(0)
+			mockSendEmailTaskPublisher.EXPECT().Publish(ctx, gomock.Any()).Times(0)
+
+			actualUser, actualErr := userSvc.UpdateUser(ctx, existingUser.UUID().String(), inbound.UserUpdateRequest{Email: &newEmail})
+			assert.Nil(t, actualUser)
+			assert.True(t, errdefs.IsConflict(actualErr))
+		})
+	})
+
+	Context("Confirming a user's email", func() {
+		It("should replace the user's email with their pending email", func() {
+			defer mockCtrl.Finish()
+
+			existingUser, err := mockuser.MockUser()
+			assert.NoError(t, err)
+			newEmail := "new-" + existingUser.Email()
+			_, err = existingUser.RequestEmailChange(newEmail)
+			assert.NoError(t, err)
+
+			emailVerified, noPendingEmail := true, ""
+			expectedRequest := repositories.UpdateUserRequest{
+				UserID:        existingUser.UUID(),
+				Email:         &newEmail,
+				PendingEmail:  &noPendingEmail,
+				EmailVerified: &emailVerified,
+			}
+
+			mockUserRepo.EXPECT().GetUserByUUID(ctx, existingUser.UUID()).Return(existingUser, nil).Times(1)
+			mockUserRepo.EXPECT().GetUserByEmail(ctx, newEmail).Return(nil, errdefs.NewNotFoundError("no document", nil)).Times(1)
+			mockUserRepo.EXPECT().UpdateUser(ctx, expectedRequest).Return(existingUser, nil).Times(1)
+
+			actualUser, actualErr := userSvc.ConfirmUserEmail(ctx, existingUser.UUID().String(), newEmail)
+			assert.NoError(t, actualErr)
+			Expect(actualUser.Email).To(Equal(newEmail))
+			Expect(actualUser.PendingEmail).To(BeEmpty())
+			Expect(actualUser.EmailVerified).To(BeTrue())
+		})
+
+		It("should only mark the user's current email as verified", func() {
+			defer mockCtrl.Finish()
+
+			existingUser, err := mockuser.MockUser()
+			assert.NoError(t, err)
+
+			emailVerified := true
+			expectedRequest := repositories.UpdateUserRequest{
+				UserID:        existingUser.UUID(),
+				EmailVerified: &emailVerified,
+			}
+
+			mockUserRepo.EXPECT().GetUserByUUID(ctx, existingUser.UUID()).Return(existingUser, nil).Times(1)
+			mockUserRepo.EXPECT().GetUserByEmail(ctx, gomock.Any()).Times(0)
+			mockUserRepo.EXPECT().UpdateUser(ctx, expectedRequest).Return(existingUser, nil).Times(1)
+
+			_, actualErr := userSvc.ConfirmUserEmail(ctx, existingUser.UUID().String(), existingUser.Email())
+			assert.NoError(t, actualErr)
+		})
+
+		It("should reject an email that is not pending", func() {
+			defer mockCtrl.Finish()
+
+			existingUser, err := mockuser.MockUser()
+			assert.NoError(t, err)
+
+			mockUserRepo.EXPECT().GetUserByUUID(ctx, existingUser.UUID()).Return(existingUser, nil).Times(1)
+			mockUserRepo.EXPECT().UpdateUser(ctx, gomock.Any()).Times(0)
+
+			actualUser, actualErr := userSvc.ConfirmUserEmail(ctx, existingUser.UUID().String(), "stale-"+existingUser.Email())
+			assert.Nil(t, actualUser)
+			assert.True(t, errdefs.IsValidation(actualErr))
+		})
+
+		It("should return a conflict when the pending email has been taken by another user", func() {
+			defer mockCtrl.Finish()
+
+			existingUser, err := mockuser.MockUser()
+			assert.NoError(t, err)
+			otherUser, err := mockuser.MockUser()
+			assert.NoError(t, err)
+			_, err = existingUser.RequestEmailChange(otherUser.Email())
+			assert.NoError(t, err)
+
+			mockUserRepo.EXPECT().GetUserByUUID(ctx, existingUser.UUID()).Return(existingUser, nil).Times(1)
+			mockUserRepo.EXPECT().GetUserByEmail(ctx, otherUser.Email()).Return(otherUser, nil).Times(1)
+			mockUserRepo.EXPECT().UpdateUser(ctx, gomock.Any()).Times(0)
+
+			_, actualErr := userSvc.ConfirmUserEmail(ctx, existingUser.UUID().String(), otherUser.Email())
+			assert.True(t, errdefs.IsConflict(actualErr))
+		})
+	})
+
+	Context("Bootstrapping an admin", func() {
+		It("should make the user an admin when they verify the bootstrap admin email", func() {
+			defer mockCtrl.Finish()
+
+			existingUser, err := mockuser.MockUser()
+			assert.NoError(t, err)
+			userSvc.config = Config{BootstrapAdminEmail: existingUser.Email()}
+
+			emailVerified, role := true, user.RoleAdmin
+			expectedRequest := repositories.UpdateUserRequest{
+				UserID:        existingUser.UUID(),
+				EmailVerified: &emailVerified,
+				Role:          &role,
+			}
+
+			mockUserRepo.EXPECT().GetUserByUUID(ctx, existingUser.UUID()).Return(existingUser, nil).Times(1)
+			mockUserRepo.EXPECT().UpdateUser(ctx, expectedRequest).Return(existingUser, nil).Times(1)
+
+			_, actualErr := userSvc.ConfirmUserEmail(ctx, existingUser.UUID().String(), existingUser.Email())
+			assert.NoError(t, actualErr)
+		})
+
+		It("should make an existing user who has verified the bootstrap admin email an admin", func() {
+			defer mockCtrl.Finish()
+
+			existingUser, err := mockuser.MockUser()
+			assert.NoError(t, err)
+			_, err = existingUser.ConfirmEmail(existingUser.Email())
+			assert.NoError(t, err)
+			userSvc.config = Config{BootstrapAdminEmail: existingUser.Email()}
+
+			role := user.RoleAdmin
+			mockUserRepo.EXPECT().GetUserByEmail(ctx, existingUser.Email()).Return(existingUser, nil).Times(1)
+			mockUserRepo.EXPECT().UpdateUser(ctx, repositories.UpdateUserRequest{
+				UserID: existingUser.UUID(),
+				Role:   &role,
+			}).Return(existingUser, nil).Times(1)
+
+			assert.NoError(t, userSvc.BootstrapAdmin(ctx))
+		})
+
+		It("should not make a user who has not verified the bootstrap admin email an admin", func() {
+			defer mockCtrl.Finish()
+
+			existingUser, err := mockuser.MockUser()
+			assert.NoError(t, err)
+			userSvc.config = Config{BootstrapAdminEmail: existingUser.Email()}
+
+			mockUserRepo.EXPECT().GetUserByEmail(ctx, existingUser.Email()).Return(existingUser, nil).Times(1)
+			mockUserRepo.EXPECT().UpdateUser(ctx, gomock.Any()).Times(0)
+
+			assert.NoError(t, userSvc.BootstrapAdmin(ctx))
+		})
+
+		It("should do nothing when the bootstrap admin has not registered", func() {
+			defer mockCtrl.Finish()
+
+			userSvc.config = Config{BootstrapAdminEmail: "admin@example.com"}
+
+			mockUserRepo.EXPECT().GetUserByEmail(ctx, "admin@example.com").Return(nil, errdefs.NewNotFoundError("no document", nil)).Times(1)
+			mockUserRepo.EXPECT().UpdateUser(ctx, gomock.Any()).Times(0)
+
+			assert.NoError(t, userSvc.BootstrapAdmin(ctx))
+		})
+	})
+
+	Context("Assigning a role to a user", func() {
+		It("should update the role of the user", func() {
+			defer mockCtrl.Finish()
+
+			existingUser, err := mockuser.MockUser()
+			assert.NoError(t, err)
+
+			role := user.RoleManager
+			mockUserRepo.EXPECT().GetUserByUUID(ctx, existingUser.UUID()).Return(existingUser, nil).Times(1)
+			mockUserRepo.EXPECT().UpdateUser(ctx, repositories.UpdateUserRequest{
+				UserID: existingUser.UUID(),
+				Role:   &role,
+			}).Return(existingUser, nil).Times(1)
+
+			actualUser, actualErr := userSvc.AssignUserRole(ctx, existingUser.UUID().String(), "manager")
+			assert.NoError(t, actualErr)
+			Expect(actualUser.Role).To(Equal("manager"))
+		})
+
+		It("should reject an unknown role", func() {
+			defer mockCtrl.Finish()
+
+			existingUser, err := mockuser.MockUser()
+			assert.NoError(t, err)
+
+			mockUserRepo.EXPECT().GetUserByUUID(ctx, existingUser.UUID()).Return(existingUser, nil).Times(1)
+			mockUserRepo.EXPECT().UpdateUser(ctx, gomock.Any()).Times(0)
+
+			_, actualErr := userSvc.AssignUserRole(ctx, existingUser.UUID().String(), "owner")
+			assert.True(t, errdefs.IsValidation(actualErr))
+		})
+	})
+
+	Describe("Updating a user's image", func() {
+		pngHeader := []byte("\x89PNG\r\n\x1a\n")
+
+		It("should stream the image to storage and update the image url", func() {
+			defer mockCtrl.Finish()
+
+			existingUser, err := mockuser.MockUser()
+			assert.NoError(t, err)
+			imageUrl := "http://localhost:9001/image.png"
+			image := append(append([]byte{}, pngHeader...), bytes.Repeat([]byte{1}, 1024)...)
+
+			expectedRequest := repositories.UpdateUserRequest{
+				UserID:   existingUser.UUID(),
+				ImageUrl: &imageUrl,
+			}
+
+			mockUserRepo.EXPECT().GetUserByUUID(ctx, existingUser.UUID()).Return(existingUser, nil).Times(1)
+			mockStorageClient.EXPECT().UploadStream(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, item storage.StorageStreamItem) (string, error) {
+					Expect(item.Name).To(Equal(existingUser.UUID().String() + "-image.png"))
+					Expect(item.ContentType).To(Equal("image/png"))
+					Expect(item.PolicyType).To(Equal(storage.PolicyTypeReadOnly))
+
+					content, err := io.ReadAll(item.Content)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(content).To(Equal(image))
+					return imageUrl, nil
+				}).Times(1)
+			mockUserRepo.EXPECT().UpdateUser(ctx, expectedRequest).Return(existingUser, nil).Times(1)
+
+			actualUser, actualErr := userSvc.UpdateUserImage(ctx, existingUser.UUID().String(), inbound.UserImageUpload{
+				Content: bytes.NewReader(image),
+			})
+			assert.NoError(t, actualErr)
+			Expect(actualUser).NotTo(BeNil())
+		})
+
+		It("should reject content that is not a supported image", func() {
+			defer mockCtrl.Finish()
+
+			existingUser, err := mockuser.MockUser()
+			assert.NoError(t, err)
+
+			mockUserRepo.EXPECT().GetUserByUUID(ctx, existingUser.UUID()).Return(existingUser, nil).Times(1)
+			mockStorageClient.EXPECT().UploadStream(ctx, gomock.Any()).Times(0)
+			mockUserRepo.EXPECT().UpdateUser(ctx, gomock.Any()).Times(0)
+
+			_, actualErr := userSvc.UpdateUserImage(ctx, existingUser.UUID().String(), inbound.UserImageUpload{
+				Content: strings.NewReader("<html><body>not an image</body></html>"),
+			})
+			Expect(errdefs.IsValidation(actualErr)).To(BeTrue())
+		})
+
+		It("should reject images larger than the maximum size", func() {
+			defer mockCtrl.Finish()
+
+			existingUser, err := mockuser.MockUser()
+			assert.NoError(t, err)
+			image := append(append([]byte{}, pngHeader...), make([]byte, MaxUserImageSize)...)
+
+			mockUserRepo.EXPECT().GetUserByUUID(ctx, existingUser.UUID()).Return(existingUser, nil).Times(1)
+			mockStorageClient.EXPECT().UploadStream(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, item storage.StorageStreamItem) (string, error) {
+					_, err := io.Copy(io.Discard, item.Content)
+					return "", errors.New("upload aborted: " + err.Error())
+				}).Times(1)
+			mockUserRepo.EXPECT().UpdateUser(ctx, gomock.Any()).Times(0)
+
+			_, actualErr := userSvc.UpdateUserImage(ctx, existingUser.UUID().String(), inbound.UserImageUpload{
+				Content: bytes.NewReader(image),
+			})
+			Expect(errdefs.IsValidation(actualErr)).To(BeTrue())
+		})
+
+		It("should report storage failures as unavailable", func() {
+			defer mockCtrl.Finish()
+
+			existingUser, err := mockuser.MockUser()
+			assert.NoError(t, err)
+
+			mockUserRepo.EXPECT().GetUserByUUID(ctx, existingUser.UUID()).Return(existingUser, nil).Times(1)
+			mockStorageClient.EXPECT().UploadStream(ctx, gomock.Any()).Return("", errors.New("storage down")).Times(1)
+			mockUserRepo.EXPECT().UpdateUser(ctx, gomock.Any()).Times(0)
+
+			_, actualErr := userSvc.UpdateUserImage(ctx, existingUser.UUID().String(), inbound.UserImageUpload{
+				Content: bytes.NewReader(pngHeader),
+			})
+			Expect(errdefs.IsUnavailable(actualErr)).To(BeTrue())
+		})
+
+		It("should not store the image of a user that does not exist", func() {
+			defer mockCtrl.Finish()
+
+			userUUID := id.NewUUID()
+
+			mockUserRepo.EXPECT().GetUserByUUID(ctx, userUUID).
+				Return(nil, errdefs.NewNotFoundError("user not found", nil)).Times(1)
+			mockStorageClient.EXPECT().UploadStream(ctx, gomock.Any()).Times(0)
+
+			_, actualErr := userSvc.UpdateUserImage(ctx, userUUID.String(), inbound.UserImageUpload{
+				Content: bytes.NewReader(pngHeader),
+			})
+			Expect(errdefs.IsNotFound(actualErr)).To(BeTrue())
+		})
+	})
 })
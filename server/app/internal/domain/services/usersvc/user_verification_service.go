@@ -7,43 +7,97 @@ import (
 
 	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/user"
 	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/publishers"
 	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories"
+	"github.com/BrianLusina/skillq/server/app/pkg/tasks"
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
 	"github.com/BrianLusina/skillq/server/domain/id"
+	"github.com/BrianLusina/skillq/server/infra/logger"
 	"github.com/BrianLusina/skillq/server/utils/security"
 	"github.com/pkg/errors"
 )
 
+// VerificationConfig is the configuration of the codes issued to users to verify their email
+type VerificationConfig struct {
+	// CodeLength is the number of digits in a code
+	CodeLength int
+
+	// CodeTTL is how long a code is valid for after it is issued
+	CodeTTL time.Duration
+
+	// CodeSecret is the secret codes are hashed with, so that the codes can not be recovered from their hashes without it
+	CodeSecret string
+
+	// MaxAttempts is the number of attempts a user can make at verifying their email within the attempts window, after which
+	// they are locked out until the window has passed
+	MaxAttempts int
+
+	// AttemptsWindow is how long the attempts of a user are counted for, starting from their first attempt. Issuing a new
+	// code does not reset the attempts
+	AttemptsWindow time.Duration
+}
+
 // userVerificationService is the structure for the business logic handling user verification
 type userVerificationService struct {
-	userSvc              inbound.UserService
-	userVerificationRepo repositories.UserVerificationRepoPort
+	config                 VerificationConfig
+	userSvc                inbound.UserService
+	userVerificationRepo   repositories.UserVerificationRepoPort
+	sendEmailTaskPublisher publishers.TaskPublisher[tasks.SendEmailVerification]
 }
 
 var _ inbound.UserVerificationService = (*userVerificationService)(nil)
 
 // NewVerification creates a new user service implementation of the user use case
 func NewVerification(
+	config VerificationConfig,
 	userSvc inbound.UserService,
 	userVerificationRepo repositories.UserVerificationRepoPort,
-) inbound.UserVerificationService {
-	return &userVerificationService{
-		userSvc:              userSvc,
-		userVerificationRepo: userVerificationRepo,
+	sendEmailTaskPublisher publishers.TaskPublisher[tasks.SendEmailVerification],
+) (inbound.UserVerificationService, error) {
+	if config.CodeLength <= 0 {
+		return nil, fmt.Errorf("verification code length must be positive, got %d", config.CodeLength)
+	}
+
+	if config.CodeTTL <= 0 {
+		return nil, fmt.Errorf("verification code ttl must be positive, got %s", config.CodeTTL)
+	}
+
+	if config.CodeSecret == "" {
+		return nil, errors.New("verification code secret is required")
+	}
+
+	if config.MaxAttempts <= 0 {
+		return nil, fmt.Errorf("verification max attempts must be positive, got %d", config.MaxAttempts)
+	}
+
+	if config.AttemptsWindow <= 0 {
+		return nil, fmt.Errorf("verification attempts window must be positive, got %s", config.AttemptsWindow)
 	}
+
+	return &userVerificationService{
+		config:                 config,
+		userSvc:                userSvc,
+		userVerificationRepo:   userVerificationRepo,
+		sendEmailTaskPublisher: sendEmailTaskPublisher,
+	}, nil
 }
 
-// CreateEmailVerification creates a user verification & publishes it to a topic for a listener to send to a user
+// CreateEmailVerification creates a user verification with a new code for an email of the user. Only the hash of the code
+// is stored, the plain code is only available on the returned verification to be sent to the email
 func (svc *userVerificationService) CreateEmailVerification(ctx context.Context, userUUID string, email string) (user.UserVerification, error) {
 	// retrieve the existingUser
 	if _, err := svc.userSvc.GetUserByUUID(ctx, userUUID); err != nil {
 		return user.UserVerification{}, fmt.Errorf("failed to retrieve user %w", err)
 	}
 
-	code := security.GenerateCode()
-
 	uuid, err := id.StringToUUID(userUUID)
 	if err != nil {
-		return user.UserVerification{}, errors.Wrapf(err, "failed to parse user UUID %s", userUUID)
+		return user.UserVerification{}, errdefs.NewValidationError(fmt.Sprintf("invalid user UUID %s", userUUID), err)
+	}
+
+	code, err := security.GenerateCode(svc.config.CodeLength)
+	if err != nil {
+		return user.UserVerification{}, err
 	}
 
 	verificationId := id.NewUUID()
@@ -52,8 +106,11 @@ func (svc *userVerificationService) CreateEmailVerification(ctx context.Context,
 	verification := user.NewVerification(user.UserVerificationParams{
 		ID:         verificationId,
 		UserId:     uuid,
+		Email:      email,
 		Code:       code,
+		CodeHash:   security.HashCode(svc.config.CodeSecret, uuid.String(), code),
 		IsVerified: false,
+		ExpiresAt:  now.Add(svc.config.CodeTTL),
 		CreatedAt:  now,
 		UpdatedAt:  now,
 	})
@@ -66,33 +123,130 @@ func (svc *userVerificationService) CreateEmailVerification(ctx context.Context,
 	return verification, nil
 }
 
-// VerifyEmail verifies a user email
+// VerifyEmail verifies a user email with a code issued to them, confirming the email the code was sent to. If the code was
+// sent to the user's pending email, it replaces their email. Every attempt is counted against the user before the code is
+// checked, whichever code it is made with, & the user is locked out once they have made too many attempts in the attempts
+// window. The attempts are reset once the email is verified
 func (svc *userVerificationService) VerifyEmail(ctx context.Context, request inbound.VerifyEmailRequest) error {
 	userId, code := request.UserID, request.Code
-	if _, err := svc.userSvc.GetUserByUUID(ctx, userId); err != nil {
+	userUUID, err := id.StringToUUID(userId)
+	if err != nil {
+		return errdefs.NewValidationError(fmt.Sprintf("invalid user UUID %s", userId), err)
+	}
+
+	existingUser, err := svc.userSvc.GetUserByUUID(ctx, userId)
+	if err != nil {
 		return fmt.Errorf("failed to retrieve user %w", err)
 	}
-	userUUID, err := id.StringToUUID(userId)
+
+	// the attempt is recorded before the code is checked, so that concurrent attempts can not guess more codes than allowed
+	attempts, err := svc.userVerificationRepo.RecordUserVerificationAttempt(ctx, userUUID, svc.config.MaxAttempts, svc.config.AttemptsWindow)
 	if err != nil {
-		return errors.Wrapf(err, "failed to parse user UUID %s", userUUID)
+		if errors.Is(err, user.ErrVerificationLocked) {
+			return mapVerificationErr(err)
+		}
+		return errors.Wrapf(err, "failed to record verification attempt for user %s", userId)
 	}
 
-	verification, err := svc.userVerificationRepo.GetUserVerificationByCode(ctx, code)
+	verification, err := svc.userVerificationRepo.GetUserVerificationByCode(ctx, userUUID, security.HashCode(svc.config.CodeSecret, userUUID.String(), code))
 	if err != nil {
-		return errors.Wrapf(err, "failed to retrieve user verification for code %s", code)
+		if errdefs.IsNotFound(err) {
+			logger.FromContext(ctx).Infof("Failed email verification attempt %d of user %s", attempts, userId)
+
+			if attempts >= svc.config.MaxAttempts {
+				return mapVerificationErr(user.ErrVerificationLocked)
+			}
+			return mapVerificationErr(user.ErrVerificationCodeInvalid)
+		}
+		return errors.Wrapf(err, "failed to retrieve user verification for user %s", userId)
+	}
+
+	if verification.IsVerified() {
+		return nil
 	}
 
-	if verification.Code() != code {
-		return fmt.Errorf("invalid code provided %s", code)
+	if err := verification.CanVerify(time.Now()); err != nil {
+		return mapVerificationErr(err)
+	}
+
+	// verifications issued before they were scoped to an email were all sent to the user's email
+	email := verification.Email()
+	if email == "" {
+		email = existingUser.Email
+	}
+
+	// the email is confirmed before the verification is used up, so that the code can be used again if confirming fails
+	if _, err := svc.userSvc.ConfirmUserEmail(ctx, userId, email); err != nil {
+		return errors.Wrapf(err, "failed to confirm email of user %s", userId)
 	}
 
 	err = svc.userVerificationRepo.UpdateUserVerification(ctx, repositories.UpdateUserVerificationRequest{
-		UserID:     userUUID,
+		ID:         verification.ID(),
 		IsVerified: true,
 	})
 	if err != nil {
 		return errors.Wrapf(err, "failed to update user's verification status")
 	}
 
+	// the email is verified either way, the attempts are left to expire with their window if they can not be reset
+	if err := svc.userVerificationRepo.ResetUserVerificationAttempts(ctx, userUUID); err != nil {
+		logger.FromContext(ctx).Errorf("Failed to reset email verification attempts of user %s: %v", userId, err)
+	}
+
+	logger.FromContext(ctx).Infof("Verified email of user %s", userId)
+
+	return nil
+}
+
+// ResendEmailVerification expires the verifications of a user that have not been verified & publishes a task to send them
+// a new code, so that only the code sent last can be used. The code is sent to the user's pending email if they have one,
+// otherwise users whose email is already verified get a conflict error
+func (svc *userVerificationService) ResendEmailVerification(ctx context.Context, userId string) error {
+	userUUID, err := id.StringToUUID(userId)
+	if err != nil {
+		return errdefs.NewValidationError(fmt.Sprintf("invalid user UUID %s", userId), err)
+	}
+
+	existingUser, err := svc.userSvc.GetUserByUUID(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve user %w", err)
+	}
+
+	email := existingUser.PendingEmail
+	if email == "" {
+		if existingUser.EmailVerified {
+			return errdefs.NewConflictError("user email is already verified", nil)
+		}
+
+		email = existingUser.Email
+	}
+
+	if err := svc.userVerificationRepo.ExpireUserVerifications(ctx, userUUID); err != nil {
+		return errors.Wrapf(err, "failed to expire user verifications for user %s", userId)
+	}
+
+	sendEmailVerification := tasks.SendEmailVerification{
+		UserUUID: existingUser.UUID,
+		Email:    email,
+		Name:     existingUser.Name,
+	}
+
+	if err := svc.sendEmailTaskPublisher.Publish(ctx, sendEmailVerification); err != nil {
+		return errdefs.NewUnavailableError("failed to publish send email verification", err)
+	}
+
 	return nil
 }
+
+// mapVerificationErr maps a verification error of the user entity to a domain error with a message that is safe to show
+// to the user
+func mapVerificationErr(err error) error {
+	switch {
+	case errors.Is(err, user.ErrVerificationLocked):
+		return errdefs.NewTooManyRequestsError("too many failed attempts, try again later", err)
+	case errors.Is(err, user.ErrVerificationCodeExpired):
+		return errdefs.NewValidationError("verification code has expired, request a new verification code", err)
+	default:
+		return errdefs.NewValidationError("invalid verification code", err)
+	}
+}
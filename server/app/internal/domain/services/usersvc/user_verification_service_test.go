@@ -0,0 +1,327 @@
+package usersvc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/user"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound"
+	mockusersvc "github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound/mocks"
+	mockpublishers "github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/publishers/mocks"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories"
+	mockuserrepo "github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories/mocks"
+	"github.com/BrianLusina/skillq/server/app/pkg/tasks"
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/domain/id"
+	"github.com/BrianLusina/skillq/server/utils/security"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("User Verification Service", func() {
+	t := GinkgoT()
+
+	var (
+		mockCtrl                 *gomock.Controller
+		mockUserSvc              *mockusersvc.MockUserService
+		mockUserVerificationRepo *mockuserrepo.MockUserVerificationRepoPort
+		mockSendEmailPublisher   *mockpublishers.MockTaskPublisher[tasks.SendEmailVerification]
+		verificationSvc          inbound.UserVerificationService
+		userUUID                 id.UUID
+	)
+
+	config := VerificationConfig{
+		CodeLength:     6,
+		CodeTTL:        15 * time.Minute,
+		CodeSecret:     "verification-code-secret",
+		MaxAttempts:    3,
+		AttemptsWindow: time.Hour,
+	}
+
+	ctx := context.Background()
+
+	newVerification := func(code string, expiresAt time.Time) *user.UserVerification {
+		verification := user.NewVerification(user.UserVerificationParams{
+			ID:        id.NewUUID(),
+			UserId:    userUUID,
+			Email:     "jane@example.com",
+			CodeHash:  security.HashCode(config.CodeSecret, userUUID.String(), code),
+			ExpiresAt: expiresAt,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		})
+		return &verification
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(t)
+		mockUserSvc = mockusersvc.NewMockUserService(mockCtrl)
+		mockUserVerificationRepo = mockuserrepo.NewMockUserVerificationRepoPort(mockCtrl)
+		mockSendEmailPublisher = mockpublishers.NewMockTaskPublisher[tasks.SendEmailVerification](mockCtrl)
+		userUUID = id.NewUUID()
+
+		var err error
+		verificationSvc, err = NewVerification(config, mockUserSvc, mockUserVerificationRepo, mockSendEmailPublisher)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("should reject invalid configuration", func() {
+		for _, invalid := range []func(c *VerificationConfig){
+			func(c *VerificationConfig) { c.CodeLength = 0 },
+			func(c *VerificationConfig) { c.CodeTTL = 0 },
+			func(c *VerificationConfig) { c.CodeSecret = "" },
+			func(c *VerificationConfig) { c.MaxAttempts = 0 },
+			func(c *VerificationConfig) { c.AttemptsWindow = 0 },
+		} {
+			invalidConfig := config
+			invalid(&invalidConfig)
+
+			_, err := NewVerification(invalidConfig, mockUserSvc, mockUserVerificationRepo, mockSendEmailPublisher)
+			Expect(err).To(HaveOccurred())
+		}
+	})
+
+	Describe("Creating an email verification", func() {
+		It("should store only the hash of a new code that expires after the ttl", func() {
+			mockUserSvc.EXPECT().GetUserByUUID(ctx, userUUID.String()).Return(&inbound.UserResponse{}, nil).Times(1)
+
+			var stored user.UserVerification
+			mockUserVerificationRepo.EXPECT().CreateUserVerification(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, verification user.UserVerification) (*user.UserVerification, error) {
+					stored = verification
+					return &verification, nil
+				}).Times(1)
+
+			verification, err := verificationSvc.CreateEmailVerification(ctx, userUUID.String(), "jane@example.com")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(verification.Code()).To(MatchRegexp("^[0-9]{6}$"))
+			Expect(stored.CodeHash()).To(Equal(security.HashCode(config.CodeSecret, userUUID.String(), verification.Code())))
+			Expect(stored.ExpiresAt()).To(BeTemporally("~", time.Now().Add(config.CodeTTL), time.Second))
+			Expect(stored.UserID()).To(Equal(userUUID))
+			Expect(stored.Email()).To(Equal("jane@example.com"))
+		})
+	})
+
+	Describe("Verifying an email", func() {
+		const code = "123456"
+
+		var request inbound.VerifyEmailRequest
+
+		// expectAttempt expects the attempt of the user to be recorded as the given attempt
+		expectAttempt := func(attempt int) {
+			mockUserVerificationRepo.EXPECT().RecordUserVerificationAttempt(ctx, userUUID, config.MaxAttempts, config.AttemptsWindow).
+				Return(attempt, nil).Times(1)
+		}
+
+		BeforeEach(func() {
+			request = inbound.VerifyEmailRequest{UserID: userUUID.String(), Code: code}
+			mockUserSvc.EXPECT().GetUserByUUID(ctx, userUUID.String()).
+				Return(&inbound.UserResponse{UUID: userUUID.String(), Email: "jane@example.com"}, nil).AnyTimes()
+		})
+
+		It("should verify the verification of the user with the code & reset their attempts", func() {
+			verification := newVerification(code, time.Now().Add(time.Minute))
+
+			gomock.InOrder(
+				mockUserVerificationRepo.EXPECT().RecordUserVerificationAttempt(ctx, userUUID, config.MaxAttempts, config.AttemptsWindow).
+					Return(1, nil).Times(1),
+				mockUserVerificationRepo.EXPECT().GetUserVerificationByCode(ctx, userUUID, security.HashCode(config.CodeSecret, userUUID.String(), code)).
+					Return(verification, nil).Times(1),
+				mockUserSvc.EXPECT().ConfirmUserEmail(ctx, userUUID.String(), "jane@example.com").
+					Return(&inbound.UserResponse{EmailVerified: true}, nil).Times(1),
+				mockUserVerificationRepo.EXPECT().UpdateUserVerification(ctx, repositories.UpdateUserVerificationRequest{
+					ID:         verification.ID(),
+					IsVerified: true,
+				}).Return(nil).Times(1),
+				mockUserVerificationRepo.EXPECT().ResetUserVerificationAttempts(ctx, userUUID).Return(nil).Times(1),
+			)
+
+			Expect(verificationSvc.VerifyEmail(ctx, request)).To(Succeed())
+		})
+
+		It("should verify the email even if the attempts can not be reset", func() {
+			expectAttempt(1)
+			mockUserVerificationRepo.EXPECT().GetUserVerificationByCode(ctx, userUUID, gomock.Any()).
+				Return(newVerification(code, time.Now().Add(time.Minute)), nil).Times(1)
+			mockUserSvc.EXPECT().ConfirmUserEmail(ctx, userUUID.String(), "jane@example.com").
+				Return(&inbound.UserResponse{EmailVerified: true}, nil).Times(1)
+			mockUserVerificationRepo.EXPECT().UpdateUserVerification(ctx, gomock.Any()).Return(nil).Times(1)
+			mockUserVerificationRepo.EXPECT().ResetUserVerificationAttempts(ctx, userUUID).Return(errors.New("database is down")).Times(1)
+
+			Expect(verificationSvc.VerifyEmail(ctx, request)).To(Succeed())
+		})
+
+		It("should confirm the pending email the code was sent to", func() {
+			pending := user.NewVerification(user.UserVerificationParams{
+				ID:        id.NewUUID(),
+				UserId:    userUUID,
+				Email:     "jane.doe@example.com",
+				CodeHash:  security.HashCode(config.CodeSecret, userUUID.String(), code),
+				ExpiresAt: time.Now().Add(time.Minute),
+			})
+
+			expectAttempt(1)
+			mockUserVerificationRepo.EXPECT().GetUserVerificationByCode(ctx, userUUID, gomock.Any()).Return(&pending, nil).Times(1)
+			mockUserSvc.EXPECT().ConfirmUserEmail(ctx, userUUID.String(), "jane.doe@example.com").
+				Return(&inbound.UserResponse{Email: "jane.doe@example.com", EmailVerified: true}, nil).Times(1)
+			mockUserVerificationRepo.EXPECT().UpdateUserVerification(ctx, gomock.Any()).Return(nil).Times(1)
+			mockUserVerificationRepo.EXPECT().ResetUserVerificationAttempts(ctx, userUUID).Return(nil).Times(1)
+
+			Expect(verificationSvc.VerifyEmail(ctx, request)).To(Succeed())
+		})
+
+		It("should confirm the user's email with a verification that has no email", func() {
+			legacy := user.NewVerification(user.UserVerificationParams{
+				ID:        id.NewUUID(),
+				UserId:    userUUID,
+				CodeHash:  security.HashCode(config.CodeSecret, userUUID.String(), code),
+				ExpiresAt: time.Now().Add(time.Minute),
+			})
+
+			expectAttempt(1)
+			mockUserVerificationRepo.EXPECT().GetUserVerificationByCode(ctx, userUUID, gomock.Any()).Return(&legacy, nil).Times(1)
+			mockUserSvc.EXPECT().ConfirmUserEmail(ctx, userUUID.String(), "jane@example.com").
+				Return(&inbound.UserResponse{EmailVerified: true}, nil).Times(1)
+			mockUserVerificationRepo.EXPECT().UpdateUserVerification(ctx, gomock.Any()).Return(nil).Times(1)
+			mockUserVerificationRepo.EXPECT().ResetUserVerificationAttempts(ctx, userUUID).Return(nil).Times(1)
+
+			Expect(verificationSvc.VerifyEmail(ctx, request)).To(Succeed())
+		})
+
+		It("should not use up the verification when the email cannot be confirmed", func() {
+			verification := newVerification(code, time.Now().Add(time.Minute))
+
+			expectAttempt(1)
+			mockUserVerificationRepo.EXPECT().GetUserVerificationByCode(ctx, userUUID, gomock.Any()).Return(verification, nil).Times(1)
+			mockUserSvc.EXPECT().ConfirmUserEmail(ctx, userUUID.String(), "jane@example.com").
+				Return(nil, errdefs.NewConflictError("email jane@example.com is already in use", nil)).Times(1)
+			mockUserVerificationRepo.EXPECT().UpdateUserVerification(ctx, gomock.Any()).Times(0)
+			mockUserVerificationRepo.EXPECT().ResetUserVerificationAttempts(ctx, gomock.Any()).Times(0)
+
+			err := verificationSvc.VerifyEmail(ctx, request)
+			Expect(errdefs.IsConflict(err)).To(BeTrue())
+		})
+
+		It("should reject an expired code", func() {
+			verification := newVerification(code, time.Now().Add(-time.Minute))
+
+			expectAttempt(1)
+			mockUserVerificationRepo.EXPECT().GetUserVerificationByCode(ctx, userUUID, gomock.Any()).Return(verification, nil).Times(1)
+			mockUserVerificationRepo.EXPECT().UpdateUserVerification(ctx, gomock.Any()).Times(0)
+
+			err := verificationSvc.VerifyEmail(ctx, request)
+			Expect(errdefs.IsValidation(err)).To(BeTrue())
+			Expect(err).To(MatchError(user.ErrVerificationCodeExpired))
+		})
+
+		It("should reject the right code once the user is out of attempts without checking it", func() {
+			mockUserVerificationRepo.EXPECT().RecordUserVerificationAttempt(ctx, userUUID, config.MaxAttempts, config.AttemptsWindow).
+				Return(config.MaxAttempts, user.ErrVerificationLocked).Times(1)
+			mockUserVerificationRepo.EXPECT().GetUserVerificationByCode(ctx, gomock.Any(), gomock.Any()).Times(0)
+			mockUserSvc.EXPECT().ConfirmUserEmail(ctx, gomock.Any(), gomock.Any()).Times(0)
+
+			err := verificationSvc.VerifyEmail(ctx, request)
+			Expect(errdefs.IsTooManyRequests(err)).To(BeTrue())
+			Expect(err).To(MatchError(user.ErrVerificationLocked))
+		})
+
+		It("should reject a wrong code", func() {
+			expectAttempt(1)
+			mockUserVerificationRepo.EXPECT().GetUserVerificationByCode(ctx, userUUID, gomock.Any()).
+				Return(nil, errdefs.NewNotFoundError("user verification does not exist", nil)).Times(1)
+
+			err := verificationSvc.VerifyEmail(ctx, request)
+			Expect(errdefs.IsValidation(err)).To(BeTrue())
+			Expect(err).To(MatchError(user.ErrVerificationCodeInvalid))
+		})
+
+		It("should lock the user out on their last failed attempt", func() {
+			expectAttempt(config.MaxAttempts)
+			mockUserVerificationRepo.EXPECT().GetUserVerificationByCode(ctx, userUUID, gomock.Any()).
+				Return(nil, errdefs.NewNotFoundError("user verification does not exist", nil)).Times(1)
+
+			err := verificationSvc.VerifyEmail(ctx, request)
+			Expect(errdefs.IsTooManyRequests(err)).To(BeTrue())
+			Expect(err).To(MatchError(user.ErrVerificationLocked))
+		})
+
+		It("should return an error when the attempt can not be recorded", func() {
+			mockUserVerificationRepo.EXPECT().RecordUserVerificationAttempt(ctx, userUUID, config.MaxAttempts, config.AttemptsWindow).
+				Return(0, errors.New("database is down")).Times(1)
+			mockUserVerificationRepo.EXPECT().GetUserVerificationByCode(ctx, gomock.Any(), gomock.Any()).Times(0)
+
+			err := verificationSvc.VerifyEmail(ctx, request)
+			Expect(err).To(HaveOccurred())
+			Expect(errdefs.IsTooManyRequests(err)).To(BeFalse())
+		})
+	})
+
+	Describe("Resending an email verification", func() {
+		var existingUser *inbound.UserResponse
+
+		BeforeEach(func() {
+			existingUser = &inbound.UserResponse{UUID: userUUID.String(), Email: "jane@example.com", Name: "Jane"}
+		})
+
+		It("should expire the previous verifications & publish a task to send a new code", func() {
+			mockUserSvc.EXPECT().GetUserByUUID(ctx, userUUID.String()).Return(existingUser, nil).Times(1)
+
+			gomock.InOrder(
+				mockUserVerificationRepo.EXPECT().ExpireUserVerifications(ctx, userUUID).Return(nil).Times(1),
+				mockSendEmailPublisher.EXPECT().Publish(ctx, tasks.SendEmailVerification{
+					UserUUID: userUUID.String(),
+					Email:    "jane@example.com",
+					Name:     "Jane",
+				}).Return(nil).Times(1),
+			)
+
+			Expect(verificationSvc.ResendEmailVerification(ctx, userUUID.String())).To(Succeed())
+		})
+
+		It("should not send a code to a user whose email is marked as verified", func() {
+			existingUser.EmailVerified = true
+
+			mockUserSvc.EXPECT().GetUserByUUID(ctx, userUUID.String()).Return(existingUser, nil).Times(1)
+			mockUserVerificationRepo.EXPECT().ExpireUserVerifications(ctx, gomock.Any()).Times(0)
+			mockSendEmailPublisher.EXPECT().Publish(ctx, gomock.Any()).Times(0)
+
+			err := verificationSvc.ResendEmailVerification(ctx, userUUID.String())
+			Expect(errdefs.IsConflict(err)).To(BeTrue())
+		})
+
+		It("should send a code to the pending email of a verified user", func() {
+			existingUser.EmailVerified = true
+			existingUser.PendingEmail = "jane.doe@example.com"
+
+			mockUserSvc.EXPECT().GetUserByUUID(ctx, userUUID.String()).Return(existingUser, nil).Times(1)
+			mockUserVerificationRepo.EXPECT().ExpireUserVerifications(ctx, userUUID).Return(nil).Times(1)
+			mockSendEmailPublisher.EXPECT().Publish(ctx, tasks.SendEmailVerification{
+				UserUUID: userUUID.String(),
+				Email:    "jane.doe@example.com",
+				Name:     "Jane",
+			}).Return(nil).Times(1)
+
+			Expect(verificationSvc.ResendEmailVerification(ctx, userUUID.String())).To(Succeed())
+		})
+
+		It("should return an unavailable error when the task cannot be published", func() {
+			mockUserSvc.EXPECT().GetUserByUUID(ctx, userUUID.String()).Return(existingUser, nil).Times(1)
+			mockUserVerificationRepo.EXPECT().ExpireUserVerifications(ctx, userUUID).Return(nil).Times(1)
+			mockSendEmailPublisher.EXPECT().Publish(ctx, gomock.Any()).Return(errors.New("broker is down")).Times(1)
+
+			err := verificationSvc.ResendEmailVerification(ctx, userUUID.String())
+			Expect(errdefs.IsUnavailable(err)).To(BeTrue())
+		})
+
+		It("should reject an invalid user ID", func() {
+			err := verificationSvc.ResendEmailVerification(ctx, "not-a-uuid")
+			Expect(errdefs.IsValidation(err)).To(BeTrue())
+		})
+	})
+})
@@ -35,14 +35,15 @@ func NewEmailVerificationSentEventHandler(
 }
 
 func (h *emailVerificationSentEventHandler) Handle(ctx context.Context, event *events.EmailVerificationSent) error {
-	h.logger.Infof("Received event email verification sent, %v", event)
+	log := logger.FromContextOr(ctx, h.logger)
+	log.Infof("Received event email verification sent, %v", event)
 
 	uuid, email, name := event.UserUUID, event.Email, event.Name
 
 	verification, err := h.userVerificationSvc.CreateEmailVerification(ctx, uuid.String(), email)
 	if err != nil {
 		msg := fmt.Sprintf("Failed to create email verification for user %s for email %s", uuid.String(), email)
-		h.logger.Errorf(msg)
+		log.Errorf(msg)
 		return errors.Wrapf(err, msg)
 	}
 
@@ -61,7 +62,7 @@ func (h *emailVerificationSentEventHandler) Handle(ctx context.Context, event *e
 
 	if err := h.emailVerificationEventPublisher.Publish(ctx, sendEmailMessage); err != nil {
 		msg := fmt.Sprintf("Failed to publish event %v", sendEmailMessage)
-		h.logger.Errorf(msg)
+		log.Errorf(msg)
 		return errors.Wrapf(err, "failed to publish user email sent event")
 	}
 
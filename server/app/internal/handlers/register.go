@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/BrianLusina/skillq/server/infra/messaging"
+)
+
+// Register registers the handler of the tasks of a topic with a consumer. The payload of a message of the topic is
+// decoded into the task the handler handles, & a payload that is not a valid task fails with messaging.ErrInvalidPayload
+// without being handled
+func Register[T any](consumer messaging.EventConsumer, topic string, handler EventHandler[T]) {
+	consumer.AddHandler(topic, func(ctx context.Context, payload []byte) error {
+		var task T
+		if err := json.Unmarshal(payload, &task); err != nil {
+			return fmt.Errorf("%w: %w", messaging.ErrInvalidPayload, err)
+		}
+
+		return handler.Handle(ctx, &task)
+	})
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/BrianLusina/skillq/server/infra/messaging"
+	"github.com/stretchr/testify/assert"
+)
+
+// consumer records the handlers added to it
+type consumer struct {
+	handlers map[string]messaging.Handler
+}
+
+func (c *consumer) Consume(ctx context.Context, queue string) error {
+	return nil
+}
+
+func (c *consumer) AddHandler(topic string, handler messaging.Handler) {
+	c.handlers[topic] = handler
+}
+
+type task struct {
+	Email string `json:"email"`
+}
+
+// handlerFunc handles tasks with a function
+type handlerFunc[T any] func(ctx context.Context, task *T) error
+
+func (f handlerFunc[T]) Handle(ctx context.Context, task *T) error {
+	return f(ctx, task)
+}
+
+func TestRegister(t *testing.T) {
+	c := &consumer{handlers: map[string]messaging.Handler{}}
+
+	var handled []string
+	Register[task](c, "send_email", handlerFunc[task](func(ctx context.Context, task *task) error {
+		if task.Email == "" {
+			return errors.New("missing email")
+		}
+		handled = append(handled, task.Email)
+		return nil
+	}))
+
+	handler, ok := c.handlers["send_email"]
+	assert.True(t, ok)
+
+	t.Run("decodes the payload into the task of the handler", func(t *testing.T) {
+		assert.NoError(t, handler(context.Background(), []byte(`{"email":"john@example.com"}`)))
+		assert.Equal(t, []string{"john@example.com"}, handled)
+	})
+
+	t.Run("returns the error of the handler", func(t *testing.T) {
+		err := handler(context.Background(), []byte(`{}`))
+		assert.EqualError(t, err, "missing email")
+		assert.False(t, errors.Is(err, messaging.ErrInvalidPayload))
+	})
+
+	t.Run("fails an invalid payload without handling it", func(t *testing.T) {
+		err := handler(context.Background(), []byte(`not json`))
+		assert.ErrorIs(t, err, messaging.ErrInvalidPayload)
+		assert.Len(t, handled, 1)
+	})
+}
@@ -0,0 +1,45 @@
+package taskhandlers
+
+import (
+	"context"
+
+	"github.com/BrianLusina/skillq/server/app/internal/handlers"
+	"github.com/BrianLusina/skillq/server/app/internal/templates"
+	"github.com/BrianLusina/skillq/server/app/pkg/tasks"
+	"github.com/BrianLusina/skillq/server/infra/clients/email"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/pkg/errors"
+)
+
+type sendEmailChangeNoticeTaskHandler struct {
+	emailClient email.EmailClient
+	logger      logger.Logger
+}
+
+var _ handlers.EventHandler[tasks.SendEmailChangeNotice] = (*sendEmailChangeNoticeTaskHandler)(nil)
+
+func NewSendEmailChangeNoticeTaskHandler(
+	emailClient email.EmailClient,
+	logger logger.Logger,
+) handlers.EventHandler[tasks.SendEmailChangeNotice] {
+	return &sendEmailChangeNoticeTaskHandler{
+		emailClient: emailClient,
+		logger:      logger,
+	}
+}
+
+// Handle emails the user at their current email a notice that they have asked to change their email
+func (h *sendEmailChangeNoticeTaskHandler) Handle(ctx context.Context, task *tasks.SendEmailChangeNotice) error {
+	log := logger.FromContextOr(ctx, h.logger)
+	log.Infof("Received task send email change notice, %v", task)
+
+	userID, email, name, newEmail := task.UserUUID, task.Email, task.Name, task.NewEmail
+
+	emailTemplate := templates.BuildEmailChangeNotice(email, name, newEmail)
+	if err := h.emailClient.Send(email, emailTemplate); err != nil {
+		log.Errorf("Failed to send email change notice for user %s with error %v", userID, err)
+		return errors.Wrapf(err, "failed to send email change notice for user %s", userID)
+	}
+
+	return nil
+}
@@ -39,35 +39,36 @@ func NewSendEmailVerificationTaskHandler(
 }
 
 func (h *sendEmailVerificationTaskHandler) Handle(ctx context.Context, task *tasks.SendEmailVerification) error {
-	h.logger.Infof("Received task send email verification, %v", task)
+	log := logger.FromContextOr(ctx, h.logger)
+	log.Infof("Received task send email verification, %v", task)
 
 	userID, email, name := task.UserUUID, task.Email, task.Name
 
 	userUUID, err := id.StringToUUID(userID)
 	if err != nil {
 		msg := fmt.Sprintf("Failed to parse user ID %s", userID)
-		h.logger.Errorf(msg)
+		log.Errorf(msg)
 		return errors.Wrapf(err, msg)
 	}
 
 	if _, err := h.userRepo.GetUserByUUID(ctx, userUUID); err != nil {
 		msg := fmt.Sprintf("Failed to retrieve user %s", userID)
-		h.logger.Errorf(msg)
+		log.Errorf(msg)
 		return errors.Wrapf(err, msg)
 	}
 
 	verification, err := h.userVerificationSvc.CreateEmailVerification(ctx, userID, email)
 	if err != nil {
 		errMsg := fmt.Sprintf("failed to create verification for user %s with error %v", userID, err)
-		h.logger.Error(errMsg)
+		log.Error(errMsg)
 		return errors.Wrapf(err, "failed to create verification for user %s with error %v", userID, err)
 	}
 
-	emailTemplate := templates.BuildEmailVerification(email, name, verification.Code())
+	emailTemplate := templates.BuildEmailVerification(email, name, userID, verification.Code())
 	err = h.emailClient.Send(email, emailTemplate)
 	if err != nil {
 		errMsg := fmt.Sprintf("Failed to send email verification for user %s with error %v", userID, err)
-		h.logger.Error(errMsg)
+		log.Error(errMsg)
 		return errors.Wrapf(err, "failed to send email verification for user %s with error %v", userID, err)
 	}
 
@@ -0,0 +1,55 @@
+package taskhandlers
+
+import (
+	"context"
+
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/inbound"
+	"github.com/BrianLusina/skillq/server/app/internal/handlers"
+	"github.com/BrianLusina/skillq/server/app/internal/templates"
+	"github.com/BrianLusina/skillq/server/app/pkg/tasks"
+	"github.com/BrianLusina/skillq/server/infra/clients/email"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/pkg/errors"
+)
+
+type sendPasswordResetTaskHandler struct {
+	emailClient email.EmailClient
+	authSvc     inbound.AuthService
+	logger      logger.Logger
+}
+
+var _ handlers.EventHandler[tasks.SendPasswordReset] = (*sendPasswordResetTaskHandler)(nil)
+
+func NewSendPasswordResetTaskHandler(
+	emailClient email.EmailClient,
+	authSvc inbound.AuthService,
+	logger logger.Logger,
+) handlers.EventHandler[tasks.SendPasswordReset] {
+	return &sendPasswordResetTaskHandler{
+		emailClient: emailClient,
+		authSvc:     authSvc,
+		logger:      logger,
+	}
+}
+
+// Handle issues a password reset token to the user of the task & emails them a link to reset their password with it
+func (h *sendPasswordResetTaskHandler) Handle(ctx context.Context, task *tasks.SendPasswordReset) error {
+	log := logger.FromContextOr(ctx, h.logger)
+	log.Infof("Received task send password reset, %v", task)
+
+	userID, email, name := task.UserUUID, task.Email, task.Name
+
+	token, err := h.authSvc.IssuePasswordReset(ctx, userID)
+	if err != nil {
+		log.Errorf("Failed to issue password reset for user %s with error %v", userID, err)
+		return errors.Wrapf(err, "failed to issue password reset for user %s", userID)
+	}
+
+	emailTemplate := templates.BuildPasswordReset(email, name, token)
+	if err := h.emailClient.Send(email, emailTemplate); err != nil {
+		log.Errorf("Failed to send password reset for user %s with error %v", userID, err)
+		return errors.Wrapf(err, "failed to send password reset for user %s", userID)
+	}
+
+	return nil
+}
@@ -0,0 +1,2 @@
+// Package outboxrelay relays the messages of the outbox to the broker
+package outboxrelay
@@ -0,0 +1,169 @@
+package outboxrelay
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/outbox"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories"
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/messaging"
+)
+
+// the defaults of the configuration of the relay, used in place of values that are not set
+const (
+	defaultPollInterval = time.Second
+	defaultBatchSize    = 100
+	defaultLease        = time.Minute
+)
+
+// Config is the configuration of the relay
+type Config struct {
+	// PollInterval is how often the outbox is checked for messages that are due. Defaults to 1s
+	PollInterval time.Duration
+
+	// BatchSize is the maximum number of messages relayed per poll. Defaults to 100
+	BatchSize int
+
+	// Lease is how long a message claimed by a relay is held before another relay can claim it, which should be longer
+	// than publishing a message takes. Defaults to 1m
+	Lease time.Duration
+
+	// Backoff is the backoff before a message that failed to be relayed is relayed again
+	Backoff messaging.Backoff
+}
+
+// Relay publishes the pending messages of the outbox to the broker & marks them dispatched. Each message is claimed for a
+// lease before it is published, so that relays of several instances do not publish the same message. A message that
+// fails to be published is retried with a backoff. A message is published at least once: if the relay stops after
+// publishing a message but before marking it dispatched, it is published again with the same message ID once its lease
+// passes, so its task may be handled twice
+type Relay struct {
+	outboxRepo repositories.OutboxRepoPort
+	publisher  messaging.EventPublisher
+	config     Config
+	logger     logger.Logger
+	now        func() time.Time
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// New creates a new relay of the outbox
+func New(outboxRepo repositories.OutboxRepoPort, publisher messaging.EventPublisher, config Config, log logger.Logger) *Relay {
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultPollInterval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultBatchSize
+	}
+	if config.Lease <= 0 {
+		config.Lease = defaultLease
+	}
+
+	return &Relay{
+		outboxRepo: outboxRepo,
+		publisher:  publisher,
+		config:     config,
+		logger:     log,
+		now:        time.Now,
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+// Run relays the messages of the outbox every poll interval until the relay is stopped. This is a blocking operation
+func (r *Relay) Run() error {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// relaying is given a context that is not cancelled when the relay is stopped, so that a message that is
+		// published is also marked dispatched
+		r.relay(context.Background())
+
+		select {
+		case <-r.stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop stops the relay, waiting for the messages it is relaying to be relayed
+func (r *Relay) Stop(ctx context.Context) error {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// relay relays up to a batch of the messages of the outbox that are due, returning the number of messages that were
+// published
+func (r *Relay) relay(ctx context.Context) int {
+	published := 0
+	for i := 0; i < r.config.BatchSize; i++ {
+		message, err := r.outboxRepo.ClaimDueMessage(ctx, r.now(), r.config.Lease)
+		if err != nil {
+			if !errdefs.IsNotFound(err) {
+				r.logger.Errorf("Failed to claim due outbox message: %v", err)
+			}
+			break
+		}
+
+		if r.relayMessage(ctx, message) {
+			published++
+		}
+	}
+
+	return published
+}
+
+// relayMessage publishes a message & marks it dispatched, or records the failed attempt to publish it so that it is
+// retried once the backoff passes. It returns whether the message was published
+func (r *Relay) relayMessage(ctx context.Context, message outbox.Message) bool {
+	ctx = messageContext(ctx, message)
+	log := logger.FromContextOr(ctx, r.logger)
+
+	err := r.publisher.Publish(ctx, messaging.Message{
+		ID:          message.ID(),
+		Topic:       message.Topic(),
+		ContentType: message.ContentType(),
+		Timestamp:   message.CreatedAt(),
+		Payload:     json.RawMessage(message.Payload()),
+	})
+	if err != nil {
+		nextAttemptAt := r.now().Add(r.config.Backoff.Delay(message.Attempts()))
+		log.Errorf("Failed to relay outbox message %s on attempt %d, retrying at %s: %v", message.ID(), message.Attempts()+1, nextAttemptAt, err)
+
+		if err := r.outboxRepo.MarkMessageFailed(ctx, message.ID(), nextAttemptAt, err.Error()); err != nil {
+			log.Errorf("Failed to mark outbox message %s failed: %v", message.ID(), err)
+		}
+		return false
+	}
+
+	// a message that fails to be marked dispatched is published again once its lease passes
+	if err := r.outboxRepo.MarkMessageDispatched(ctx, message.ID(), r.now()); err != nil {
+		log.Errorf("Failed to mark outbox message %s dispatched: %v", message.ID(), err)
+	}
+
+	return true
+}
+
+// messageContext returns a copy of the context to relay a message in, which carries the request ID & trace context the
+// message was added to the outbox in
+func messageContext(ctx context.Context, message outbox.Message) context.Context {
+	return messaging.ContextWithHeaders(ctx, message.Headers())
+}
@@ -0,0 +1,352 @@
+package outboxrelay
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/outbox"
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/messaging"
+	"github.com/BrianLusina/skillq/server/infra/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOutboxRepo is an outbox kept in memory. Marking messages dispatched fails while failMarks is set, as it does when
+// the relay stops after publishing a message
+type fakeOutboxRepo struct {
+	mu        sync.Mutex
+	messages  []outbox.Message
+	failMarks bool
+}
+
+func (f *fakeOutboxRepo) AddMessage(_ context.Context, message outbox.Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.messages = append(f.messages, message)
+	return nil
+}
+
+func (f *fakeOutboxRepo) ClaimDueMessage(_ context.Context, now time.Time, lease time.Duration) (outbox.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var claimed *outbox.Message
+	for i, message := range f.messages {
+		if message.IsDue(now) && (claimed == nil || message.NextAttemptAt().Before(claimed.NextAttemptAt())) {
+			claimed = &f.messages[i]
+		}
+	}
+	if claimed == nil {
+		return outbox.Message{}, errdefs.NewNotFoundError("no outbox message is due", nil)
+	}
+
+	message := *claimed
+	if err := f.update(message.ID(), func(params *outbox.MessageParams) {
+		params.NextAttemptAt = now.Add(lease)
+	}); err != nil {
+		return outbox.Message{}, err
+	}
+	return message, nil
+}
+
+func (f *fakeOutboxRepo) MarkMessageDispatched(_ context.Context, messageID string, dispatchedAt time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.failMarks {
+		return errors.New("relay stopped")
+	}
+
+	return f.update(messageID, func(params *outbox.MessageParams) {
+		params.DispatchedAt = &dispatchedAt
+	})
+}
+
+func (f *fakeOutboxRepo) MarkMessageFailed(_ context.Context, messageID string, nextAttemptAt time.Time, reason string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.update(messageID, func(params *outbox.MessageParams) {
+		params.Attempts++
+		params.NextAttemptAt = nextAttemptAt
+		params.LastError = reason
+	})
+}
+
+func (f *fakeOutboxRepo) update(messageID string, change func(params *outbox.MessageParams)) error {
+	for i, m := range f.messages {
+		if m.ID() != messageID {
+			continue
+		}
+
+		params := outbox.MessageParams{
+			ID:            m.ID(),
+			Topic:         m.Topic(),
+			ContentType:   m.ContentType(),
+			Payload:       m.Payload(),
+			Headers:       m.Headers(),
+			Attempts:      m.Attempts(),
+			NextAttemptAt: m.NextAttemptAt(),
+			LastError:     m.LastError(),
+			DispatchedAt:  m.DispatchedAt(),
+			CreatedAt:     m.CreatedAt(),
+			UpdatedAt:     m.UpdatedAt(),
+		}
+		change(&params)
+		f.messages[i] = outbox.NewMessage(params)
+		return nil
+	}
+	return errors.New("no message " + messageID)
+}
+
+func (f *fakeOutboxRepo) message(messageID string) *outbox.Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, m := range f.messages {
+		if m.ID() == messageID {
+			return &m
+		}
+	}
+	return &outbox.Message{}
+}
+
+// fakePublisher records the messages it publishes, failing while err is set
+type fakePublisher struct {
+	mu         sync.Mutex
+	published  []messaging.Message
+	requestIDs []string
+	err        error
+}
+
+func (f *fakePublisher) Publish(ctx context.Context, message messaging.Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return f.err
+	}
+
+	requestID, _ := requestid.FromContext(ctx)
+	f.published = append(f.published, message)
+	f.requestIDs = append(f.requestIDs, requestID)
+	return nil
+}
+
+func (f *fakePublisher) Close() error {
+	return nil
+}
+
+func (f *fakePublisher) publishedIDs() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ids := make([]string, 0, len(f.published))
+	for _, message := range f.published {
+		ids = append(ids, message.ID)
+	}
+	return ids
+}
+
+func newTestRelay(repo *fakeOutboxRepo, publisher *fakePublisher, now time.Time) *Relay {
+	log, _ := logger.NewTestLogger()
+	relay := New(repo, publisher, Config{
+		PollInterval: time.Millisecond,
+		BatchSize:    10,
+		Lease:        time.Minute,
+		Backoff:      messaging.Backoff{InitialDelay: time.Minute, MaxDelay: time.Hour},
+	}, log)
+	relay.now = func() time.Time { return now }
+
+	return relay
+}
+
+func pendingMessage(messageID string, addedAt time.Time) outbox.Message {
+	return outbox.NewMessage(outbox.MessageParams{
+		ID:            messageID,
+		Topic:         "SendEmailVerification",
+		ContentType:   "text/plain",
+		Payload:       []byte(`{"userUUID":"user-1"}`),
+		Headers:       map[string]string{messaging.HeaderRequestID: "request-1"},
+		NextAttemptAt: addedAt,
+		CreatedAt:     addedAt,
+		UpdatedAt:     addedAt,
+	})
+}
+
+func TestRelay(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("publishes pending messages in the request they were added in & marks them dispatched", func(t *testing.T) {
+		repo := &fakeOutboxRepo{}
+		publisher := &fakePublisher{}
+		require.NoError(t, repo.AddMessage(ctx, pendingMessage("message-1", now)))
+
+		published := newTestRelay(repo, publisher, now).relay(ctx)
+
+		assert.Equal(t, 1, published)
+		require.Equal(t, []string{"message-1"}, publisher.publishedIDs())
+		assert.Equal(t, "SendEmailVerification", publisher.published[0].Topic)
+		assert.Equal(t, now, publisher.published[0].Timestamp)
+		assert.Equal(t, []string{"request-1"}, publisher.requestIDs)
+
+		message := repo.message("message-1")
+		assert.True(t, message.IsDispatched())
+		assert.Equal(t, now, *message.DispatchedAt())
+	})
+
+	t.Run("does not publish dispatched messages again", func(t *testing.T) {
+		repo := &fakeOutboxRepo{}
+		publisher := &fakePublisher{}
+		require.NoError(t, repo.AddMessage(ctx, pendingMessage("message-1", now)))
+		relay := newTestRelay(repo, publisher, now)
+
+		relay.relay(ctx)
+		published := relay.relay(ctx)
+
+		assert.Zero(t, published)
+		assert.Equal(t, []string{"message-1"}, publisher.publishedIDs())
+	})
+
+	t.Run("publishes messages added before the relay started, as after a crash following the commit", func(t *testing.T) {
+		repo := &fakeOutboxRepo{}
+		publisher := &fakePublisher{}
+		require.NoError(t, repo.AddMessage(ctx, pendingMessage("message-1", now.Add(-time.Hour))))
+		require.NoError(t, repo.AddMessage(ctx, pendingMessage("message-2", now.Add(-time.Minute))))
+
+		published := newTestRelay(repo, publisher, now).relay(ctx)
+
+		assert.Equal(t, 2, published)
+		assert.Equal(t, []string{"message-1", "message-2"}, publisher.publishedIDs())
+	})
+
+	t.Run("does not publish a message claimed by another relay", func(t *testing.T) {
+		repo := &fakeOutboxRepo{}
+		publisher := &fakePublisher{}
+		require.NoError(t, repo.AddMessage(ctx, pendingMessage("message-1", now)))
+
+		message, err := repo.ClaimDueMessage(ctx, now, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, "message-1", message.ID())
+
+		published := newTestRelay(repo, publisher, now).relay(ctx)
+
+		assert.Zero(t, published)
+		assert.Empty(t, publisher.publishedIDs())
+	})
+
+	t.Run("relays no more than a batch of messages per poll", func(t *testing.T) {
+		repo := &fakeOutboxRepo{}
+		publisher := &fakePublisher{}
+		for _, messageID := range []string{"message-1", "message-2", "message-3"} {
+			require.NoError(t, repo.AddMessage(ctx, pendingMessage(messageID, now)))
+		}
+		relay := newTestRelay(repo, publisher, now)
+		relay.config.BatchSize = 2
+
+		assert.Equal(t, 2, relay.relay(ctx))
+		assert.Equal(t, 1, relay.relay(ctx))
+	})
+
+	t.Run("publishes a message again with the same ID after a crash between publishing & marking it dispatched", func(t *testing.T) {
+		repo := &fakeOutboxRepo{failMarks: true}
+		publisher := &fakePublisher{}
+		require.NoError(t, repo.AddMessage(ctx, pendingMessage("message-1", now)))
+
+		newTestRelay(repo, publisher, now).relay(ctx)
+		assert.False(t, repo.message("message-1").IsDispatched())
+
+		// the relay restarts, & relays the message again once its lease passes
+		repo.failMarks = false
+		published := newTestRelay(repo, publisher, now.Add(time.Second)).relay(ctx)
+		assert.Zero(t, published)
+
+		newTestRelay(repo, publisher, now.Add(time.Minute)).relay(ctx)
+
+		assert.Equal(t, []string{"message-1", "message-1"}, publisher.publishedIDs())
+		assert.True(t, repo.message("message-1").IsDispatched())
+	})
+
+	t.Run("retries a message that failed to be published once the backoff passes", func(t *testing.T) {
+		repo := &fakeOutboxRepo{}
+		publisher := &fakePublisher{err: errors.New("broker is down")}
+		require.NoError(t, repo.AddMessage(ctx, pendingMessage("message-1", now)))
+
+		published := newTestRelay(repo, publisher, now).relay(ctx)
+
+		assert.Zero(t, published)
+		message := repo.message("message-1")
+		assert.Equal(t, 1, message.Attempts())
+		assert.Equal(t, "broker is down", message.LastError())
+		assert.False(t, message.IsDispatched())
+
+		// the first retry is backed off by between half & all of the initial delay
+		assert.False(t, message.NextAttemptAt().Before(now.Add(30*time.Second)))
+		assert.False(t, message.NextAttemptAt().After(now.Add(time.Minute)))
+
+		publisher.err = nil
+		published = newTestRelay(repo, publisher, now.Add(29*time.Second)).relay(ctx)
+		assert.Zero(t, published)
+		assert.Empty(t, publisher.publishedIDs())
+
+		published = newTestRelay(repo, publisher, now.Add(time.Minute)).relay(ctx)
+		assert.Equal(t, 1, published)
+		assert.Equal(t, []string{"message-1"}, publisher.publishedIDs())
+		assert.True(t, repo.message("message-1").IsDispatched())
+	})
+
+	t.Run("backs off exponentially with every failed attempt", func(t *testing.T) {
+		repo := &fakeOutboxRepo{}
+		publisher := &fakePublisher{err: errors.New("broker is down")}
+		require.NoError(t, repo.AddMessage(ctx, pendingMessage("message-1", now)))
+
+		at := now
+		for attempt := 1; attempt <= 3; attempt++ {
+			newTestRelay(repo, publisher, at).relay(ctx)
+
+			message := repo.message("message-1")
+			assert.Equal(t, attempt, message.Attempts())
+
+			maxDelay := time.Minute << (attempt - 1)
+			assert.False(t, message.NextAttemptAt().Before(at.Add(maxDelay/2)), "attempt %d", attempt)
+			assert.False(t, message.NextAttemptAt().After(at.Add(maxDelay)), "attempt %d", attempt)
+			at = message.NextAttemptAt()
+		}
+	})
+
+	t.Run("defaults the configuration that is not set", func(t *testing.T) {
+		log, _ := logger.NewTestLogger()
+		relay := New(&fakeOutboxRepo{}, &fakePublisher{}, Config{}, log)
+
+		assert.Equal(t, defaultPollInterval, relay.config.PollInterval)
+		assert.Equal(t, defaultBatchSize, relay.config.BatchSize)
+		assert.Equal(t, defaultLease, relay.config.Lease)
+	})
+
+	t.Run("run relays until stopped", func(t *testing.T) {
+		repo := &fakeOutboxRepo{}
+		publisher := &fakePublisher{}
+		relay := newTestRelay(repo, publisher, now)
+
+		done := make(chan error, 1)
+		go func() {
+			done <- relay.Run()
+		}()
+
+		require.NoError(t, repo.AddMessage(ctx, pendingMessage("message-1", now)))
+		assert.Eventually(t, func() bool {
+			return repo.message("message-1").IsDispatched()
+		}, time.Second, time.Millisecond)
+
+		require.NoError(t, relay.Stop(ctx))
+		assert.NoError(t, <-done)
+		assert.NoError(t, relay.Stop(ctx))
+	})
+}
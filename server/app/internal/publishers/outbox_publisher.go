@@ -0,0 +1,61 @@
+package publishers
+
+import (
+	"context"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/app/internal/domain/entities/outbox"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/publishers"
+	"github.com/BrianLusina/skillq/server/app/internal/domain/ports/outbound/repositories"
+	"github.com/BrianLusina/skillq/server/app/pkg/tasks"
+	"github.com/BrianLusina/skillq/server/infra/messaging"
+	amqppublisher "github.com/BrianLusina/skillq/server/infra/messaging/amqp/publisher"
+)
+
+// outboxTaskPublisherAdapter publishes tasks by adding them to the outbox, from which they are relayed to the broker.
+// Tasks published with a context that is in a transaction are only relayed once the transaction commits
+type outboxTaskPublisherAdapter[T any] struct {
+	outboxRepo repositories.OutboxRepoPort
+	topic      tasks.TaskName
+}
+
+// NewOutboxTaskPublisher creates a new publisher of tasks of the given topic that adds them to the outbox
+func NewOutboxTaskPublisher[T any](outboxRepo repositories.OutboxRepoPort, topic tasks.TaskName) publishers.TaskPublisher[T] {
+	return &outboxTaskPublisherAdapter[T]{
+		outboxRepo: outboxRepo,
+		topic:      topic,
+	}
+}
+
+// Publish adds the task to the outbox, keeping the request ID & trace context it is published in, so that it is relayed
+// in them
+func (s *outboxTaskPublisherAdapter[T]) Publish(ctx context.Context, task T) error {
+	message := messaging.New(
+		messaging.MessageParams{
+			Topic:       string(s.topic),
+			ContentType: "text/plain",
+			Payload:     task,
+		},
+	)
+
+	payload, err := message.PayloadToBytes()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	return s.outboxRepo.AddMessage(ctx, outbox.NewMessage(outbox.MessageParams{
+		ID:            message.ID,
+		Topic:         message.Topic,
+		ContentType:   message.ContentType,
+		Payload:       payload,
+		Headers:       messaging.HeadersFromContext(ctx),
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}))
+}
+
+// Configure is a no-op, tasks are relayed with the options of the publisher of the relay
+func (s *outboxTaskPublisherAdapter[T]) Configure(...amqppublisher.Option) {}
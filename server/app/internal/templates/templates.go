@@ -1,10 +1,16 @@
 package templates
 
-import "os"
+import (
+	"net/url"
+	"os"
+)
 
-func BuildEmailVerification(to string, name string, code string) []byte {
+// BuildEmailVerification builds the email sent to a user with a link to verify their email with the given code. Codes are
+// scoped to a user, so the link carries the user's ID along with the code
+func BuildEmailVerification(to string, name string, userID string, code string) []byte {
 	frontendURL := os.Getenv("FRONTEND_URL")
-	link := frontendURL + "/verify-email?code=" + code
+	query := url.Values{"userId": {userID}, "code": {code}}
+	link := frontendURL + "/verify-email?" + query.Encode()
 	msg := []byte("To: " + to + "\r\n" +
 		"Subject: SkillQ: Verify your email address\r\n" +
 		"\r\n" +
@@ -13,3 +19,32 @@ func BuildEmailVerification(to string, name string, code string) []byte {
 
 	return msg
 }
+
+// BuildPasswordReset builds the email sent to a user with a link to reset their password with the given token
+func BuildPasswordReset(to string, name string, token string) []byte {
+	frontendURL := os.Getenv("FRONTEND_URL")
+	query := url.Values{"token": {token}}
+	link := frontendURL + "/reset-password?" + query.Encode()
+	msg := []byte("To: " + to + "\r\n" +
+		"Subject: SkillQ: Reset your password\r\n" +
+		"\r\n" +
+		"Hi " + name + ",\n\n" +
+		"Please follow the link to reset your password: " + link + "\n\n" +
+		"If you did not ask to reset your password, you can ignore this email.\r\n")
+
+	return msg
+}
+
+// BuildEmailChangeNotice builds the email sent to a user at their current email when they ask to change their email to a
+// new email, so that they can act on a change they did not ask for
+func BuildEmailChangeNotice(to string, name string, newEmail string) []byte {
+	msg := []byte("To: " + to + "\r\n" +
+		"Subject: SkillQ: Your email address is being changed\r\n" +
+		"\r\n" +
+		"Hi " + name + ",\n\n" +
+		"A request was made to change the email address of your account to " + newEmail + ". " +
+		"The change only takes effect once the new email address is verified.\n\n" +
+		"If you did not ask to change your email address, please reset your password.\r\n")
+
+	return msg
+}
@@ -1,14 +1,30 @@
 package configs
 
+import "time"
+
 type (
 	App struct {
 		Name    string `env-required:"true" yaml:"name"    env:"APP_NAME"`
 		Version string `env-required:"true" yaml:"version" env:"APP_VERSION"`
+
+		// ShutdownTimeout is the deadline for draining requests, stopping consumers & closing connections on shutdown
+		ShutdownTimeout time.Duration `yaml:"shutdownTimeout" env:"APP_SHUTDOWN_TIMEOUT"`
 	}
 
 	HTTP struct {
 		Host string `env-required:"true" yaml:"host" env:"HTTP_HOST"`
 		Port int    `env-required:"true" yaml:"port" env:"HTTP_PORT"`
+
+		// ValidateRequests enables validating requests against the OpenAPI document before they are handled
+		ValidateRequests bool `yaml:"validateRequests" env:"HTTP_VALIDATE_REQUESTS"`
+
+		// ProxyHeader is the header the IP address of the client is read from, such as X-Real-IP, on requests sent by trusted
+		// proxies. It has to be a header the proxies overwrite rather than append to, otherwise clients can set it
+		ProxyHeader string `yaml:"proxyHeader" env:"HTTP_PROXY_HEADER"`
+
+		// TrustedProxies are the IP addresses or CIDR ranges of the proxies in front of the service. The proxy header is only
+		// read on requests sent by them, the IP address of the client is the remote address of any other request
+		TrustedProxies []string `yaml:"trustedProxies" env:"HTTP_TRUSTED_PROXIES" env-separator:","`
 	}
 
 	Log struct {
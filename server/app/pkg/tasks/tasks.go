@@ -62,20 +62,39 @@ func (sev *SendEmailVerification) String() string {
 	return fmt.Sprintf("SendEmailVerification(userUUID=%s, email=%s, name=%s)", sev.UserUUID, sev.Email, sev.Name)
 }
 
-// StoreUserImage is a task message that triggers the storage of a user image
-type StoreUserImage struct {
+// SendPasswordReset is a task that is triggered to signal that a password reset link is to be sent to a user. The task does
+// not carry the token, which is issued when the task is handled, so that the token is never stored with the task
+type SendPasswordReset struct {
 	sharedkernel.DomainEvent
-	UserUUID    string `json:"userUUID"`
-	ContentType string `json:"contentType"`
-	Content     string `json:"content"`
-	Name        string `json:"name"`
-	Bucket      string `json:"bucket"`
+	UserUUID string `json:"userId"`
+	Email    string `json:"email"`
+	Name     string `json:"name"`
 }
 
-func (st *StoreUserImage) Identity() string {
-	return string(StoreUserImageTaskName)
+func (spr *SendPasswordReset) Identity() string {
+	return string(SendPasswordResetName)
 }
 
-func (st *StoreUserImage) String() string {
-	return fmt.Sprintf("StoreUserImage(userUUID=%s, contentType=%s, name=%s, bucket=%s)", st.UserUUID, st.ContentType, st.Name, st.Bucket)
+// String describes the task. It has a value receiver so that the task is also described when it is logged as the payload
+// of a message
+func (spr SendPasswordReset) String() string {
+	return fmt.Sprintf("SendPasswordReset(userUUID=%s, email=%s, name=%s)", spr.UserUUID, spr.Email, spr.Name)
+}
+
+// SendEmailChangeNotice is a task that is triggered to notify a user at their current email that they have asked to change
+// their email to a new email
+type SendEmailChangeNotice struct {
+	sharedkernel.DomainEvent
+	UserUUID string `json:"userId"`
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	NewEmail string `json:"newEmail"`
+}
+
+func (secn *SendEmailChangeNotice) Identity() string {
+	return string(SendEmailChangeNoticeName)
+}
+
+func (secn *SendEmailChangeNotice) String() string {
+	return fmt.Sprintf("SendEmailChangeNotice(userUUID=%s, email=%s, name=%s, newEmail=%s)", secn.UserUUID, secn.Email, secn.Name, secn.NewEmail)
 }
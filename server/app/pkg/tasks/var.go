@@ -3,7 +3,8 @@ package tasks
 type TaskName string
 
 const (
-	StoreUserImageTaskName     TaskName = "StoreUserImage"
 	SendEmailVerificationName  TaskName = "SendEmailVerification"
 	StartEmailVerificationName TaskName = "StartEmailVerification"
+	SendPasswordResetName      TaskName = "SendPasswordReset"
+	SendEmailChangeNoticeName  TaskName = "SendEmailChangeNotice"
 )
@@ -0,0 +1,3 @@
+// Package errdefs contains the typed errors of the domain. Adapters & services return these so that callers, such as transports, can decide how to
+// handle a failure without inspecting error messages
+package errdefs
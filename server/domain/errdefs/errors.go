@@ -0,0 +1,139 @@
+package errdefs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// domainError is the common structure of all typed domain errors. It carries a message that is safe to show to a client & the underlying cause
+type domainError struct {
+	msg string
+	err error
+}
+
+// Error returns the message of the error along with the cause if there is one
+func (e *domainError) Error() string {
+	if e.err != nil {
+		return fmt.Sprintf("%s: %v", e.msg, e.err)
+	}
+	return e.msg
+}
+
+// Message returns the message of the error without the underlying cause
+func (e *domainError) Message() string {
+	return e.msg
+}
+
+// Unwrap returns the underlying cause of the error
+func (e *domainError) Unwrap() error {
+	return e.err
+}
+
+type (
+	// NotFoundError is returned when a requested resource does not exist
+	NotFoundError struct{ domainError }
+
+	// ConflictError is returned when a resource can not be created or changed because it conflicts with an existing resource
+	ConflictError struct{ domainError }
+
+	// ValidationError is returned when provided input is invalid
+	ValidationError struct{ domainError }
+
+	// UnauthorizedError is returned when a caller could not be authenticated
+	UnauthorizedError struct{ domainError }
+
+	// ForbiddenError is returned when an authenticated caller is not allowed to perform an operation
+	ForbiddenError struct{ domainError }
+
+	// TooManyRequestsError is returned when a caller has made too many attempts at an operation & has to wait or start over
+	TooManyRequestsError struct{ domainError }
+
+	// UnavailableError is returned when a dependency such as a database or broker can not be reached
+	UnavailableError struct{ domainError }
+)
+
+// NewNotFoundError creates a new NotFoundError with a message and an optional cause
+func NewNotFoundError(msg string, err error) error {
+	return &NotFoundError{domainError{msg: msg, err: err}}
+}
+
+// NewConflictError creates a new ConflictError with a message and an optional cause
+func NewConflictError(msg string, err error) error {
+	return &ConflictError{domainError{msg: msg, err: err}}
+}
+
+// NewValidationError creates a new ValidationError with a message and an optional cause
+func NewValidationError(msg string, err error) error {
+	return &ValidationError{domainError{msg: msg, err: err}}
+}
+
+// NewUnauthorizedError creates a new UnauthorizedError with a message and an optional cause
+func NewUnauthorizedError(msg string, err error) error {
+	return &UnauthorizedError{domainError{msg: msg, err: err}}
+}
+
+// NewForbiddenError creates a new ForbiddenError with a message and an optional cause
+func NewForbiddenError(msg string, err error) error {
+	return &ForbiddenError{domainError{msg: msg, err: err}}
+}
+
+// NewTooManyRequestsError creates a new TooManyRequestsError with a message and an optional cause
+func NewTooManyRequestsError(msg string, err error) error {
+	return &TooManyRequestsError{domainError{msg: msg, err: err}}
+}
+
+// NewUnavailableError creates a new UnavailableError with a message and an optional cause
+func NewUnavailableError(msg string, err error) error {
+	return &UnavailableError{domainError{msg: msg, err: err}}
+}
+
+// IsNotFound checks if err is or wraps a NotFoundError
+func IsNotFound(err error) bool {
+	var target *NotFoundError
+	return errors.As(err, &target)
+}
+
+// IsConflict checks if err is or wraps a ConflictError
+func IsConflict(err error) bool {
+	var target *ConflictError
+	return errors.As(err, &target)
+}
+
+// IsValidation checks if err is or wraps a ValidationError
+func IsValidation(err error) bool {
+	var target *ValidationError
+	return errors.As(err, &target)
+}
+
+// IsUnauthorized checks if err is or wraps an UnauthorizedError
+func IsUnauthorized(err error) bool {
+	var target *UnauthorizedError
+	return errors.As(err, &target)
+}
+
+// IsForbidden checks if err is or wraps a ForbiddenError
+func IsForbidden(err error) bool {
+	var target *ForbiddenError
+	return errors.As(err, &target)
+}
+
+// IsTooManyRequests checks if err is or wraps a TooManyRequestsError
+func IsTooManyRequests(err error) bool {
+	var target *TooManyRequestsError
+	return errors.As(err, &target)
+}
+
+// IsUnavailable checks if err is or wraps an UnavailableError
+func IsUnavailable(err error) bool {
+	var target *UnavailableError
+	return errors.As(err, &target)
+}
+
+// Message returns the client safe message of the first domain error found in the chain of err. If there is none, ok is false
+func Message(err error) (msg string, ok bool) {
+	var target interface{ Message() string }
+	if errors.As(err, &target) {
+		return target.Message(), true
+	}
+	return "", false
+}
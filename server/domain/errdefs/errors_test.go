@@ -0,0 +1,92 @@
+package errdefs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type errorTestCase struct {
+	name  string
+	err   error
+	check func(error) bool
+}
+
+var cause = errors.New("mongo: no documents in result")
+
+var errorTestCases = []errorTestCase{
+	{
+		name:  "not found error should be detected when wrapped",
+		err:   fmt.Errorf("failed to retrieve user: %w", NewNotFoundError("user does not exist", cause)),
+		check: IsNotFound,
+	},
+	{
+		name:  "conflict error should be detected when wrapped",
+		err:   fmt.Errorf("failed to create user: %w", NewConflictError("user already exists", cause)),
+		check: IsConflict,
+	},
+	{
+		name:  "validation error should be detected when wrapped",
+		err:   fmt.Errorf("failed to create user: %w", NewValidationError("invalid email", nil)),
+		check: IsValidation,
+	},
+	{
+		name:  "unauthorized error should be detected when wrapped",
+		err:   fmt.Errorf("failed to login: %w", NewUnauthorizedError("invalid credentials", nil)),
+		check: IsUnauthorized,
+	},
+	{
+		name:  "forbidden error should be detected when wrapped",
+		err:   fmt.Errorf("failed to delete user: %w", NewForbiddenError("not allowed to delete user", nil)),
+		check: IsForbidden,
+	},
+	{
+		name:  "too many requests error should be detected when wrapped",
+		err:   fmt.Errorf("failed to verify email: %w", NewTooManyRequestsError("too many attempts", nil)),
+		check: IsTooManyRequests,
+	},
+	{
+		name:  "unavailable error should be detected when wrapped",
+		err:   fmt.Errorf("failed to publish: %w", NewUnavailableError("broker unavailable", cause)),
+		check: IsUnavailable,
+	},
+}
+
+func TestErrorTypes(t *testing.T) {
+	for _, tc := range errorTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if !tc.check(tc.err) {
+				t.Errorf("expected %v to be detected as its domain error type", tc.err)
+			}
+		})
+	}
+
+	t.Run("plain errors should not be detected as domain errors", func(t *testing.T) {
+		if IsNotFound(cause) || IsConflict(cause) || IsValidation(cause) || IsUnauthorized(cause) || IsForbidden(cause) || IsTooManyRequests(cause) || IsUnavailable(cause) {
+			t.Errorf("expected %v not to be detected as a domain error", cause)
+		}
+	})
+
+	t.Run("causes should be unwrapped", func(t *testing.T) {
+		err := NewNotFoundError("user does not exist", cause)
+		if !errors.Is(err, cause) {
+			t.Errorf("expected %v to wrap %v", err, cause)
+		}
+	})
+}
+
+func TestMessage(t *testing.T) {
+	t.Run("should return the message of a wrapped domain error without its cause", func(t *testing.T) {
+		err := fmt.Errorf("failed to retrieve user: %w", NewNotFoundError("user does not exist", cause))
+		msg, ok := Message(err)
+		if !ok || msg != "user does not exist" {
+			t.Errorf("Message(%v) = (%s, %v), expected (user does not exist, true)", err, msg, ok)
+		}
+	})
+
+	t.Run("should return false for errors that are not domain errors", func(t *testing.T) {
+		if _, ok := Message(cause); ok {
+			t.Errorf("Message(%v) should not find a domain error", cause)
+		}
+	})
+}
@@ -0,0 +1,2 @@
+// Package health contains the checks used to report whether the dependencies of an application are healthy
+package health
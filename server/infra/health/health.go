@@ -0,0 +1,134 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Status is the status of a check or of a report
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+// DefaultTimeout is how long a check without a timeout is given to complete
+const DefaultTimeout = 2 * time.Second
+
+// HealthChecker is implemented by dependencies that can check whether they are healthy
+type HealthChecker interface {
+	// HealthCheck returns an error if the dependency is not healthy
+	HealthCheck(ctx context.Context) error
+}
+
+// CheckerFunc is a function that is used as a HealthChecker
+type CheckerFunc func(ctx context.Context) error
+
+// HealthCheck calls the function
+func (f CheckerFunc) HealthCheck(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Check is a named health check of a dependency
+type Check struct {
+	// Name is the name the result of the check is reported under
+	Name string
+
+	// Checker checks the health of the dependency
+	Checker HealthChecker
+
+	// Timeout is how long the check is given to complete, DefaultTimeout is used if it is not set
+	Timeout time.Duration
+}
+
+// CheckResult is the result of a check
+type CheckResult struct {
+	Status Status `json:"status"`
+
+	// DurationMs is how long the check took in milliseconds
+	DurationMs int64 `json:"durationMs"`
+
+	// Error is why the check failed, it is only set if the check is down
+	Error string `json:"error,omitempty"`
+}
+
+// Report is the result of running checks, which is up only if every check is up
+type Report struct {
+	Status Status                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// Run runs checks concurrently & reports their results. A check that does not complete within its timeout is reported as
+// down, even if its checker does not stop when its context is done
+func Run(ctx context.Context, checks ...Check) Report {
+	report := Report{
+		Status: StatusUp,
+		Checks: make(map[string]CheckResult, len(checks)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for _, check := range checks {
+		wg.Add(1)
+		go func(check Check) {
+			defer wg.Done()
+
+			result := run(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[check.Name] = result
+			if result.Status != StatusUp {
+				report.Status = StatusDown
+			}
+		}(check)
+	}
+
+	wg.Wait()
+
+	return report
+}
+
+// run runs a check within its timeout
+func run(ctx context.Context, check Check) CheckResult {
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+
+	// the channel is buffered so that a checker that outlives its timeout does not block forever
+	done := make(chan error, 1)
+	go func() {
+		done <- check.Checker.HealthCheck(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("check did not complete within %s: %w", timeout, ctx.Err())
+	}
+
+	result := CheckResult{
+		Status:     StatusUp,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	return result
+}
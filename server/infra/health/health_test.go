@@ -0,0 +1,64 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+
+	up := CheckerFunc(func(ctx context.Context) error {
+		return nil
+	})
+
+	t.Run("reports up when every check is up", func(t *testing.T) {
+		report := Run(ctx, Check{Name: "mongodb", Checker: up}, Check{Name: "rabbitmq", Checker: up})
+
+		assert.Equal(t, StatusUp, report.Status)
+		assert.Len(t, report.Checks, 2)
+		assert.Equal(t, StatusUp, report.Checks["mongodb"].Status)
+		assert.Empty(t, report.Checks["mongodb"].Error)
+	})
+
+	t.Run("reports down with the error of a failing check", func(t *testing.T) {
+		down := CheckerFunc(func(ctx context.Context) error {
+			return errors.New("connection is closed")
+		})
+
+		report := Run(ctx, Check{Name: "mongodb", Checker: up}, Check{Name: "rabbitmq", Checker: down})
+
+		assert.Equal(t, StatusDown, report.Status)
+		assert.Equal(t, StatusUp, report.Checks["mongodb"].Status)
+		assert.Equal(t, StatusDown, report.Checks["rabbitmq"].Status)
+		assert.Equal(t, "connection is closed", report.Checks["rabbitmq"].Error)
+	})
+
+	t.Run("reports a check that ignores its timeout as down once it times out", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		hanging := CheckerFunc(func(ctx context.Context) error {
+			<-release
+			return nil
+		})
+
+		start := time.Now()
+		report := Run(ctx, Check{Name: "storage", Checker: hanging, Timeout: 50 * time.Millisecond})
+
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, StatusDown, report.Status)
+		assert.Contains(t, report.Checks["storage"].Error, "did not complete within 50ms")
+	})
+
+	t.Run("reports up without any checks", func(t *testing.T) {
+		report := Run(ctx)
+
+		assert.Equal(t, StatusUp, report.Status)
+		assert.Empty(t, report.Checks)
+	})
+}
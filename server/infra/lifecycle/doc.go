@@ -0,0 +1,3 @@
+// Package lifecycle contains the manager that starts the components of an application in order & shuts them down
+// gracefully within a deadline
+package lifecycle
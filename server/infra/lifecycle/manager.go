@@ -0,0 +1,113 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+)
+
+// DefaultShutdownTimeout is the deadline components are given to stop when a manager has no shutdown timeout
+const DefaultShutdownTimeout = 30 * time.Second
+
+// Component is a part of an application that is started & stopped by a manager
+type Component struct {
+	// Name identifies the component in logs & errors
+	Name string
+
+	// Run runs the component until it is stopped. It is optional, components that are ready once they are created only
+	// have to be stopped. Run returning before the component is stopped shuts the application down
+	Run func() error
+
+	// Stop stops the component, giving up once the context is done. It is optional
+	Stop func(ctx context.Context) error
+}
+
+// Manager starts components in the order they are added & stops them in reverse order, so that a component is stopped
+// before the components it depends on
+type Manager struct {
+	components      []Component
+	shutdownTimeout time.Duration
+	logger          logger.Logger
+}
+
+// New creates a new manager that gives components the shutdown timeout to stop
+func New(shutdownTimeout time.Duration, log logger.Logger) *Manager {
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = DefaultShutdownTimeout
+	}
+
+	return &Manager{
+		shutdownTimeout: shutdownTimeout,
+		logger:          log,
+	}
+}
+
+// Add adds components to be started after the components that have already been added
+func (m *Manager) Add(components ...Component) *Manager {
+	m.components = append(m.components, components...)
+	return m
+}
+
+// componentExit is the result of running a component
+type componentExit struct {
+	name string
+	err  error
+}
+
+// Run starts the components & blocks until the context is done or a component stops running on its own, then stops the
+// components within the shutdown timeout. The errors of the component that stopped running & of stopping the components
+// are returned
+func (m *Manager) Run(ctx context.Context) error {
+	exits := make(chan componentExit, len(m.components))
+
+	for _, component := range m.components {
+		if component.Run == nil {
+			continue
+		}
+
+		m.logger.Infof("Starting %s", component.Name)
+		go func(component Component) {
+			exits <- componentExit{name: component.Name, err: component.Run()}
+		}(component)
+	}
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		m.logger.Info("Shutting down")
+	case exit := <-exits:
+		runErr = fmt.Errorf("%s stopped running: %w", exit.name, exit.err)
+		if exit.err == nil {
+			runErr = fmt.Errorf("%s stopped running", exit.name)
+		}
+		m.logger.Errorf("Shutting down after %v", runErr)
+	}
+
+	return errors.Join(runErr, m.shutdown())
+}
+
+// shutdown stops the components in reverse order within the shutdown timeout, carrying on with the rest of the components
+// if one fails to stop
+func (m *Manager) shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), m.shutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(m.components) - 1; i >= 0; i-- {
+		component := m.components[i]
+		if component.Stop == nil {
+			continue
+		}
+
+		m.logger.Infof("Stopping %s", component.Name)
+		if err := component.Stop(ctx); err != nil {
+			m.logger.Errorf("Failed to stop %s: %v", component.Name, err)
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", component.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,140 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder records the order components are stopped in
+type recorder struct {
+	mu      sync.Mutex
+	stopped []string
+}
+
+func (r *recorder) stop(name string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.stopped = append(r.stopped, name)
+		return nil
+	}
+}
+
+// blockingComponent runs until it is stopped
+func blockingComponent(name string, r *recorder) Component {
+	stop := make(chan struct{})
+	return Component{
+		Name: name,
+		Run: func() error {
+			<-stop
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			close(stop)
+			return r.stop(name)(ctx)
+		},
+	}
+}
+
+func TestManager(t *testing.T) {
+	log, _ := logger.NewTestLogger()
+
+	t.Run("stops the components in reverse order once the context is done", func(t *testing.T) {
+		r := &recorder{}
+		ctx, cancel := context.WithCancel(context.Background())
+
+		manager := New(time.Second, log).Add(
+			Component{Name: "mongodb", Stop: r.stop("mongodb")},
+			Component{Name: "rabbitmq", Stop: r.stop("rabbitmq")},
+			blockingComponent("consumer", r),
+			blockingComponent("http", r),
+		)
+
+		done := make(chan error, 1)
+		go func() {
+			done <- manager.Run(ctx)
+		}()
+
+		cancel()
+
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("manager did not shut down")
+		}
+		assert.Equal(t, []string{"http", "consumer", "rabbitmq", "mongodb"}, r.stopped)
+	})
+
+	t.Run("shuts down when a component stops running on its own", func(t *testing.T) {
+		r := &recorder{}
+		brokerErr := errors.New("connection to rabbit is closed")
+
+		manager := New(time.Second, log).Add(
+			Component{Name: "mongodb", Stop: r.stop("mongodb")},
+			Component{
+				Name: "consumer",
+				Run: func() error {
+					return brokerErr
+				},
+				Stop: r.stop("consumer"),
+			},
+		)
+
+		err := manager.Run(context.Background())
+		assert.ErrorIs(t, err, brokerErr)
+		assert.Equal(t, []string{"consumer", "mongodb"}, r.stopped)
+	})
+
+	t.Run("stops the rest of the components when one fails to stop", func(t *testing.T) {
+		r := &recorder{}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		stopErr := errors.New("disconnect failed")
+		manager := New(time.Second, log).Add(
+			Component{Name: "mongodb", Stop: r.stop("mongodb")},
+			Component{
+				Name: "rabbitmq",
+				Stop: func(ctx context.Context) error {
+					return stopErr
+				},
+			},
+		)
+
+		err := manager.Run(ctx)
+		assert.ErrorIs(t, err, stopErr)
+		assert.Equal(t, []string{"mongodb"}, r.stopped)
+	})
+
+	t.Run("gives every component the same shutdown deadline", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var deadlines []time.Time
+		stop := func(ctx context.Context) error {
+			deadline, ok := ctx.Deadline()
+			assert.True(t, ok)
+			deadlines = append(deadlines, deadline)
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		start := time.Now()
+		err := New(50*time.Millisecond, log).Add(
+			Component{Name: "mongodb", Stop: stop},
+			Component{Name: "http", Stop: stop},
+		).Run(ctx)
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Len(t, deadlines, 2)
+		assert.Equal(t, deadlines[0], deadlines[1])
+	})
+}
@@ -39,6 +39,11 @@ func (l *logger) WithoutCaller() Logger {
 	return &logger{l.SugaredLogger.WithOptions(zap.WithCaller(false))}
 }
 
+// WithLogger returns a copy of the context that carries the logger, which is retrieved with FromContext.
+func WithLogger(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, LoggerKey, l)
+}
+
 // FromContext returns a logger from context. If none found, instantiate a new logger.
 func FromContext(ctx context.Context) Logger {
 	if l, ok := ctx.Value(LoggerKey).(Logger); ok {
@@ -46,3 +51,11 @@ func FromContext(ctx context.Context) Logger {
 	}
 	return New()
 }
+
+// FromContextOr returns a logger from context. If none found, the fallback logger is returned.
+func FromContextOr(ctx context.Context, fallback Logger) Logger {
+	if l, ok := ctx.Value(LoggerKey).(Logger); ok {
+		return l
+	}
+	return fallback
+}
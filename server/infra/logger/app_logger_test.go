@@ -51,3 +51,23 @@ func Test_logger_FromContext(t *testing.T) {
 		assert.NotNil(t, loggerFromCtx)
 	})
 }
+
+func Test_logger_WithLogger(t *testing.T) {
+	l, _ := NewTestLogger()
+	ctx := WithLogger(context.Background(), l)
+	assert.Equal(t, l, FromContext(ctx))
+}
+
+func Test_logger_FromContextOr(t *testing.T) {
+	t.Run("context with logger", func(t *testing.T) {
+		l, _ := NewTestLogger()
+		fallback, _ := NewTestLogger()
+		ctx := WithLogger(context.Background(), l)
+		assert.Equal(t, l, FromContextOr(ctx, fallback))
+	})
+
+	t.Run("context without logger", func(t *testing.T) {
+		fallback, _ := NewTestLogger()
+		assert.Equal(t, fallback, FromContextOr(context.Background(), fallback))
+	})
+}
@@ -1,62 +1,296 @@
 package amqp
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/BrianLusina/skillq/server/infra/logger"
 	rabbitmq "github.com/rabbitmq/amqp091-go"
 )
 
-// AmqpClient defines an interface that Advanced Message Queuing Protocol clients implement
+// connection is a connection to an AMQP broker
+type connection interface {
+	Channel() (*rabbitmq.Channel, error)
+	NotifyClose(receiver chan *rabbitmq.Error) chan *rabbitmq.Error
+	IsClosed() bool
+	Close() error
+}
+
+// dialer opens a connection to the broker at the URI
+type dialer func(uri string, config rabbitmq.Config) (connection, error)
+
+// dial opens a connection to a RabbitMQ broker
+func dial(uri string, config rabbitmq.Config) (connection, error) {
+	return rabbitmq.DialConfig(uri, config)
+}
+
+// declarer declares a topology on a connection
+type declarer func(conn connection, topology Topology, log logger.Logger) error
+
+// AmqpClient manages the connection to an AMQP broker. The connection is watched once it is opened & re-established with
+// a jittered backoff whenever it is lost, until the client is closed. The topology declared with the client is declared
+// again on every connection it re-establishes, so that queues the broker lost while the connection was down exist again
+// before callers waiting for the connection resume
 type AmqpClient struct {
-	AmqpConn *rabbitmq.Connection
-	logger   logger.Logger
+	uri            string
+	config         rabbitmq.Config
+	dial           dialer
+	declare        declarer
+	backoff        Backoff
+	channelTimeout time.Duration
+	logger         logger.Logger
+
+	// mu guards the connection, connected, which is closed while the connection is open, the topology & closed
+	mu        sync.RWMutex
+	conn      connection
+	connected chan struct{}
+	topology  *Topology
+	closed    bool
+	done      chan struct{}
 }
 
-// NewAmqpClient creates an AMQP Client that contains a connection to an AMQP broker
+// NewAmqpClient creates an AMQP Client that contains a connection to an AMQP broker. Connecting is retried a few times
+// before an error is returned, in case the broker is still starting
 func NewAmqpClient(config Config, log logger.Logger) (*AmqpClient, error) {
-	connString := fmt.Sprintf("amqp://%v:%v@%v:%v/", config.Username, config.Password, config.Host, config.Port)
-	var (
-		amqpConn *rabbitmq.Connection
-		counts   int64
-	)
+	uri, err := config.URI()
+	if err != nil {
+		return nil, err
+	}
 
-	for {
-		conn, err := rabbitmq.Dial(connString)
-		if err != nil {
-			log.Errorf("RabbitMq at %s:%s not ready...\n", config.Host, config.Port)
-			counts++
-		} else {
-			amqpConn = conn
-			break
+	tlsConfig, err := config.TLS.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	client := newClient(config, uri, rabbitmq.Config{
+		Heartbeat:       10 * time.Second,
+		Locale:          "en_US",
+		TLSClientConfig: tlsConfig,
+		Vhost:           config.VHost,
+	}, dial, log)
+
+	if err := client.connectWithRetries(_retryTimes); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// newClient creates a client that is not connected yet, which opens connections with dial
+func newClient(config Config, uri string, amqpConfig rabbitmq.Config, dial dialer, log logger.Logger) *AmqpClient {
+	backoff := Backoff{InitialDelay: config.ReconnectInitialDelay, MaxDelay: config.ReconnectMaxDelay}
+	if backoff.InitialDelay <= 0 {
+		backoff.InitialDelay = _reconnectInitialDelay
+	}
+	if backoff.MaxDelay <= 0 {
+		backoff.MaxDelay = _reconnectMaxDelay
+	}
+
+	channelTimeout := config.ChannelTimeout
+	if channelTimeout <= 0 {
+		channelTimeout = _channelTimeout
+	}
+
+	return &AmqpClient{
+		uri:            uri,
+		config:         amqpConfig,
+		dial:           dial,
+		declare:        declareOnConnection,
+		backoff:        backoff,
+		channelTimeout: channelTimeout,
+		logger:         log,
+		connected:      make(chan struct{}),
+		done:           make(chan struct{}),
+	}
+}
+
+// connectWithRetries connects to the broker, retrying with the backoff until it has failed more than retries times
+func (c *AmqpClient) connectWithRetries(retries int) error {
+	for attempt := 0; ; attempt++ {
+		err := c.connect()
+		if err == nil {
+			return nil
+		}
+
+		c.logger.Errorf("RabbitMQ not ready: %v", err)
+		if attempt >= retries {
+			return fmt.Errorf("%w: %w", ErrCannotConnectRabbitMQ, err)
+		}
+
+		delay := c.backoff.Delay(attempt)
+		c.logger.Infof("Backing off for %s...", delay)
+		time.Sleep(delay)
+	}
+}
+
+// connect opens a connection to the broker, declares the topology of the client on it & watches it, so that it is
+// re-established if it is lost. The connection is only shared once the topology is declared
+func (c *AmqpClient) connect() error {
+	conn, err := c.dial(c.uri, c.config)
+	if err != nil {
+		return err
+	}
+
+	// closes are registered before the connection is shared, so that a connection lost straight away is not missed
+	closes := conn.NotifyClose(make(chan *rabbitmq.Error, 1))
+
+	c.mu.RLock()
+	topology := c.topology
+	c.mu.RUnlock()
+
+	if topology != nil {
+		if err := c.declare(conn, *topology, c.logger); err != nil {
+			if closeErr := conn.Close(); closeErr != nil {
+				c.logger.Errorf("Failed to close connection the topology failed to be declared on: %v", closeErr)
+			}
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return conn.Close()
+	}
+	c.conn = conn
+	close(c.connected)
+	c.mu.Unlock()
+
+	c.logger.Info("Connected to RabbitMQ")
+
+	go c.watch(conn, closes)
+
+	return nil
+}
+
+// watch waits for the connection to close & reconnects if it was lost rather than closed by the client
+func (c *AmqpClient) watch(conn connection, closes <-chan *rabbitmq.Error) {
+	// the connection only closes without an error when it is closed by this client
+	err := <-closes
+	if err == nil {
+		return
+	}
+
+	c.logger.Errorf("Connection to RabbitMQ lost: %v, reconnecting", err)
+	c.disconnected(conn)
+	c.reconnect()
+}
+
+// disconnected marks the connection as lost, so that callers wait for it to be re-established. It is a no-op if the
+// connection has already been replaced
+func (c *AmqpClient) disconnected(conn connection) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn != conn {
+		return
+	}
+
+	select {
+	case <-c.connected:
+		c.connected = make(chan struct{})
+	default:
+	}
+}
+
+// reconnect reconnects to the broker with the backoff until it succeeds or the client is closed
+func (c *AmqpClient) reconnect() {
+	for attempt := 0; ; attempt++ {
+		select {
+		case <-c.done:
+			return
+		case <-time.After(c.backoff.Delay(attempt)):
 		}
 
-		if counts > _retryTimes {
-			log.Fatalf("failed to retry: %v", err)
+		if err := c.connect(); err != nil {
+			c.logger.Errorf("Failed to reconnect to RabbitMQ on attempt %d: %v", attempt+1, err)
+			continue
+		}
 
-			return nil, ErrCannotConnectRabbitMQ
+		c.logger.Infof("Reconnected to RabbitMQ after %d attempts", attempt+1)
+		return
+	}
+}
+
+// Connected returns a channel that is closed while the client is connected to the broker. The channel returned while the
+// connection is down is closed once it is re-established
+func (c *AmqpClient) Connected() <-chan struct{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.connected
+}
+
+// Channel opens a channel on the connection to the broker. While the connection is down, it waits for it to be
+// re-established until the context is done or the channel timeout of the client passes, whichever is first
+func (c *AmqpClient) Channel(ctx context.Context) (*rabbitmq.Channel, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.channelTimeout)
+	defer cancel()
+
+	for {
+		c.mu.RLock()
+		conn, connected := c.conn, c.connected
+		c.mu.RUnlock()
+
+		select {
+		case <-connected:
+		case <-c.done:
+			return nil, ErrConnectionClosed
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %w", ErrNotConnected, ctx.Err())
+		}
+
+		ch, err := conn.Channel()
+		if errors.Is(err, rabbitmq.ErrClosed) {
+			// the connection was lost before its close was noticed, so the channel is opened once it is re-established
+			c.disconnected(conn)
+			continue
 		}
 
-		log.Info("Backing off for 2 seconds...")
-		time.Sleep(_backOffSeconds * time.Second)
+		return ch, err
+	}
+}
+
+// HealthCheck checks that the connection to the broker is open & that the broker responds by opening & closing a channel
+func (c *AmqpClient) HealthCheck(ctx context.Context) error {
+	c.mu.RLock()
+	conn, closed := c.conn, c.closed
+	c.mu.RUnlock()
 
-		continue
+	if closed || conn == nil || conn.IsClosed() {
+		return ErrConnectionClosed
 	}
 
-	log.Infof("Connected to RabbitMQ running on host: %s:%s", config.Host, config.Port)
+	channel, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
 
-	return &AmqpClient{
-		AmqpConn: amqpConn,
-		logger:   log,
-	}, nil
+	return channel.Close()
 }
 
-// Close closes connection to a broker
-func (p *AmqpClient) Close() error {
-	err := p.AmqpConn.Close()
+// Close closes connection to a broker & stops reconnecting to it. Closing a connection that is already closed is a no-op
+func (c *AmqpClient) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	close(c.done)
+	conn := c.conn
+	c.mu.Unlock()
+
+	if conn == nil || conn.IsClosed() {
+		return nil
+	}
+
+	err := conn.Close()
 	if err != nil {
-		p.logger.Errorf("Publisher CloseChan: %v", err)
+		c.logger.Errorf("Failed to close connection: %v", err)
 		return err
 	}
 	return nil
@@ -0,0 +1,7 @@
+package amqp
+
+import "github.com/BrianLusina/skillq/server/infra/messaging"
+
+// Backoff is an exponential backoff with jitter for retrying an operation, such as reconnecting to the broker, which is
+// shared by the clients of every broker
+type Backoff = messaging.Backoff
@@ -1,11 +1,148 @@
 package amqp
 
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	rabbitmq "github.com/rabbitmq/amqp091-go"
+)
+
 // Config is the AMQP configuration to establish a connection to an AMQP broker
 type Config struct {
 	Username string
 	Password string
 	Host     string
 	Port     string
+
+	// URL is the AMQP URI of the broker, which takes precedence over the username, password, host & port when it is set
+	URL string
+
+	// VHost is the virtual host to connect to, which overrides the virtual host of the URL when it is set
+	VHost string
+
+	// TLS configures connecting to the broker over TLS
+	TLS TLSConfig
+
+	// ReconnectInitialDelay is the delay before the first attempt to reconnect once the connection is lost, which doubles
+	// with every failed attempt up to ReconnectMaxDelay
+	ReconnectInitialDelay time.Duration
+	ReconnectMaxDelay     time.Duration
+
+	// ChannelTimeout is how long opening a channel waits for the connection to be re-established while it is down, so
+	// that publishing blocks for at most this long before it fails
+	ChannelTimeout time.Duration
+}
+
+// TLSConfig is the configuration to connect to an AMQP broker over TLS
+type TLSConfig struct {
+	Enabled bool
+
+	// CAFile is the path of the PEM certificate of the authority that signed the certificate of the broker. The system
+	// certificate pool is used when it is not set
+	CAFile string
+
+	// CertFile & KeyFile are the paths of the PEM certificate & key the client authenticates with, if the broker requires
+	// client certificates
+	CertFile string
+	KeyFile  string
+
+	// ServerName is the name the certificate of the broker is verified against, which defaults to the host
+	ServerName string
+
+	InsecureSkipVerify bool
+}
+
+// URI returns the AMQP URI of the broker to connect to, which uses amqps when TLS is enabled
+func (c Config) URI() (string, error) {
+	if c.URL != "" {
+		uri, err := rabbitmq.ParseURI(c.URL)
+		if err != nil {
+			return "", fmt.Errorf("invalid RabbitMQ URL: %w", err)
+		}
+
+		// the URL is upgraded to amqps when TLS is enabled, so that TLS is not silently skipped
+		if c.TLS.Enabled && uri.Scheme == "amqp" {
+			uri.Scheme = "amqps"
+			if uri.Port == _defaultPort && !hasPort(c.URL) {
+				uri.Port = _defaultTLSPort
+			}
+		}
+
+		if c.VHost != "" {
+			uri.Vhost = c.VHost
+		}
+
+		return uri.String(), nil
+	}
+
+	port, err := strconv.Atoi(c.Port)
+	if err != nil {
+		return "", fmt.Errorf("invalid RabbitMQ port %q: %w", c.Port, err)
+	}
+
+	uri := rabbitmq.URI{
+		Scheme:   "amqp",
+		Host:     c.Host,
+		Port:     port,
+		Username: c.Username,
+		Password: c.Password,
+		Vhost:    "/",
+	}
+
+	if c.TLS.Enabled {
+		uri.Scheme = "amqps"
+	}
+
+	if c.VHost != "" {
+		uri.Vhost = c.VHost
+	}
+
+	return uri.String(), nil
+}
+
+// hasPort checks if a URL sets its port, rather than using the default port of its scheme
+func hasPort(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && u.Port() != ""
+}
+
+// Config returns the TLS configuration of the connection, which is nil when TLS is not enabled
+func (c TLSConfig) Config() (*tls.Config, error) {
+	if !c.Enabled {
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	if c.CAFile != "" {
+		ca, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file %s: %w", c.CAFile, err)
+		}
+
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", c.CAFile)
+		}
+	}
+
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
 }
 
 // ExchangeOptionParams are the parameters for configuring an exchange
@@ -2,8 +2,8 @@ package amqpconsumer
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"sync"
+	"time"
 
 	"github.com/BrianLusina/skillq/server/infra/logger"
 	"github.com/BrianLusina/skillq/server/infra/messaging"
@@ -12,238 +12,354 @@ import (
 	rabbitmq "github.com/rabbitmq/amqp091-go"
 )
 
-// amqpConsumerClient defines a consumer that handles consumption of messages from an AMQP Broker
+// amqpConsumerClient defines a consumer that handles consumption of messages from the queues it subscribes to on an AMQP
+// Broker
 type amqpConsumerClient struct {
-	exchangeName       string
-	exchangeKind       string
-	exchangeDurable    bool
-	exchangeAutoDelete bool
-	exchangeInternal   bool
-	exchangeNoWait     bool
-	exchangeArgs       map[string]any
-	queueName          string
-	queueDurable       bool
-	queueAutoDelete    bool
-	queueExclusive     bool
-	queueNoWait        bool
-	queueArgs          map[string]any
-	bindingKey         string
-	consumerTag        string
-	consumeAutoAck     bool
-	consumeExclusive   bool
-	consumeNoLocal     bool
-	consumeNoWait      bool
-	consumerArgs       map[string]any
-	qosPrefetchCount   int
-	qosPrefetchSize    int
-	qosPrefetchGlobal  bool
-	workerPoolSize     int
+	exchanges          []amqp.ExchangeOptionParams
+	subscriptions      []*subscription
+	defaultRetryPolicy RetryPolicy
+	retryPolicies      map[string]RetryPolicy
+	requeueBackoff     amqp.Backoff
+	resubscribeBackoff amqp.Backoff
 	client             *amqp.AmqpClient
 	logger             logger.Logger
-	handlers           map[string]func(payload []byte) error
+	metrics            *Metrics
+	handlers           map[string]messaging.Handler
+
+	// stopped is closed once the consumer is stopped, so that it does not resume consuming
+	stopped  chan struct{}
+	stopOnce sync.Once
 }
 
-// NewConsumer creates a new AMQP consumer
-func NewConsumer(client *amqp.AmqpClient, log logger.Logger) (AmqpEventConsumer, error) {
-	handlers := make(map[string]func(payload []byte) error)
+var (
+	// ErrNotSubscribed is returned when consuming from a queue the consumer does not subscribe to
+	ErrNotSubscribed = errors.New("consumer does not subscribe to queue")
+
+	// ErrNoSubscriptions is returned when starting a consumer that subscribes to no queues
+	ErrNoSubscriptions = errors.New("consumer subscribes to no queues")
+
+	// errChannelLost is returned when the channel deliveries are consumed on is closed by the broker or with the connection
+	errChannelLost = errors.New("channel lost")
+)
+
+// NewConsumer creates a new AMQP consumer that records the metrics of the deliveries it handles. The consumer consumes
+// from the queues it is configured to subscribe to
+func NewConsumer(client *amqp.AmqpClient, log logger.Logger, metrics *Metrics) (AmqpEventConsumer, error) {
 	sub := &amqpConsumerClient{
-		client:             client,
-		logger:             log,
-		exchangeName:       _exchangeName,
-		exchangeKind:       _exchangeKind,
-		exchangeDurable:    _exchangeDurable,
-		exchangeAutoDelete: _exchangeAutoDelete,
-		exchangeInternal:   _exchangeInternal,
-		exchangeNoWait:     _exchangeNoWait,
-		exchangeArgs:       map[string]any{},
-		queueName:          _queueName,
-		queueDurable:       _queueDurable,
-		queueAutoDelete:    _queueAutoDelete,
-		queueExclusive:     _queueExclusive,
-		queueNoWait:        _queueNoWait,
-		queueArgs:          map[string]any{},
-		bindingKey:         _bindingKey,
-		consumerTag:        _consumerTag,
-		consumeAutoAck:     _consumeAutoAck,
-		consumeExclusive:   _consumeExclusive,
-		consumeNoLocal:     _consumeNoLocal,
-		consumeNoWait:      _consumeNoWait,
-		consumerArgs:       map[string]any{},
-		qosPrefetchCount:   _prefetchCount,
-		qosPrefetchSize:    _prefetchSize,
-		qosPrefetchGlobal:  _prefetchGlobal,
-		workerPoolSize:     _workerPoolSize,
-		handlers:           handlers,
+		client:  client,
+		logger:  log,
+		metrics: metrics,
+		defaultRetryPolicy: RetryPolicy{
+			MaxRetries:   _retryMaxRetries,
+			InitialDelay: _retryInitialDelay,
+			MaxDelay:     _retryMaxDelay,
+			Multiplier:   _retryMultiplier,
+		},
+		retryPolicies: map[string]RetryPolicy{},
+		handlers:      map[string]messaging.Handler{},
+		stopped:       make(chan struct{}),
+		requeueBackoff: amqp.Backoff{
+			InitialDelay: _requeueInitialDelay,
+			MaxDelay:     _requeueMaxDelay,
+		},
+		resubscribeBackoff: amqp.Backoff{
+			InitialDelay: _resubscribeInitialDelay,
+			MaxDelay:     _resubscribeMaxDelay,
+		},
 	}
 
 	return sub, nil
 }
 
-// Consumes a message from a given queue. This is mostly a blocking operation
+// Declare declares the exchanges the consumer is configured with & the queues it subscribes to on the broker, binding the
+// queues to the exchanges. The topology is declared at startup, before messages are published or consumed, & again by the
+// client whenever the connection is re-established
+func (c *amqpConsumerClient) Declare(ctx context.Context) error {
+	return c.client.DeclareTopology(ctx, c.topology())
+}
+
+// topology is the topology of the consumer, which has the parking, retry & dead letter queues of every queue it
+// subscribes to besides the queue. The queues are declared with the arguments they are configured with only, as the broker
+// fails to redeclare a queue with arguments it was not declared with, so deliveries are moved to the parking, retry & dead
+// letter queues by the consumer rather than dead-lettered to them by the broker
+func (c *amqpConsumerClient) topology() amqp.Topology {
+	topology := amqp.Topology{Exchanges: c.exchanges}
+
+	for _, sub := range c.subscriptions {
+		topology.Queues = append(topology.Queues, sub.queue)
+
+		// deliveries of a type no handler is added for are parked in a queue of their own, so they are kept until a
+		// handler is deployed for them
+		topology.Queues = append(topology.Queues, amqp.QueueOptionParams{Name: sub.parkingQueue, Durable: true})
+
+		// deliveries that fail to be handled wait out the delay before they are retried in a retry queue, which is new &
+		// dead-letters them back to the queue, & are moved to the dead letter queue once their retries run out
+		for _, delay := range c.retryDelays() {
+			topology.Queues = append(topology.Queues, amqp.QueueOptionParams{
+				Name:    sub.retryQueue(delay),
+				Durable: true,
+				Args:    sub.retryQueueArgs(delay),
+			})
+		}
+		topology.Queues = append(topology.Queues, amqp.QueueOptionParams{Name: sub.deadLetterQueue(), Durable: true})
+
+		topology.Bindings = append(topology.Bindings, sub.bindings...)
+	}
+
+	return topology
+}
+
+// Consume consumes deliveries from a queue the consumer subscribes to, handling them with the handlers added for their
+// type until the consumer is stopped or the context is done. This is a blocking operation
 func (c *amqpConsumerClient) Consume(ctx context.Context, queue string) error {
-	ch, err := c.createChannel()
-	if err != nil {
-		return fmt.Errorf("failed to create channel: %w", err)
+	for _, sub := range c.subscriptions {
+		if sub.queue.Name == queue {
+			return c.consume(ctx, sub)
+		}
 	}
 
-	msgs, err := ch.Consume(
-		queue,              // queue
-		c.consumerTag,      // consumer
-		c.consumeAutoAck,   // auto-ack
-		c.consumeExclusive, // exclusive
-		c.consumeNoLocal,   // no-local
-		c.consumeNoWait,    // no-wait
-		c.consumerArgs,     // args
-	)
-	if err != nil {
-		return fmt.Errorf("failed to register as consumer: %w", err)
+	return errors.Wrapf(ErrNotSubscribed, "%s", queue)
+}
+
+// Start consumes from every queue the consumer subscribes to until the consumer is stopped. It returns once every queue
+// has stopped being consumed from. When consuming from a queue fails, the consumer is stopped & it returns the error once
+// the other queues have stopped being consumed from too. This is a blocking operation
+func (c *amqpConsumerClient) Start() error {
+	if len(c.subscriptions) == 0 {
+		return ErrNoSubscriptions
 	}
 
-	forever := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	go func() {
-		for delivery := range msgs {
-			var msg messaging.ConsumeMessage
-			err := json.Unmarshal(delivery.Body, &msg)
-			if err != nil {
-				c.logger.Errorf("failed to unmarshal message: %v", err)
-				continue
-			}
-			if handler, ok := c.handlers[msg.Topic]; ok {
-				err = handler(msg.Payload)
-				if err != nil {
-					c.logger.Errorf("failed to handle message: %v", err)
-					delivery.Nack(false, false)
-					continue
-				}
-				err = delivery.Ack(false)
-				if err != nil {
-					c.logger.Errorf("failed to acknowledge deliver: %v", err)
-				}
-			} else {
-				c.logger.Warn("task does not exist: %v", msg.Topic)
-				delivery.Nack(false, false)
-			}
+	errs := make(chan error, len(c.subscriptions))
+	for _, sub := range c.subscriptions {
+		go func() {
+			errs <- c.consume(ctx, sub)
+		}()
+	}
+
+	var firstErr error
+	for range c.subscriptions {
+		err := <-errs
+		if err == nil || firstErr != nil {
+			continue
 		}
-	}()
 
-	<-forever
+		firstErr = err
+		c.logger.Errorf("Stopping consumer as consuming from a queue failed: %v", err)
 
-	return nil
+		// the queues that are still consumed from are stopped, so that none is left consuming once Start returns
+		cancel()
+		if err := c.Stop(context.Background()); err != nil {
+			c.logger.Errorf("Failed to stop consumer: %v", err)
+		}
+	}
+
+	return firstErr
 }
 
-// StartConsumer starts a new consumer worker. Used for async workflows
-func (c *amqpConsumerClient) StartConsumer(fn func(ctx context.Context, message <-chan rabbitmq.Delivery)) error {
-	ctx, cancel := context.WithCancel(context.Background())
+// consume consumes deliveries from the queue of a subscription with its pool of workers until the consumer is stopped or
+// the context is done. When the channel or the connection is lost, consuming resumes on a new channel after a jittered
+// backoff, once the connection is re-established
+func (c *amqpConsumerClient) consume(ctx context.Context, sub *subscription) error {
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		err := c.consumeChannel(ctx, sub)
+		if !errors.Is(err, errChannelLost) && !errors.Is(err, amqp.ErrNotConnected) {
+			return err
+		}
+
+		// a channel the broker keeps closing while the connection is up would otherwise be resubscribed to in a hot loop,
+		// while a channel that was consumed from for a while is resubscribed to without waiting long
+		if time.Since(start) > c.resubscribeBackoff.MaxDelay {
+			attempt = 0
+		}
+		delay := c.resubscribeBackoff.Delay(attempt)
+
+		c.logger.Errorf("Consumer %s is not consuming from queue %s: %v, resuming in %s once connected", sub.consumer.Tag, sub.queue.Name, err, delay)
+
+		c.wait(ctx, delay)
+
+		select {
+		case <-c.client.Connected():
+		case <-c.stopped:
+			return nil
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// consumeChannel consumes deliveries from the queue of a subscription on a new channel until the consumer is stopped, the
+// channel is lost or the context is done
+func (c *amqpConsumerClient) consumeChannel(ctx context.Context, sub *subscription) error {
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	ch, err := c.createChannel()
+	select {
+	case <-c.stopped:
+		return nil
+	default:
+	}
+
+	ch, err := c.createChannel(ctx, sub)
 	if err != nil {
 		c.logger.Errorf("Failed to create channel with error: %s", err.Error())
 		return errors.Wrapf(err, "failed to create channel")
 	}
-	c.logger.Info("Successfully created channel")
+	c.logger.Infof("Successfully created channel for queue %s", sub.queue.Name)
 
 	defer func() {
-		c.logger.Info("Closing channel connection")
+		c.logger.Infof("Closing channel of queue %s", sub.queue.Name)
 		err := ch.Close()
-		if err != nil {
+		if err != nil && !errors.Is(err, rabbitmq.ErrClosed) {
 			c.logger.Errorf("Failed to close channel connection %s", err.Error())
 		}
 	}()
 
+	closed := ch.NotifyClose(make(chan *rabbitmq.Error, 1))
+
 	deliveries, err := ch.Consume(
-		c.queueName,
-		c.consumerTag,
-		c.consumeAutoAck,
-		c.consumeExclusive,
-		c.consumeNoLocal,
-		c.consumeNoWait,
-		c.consumerArgs,
+		sub.queue.Name,
+		sub.consumer.Tag,
+		sub.consumer.AutoAck,
+		sub.consumer.Exclusive,
+		sub.consumer.NoLocal,
+		sub.consumer.NoWait,
+		sub.consumer.Args,
 	)
 	if err != nil {
 		c.logger.Errorf("Failed to consume messages with error: %s", err.Error())
 		return errors.Wrapf(err, "failed to consume messages")
 	}
 
-	c.logger.Infof("Retrieved deliveries of count %d from queue %s", len(deliveries), c.queueName)
+	c.logger.Infof("Consuming from queue %s with %d workers, consumerTag: %s", sub.queue.Name, sub.workers, sub.consumer.Tag)
 
-	forever := make(chan bool)
+	done := make(chan struct{})
 
-	for i := 0; i < c.workerPoolSize; i++ {
-		go fn(ctx, deliveries)
+	sub.mu.Lock()
+	sub.channel = ch
+	sub.publisher = &confirmPublisher{channel: ch, timeout: _republishConfirmTimeout}
+	sub.done = done
+	sub.mu.Unlock()
+
+	// workers return once the deliveries channel is closed, which happens when the consumer is stopped or the channel closes
+	var workers sync.WaitGroup
+	for i := 0; i < sub.workers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			c.dispatchDeliveries(ctx, sub, deliveries)
+		}()
 	}
 
-	chanErr := <-ch.NotifyClose(make(chan *rabbitmq.Error))
-	c.logger.Errorf("notify close: %v", chanErr)
-	<-forever
-	return chanErr
-}
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		// the error the channel is lost with is sent before its deliveries are closed, which tells a lost channel apart
+		// from the consumer being stopped
+		select {
+		case chanErr := <-closed:
+			if chanErr != nil {
+				return errors.Wrapf(errChannelLost, "%v", chanErr)
+			}
+		default:
+		}
 
-// AddHandler adds a handler that will handle consumption of messages from a queue
-func (c *amqpConsumerClient) AddHandler(ctx context.Context, task string, handler func(payload []byte) error) {
+		c.logger.Infof("Stopped consuming from queue %s", sub.queue.Name)
+		return nil
+	case <-ctx.Done():
+		return c.Stop(context.Background())
+	case chanErr := <-closed:
+		// the channel only closes without an error when it is closed by this client
+		if chanErr == nil {
+			return nil
+		}
 
+		// the workers finish the deliveries they are on, which can no longer be acknowledged & are redelivered
+		<-done
+		return errors.Wrapf(errChannelLost, "%v", chanErr)
+	}
 }
 
-// createChannel creates a rabbit MQ channel
-func (c *amqpConsumerClient) createChannel() (*rabbitmq.Channel, error) {
-	ch, err := c.client.AmqpConn.Channel()
-	if err != nil {
-		c.logger.Errorf("Failed to create AMQP channel with error: %s", err.Error())
-		return nil, errors.Wrapf(err, "failed to create AMQP channel")
+// Stop cancels the consumers of every queue so that the broker stops sending deliveries, then waits for the workers to
+// return after handling the delivery they are on. Deliveries that were sent but not handled are redelivered by the broker
+// once the channels close. A stopped consumer does not resume consuming once the connection is re-established
+func (c *amqpConsumerClient) Stop(ctx context.Context) error {
+	c.stopOnce.Do(func() {
+		close(c.stopped)
+	})
+
+	for _, sub := range c.subscriptions {
+		if err := c.stopSubscription(ctx, sub); err != nil {
+			return err
+		}
 	}
 
-	c.logger.Infof("Declaring exchange: %s", c.exchangeName)
-	err = ch.ExchangeDeclare(
-		c.exchangeName,
-		c.exchangeKind,
-		c.exchangeDurable,
-		c.exchangeAutoDelete,
-		c.exchangeInternal,
-		c.exchangeNoWait,
-		c.exchangeArgs,
-	)
+	return nil
+}
 
-	if err != nil {
-		c.logger.Errorf("Failed to declare exchange: %s with error: %s", c.exchangeName, err.Error())
-		return nil, errors.Wrapf(err, "failed to declare exchange: %s", c.exchangeName)
+// stopSubscription cancels the consumer of the queue of a subscription & waits for its workers to return
+func (c *amqpConsumerClient) stopSubscription(ctx context.Context, sub *subscription) error {
+	sub.mu.Lock()
+	ch, done := sub.channel, sub.done
+	sub.mu.Unlock()
+
+	if ch == nil {
+		return nil
 	}
 
-	c.logger.Infof("Declaring queue: %s", c.queueName)
-	queue, err := ch.QueueDeclare(c.queueName, c.queueDurable, c.queueAutoDelete, c.queueExclusive, c.queueNoWait, c.queueArgs)
-	if err != nil {
-		c.logger.Errorf("Failed to declare queue: %s with error %s", c.queueName, err.Error())
-		return nil, errors.Wrapf(err, "failed to declare queue: %s", c.queueName)
+	c.logger.Infof("Stopping consumer %s", sub.consumer.Tag)
+	if err := ch.Cancel(sub.consumer.Tag, false); err != nil && !errors.Is(err, rabbitmq.ErrClosed) {
+		return errors.Wrapf(err, "failed to cancel consumer %s", sub.consumer.Tag)
 	}
 
-	c.logger.Infof("Declaring queue %s, binding it to exchange %s, messagesCount: %v, Consumer Count: %v, bindingKey: %v",
-		queue.Name, c.exchangeName, queue.Messages, queue.Consumers, c.bindingKey,
-	)
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return errors.Wrapf(ctx.Err(), "workers of consumer %s did not finish", sub.consumer.Tag)
+	}
+}
 
-	err = ch.QueueBind(
-		queue.Name,
-		c.bindingKey,
-		c.exchangeName,
-		c.queueNoWait,
-		nil,
-	)
+// AddHandler adds a handler that will handle the deliveries of a topic from the queues that handle the topic. Handlers are
+// added before the consumer is started
+func (c *amqpConsumerClient) AddHandler(topic string, handler messaging.Handler) {
+	c.handlers[topic] = handler
+}
+
+// createChannel creates a rabbit MQ channel to consume from the queue of a subscription on, with the QoS of the
+// subscription. The channel is in confirm mode, so that deliveries moved to other queues on it are only acknowledged once
+// the broker has confirmed their copies. The topology is not declared on it, as the client declares it whenever it connects
+func (c *amqpConsumerClient) createChannel(ctx context.Context, sub *subscription) (*rabbitmq.Channel, error) {
+	ch, err := c.client.Channel(ctx)
 	if err != nil {
-		c.logger.Errorf("Failed to bind exchange %s to queue %s with error: %s", c.exchangeName, queue.Name, err.Error())
-		return nil, errors.Wrapf(err, "failed to bind queue: %s", queue.Name)
+		c.logger.Errorf("Failed to create AMQP channel with error: %s", err.Error())
+		return nil, errors.Wrapf(err, "failed to create AMQP channel")
 	}
 
-	c.logger.Infof("Queue bound to exchange %s, starting to consume from queue %s, consumerTag: %s", c.exchangeName, queue.Name, c.consumerTag)
-
 	err = ch.Qos(
-		c.qosPrefetchCount,
-		c.qosPrefetchSize,
-		c.qosPrefetchGlobal,
+		sub.qos.PrefetchCount,
+		sub.qos.PrefetchSize,
+		sub.qos.PrefetchGlobal,
 	)
 	if err != nil {
 		c.logger.Errorf("Failed to QOS channel with error: %s", err.Error())
+		_ = ch.Close()
 		return nil, errors.Wrapf(err, "failure to qos channel")
 	}
 
+	if err := ch.Confirm(false); err != nil {
+		c.logger.Errorf("Failed to put channel in confirm mode with error: %s", err.Error())
+		_ = ch.Close()
+		return nil, errors.Wrapf(err, "failed to put channel in confirm mode")
+	}
+
 	return ch, nil
 }
 
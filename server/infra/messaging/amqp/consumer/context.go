@@ -0,0 +1,25 @@
+package amqpconsumer
+
+import (
+	"context"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/messaging/amqp"
+	"github.com/BrianLusina/skillq/server/infra/requestid"
+	rabbitmq "github.com/rabbitmq/amqp091-go"
+)
+
+// DeliveryContext returns a copy of the context to handle a delivery with. It carries the request ID from the headers of
+// the delivery, or a new one if the publisher did not set it, & a logger that adds the request ID & message ID to every
+// line it logs, so the logs of handling the delivery can be joined with those of the request that published it. It also
+// carries the trace context from the headers, so the delivery is handled in the trace it was published in
+func DeliveryContext(ctx context.Context, delivery rabbitmq.Delivery, log logger.Logger) context.Context {
+	requestID, ok := delivery.Headers[amqp.HeaderRequestID].(string)
+	if !ok || !requestid.IsValid(requestID) {
+		requestID = requestid.New()
+	}
+
+	ctx = amqp.ContextWithHeaders(ctx, delivery.Headers)
+	ctx = requestid.WithRequestID(ctx, requestID)
+	return logger.WithLogger(ctx, log.With("requestID", requestID, "messageID", delivery.MessageId))
+}
@@ -0,0 +1,73 @@
+package amqpconsumer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/messaging/amqp"
+	"github.com/BrianLusina/skillq/server/infra/requestid"
+	rabbitmq "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestDeliveryContext(t *testing.T) {
+	t.Run("delivery with request ID", func(t *testing.T) {
+		log, observed := logger.NewTestLogger()
+		delivery := rabbitmq.Delivery{
+			MessageId: "message-1",
+			Headers:   rabbitmq.Table{amqp.HeaderRequestID: "request-1"},
+		}
+
+		ctx := DeliveryContext(context.Background(), delivery, log)
+
+		requestID, ok := requestid.FromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, "request-1", requestID)
+
+		logger.FromContext(ctx).Info("handling delivery")
+		fields := observed.All()[0].ContextMap()
+		assert.Equal(t, "request-1", fields["requestID"])
+		assert.Equal(t, "message-1", fields["messageID"])
+	})
+
+	t.Run("delivery without request ID", func(t *testing.T) {
+		log, _ := logger.NewTestLogger()
+
+		ctx := DeliveryContext(context.Background(), rabbitmq.Delivery{}, log)
+
+		requestID, ok := requestid.FromContext(ctx)
+		assert.True(t, ok)
+		assert.True(t, requestid.IsValid(requestID))
+	})
+
+	t.Run("delivery with invalid request ID", func(t *testing.T) {
+		log, _ := logger.NewTestLogger()
+		delivery := rabbitmq.Delivery{Headers: rabbitmq.Table{amqp.HeaderRequestID: "bad\nid"}}
+
+		ctx := DeliveryContext(context.Background(), delivery, log)
+
+		requestID, _ := requestid.FromContext(ctx)
+		assert.NotEqual(t, "bad\nid", requestID)
+	})
+}
+
+func TestDeliveryContextTraceContext(t *testing.T) {
+	defer otel.SetTextMapPropagator(otel.GetTextMapPropagator())
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	log, _ := logger.NewTestLogger()
+	delivery := rabbitmq.Delivery{
+		Headers: rabbitmq.Table{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+	}
+
+	ctx := DeliveryContext(context.Background(), delivery, log)
+
+	spanContext := trace.SpanContextFromContext(ctx)
+	assert.True(t, spanContext.IsRemote())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spanContext.SpanID().String())
+}
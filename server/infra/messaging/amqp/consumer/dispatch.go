@@ -0,0 +1,105 @@
+package amqpconsumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/messaging"
+	rabbitmq "github.com/rabbitmq/amqp091-go"
+)
+
+// dispatchDeliveries handles the deliveries of a queue with the handlers added for their type until the deliveries
+// channel is closed. A delivery is acknowledged once it is handled, while a delivery that fails to be handled is retried
+// by the retry policy of its type & dead-lettered once its retries run out. A delivery whose payload is invalid is
+// dead-lettered without being retried & a delivery of a type the queue has no handler for is moved to the parking queue.
+// It is the worker of the pools the consumer consumes queues with
+func (c *amqpConsumerClient) dispatchDeliveries(ctx context.Context, sub *subscription, deliveries <-chan rabbitmq.Delivery) {
+	for delivery := range deliveries {
+		c.dispatch(ctx, sub, delivery)
+	}
+}
+
+// dispatch handles a delivery with the handler of its type in the context & span of the delivery, recording its outcome
+func (c *amqpConsumerClient) dispatch(ctx context.Context, sub *subscription, delivery rabbitmq.Delivery) {
+	start := time.Now()
+
+	// every delivery is handled with the request ID it was published with, so its logs can be joined with the request's
+	ctx = DeliveryContext(ctx, delivery, c.logger)
+	log := logger.FromContext(ctx)
+
+	ctx, span := StartDeliverySpan(ctx, delivery)
+
+	log.Infof("Processing message with Tag %d & Type %s", delivery.DeliveryTag, delivery.Type)
+
+	handler, ok := c.handler(sub, delivery.Type)
+	if !ok {
+		err := c.park(ctx, sub, delivery)
+		c.metrics.Observe(delivery.Type, OutcomeUnknown, start)
+		EndDeliverySpan(span, err)
+		return
+	}
+
+	err := handler(ctx, delivery.Body)
+	switch {
+	case errors.Is(err, messaging.ErrInvalidPayload):
+		// handling a poison message again would fail the same way, so it is dead-lettered straight away
+		log.Errorf("Failed to decode message, dead-lettering it: %s", err)
+		c.deadLetter(ctx, sub, delivery)
+		c.metrics.Observe(delivery.Type, OutcomeUnmarshalFailure, start)
+	case err != nil:
+		log.Errorf("Failed to process delivery with err: %s", err)
+
+		policy, retries := c.retryPolicy(delivery.Type), retryCount(delivery)
+		if retries < policy.MaxRetries {
+			if retryErr := c.retry(ctx, sub, delivery, retries, policy.Delay(retries)); retryErr == nil {
+				c.metrics.Observe(delivery.Type, OutcomeRetried, start)
+				break
+			}
+		} else {
+			log.Errorf("Message with Type %s failed after %d retries, dead-lettering it", delivery.Type, retries)
+			c.deadLetter(ctx, sub, delivery)
+		}
+		c.metrics.Observe(delivery.Type, OutcomeRejected, start)
+	default:
+		if err := delivery.Ack(false); err != nil {
+			log.Errorf("Failed to acknowledge delivery with err: %s", err)
+		}
+		c.metrics.Observe(delivery.Type, OutcomeSuccess, start)
+	}
+
+	EndDeliverySpan(span, err)
+}
+
+// handler is the handler of the deliveries of a type consumed from a queue, if the queue handles them & a handler is
+// added for them
+func (c *amqpConsumerClient) handler(sub *subscription, deliveryType string) (messaging.Handler, bool) {
+	if !sub.handles(deliveryType) {
+		return nil, false
+	}
+
+	handler, ok := c.handlers[deliveryType]
+	return handler, ok
+}
+
+// deadLetter moves a delivery to the dead letter queue. The delivery is rejected instead if the queue is configured with a
+// dead letter exchange, which the broker dead-letters it to
+func (c *amqpConsumerClient) deadLetter(ctx context.Context, sub *subscription, delivery rabbitmq.Delivery) {
+	if sub.hasDeadLetterExchange() {
+		if err := delivery.Reject(false); err != nil {
+			logger.FromContext(ctx).Errorf("Failed to delivery.Reject with err: %s", err)
+		}
+		return
+	}
+
+	_ = c.move(ctx, sub, delivery, sub.deadLetterQueue(), delivery.Headers)
+}
+
+// park moves a delivery the queue has no handler for to its parking queue
+func (c *amqpConsumerClient) park(ctx context.Context, sub *subscription, delivery rabbitmq.Delivery) error {
+	logger.FromContext(ctx).Warn(fmt.Sprintf("No handler for message with Type %s in queue %s, parking it in queue %s", delivery.Type, sub.queue.Name, sub.parkingQueue))
+
+	return c.move(ctx, sub, delivery, sub.parkingQueue, delivery.Headers)
+}
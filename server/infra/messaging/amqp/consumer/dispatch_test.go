@@ -0,0 +1,310 @@
+package amqpconsumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/messaging"
+	"github.com/BrianLusina/skillq/server/infra/messaging/amqp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	rabbitmq "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// acknowledger records how deliveries are acknowledged
+type acknowledger struct {
+	acked    []uint64
+	rejected []uint64
+	requeued []uint64
+}
+
+func (a *acknowledger) Ack(tag uint64, multiple bool) error {
+	a.acked = append(a.acked, tag)
+	return nil
+}
+
+func (a *acknowledger) Nack(tag uint64, multiple bool, requeue bool) error {
+	if requeue {
+		a.requeued = append(a.requeued, tag)
+		return nil
+	}
+	a.rejected = append(a.rejected, tag)
+	return nil
+}
+
+func (a *acknowledger) Reject(tag uint64, requeue bool) error {
+	return a.Nack(tag, false, requeue)
+}
+
+// parkingPublisher records the messages published to the parking, retry & dead letter queues
+type parkingPublisher struct {
+	err       error
+	keys      []string
+	published []rabbitmq.Publishing
+}
+
+func (p *parkingPublisher) Publish(ctx context.Context, queue string, msg rabbitmq.Publishing) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.keys = append(p.keys, queue)
+	p.published = append(p.published, msg)
+	return nil
+}
+
+func TestDispatch(t *testing.T) {
+	newTestConsumer := func(publisher channelPublisher) (*amqpConsumerClient, *prometheus.Registry) {
+		log, _ := logger.NewTestLogger()
+		registry := prometheus.NewRegistry()
+
+		consumer, err := NewConsumer(nil, log, NewMetrics(registry))
+		assert.NoError(t, err)
+
+		c := consumer.Configure(Subscribe(SubscriptionParams{
+			Queue: amqp.QueueOptionParams{Name: "send-email-queue"},
+		})).(*amqpConsumerClient)
+		c.subscriptions[0].publisher = publisher
+		// deliveries that could not be moved are requeued straight away
+		c.requeueBackoff = amqp.Backoff{}
+
+		c.AddHandler("send_email_verification", func(ctx context.Context, payload []byte) error {
+			switch string(payload) {
+			case "invalid":
+				return fmt.Errorf("%w: unexpected end of JSON input", messaging.ErrInvalidPayload)
+			case "failing":
+				return errors.New("smtp server unavailable")
+			}
+			return nil
+		})
+
+		return c, registry
+	}
+
+	dispatch := func(c *amqpConsumerClient, deliveries ...rabbitmq.Delivery) {
+		ch := make(chan rabbitmq.Delivery, len(deliveries))
+		for _, delivery := range deliveries {
+			ch <- delivery
+		}
+		close(ch)
+
+		c.dispatchDeliveries(context.Background(), c.subscriptions[0], ch)
+	}
+
+	t.Run("acknowledges handled deliveries & dead-letters poison ones without retrying them", func(t *testing.T) {
+		publisher := &parkingPublisher{}
+		c, registry := newTestConsumer(publisher)
+		ack := &acknowledger{}
+
+		dispatch(c,
+			rabbitmq.Delivery{Acknowledger: ack, DeliveryTag: 1, Type: "send_email_verification", Body: []byte("{}")},
+			rabbitmq.Delivery{Acknowledger: ack, DeliveryTag: 2, Type: "send_email_verification", Body: []byte("invalid")},
+		)
+
+		assert.Equal(t, []uint64{1, 2}, ack.acked)
+		assert.Empty(t, ack.rejected)
+		assert.Equal(t, []string{"send-email-queue.dlq"}, publisher.keys)
+
+		expected := `
+# HELP skillq_amqp_processed_messages_total Number of deliveries processed by type & outcome.
+# TYPE skillq_amqp_processed_messages_total counter
+skillq_amqp_processed_messages_total{outcome="success",type="send_email_verification"} 1
+skillq_amqp_processed_messages_total{outcome="unmarshal_failure",type="send_email_verification"} 1
+`
+		assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "skillq_amqp_processed_messages_total"))
+	})
+
+	t.Run("retries failed deliveries after an exponential delay", func(t *testing.T) {
+		publisher := &parkingPublisher{}
+		c, registry := newTestConsumer(publisher)
+		ack := &acknowledger{}
+
+		dispatch(c,
+			rabbitmq.Delivery{Acknowledger: ack, DeliveryTag: 1, MessageId: "message-1", Type: "send_email_verification", Body: []byte("failing")},
+			rabbitmq.Delivery{
+				Acknowledger: ack,
+				DeliveryTag:  2,
+				Type:         "send_email_verification",
+				Headers:      rabbitmq.Table{HeaderRetryCount: int32(2), "x-request-id": "request-1"},
+				Body:         []byte("failing"),
+			},
+		)
+
+		assert.Equal(t, []uint64{1, 2}, ack.acked)
+		assert.Empty(t, ack.rejected)
+		assert.Equal(t, []string{"send-email-queue.retry.5s", "send-email-queue.retry.20s"}, publisher.keys)
+
+		assert.Equal(t, "message-1", publisher.published[0].MessageId)
+		assert.Equal(t, []byte("failing"), publisher.published[0].Body)
+		assert.Equal(t, int32(1), publisher.published[0].Headers[HeaderRetryCount])
+		assert.Equal(t, int32(3), publisher.published[1].Headers[HeaderRetryCount])
+		assert.Equal(t, "request-1", publisher.published[1].Headers["x-request-id"])
+
+		expected := `
+# HELP skillq_amqp_processed_messages_total Number of deliveries processed by type & outcome.
+# TYPE skillq_amqp_processed_messages_total counter
+skillq_amqp_processed_messages_total{outcome="retried",type="send_email_verification"} 2
+`
+		assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "skillq_amqp_processed_messages_total"))
+	})
+
+	t.Run("dead-letters failed deliveries once their retries run out", func(t *testing.T) {
+		publisher := &parkingPublisher{}
+		c, registry := newTestConsumer(publisher)
+		ack := &acknowledger{}
+
+		dispatch(c, rabbitmq.Delivery{
+			Acknowledger: ack,
+			DeliveryTag:  1,
+			Type:         "send_email_verification",
+			Headers:      rabbitmq.Table{HeaderRetryCount: int64(3)},
+			Body:         []byte("failing"),
+		})
+
+		assert.Equal(t, []uint64{1}, ack.acked)
+		assert.Equal(t, []string{"send-email-queue.dlq"}, publisher.keys)
+		assert.Equal(t, int64(3), publisher.published[0].Headers[HeaderRetryCount])
+
+		expected := `
+# HELP skillq_amqp_processed_messages_total Number of deliveries processed by type & outcome.
+# TYPE skillq_amqp_processed_messages_total counter
+skillq_amqp_processed_messages_total{outcome="rejected",type="send_email_verification"} 1
+`
+		assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "skillq_amqp_processed_messages_total"))
+	})
+
+	t.Run("retries failed deliveries by the retry policy of their type", func(t *testing.T) {
+		publisher := &parkingPublisher{}
+		c, _ := newTestConsumer(publisher)
+		c.Configure(TaskRetryPolicy("send_email_verification", RetryPolicy{MaxRetries: 0}))
+		ack := &acknowledger{}
+
+		dispatch(c, rabbitmq.Delivery{Acknowledger: ack, DeliveryTag: 1, Type: "send_email_verification", Body: []byte("failing")})
+
+		assert.Equal(t, []string{"send-email-queue.dlq"}, publisher.keys)
+	})
+
+	t.Run("rejects deliveries to the dead letter exchange the queue is configured with", func(t *testing.T) {
+		publisher := &parkingPublisher{}
+		c, _ := newTestConsumer(publisher)
+		c.Configure(Subscribe(SubscriptionParams{
+			Queue: amqp.QueueOptionParams{Name: "send-email-queue", Args: map[string]any{"x-dead-letter-exchange": "dlx"}},
+		}))
+		c.subscriptions[0].publisher = publisher
+		ack := &acknowledger{}
+
+		dispatch(c, rabbitmq.Delivery{Acknowledger: ack, DeliveryTag: 1, Type: "send_email_verification", Body: []byte("invalid")})
+
+		assert.Equal(t, []uint64{1}, ack.rejected)
+		assert.Empty(t, publisher.published)
+	})
+
+	t.Run("requeues failed deliveries that could not be retried", func(t *testing.T) {
+		c, _ := newTestConsumer(&parkingPublisher{err: rabbitmq.ErrClosed})
+		ack := &acknowledger{}
+
+		dispatch(c, rabbitmq.Delivery{Acknowledger: ack, DeliveryTag: 1, Type: "send_email_verification", Body: []byte("failing")})
+
+		assert.Empty(t, ack.acked)
+		assert.Empty(t, ack.rejected)
+		assert.Equal(t, []uint64{1}, ack.requeued)
+	})
+
+	t.Run("backs off before requeueing deliveries that could not be moved in a row", func(t *testing.T) {
+		publisher := &parkingPublisher{err: rabbitmq.ErrClosed}
+		c, _ := newTestConsumer(publisher)
+		c.requeueBackoff = amqp.Backoff{InitialDelay: 20 * time.Millisecond, MaxDelay: time.Second}
+		ack := &acknowledger{}
+
+		start := time.Now()
+		dispatch(c,
+			rabbitmq.Delivery{Acknowledger: ack, DeliveryTag: 1, Type: "send_email_verification", Body: []byte("failing")},
+			rabbitmq.Delivery{Acknowledger: ack, DeliveryTag: 2, Type: "send_email_verification", Body: []byte("failing")},
+		)
+
+		// the first requeue waits at least half the initial delay & the second at least half of twice the initial delay
+		assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+		assert.Equal(t, []uint64{1, 2}, ack.requeued)
+		assert.Equal(t, int32(2), c.subscriptions[0].republishFailures.Load())
+
+		publisher.err = nil
+		dispatch(c, rabbitmq.Delivery{Acknowledger: ack, DeliveryTag: 3, Type: "send_email_verification", Body: []byte("failing")})
+
+		assert.Equal(t, []uint64{3}, ack.acked)
+		assert.Zero(t, c.subscriptions[0].republishFailures.Load())
+	})
+
+	t.Run("parks deliveries of an unknown type", func(t *testing.T) {
+		publisher := &parkingPublisher{}
+		c, registry := newTestConsumer(publisher)
+		ack := &acknowledger{}
+
+		dispatch(c, rabbitmq.Delivery{
+			Acknowledger: ack,
+			DeliveryTag:  1,
+			MessageId:    "message-1",
+			Type:         "send_sms",
+			Headers:      rabbitmq.Table{"x-request-id": "request-1"},
+			Body:         []byte(`{"phone":"555"}`),
+		})
+
+		assert.Equal(t, []uint64{1}, ack.acked)
+		assert.Equal(t, []string{"send-email-queue.parking"}, publisher.keys)
+		assert.Equal(t, "message-1", publisher.published[0].MessageId)
+		assert.Equal(t, "send_sms", publisher.published[0].Type)
+		assert.Equal(t, "request-1", publisher.published[0].Headers["x-request-id"])
+		assert.Equal(t, []byte(`{"phone":"555"}`), publisher.published[0].Body)
+
+		expected := `
+# HELP skillq_amqp_processed_messages_total Number of deliveries processed by type & outcome.
+# TYPE skillq_amqp_processed_messages_total counter
+skillq_amqp_processed_messages_total{outcome="unknown",type="send_sms"} 1
+`
+		assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "skillq_amqp_processed_messages_total"))
+	})
+
+	t.Run("requeues deliveries of an unknown type that could not be parked", func(t *testing.T) {
+		c, _ := newTestConsumer(&parkingPublisher{err: rabbitmq.ErrClosed})
+		ack := &acknowledger{}
+
+		dispatch(c, rabbitmq.Delivery{Acknowledger: ack, DeliveryTag: 1, Type: "send_sms"})
+
+		assert.Empty(t, ack.acked)
+		assert.Equal(t, []uint64{1}, ack.requeued)
+	})
+
+	t.Run("parks deliveries in the configured parking queue", func(t *testing.T) {
+		publisher := &parkingPublisher{}
+		c, _ := newTestConsumer(publisher)
+		c.Configure(Subscribe(SubscriptionParams{
+			Queue:        amqp.QueueOptionParams{Name: "send-email-queue"},
+			ParkingQueue: "skillq.parking",
+		}))
+		c.subscriptions[0].publisher = publisher
+
+		dispatch(c, rabbitmq.Delivery{Acknowledger: &acknowledger{}, Type: "send_sms"})
+
+		assert.Equal(t, []string{"skillq.parking"}, publisher.keys)
+	})
+
+	t.Run("parks deliveries of a topic the queue does not handle", func(t *testing.T) {
+		publisher := &parkingPublisher{}
+		c, _ := newTestConsumer(publisher)
+		c.Configure(Subscribe(SubscriptionParams{
+			Queue:  amqp.QueueOptionParams{Name: "send-email-queue"},
+			Topics: []string{"send_password_reset"},
+		}))
+		c.subscriptions[0].publisher = publisher
+		ack := &acknowledger{}
+
+		dispatch(c, rabbitmq.Delivery{Acknowledger: ack, DeliveryTag: 1, Type: "send_email_verification", Body: []byte("{}")})
+
+		assert.Equal(t, []uint64{1}, ack.acked)
+		assert.Equal(t, []string{"send-email-queue.parking"}, publisher.keys)
+	})
+}
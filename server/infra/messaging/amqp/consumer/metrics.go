@@ -0,0 +1,61 @@
+package amqpconsumer
+
+import (
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// OutcomeSuccess labels a delivery that was handled & acknowledged
+	OutcomeSuccess = metrics.OutcomeSuccess
+
+	// OutcomeRejected labels a delivery that failed to be handled & was rejected, which dead-letters it unless it could
+	// not be retried & was requeued
+	OutcomeRejected = "rejected"
+
+	// OutcomeRetried labels a delivery that failed to be handled & was published to be retried after a delay
+	OutcomeRetried = "retried"
+
+	// OutcomeUnmarshalFailure labels a delivery whose body could not be unmarshalled into its task, which is dead-lettered
+	OutcomeUnmarshalFailure = "unmarshal_failure"
+
+	// OutcomeUnknown labels a delivery of a type no handler exists for, which is parked
+	OutcomeUnknown = "unknown"
+)
+
+// Metrics are the metrics of the deliveries consumed from an AMQP broker
+type Metrics struct {
+	processed *prometheus.CounterVec
+	duration  *prometheus.HistogramVec
+}
+
+// NewMetrics creates the metrics of consumed deliveries & registers them with the registerer
+func NewMetrics(registerer prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		processed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "amqp",
+			Name:      "processed_messages_total",
+			Help:      "Number of deliveries processed by type & outcome.",
+		}, []string{"type", "outcome"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "amqp",
+			Name:      "processing_duration_seconds",
+			Help:      "Duration of processing deliveries by type.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"type"}),
+	}
+
+	registerer.MustRegister(m.processed, m.duration)
+
+	return m
+}
+
+// Observe records a delivery of the type that was processed with the outcome, starting at the given time
+func (m *Metrics) Observe(deliveryType, outcome string, start time.Time) {
+	m.processed.WithLabelValues(deliveryType, outcome).Inc()
+	m.duration.WithLabelValues(deliveryType).Observe(time.Since(start).Seconds())
+}
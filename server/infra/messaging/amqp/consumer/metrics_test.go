@@ -0,0 +1,31 @@
+package amqpconsumer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m := NewMetrics(registry)
+
+	start := time.Now()
+	m.Observe("send_email_verification", OutcomeSuccess, start)
+	m.Observe("send_email_verification", OutcomeRejected, start)
+	m.Observe("store_user_image", OutcomeUnmarshalFailure, start)
+
+	expected := `
+# HELP skillq_amqp_processed_messages_total Number of deliveries processed by type & outcome.
+# TYPE skillq_amqp_processed_messages_total counter
+skillq_amqp_processed_messages_total{outcome="rejected",type="send_email_verification"} 1
+skillq_amqp_processed_messages_total{outcome="success",type="send_email_verification"} 1
+skillq_amqp_processed_messages_total{outcome="unmarshal_failure",type="store_user_image"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "skillq_amqp_processed_messages_total"))
+	assert.Equal(t, 2, testutil.CollectAndCount(registry, "skillq_amqp_processing_duration_seconds"))
+}
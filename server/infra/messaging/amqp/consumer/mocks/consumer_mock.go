@@ -13,6 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	messaging "github.com/BrianLusina/skillq/server/infra/messaging"
 	amqpconsumer "github.com/BrianLusina/skillq/server/infra/messaging/amqp/consumer"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -41,15 +42,15 @@ func (m *MockAmqpEventConsumer) EXPECT() *MockAmqpEventConsumerMockRecorder {
 }
 
 // AddHandler mocks base method.
-func (m *MockAmqpEventConsumer) AddHandler(ctx context.Context, task string, handler func([]byte) error) {
+func (m *MockAmqpEventConsumer) AddHandler(topic string, handler messaging.Handler) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "AddHandler", ctx, task, handler)
+	m.ctrl.Call(m, "AddHandler", topic, handler)
 }
 
 // AddHandler indicates an expected call of AddHandler.
-func (mr *MockAmqpEventConsumerMockRecorder) AddHandler(ctx, task, handler any) *gomock.Call {
+func (mr *MockAmqpEventConsumerMockRecorder) AddHandler(topic, handler any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddHandler", reflect.TypeOf((*MockAmqpEventConsumer)(nil).AddHandler), ctx, task, handler)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddHandler", reflect.TypeOf((*MockAmqpEventConsumer)(nil).AddHandler), topic, handler)
 }
 
 // Configure mocks base method.
@@ -84,16 +85,44 @@ func (mr *MockAmqpEventConsumerMockRecorder) Consume(ctx, queue any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockAmqpEventConsumer)(nil).Consume), ctx, queue)
 }
 
-// StartConsumer mocks base method.
-func (m *MockAmqpEventConsumer) StartConsumer(fn func(context.Context, any)) error {
+// Declare mocks base method.
+func (m *MockAmqpEventConsumer) Declare(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "StartConsumer", fn)
+	ret := m.ctrl.Call(m, "Declare", ctx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// StartConsumer indicates an expected call of StartConsumer.
-func (mr *MockAmqpEventConsumerMockRecorder) StartConsumer(fn any) *gomock.Call {
+// Declare indicates an expected call of Declare.
+func (mr *MockAmqpEventConsumerMockRecorder) Declare(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartConsumer", reflect.TypeOf((*MockAmqpEventConsumer)(nil).StartConsumer), fn)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Declare", reflect.TypeOf((*MockAmqpEventConsumer)(nil).Declare), ctx)
+}
+
+// Start mocks base method.
+func (m *MockAmqpEventConsumer) Start() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockAmqpEventConsumerMockRecorder) Start() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockAmqpEventConsumer)(nil).Start))
+}
+
+// Stop mocks base method.
+func (m *MockAmqpEventConsumer) Stop(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stop", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockAmqpEventConsumerMockRecorder) Stop(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockAmqpEventConsumer)(nil).Stop), ctx)
 }
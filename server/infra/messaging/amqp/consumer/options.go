@@ -4,58 +4,44 @@ import "github.com/BrianLusina/skillq/server/infra/messaging/amqp"
 
 type Option func(*amqpConsumerClient)
 
-// Exchange configures an exchange for the consumer to construct
+// Exchange adds an exchange for the consumer to declare, replacing an exchange of the same name
 func Exchange(params amqp.ExchangeOptionParams) Option {
 	return func(c *amqpConsumerClient) {
-		c.exchangeName = params.Name
-		c.exchangeKind = params.Kind
-		c.exchangeDurable = params.Durable
-		c.exchangeAutoDelete = params.AutoDelete
-		c.exchangeInternal = params.Internal
-		c.exchangeNoWait = params.NoWait
+		for i, exchange := range c.exchanges {
+			if exchange.Name == params.Name {
+				c.exchanges[i] = params
+				return
+			}
+		}
+		c.exchanges = append(c.exchanges, params)
 	}
 }
 
-func Queue(params amqp.QueueOptionParams) Option {
+// Subscribe adds a queue for the consumer to declare & consume from with a pool of workers of its own, replacing a
+// subscription to a queue of the same name
+func Subscribe(params SubscriptionParams) Option {
 	return func(c *amqpConsumerClient) {
-		c.queueName = params.Name
-		c.queueDurable = params.Durable
-		c.queueAutoDelete = params.AutoDelete
-		c.queueExclusive = params.Exclusive
-		c.queueNoWait = params.NoWait
-		c.queueArgs = params.Args
+		sub := newSubscription(params)
+		for i, existing := range c.subscriptions {
+			if existing.queue.Name == sub.queue.Name {
+				c.subscriptions[i] = sub
+				return
+			}
+		}
+		c.subscriptions = append(c.subscriptions, sub)
 	}
 }
 
-func BindingKey(bindingKey string) Option {
+// DefaultRetryPolicy sets the retry policy of deliveries of the types that have no retry policy of their own
+func DefaultRetryPolicy(policy RetryPolicy) Option {
 	return func(c *amqpConsumerClient) {
-		c.bindingKey = bindingKey
+		c.defaultRetryPolicy = policy
 	}
 }
 
-// Consumer sets up the consumer options
-func Consumer(params amqp.ConsumerOptionParams) Option {
+// TaskRetryPolicy sets the retry policy of deliveries of a type
+func TaskRetryPolicy(deliveryType string, policy RetryPolicy) Option {
 	return func(c *amqpConsumerClient) {
-		c.consumerTag = params.Tag
-		c.consumeAutoAck = params.AutoAck
-		c.consumeExclusive = params.Exclusive
-		c.consumeNoLocal = params.NoLocal
-		c.consumeNoWait = params.NoWait
-		c.consumerArgs = params.Args
-	}
-}
-
-// Qos sets up the consumer QOS for message consumption
-func Qos(params amqp.QosOptionParams) Option {
-	return func(c *amqpConsumerClient) {
-		c.qosPrefetchCount = params.PrefetchCount
-		c.qosPrefetchSize = params.PrefetchSize
-		c.qosPrefetchGlobal = params.PrefetchGlobal
-	}
-}
-
-func WorkerPoolSize(size int) Option {
-	return func(c *amqpConsumerClient) {
-		c.workerPoolSize = size
+		c.retryPolicies[deliveryType] = policy
 	}
 }
@@ -0,0 +1,113 @@
+package amqpconsumer
+
+import (
+	"context"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/pkg/errors"
+	rabbitmq "github.com/rabbitmq/amqp091-go"
+)
+
+// errRepublishNacked is returned when the broker nacks a delivery that is republished to a queue
+var errRepublishNacked = errors.New("broker nacked republished message")
+
+// channelPublisher publishes messages to queues, returning once the broker has confirmed them. Deliveries are parked,
+// retried & dead-lettered with it
+type channelPublisher interface {
+	Publish(ctx context.Context, queue string, msg rabbitmq.Publishing) error
+}
+
+// confirmPublisher publishes messages on a channel in confirm mode through the default exchange, which routes messages to
+// the queue named by their routing key
+type confirmPublisher struct {
+	channel *rabbitmq.Channel
+	timeout time.Duration
+}
+
+var _ channelPublisher = (*confirmPublisher)(nil)
+
+// Publish publishes a message to a queue & waits for the broker to confirm it within the timeout of the publisher
+func (p *confirmPublisher) Publish(ctx context.Context, queue string, msg rabbitmq.Publishing) error {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	confirmation, err := p.channel.PublishWithDeferredConfirmWithContext(ctx, "", queue, false, false, msg)
+	if err != nil {
+		return errors.Wrapf(err, "failed to publish message to queue %s", queue)
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "broker did not confirm message published to queue %s", queue)
+	}
+
+	if !acked {
+		return errors.Wrapf(errRepublishNacked, "%s", queue)
+	}
+
+	return nil
+}
+
+// republish publishes a copy of a delivery with the headers to a queue, returning once the broker has confirmed it
+func (s *subscription) republish(ctx context.Context, queue string, delivery rabbitmq.Delivery, headers rabbitmq.Table) error {
+	s.mu.Lock()
+	publisher := s.publisher
+	s.mu.Unlock()
+
+	return publisher.Publish(ctx, queue, rabbitmq.Publishing{
+		Headers:         headers,
+		ContentType:     delivery.ContentType,
+		ContentEncoding: delivery.ContentEncoding,
+		DeliveryMode:    rabbitmq.Persistent,
+		MessageId:       delivery.MessageId,
+		Timestamp:       delivery.Timestamp,
+		Type:            delivery.Type,
+		Body:            delivery.Body,
+	})
+}
+
+// move moves a delivery to a queue by republishing it with the headers, acknowledging it only once the broker has
+// confirmed the copy so that it is not lost. A delivery that could not be moved is requeued after a backoff that grows
+// with the deliveries of the subscription that failed to be moved in a row, so that the same deliveries are not redelivered
+// & failed again straight away while the broker can not take them
+func (c *amqpConsumerClient) move(ctx context.Context, sub *subscription, delivery rabbitmq.Delivery, queue string, headers rabbitmq.Table) error {
+	log := logger.FromContext(ctx)
+
+	if err := sub.republish(ctx, queue, delivery, headers); err != nil {
+		failures := sub.republishFailures.Add(1)
+		delay := c.requeueBackoff.Delay(int(failures) - 1)
+
+		log.Errorf("Failed to move message with Type %s to queue %s with err: %s, requeueing it in %s", delivery.Type, queue, err, delay)
+		c.wait(ctx, delay)
+
+		if err := delivery.Nack(false, true); err != nil {
+			log.Errorf("Failed to delivery.Nack with err: %s", err)
+		}
+		return err
+	}
+
+	sub.republishFailures.Store(0)
+
+	if err := delivery.Ack(false); err != nil {
+		log.Errorf("Failed to acknowledge delivery with err: %s", err)
+	}
+
+	return nil
+}
+
+// wait waits for the delay, returning early if the consumer is stopped or the context is done
+func (c *amqpConsumerClient) wait(ctx context.Context, delay time.Duration) {
+	if delay <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-c.stopped:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,76 @@
+package amqpconsumer
+
+import (
+	"context"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/messaging"
+	rabbitmq "github.com/rabbitmq/amqp091-go"
+)
+
+// HeaderRetryCount is the header of a delivery carrying the number of times it has been retried
+const HeaderRetryCount = "x-retry-count"
+
+// RetryPolicy is how deliveries of a type that fail to be handled are retried, which is shared by the consumers of every
+// broker
+type RetryPolicy = messaging.RetryPolicy
+
+// retryPolicy is the retry policy of deliveries of a type, which is the default retry policy unless the type has its own
+func (c *amqpConsumerClient) retryPolicy(deliveryType string) RetryPolicy {
+	if policy, ok := c.retryPolicies[deliveryType]; ok {
+		return policy
+	}
+	return c.defaultRetryPolicy
+}
+
+// retryDelays are the delays deliveries are retried after by the retry policies of the consumer, each of which has a
+// retry queue of its own
+func (c *amqpConsumerClient) retryDelays() []time.Duration {
+	seen := map[time.Duration]bool{}
+	var delays []time.Duration
+
+	policies := []RetryPolicy{c.defaultRetryPolicy}
+	for _, policy := range c.retryPolicies {
+		policies = append(policies, policy)
+	}
+
+	for _, policy := range policies {
+		for retries := 0; retries < policy.MaxRetries; retries++ {
+			delay := policy.Delay(retries)
+			if !seen[delay] {
+				seen[delay] = true
+				delays = append(delays, delay)
+			}
+		}
+	}
+
+	return delays
+}
+
+// retry moves a delivery that failed to be handled to the retry queue of its delay with its retry count incremented
+func (c *amqpConsumerClient) retry(ctx context.Context, sub *subscription, delivery rabbitmq.Delivery, retries int, delay time.Duration) error {
+	logger.FromContext(ctx).Infof("Retrying message with Type %s in %s, retry %d", delivery.Type, delay, retries+1)
+
+	headers := rabbitmq.Table{}
+	for key, value := range delivery.Headers {
+		headers[key] = value
+	}
+	headers[HeaderRetryCount] = int32(retries + 1)
+
+	return c.move(ctx, sub, delivery, sub.retryQueue(delay), headers)
+}
+
+// retryCount is the number of times a delivery has been retried
+func retryCount(delivery rabbitmq.Delivery) int {
+	switch count := delivery.Headers[HeaderRetryCount].(type) {
+	case int32:
+		return int(count)
+	case int64:
+		return int(count)
+	case int:
+		return count
+	default:
+		return 0
+	}
+}
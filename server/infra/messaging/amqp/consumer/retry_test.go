@@ -0,0 +1,56 @@
+package amqpconsumer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/messaging/amqp"
+	"github.com/prometheus/client_golang/prometheus"
+	rabbitmq "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 5, InitialDelay: time.Second, MaxDelay: 10 * time.Second, Multiplier: 3}
+
+	assert.Equal(t, time.Second, policy.Delay(0))
+	assert.Equal(t, 3*time.Second, policy.Delay(1))
+	assert.Equal(t, 9*time.Second, policy.Delay(2))
+	assert.Equal(t, 10*time.Second, policy.Delay(3))
+	assert.Equal(t, 10*time.Second, policy.Delay(100))
+}
+
+func TestRetryTopology(t *testing.T) {
+	log, _ := logger.NewTestLogger()
+	consumer, err := NewConsumer(nil, log, NewMetrics(prometheus.NewRegistry()))
+	assert.NoError(t, err)
+
+	c := consumer.Configure(
+		Subscribe(SubscriptionParams{Queue: amqp.QueueOptionParams{Name: "send-email-queue"}}),
+		DefaultRetryPolicy(RetryPolicy{MaxRetries: 3, InitialDelay: time.Second, MaxDelay: 5 * time.Second, Multiplier: 4}),
+		TaskRetryPolicy("store_user_image", RetryPolicy{MaxRetries: 2, InitialDelay: time.Second, Multiplier: 2}),
+	).(*amqpConsumerClient)
+	sub := c.subscriptions[0]
+
+	t.Run("has a retry queue for every delay", func(t *testing.T) {
+		assert.ElementsMatch(t, []time.Duration{time.Second, 4 * time.Second, 5 * time.Second, 2 * time.Second}, c.retryDelays())
+	})
+
+	t.Run("retry queues dead-letter deliveries back to the queue after the delay", func(t *testing.T) {
+		assert.Equal(t, "send-email-queue.retry.4s", sub.retryQueue(4*time.Second))
+		assert.Equal(t, rabbitmq.Table{
+			"x-message-ttl":             int64(4000),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": "send-email-queue",
+		}, sub.retryQueueArgs(4*time.Second))
+	})
+
+	t.Run("queue has a dead letter exchange only if it is configured with one", func(t *testing.T) {
+		assert.False(t, sub.hasDeadLetterExchange())
+
+		c.Configure(Subscribe(SubscriptionParams{Queue: amqp.QueueOptionParams{Name: "send-email-queue", Args: map[string]any{"x-dead-letter-exchange": "dlx"}}}))
+
+		assert.True(t, c.subscriptions[0].hasDeadLetterExchange())
+	})
+}
@@ -0,0 +1,125 @@
+package amqpconsumer
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/messaging/amqp"
+	rabbitmq "github.com/rabbitmq/amqp091-go"
+)
+
+// SubscriptionParams are the parameters of a queue the consumer consumes from
+type SubscriptionParams struct {
+	// Queue is the queue consumed from
+	Queue amqp.QueueOptionParams
+
+	// Bindings bind the queue to the exchanges it receives messages from. The queue of a binding is set to the queue
+	Bindings []amqp.BindingOptionParams
+
+	// Consumer configures consuming from the queue. The tag defaults to the name of the queue with a -consumer suffix
+	Consumer amqp.ConsumerOptionParams
+
+	// Qos configures the deliveries the broker sends before they are acknowledged. The prefetch count defaults to the
+	// number of workers, so that every worker has a delivery to handle
+	Qos amqp.QosOptionParams
+
+	// Workers is the number of workers that handle the deliveries of the queue concurrently
+	Workers int
+
+	// Topics are the topics of the deliveries handled from the queue. Deliveries of other topics are parked. The queue
+	// handles the deliveries of every topic a handler is added for if no topics are set
+	Topics []string
+
+	// ParkingQueue is the queue deliveries no handler is added for are parked in. It defaults to the name of the queue
+	// with a .parking suffix
+	ParkingQueue string
+}
+
+// subscription is a queue the consumer consumes from with a pool of workers of its own
+type subscription struct {
+	queue        amqp.QueueOptionParams
+	bindings     []amqp.BindingOptionParams
+	consumer     amqp.ConsumerOptionParams
+	qos          amqp.QosOptionParams
+	workers      int
+	topics       []string
+	parkingQueue string
+
+	// mu guards the channel deliveries are consumed on, the publisher deliveries are moved to other queues with & done,
+	// which is closed once every worker has returned
+	mu        sync.Mutex
+	channel   *rabbitmq.Channel
+	publisher channelPublisher
+	done      chan struct{}
+
+	// republishFailures counts the deliveries that failed to be moved to other queues in a row, which the backoff before
+	// they are requeued grows with
+	republishFailures atomic.Int32
+}
+
+// newSubscription creates a subscription to a queue, filling in the defaults of the parameters that are not set
+func newSubscription(params SubscriptionParams) *subscription {
+	sub := &subscription{
+		queue:        params.Queue,
+		consumer:     params.Consumer,
+		qos:          params.Qos,
+		workers:      params.Workers,
+		topics:       params.Topics,
+		parkingQueue: params.ParkingQueue,
+	}
+
+	for _, binding := range params.Bindings {
+		binding.Queue = params.Queue.Name
+		sub.bindings = append(sub.bindings, binding)
+	}
+
+	if sub.consumer.Tag == "" {
+		sub.consumer.Tag = sub.queue.Name + _consumerTagSuffix
+	}
+	if sub.workers <= 0 {
+		sub.workers = _workerPoolSize
+	}
+	if sub.qos.PrefetchCount <= 0 {
+		sub.qos.PrefetchCount = sub.workers
+	}
+	if sub.parkingQueue == "" {
+		sub.parkingQueue = sub.queue.Name + _parkingQueueSuffix
+	}
+
+	return sub
+}
+
+// handles checks if deliveries of a topic are handled from the queue
+func (s *subscription) handles(topic string) bool {
+	return len(s.topics) == 0 || slices.Contains(s.topics, topic)
+}
+
+// retryQueue is the name of the queue deliveries are kept in for the delay before they are retried
+func (s *subscription) retryQueue(delay time.Duration) string {
+	return fmt.Sprintf("%s%s%s", s.queue.Name, _retryQueueSuffix, delay)
+}
+
+// deadLetterQueue is the name of the queue deliveries are dead-lettered to
+func (s *subscription) deadLetterQueue() string {
+	return s.queue.Name + _deadLetterQueueSuffix
+}
+
+// retryQueueArgs are the arguments of a retry queue, which dead-letters deliveries back to the queue once they have been
+// in it for the delay
+func (s *subscription) retryQueueArgs(delay time.Duration) rabbitmq.Table {
+	return rabbitmq.Table{
+		"x-message-ttl":             delay.Milliseconds(),
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": s.queue.Name,
+	}
+}
+
+// hasDeadLetterExchange checks if the queue is configured with a dead letter exchange, which the broker dead-letters
+// rejected deliveries to
+func (s *subscription) hasDeadLetterExchange() bool {
+	_, ok := s.queue.Args["x-dead-letter-exchange"]
+	return ok
+}
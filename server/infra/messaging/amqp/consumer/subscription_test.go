@@ -0,0 +1,138 @@
+package amqpconsumer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/messaging/amqp"
+	"github.com/prometheus/client_golang/prometheus"
+	rabbitmq "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptions(t *testing.T) {
+	newTestConsumer := func(opts ...Option) *amqpConsumerClient {
+		log, _ := logger.NewTestLogger()
+		consumer, err := NewConsumer(nil, log, NewMetrics(prometheus.NewRegistry()))
+		require.NoError(t, err)
+
+		return consumer.Configure(opts...).(*amqpConsumerClient)
+	}
+
+	t.Run("fills in the defaults of a subscription", func(t *testing.T) {
+		c := newTestConsumer(Subscribe(SubscriptionParams{Queue: amqp.QueueOptionParams{Name: "send-email-queue"}}))
+
+		require.Len(t, c.subscriptions, 1)
+		sub := c.subscriptions[0]
+		assert.Equal(t, "send-email-queue-consumer", sub.consumer.Tag)
+		assert.Equal(t, _workerPoolSize, sub.workers)
+		assert.Equal(t, _workerPoolSize, sub.qos.PrefetchCount)
+		assert.Equal(t, "send-email-queue.parking", sub.parkingQueue)
+	})
+
+	t.Run("subscribes to several queues, each with its own workers & topics", func(t *testing.T) {
+		c := newTestConsumer(
+			Subscribe(SubscriptionParams{
+				Queue:   amqp.QueueOptionParams{Name: "send-email-queue"},
+				Workers: 8,
+				Topics:  []string{"SendEmailVerification", "SendPasswordReset"},
+			}),
+			Subscribe(SubscriptionParams{
+				Queue:   amqp.QueueOptionParams{Name: "store-image-queue"},
+				Workers: 2,
+				Qos:     amqp.QosOptionParams{PrefetchCount: 1},
+				Topics:  []string{"StoreUserImage"},
+			}),
+		)
+		c.AddHandler("SendEmailVerification", func(ctx context.Context, payload []byte) error { return nil })
+		c.AddHandler("StoreUserImage", func(ctx context.Context, payload []byte) error { return nil })
+
+		require.Len(t, c.subscriptions, 2)
+		sendEmail, storeImage := c.subscriptions[0], c.subscriptions[1]
+		assert.Equal(t, 8, sendEmail.workers)
+		assert.Equal(t, 2, storeImage.workers)
+		assert.Equal(t, 1, storeImage.qos.PrefetchCount)
+
+		_, ok := c.handler(sendEmail, "SendEmailVerification")
+		assert.True(t, ok)
+		_, ok = c.handler(sendEmail, "StoreUserImage")
+		assert.False(t, ok)
+		_, ok = c.handler(storeImage, "StoreUserImage")
+		assert.True(t, ok)
+		_, ok = c.handler(sendEmail, "SendPasswordReset")
+		assert.False(t, ok, "no handler is added for the topic")
+	})
+
+	t.Run("replaces a subscription to the same queue", func(t *testing.T) {
+		c := newTestConsumer(
+			Subscribe(SubscriptionParams{Queue: amqp.QueueOptionParams{Name: "send-email-queue"}, Workers: 8}),
+			Subscribe(SubscriptionParams{Queue: amqp.QueueOptionParams{Name: "send-email-queue"}, Workers: 4}),
+		)
+
+		require.Len(t, c.subscriptions, 1)
+		assert.Equal(t, 4, c.subscriptions[0].workers)
+	})
+
+	t.Run("has the queues of every subscription in its topology", func(t *testing.T) {
+		c := newTestConsumer(
+			Exchange(amqp.ExchangeOptionParams{Name: "send-email-exchange", Kind: "fanout", Durable: true}),
+			Exchange(amqp.ExchangeOptionParams{Name: "store-image-exchange", Kind: "fanout", Durable: true}),
+			DefaultRetryPolicy(RetryPolicy{MaxRetries: 1, InitialDelay: time.Second, Multiplier: 2}),
+			Subscribe(SubscriptionParams{
+				Queue:    amqp.QueueOptionParams{Name: "send-email-queue", Durable: true},
+				Bindings: []amqp.BindingOptionParams{{Exchange: "send-email-exchange", RoutingKey: "send-email"}},
+			}),
+			Subscribe(SubscriptionParams{
+				Queue:    amqp.QueueOptionParams{Name: "store-image-queue", Durable: true},
+				Bindings: []amqp.BindingOptionParams{{Exchange: "store-image-exchange"}},
+			}),
+		)
+
+		topology := c.topology()
+
+		assert.Equal(t, []amqp.ExchangeOptionParams{
+			{Name: "send-email-exchange", Kind: "fanout", Durable: true},
+			{Name: "store-image-exchange", Kind: "fanout", Durable: true},
+		}, topology.Exchanges)
+		assert.Equal(t, []amqp.QueueOptionParams{
+			{Name: "send-email-queue", Durable: true},
+			{Name: "send-email-queue.parking", Durable: true},
+			{
+				Name:    "send-email-queue.retry.1s",
+				Durable: true,
+				Args:    rabbitmq.Table{"x-message-ttl": int64(1000), "x-dead-letter-exchange": "", "x-dead-letter-routing-key": "send-email-queue"},
+			},
+			{Name: "send-email-queue.dlq", Durable: true},
+			{Name: "store-image-queue", Durable: true},
+			{Name: "store-image-queue.parking", Durable: true},
+			{
+				Name:    "store-image-queue.retry.1s",
+				Durable: true,
+				Args:    rabbitmq.Table{"x-message-ttl": int64(1000), "x-dead-letter-exchange": "", "x-dead-letter-routing-key": "store-image-queue"},
+			},
+			{Name: "store-image-queue.dlq", Durable: true},
+		}, topology.Queues)
+		assert.Equal(t, []amqp.BindingOptionParams{
+			{Queue: "send-email-queue", Exchange: "send-email-exchange", RoutingKey: "send-email"},
+			{Queue: "store-image-queue", Exchange: "store-image-exchange"},
+		}, topology.Bindings)
+	})
+
+	t.Run("does not consume from a queue it does not subscribe to", func(t *testing.T) {
+		c := newTestConsumer(Subscribe(SubscriptionParams{Queue: amqp.QueueOptionParams{Name: "send-email-queue"}}))
+
+		err := c.Consume(context.Background(), "store-image-queue")
+
+		assert.ErrorIs(t, err, ErrNotSubscribed)
+	})
+
+	t.Run("does not start without subscriptions", func(t *testing.T) {
+		c := newTestConsumer()
+
+		assert.ErrorIs(t, c.Start(), ErrNoSubscriptions)
+		assert.NoError(t, c.Stop(context.Background()))
+	})
+}
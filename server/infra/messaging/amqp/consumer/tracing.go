@@ -0,0 +1,38 @@
+package amqpconsumer
+
+import (
+	"context"
+
+	rabbitmq "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the tracer of AMQP consumers
+const tracerName = "github.com/BrianLusina/skillq/server/infra/messaging/amqp/consumer"
+
+// StartDeliverySpan starts a consumer span to handle a delivery in, named after the type of the delivery. The context
+// should be created with DeliveryContext, so that the span is part of the trace the delivery was published in
+func StartDeliverySpan(ctx context.Context, delivery rabbitmq.Delivery) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, delivery.Type+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitmq,
+			semconv.MessagingOperationDeliver,
+			semconv.MessagingDestinationName(delivery.Exchange),
+			semconv.MessagingRabbitmqDestinationRoutingKey(delivery.RoutingKey),
+			semconv.MessagingMessageID(delivery.MessageId),
+		),
+	)
+}
+
+// EndDeliverySpan ends the span a delivery was handled in, recording the error if handling it failed
+func EndDeliverySpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
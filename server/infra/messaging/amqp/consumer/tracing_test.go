@@ -0,0 +1,47 @@
+package amqpconsumer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	rabbitmq "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestDeliverySpan(t *testing.T) {
+	defer otel.SetTracerProvider(otel.GetTracerProvider())
+	defer otel.SetTextMapPropagator(otel.GetTextMapPropagator())
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	log, _ := logger.NewTestLogger()
+	delivery := rabbitmq.Delivery{
+		MessageId: "message-1",
+		Type:      "send_email_verification",
+		Exchange:  "send-email-exchange",
+		Headers:   rabbitmq.Table{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+	}
+
+	_, span := StartDeliverySpan(DeliveryContext(context.Background(), delivery, log), delivery)
+	EndDeliverySpan(span, errors.New("failed to send email"))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "send_email_verification process", spans[0].Name())
+	assert.Equal(t, trace.SpanKindConsumer, spans[0].SpanKind())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("messaging.destination.name", "send-email-exchange"))
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
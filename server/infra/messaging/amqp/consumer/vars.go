@@ -1,30 +1,24 @@
 package amqpconsumer
 
-const (
-	_exchangeKind       = "direct"
-	_exchangeDurable    = true
-	_exchangeAutoDelete = false
-	_exchangeInternal   = false
-	_exchangeNoWait     = false
+import "time"
 
-	_queueDurable    = true
-	_queueAutoDelete = false
-	_queueExclusive  = false
-	_queueNoWait     = false
+const (
+	_consumerTagSuffix = "-consumer"
+	_workerPoolSize    = 24
 
-	_prefetchCount  = 1
-	_prefetchSize   = 0
-	_prefetchGlobal = false
+	_parkingQueueSuffix    = ".parking"
+	_retryQueueSuffix      = ".retry."
+	_deadLetterQueueSuffix = ".dlq"
 
-	_consumeAutoAck   = false
-	_consumeExclusive = false
-	_consumeNoLocal   = false
-	_consumeNoWait    = false
+	_retryMaxRetries   = 3
+	_retryInitialDelay = 5 * time.Second
+	_retryMaxDelay     = 5 * time.Minute
+	_retryMultiplier   = 2
 
-	_queueName   = "skillq-queue"
-	_consumerTag = "skillq-consumer"
+	_republishConfirmTimeout = 5 * time.Second
+	_requeueInitialDelay     = time.Second
+	_requeueMaxDelay         = 30 * time.Second
 
-	_exchangeName   = "skillq-exchange"
-	_bindingKey     = "skillq-routing-key"
-	_workerPoolSize = 24
+	_resubscribeInitialDelay = time.Second
+	_resubscribeMaxDelay     = 30 * time.Second
 )
@@ -3,3 +3,9 @@ package amqp
 import "errors"
 
 var ErrCannotConnectRabbitMQ = errors.New("cannot connect to rabbit")
+
+// ErrConnectionClosed is returned when the connection to the broker has been closed
+var ErrConnectionClosed = errors.New("connection to rabbit is closed")
+
+// ErrNotConnected is returned when the connection to the broker is not re-established in time after it was lost
+var ErrNotConnected = errors.New("not connected to rabbit")
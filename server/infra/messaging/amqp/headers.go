@@ -0,0 +1,53 @@
+package amqp
+
+import (
+	"context"
+
+	"github.com/BrianLusina/skillq/server/infra/messaging"
+	"github.com/BrianLusina/skillq/server/infra/requestid"
+	rabbitmq "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// HeaderRequestID is the message header carrying the ID of the request a message was published while handling
+const HeaderRequestID = messaging.HeaderRequestID
+
+// HeadersFromContext builds the headers of a message published with the context, carrying the request ID of the context
+// if it has one & the trace context of its span, so the message is handled in the same trace it was published in
+func HeadersFromContext(ctx context.Context) rabbitmq.Table {
+	headers := rabbitmq.Table{}
+	if requestID, ok := requestid.FromContext(ctx); ok {
+		headers[HeaderRequestID] = requestID
+	}
+	otel.GetTextMapPropagator().Inject(ctx, headersCarrier(headers))
+	return headers
+}
+
+// ContextWithHeaders returns a copy of the context carrying the trace context from the headers of a message, so spans
+// started from it are part of the trace the message was published in
+func ContextWithHeaders(ctx context.Context, headers rabbitmq.Table) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, headersCarrier(headers))
+}
+
+// headersCarrier carries trace context in the headers of a message
+type headersCarrier rabbitmq.Table
+
+var _ propagation.TextMapCarrier = headersCarrier(nil)
+
+func (c headersCarrier) Get(key string) string {
+	value, _ := c[key].(string)
+	return value
+}
+
+func (c headersCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c headersCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
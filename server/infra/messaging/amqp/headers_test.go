@@ -0,0 +1,49 @@
+package amqp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/BrianLusina/skillq/server/infra/requestid"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestHeadersFromContext(t *testing.T) {
+	t.Run("context with request ID", func(t *testing.T) {
+		ctx := requestid.WithRequestID(context.Background(), "request-1")
+
+		headers := HeadersFromContext(ctx)
+
+		assert.Equal(t, "request-1", headers[HeaderRequestID])
+	})
+
+	t.Run("context without request ID", func(t *testing.T) {
+		headers := HeadersFromContext(context.Background())
+
+		assert.NotContains(t, headers, HeaderRequestID)
+	})
+}
+
+func TestTraceContextHeaders(t *testing.T) {
+	defer otel.SetTextMapPropagator(otel.GetTextMapPropagator())
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	provider := sdktrace.NewTracerProvider()
+	defer func() {
+		assert.NoError(t, provider.Shutdown(context.Background()))
+	}()
+
+	ctx, span := provider.Tracer("test").Start(context.Background(), "publish")
+	defer span.End()
+
+	headers := HeadersFromContext(ctx)
+	assert.Contains(t, headers, "traceparent")
+
+	extracted := trace.SpanContextFromContext(ContextWithHeaders(context.Background(), headers))
+	assert.True(t, extracted.IsRemote())
+	assert.Equal(t, span.SpanContext().TraceID(), extracted.TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), extracted.SpanID())
+}
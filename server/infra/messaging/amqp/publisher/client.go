@@ -2,13 +2,15 @@ package amqppublisher
 
 import (
 	"context"
-	"fmt"
+	"time"
 
 	"github.com/BrianLusina/skillq/server/infra/logger"
 	"github.com/BrianLusina/skillq/server/infra/messaging"
 	"github.com/BrianLusina/skillq/server/infra/messaging/amqp"
 	"github.com/pkg/errors"
 	rabbitmq "github.com/rabbitmq/amqp091-go"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // amqpPublisherClient handles defines the methods used to handle publication of messages to a topic on a broker
@@ -25,9 +27,18 @@ type amqpPublisherClient struct {
 	messageTypeName    string
 	publishMandatory   bool
 	publishImmediate   bool
+	routes             map[string]route
+	confirmTimeout     time.Duration
+	pool               *channelPool
 	logger             logger.Logger
 }
 
+// route is the exchange & routing key messages are published to
+type route struct {
+	exchange   string
+	routingKey string
+}
+
 // NewPublisher creates a new AMQP Publisher
 func NewPublisher(client *amqp.AmqpClient, log logger.Logger) (AmqpEventPublisher, error) {
 	publisher := &amqpPublisherClient{
@@ -38,38 +49,55 @@ func NewPublisher(client *amqp.AmqpClient, log logger.Logger) (AmqpEventPublishe
 		messageTypeName:  _messageTypeName,
 		publishMandatory: _publishMandatory,
 		publishImmediate: _publishImmediate,
+		routes:           map[string]route{},
+		confirmTimeout:   _confirmTimeout,
+		pool:             newChannelPool(client, _channelPoolSize),
 	}
 
 	return publisher, nil
 }
 
-// Publish publishes a message to a given topic
+// route is the route of the messages of a topic, which is the exchange & binding key of the publisher unless the topic is
+// routed elsewhere
+func (p *amqpPublisherClient) route(topic string) route {
+	if to, ok := p.routes[topic]; ok {
+		return to
+	}
+	return route{exchange: p.exchangeName, routingKey: p.bindingKey}
+}
+
+// Publish publishes a message to the exchange its topic is routed to & waits for the broker to confirm it within the confirm timeout. A
+// *PublishError is returned if the broker does not confirm it
 func (p *amqpPublisherClient) Publish(ctx context.Context, message messaging.Message) error {
+	log := logger.FromContextOr(ctx, p.logger)
+
 	body, err := message.PayloadToBytes()
 	if err != nil {
-		p.logger.Errorf("Failed to parse message: %v", err)
+		log.Errorf("Failed to parse message: %v", err)
 		return errors.Wrapf(err, "failed to parse message event")
 	}
 
-	amqpChan, err := p.client.AmqpConn.Channel()
+	// while the connection is down, publishing waits for it to be re-established within the channel timeout of the client
+	ch, err := p.pool.get(ctx)
 	if err != nil {
-		p.logger.Errorf("Failed to open channel: %v", err)
-		return fmt.Errorf("failed to open a channel: %w", err)
+		log.Errorf("Failed to open channel: %v", err)
+		return err
 	}
 
-	defer func() {
-		err := amqpChan.Close()
-		if err != nil {
-			p.logger.Errorf("Failed to close channel with error %v", err)
-		}
-	}()
+	to := p.route(message.Topic)
 
-	p.logger.Infof("Publishing message %v to exchange: %s, with routingKey: %s", message, p.exchangeName, p.bindingKey)
+	log.Infof("Publishing message %v to exchange: %s, with routingKey: %s", message, to.exchange, to.routingKey)
+
+	// the span the message is published in, if it is traced, records where it is published to
+	trace.SpanFromContext(ctx).SetAttributes(
+		semconv.MessagingDestinationName(to.exchange),
+		semconv.MessagingRabbitmqDestinationRoutingKey(to.routingKey),
+	)
 
-	err = amqpChan.PublishWithContext(
+	confirmation, err := ch.channel.PublishWithDeferredConfirmWithContext(
 		ctx,
-		p.exchangeName,
-		p.bindingKey,
+		to.exchange,
+		to.routingKey,
 		p.publishMandatory,
 		p.publishImmediate,
 		rabbitmq.Publishing{
@@ -79,20 +107,45 @@ func (p *amqpPublisherClient) Publish(ctx context.Context, message messaging.Mes
 			Timestamp:    message.Timestamp,
 			Body:         body,
 			Type:         message.Topic,
+			Headers:      amqp.HeadersFromContext(ctx),
 		},
 	)
 	if err != nil {
-		p.logger.Errorf("Failed to publish message to exchange %s with error: %v", p.exchangeName, err)
+		p.pool.discard(ch)
+		log.Errorf("Failed to publish message to exchange %s with error: %v", to.exchange, err)
 		return errors.Wrapf(err, "failed to publish message: %v", err)
 	}
 
-	p.logger.Infof("Successfully published message %v to exchange: %s, with routingKey: %s", message, p.exchangeName, p.bindingKey)
+	if err := p.awaitConfirmation(ctx, ch, confirmation, message.ID, to); err != nil {
+		if reusable(err) {
+			p.pool.put(ch)
+		} else {
+			p.pool.discard(ch)
+		}
+
+		log.Errorf("Broker did not confirm message: %v", err)
+		return err
+	}
+
+	p.pool.put(ch)
+
+	log.Infof("Successfully published message %v to exchange: %s, with routingKey: %s", message, to.exchange, to.routingKey)
 
 	return nil
 }
 
-// Close closes connection to a broker
+// reusable checks if the channel a message failed to be published on can be returned to the pool. Only the channel of a
+// message that was returned as unroutable is reused, as its return has been taken off the channel. A message that is not
+// confirmed in time may still be confirmed or returned on its channel, while the return of a nacked message may be left
+// on its channel, where it would be taken for the return of the next message published on the channel
+func reusable(err error) bool {
+	return errors.Is(err, ErrUnroutable)
+}
+
+// Close closes the channels of the publisher & the connection to a broker
 func (p *amqpPublisherClient) Close() error {
+	p.pool.close()
+
 	if err := p.client.Close(); err != nil {
 		p.logger.Errorf("Failed to close client connection with error %v", err)
 		return err
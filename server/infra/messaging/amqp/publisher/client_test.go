@@ -0,0 +1,34 @@
+package amqppublisher
+
+import (
+	"testing"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/messaging/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoute(t *testing.T) {
+	log, _ := logger.NewTestLogger()
+	publisher, err := NewPublisher(nil, log)
+	require.NoError(t, err)
+
+	p := publisher.Configure(
+		Exchange(amqp.ExchangeOptionParams{Name: "skillq-exchange"}),
+		BindingKey("skillq-routing-key"),
+		Route("SendEmailVerification", "send-email-exchange", "send-email-routing-key"),
+		Route("StoreUserImage", "store-image-exchange", ""),
+	).(*amqpPublisherClient)
+
+	assert.Equal(t, route{exchange: "send-email-exchange", routingKey: "send-email-routing-key"}, p.route("SendEmailVerification"))
+	assert.Equal(t, route{exchange: "store-image-exchange"}, p.route("StoreUserImage"))
+	assert.Equal(t, route{exchange: "skillq-exchange", routingKey: "skillq-routing-key"}, p.route("SendSms"))
+}
+
+func TestReusable(t *testing.T) {
+	assert.True(t, reusable(&PublishError{Err: ErrUnroutable}))
+	assert.False(t, reusable(&PublishError{Err: ErrNacked}))
+	assert.False(t, reusable(&PublishError{Err: ErrConfirmTimeout}))
+	assert.False(t, reusable(&PublishError{Err: ErrChannelClosed}))
+}
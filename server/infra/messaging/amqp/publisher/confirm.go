@@ -0,0 +1,66 @@
+package amqppublisher
+
+import (
+	"context"
+	"fmt"
+
+	rabbitmq "github.com/rabbitmq/amqp091-go"
+)
+
+// confirmation is the confirmation of a message by the broker
+type confirmation interface {
+	Done() <-chan struct{}
+	Acked() bool
+}
+
+var _ confirmation = (*rabbitmq.DeferredConfirmation)(nil)
+
+// awaitConfirmation waits for the broker to confirm a message published on the channel within the confirm timeout,
+// returning a *PublishError if it is nacked, returned, not confirmed in time or the channel closes before it is confirmed
+func (p *amqpPublisherClient) awaitConfirmation(ctx context.Context, ch *confirmChannel, confirmation confirmation, messageID string, to route) error {
+	ctx, cancel := context.WithTimeout(ctx, p.confirmTimeout)
+	defer cancel()
+
+	publishErr := &PublishError{
+		MessageID:  messageID,
+		Exchange:   to.exchange,
+		RoutingKey: to.routingKey,
+	}
+
+	select {
+	case <-confirmation.Done():
+	case <-ctx.Done():
+		publishErr.Err = fmt.Errorf("%w: %w", ErrConfirmTimeout, ctx.Err())
+		return publishErr
+	}
+
+	if !confirmation.Acked() {
+		// the broker closes the channel when the message cannot be published, such as when the exchange does not exist,
+		// & the close is sent before the messages waiting for a confirmation on the channel are nacked
+		select {
+		case chanErr := <-ch.closes:
+			if chanErr != nil {
+				publishErr.ReplyCode = uint16(chanErr.Code)
+				publishErr.ReplyText = chanErr.Reason
+				publishErr.Err = ErrChannelClosed
+				return publishErr
+			}
+		default:
+		}
+
+		publishErr.Err = ErrNacked
+		return publishErr
+	}
+
+	// a mandatory message that is not routed to any queue is returned before it is acked
+	select {
+	case returned := <-ch.returns:
+		publishErr.ReplyCode = returned.ReplyCode
+		publishErr.ReplyText = returned.ReplyText
+		publishErr.Err = ErrUnroutable
+		return publishErr
+	default:
+	}
+
+	return nil
+}
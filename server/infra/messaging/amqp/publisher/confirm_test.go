@@ -0,0 +1,95 @@
+package amqppublisher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	rabbitmq "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeConfirmation is a confirmation that is confirmed when the test says so
+type fakeConfirmation struct {
+	done  chan struct{}
+	acked bool
+}
+
+func confirmed(acked bool) *fakeConfirmation {
+	c := &fakeConfirmation{done: make(chan struct{}), acked: acked}
+	close(c.done)
+	return c
+}
+
+func (c *fakeConfirmation) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *fakeConfirmation) Acked() bool {
+	return c.acked
+}
+
+func newConfirmChannel() *confirmChannel {
+	return &confirmChannel{
+		returns: make(chan rabbitmq.Return, 1),
+		closes:  make(chan *rabbitmq.Error, 1),
+	}
+}
+
+func TestAwaitConfirmation(t *testing.T) {
+	publisher := &amqpPublisherClient{confirmTimeout: 20 * time.Millisecond}
+	to := route{exchange: "send-email-exchange", routingKey: "send-email-routing-key"}
+	ctx := context.Background()
+
+	t.Run("acked", func(t *testing.T) {
+		err := publisher.awaitConfirmation(ctx, newConfirmChannel(), confirmed(true), "message-1", to)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("nacked", func(t *testing.T) {
+		err := publisher.awaitConfirmation(ctx, newConfirmChannel(), confirmed(false), "message-1", to)
+
+		var publishErr *PublishError
+		require.True(t, errors.As(err, &publishErr))
+		assert.ErrorIs(t, err, ErrNacked)
+		assert.Equal(t, "message-1", publishErr.MessageID)
+		assert.Equal(t, "send-email-exchange", publishErr.Exchange)
+		assert.Equal(t, "send-email-routing-key", publishErr.RoutingKey)
+	})
+
+	t.Run("returned as unroutable", func(t *testing.T) {
+		ch := newConfirmChannel()
+		ch.returns <- rabbitmq.Return{ReplyCode: rabbitmq.NoRoute, ReplyText: "NO_ROUTE", MessageId: "message-1"}
+
+		err := publisher.awaitConfirmation(ctx, ch, confirmed(true), "message-1", to)
+
+		var publishErr *PublishError
+		require.True(t, errors.As(err, &publishErr))
+		assert.ErrorIs(t, err, ErrUnroutable)
+		assert.Equal(t, uint16(rabbitmq.NoRoute), publishErr.ReplyCode)
+		assert.Equal(t, "NO_ROUTE", publishErr.ReplyText)
+	})
+
+	t.Run("channel closed because the exchange does not exist", func(t *testing.T) {
+		ch := newConfirmChannel()
+		ch.closes <- &rabbitmq.Error{Code: rabbitmq.NotFound, Reason: "NOT_FOUND - no exchange 'send-email-exchange'"}
+
+		err := publisher.awaitConfirmation(ctx, ch, confirmed(false), "message-1", to)
+
+		var publishErr *PublishError
+		require.True(t, errors.As(err, &publishErr))
+		assert.ErrorIs(t, err, ErrChannelClosed)
+		assert.Equal(t, uint16(rabbitmq.NotFound), publishErr.ReplyCode)
+		assert.Contains(t, err.Error(), "no exchange 'send-email-exchange'")
+	})
+
+	t.Run("not confirmed in time", func(t *testing.T) {
+		err := publisher.awaitConfirmation(ctx, newConfirmChannel(), &fakeConfirmation{done: make(chan struct{})}, "message-1", to)
+
+		assert.ErrorIs(t, err, ErrConfirmTimeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
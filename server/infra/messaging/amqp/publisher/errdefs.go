@@ -1,5 +1,50 @@
 package amqppublisher
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var ErrCannotConnectRabbitMQ = errors.New("cannot connect to rabbit")
+
+var (
+	// ErrNacked is the reason a message was not published when the broker rejects it
+	ErrNacked = errors.New("message was nacked by the broker")
+
+	// ErrUnroutable is the reason a mandatory message was not published when the broker returns it, because it was not
+	// routed to any queue
+	ErrUnroutable = errors.New("message was returned by the broker as unroutable")
+
+	// ErrConfirmTimeout is the reason a message may not have been published when the broker does not confirm it in time
+	ErrConfirmTimeout = errors.New("broker did not confirm the message in time")
+
+	// ErrChannelClosed is the reason a message was not published when the channel it was published on is closed before
+	// the broker confirms it, such as when the exchange it is published to does not exist
+	ErrChannelClosed = errors.New("channel closed before the message was confirmed")
+)
+
+// PublishError is returned when the broker does not confirm that a message was published. Err is the reason, which is
+// one of ErrNacked, ErrUnroutable, ErrConfirmTimeout or ErrChannelClosed once the message has been sent to the broker
+type PublishError struct {
+	MessageID  string
+	Exchange   string
+	RoutingKey string
+
+	// ReplyCode & ReplyText are the reason the broker gave for returning the message or closing the channel
+	ReplyCode uint16
+	ReplyText string
+
+	Err error
+}
+
+func (e *PublishError) Error() string {
+	msg := fmt.Sprintf("failed to publish message %s to exchange %s with routing key %s: %v", e.MessageID, e.Exchange, e.RoutingKey, e.Err)
+	if e.ReplyText != "" {
+		msg = fmt.Sprintf("%s (%d %s)", msg, e.ReplyCode, e.ReplyText)
+	}
+	return msg
+}
+
+func (e *PublishError) Unwrap() error {
+	return e.Err
+}
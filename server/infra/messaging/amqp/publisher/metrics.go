@@ -0,0 +1,75 @@
+package amqppublisher
+
+import (
+	"context"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/messaging"
+	"github.com/BrianLusina/skillq/server/infra/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics are the metrics of the messages published to an AMQP broker
+type Metrics struct {
+	published *prometheus.CounterVec
+	duration  *prometheus.HistogramVec
+}
+
+// NewMetrics creates the metrics of published messages & registers them with the registerer
+func NewMetrics(registerer prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		published: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "amqp",
+			Name:      "published_messages_total",
+			Help:      "Number of messages published by topic & outcome.",
+		}, []string{"topic", "outcome"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "amqp",
+			Name:      "publish_duration_seconds",
+			Help:      "Duration of publishing messages by topic.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"topic"}),
+	}
+
+	registerer.MustRegister(m.published, m.duration)
+
+	return m
+}
+
+// instrumentedPublisher decorates an AMQP publisher, recording metrics of the messages it publishes
+type instrumentedPublisher struct {
+	publisher AmqpEventPublisher
+	metrics   *Metrics
+}
+
+// NewInstrumentedPublisher decorates an AMQP publisher with metrics
+func NewInstrumentedPublisher(publisher AmqpEventPublisher, m *Metrics) AmqpEventPublisher {
+	return &instrumentedPublisher{
+		publisher: publisher,
+		metrics:   m,
+	}
+}
+
+// Publish publishes a message with the decorated publisher, recording its outcome & duration by the topic of the message
+func (p *instrumentedPublisher) Publish(ctx context.Context, message messaging.Message) error {
+	start := time.Now()
+	err := p.publisher.Publish(ctx, message)
+
+	p.metrics.published.WithLabelValues(message.Topic, metrics.Outcome(err)).Inc()
+	p.metrics.duration.WithLabelValues(message.Topic).Observe(time.Since(start).Seconds())
+
+	return err
+}
+
+// Close closes the decorated publisher
+func (p *instrumentedPublisher) Close() error {
+	return p.publisher.Close()
+}
+
+// Configure configures the decorated publisher, keeping it decorated
+func (p *instrumentedPublisher) Configure(opts ...Option) AmqpEventPublisher {
+	p.publisher.Configure(opts...)
+	return p
+}
@@ -0,0 +1,47 @@
+package amqppublisher_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/BrianLusina/skillq/server/infra/messaging"
+	amqppublisher "github.com/BrianLusina/skillq/server/infra/messaging/amqp/publisher"
+	mockamqppublisher "github.com/BrianLusina/skillq/server/infra/messaging/amqp/publisher/mocks"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestInstrumentedPublisher(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	mockPublisher := mockamqppublisher.NewMockAmqpEventPublisher(mockCtrl)
+
+	registry := prometheus.NewRegistry()
+	publisher := amqppublisher.NewInstrumentedPublisher(mockPublisher, amqppublisher.NewMetrics(registry))
+
+	sent := messaging.New(messaging.MessageParams{Topic: "send_email_verification"})
+	failed := messaging.New(messaging.MessageParams{Topic: "store_user_image"})
+
+	mockPublisher.EXPECT().Publish(ctx, sent).Return(nil).Times(2)
+	mockPublisher.EXPECT().Publish(ctx, failed).Return(errors.New("channel closed")).Times(1)
+	mockPublisher.EXPECT().Configure(gomock.Any()).Return(mockPublisher).Times(1)
+
+	assert.NoError(t, publisher.Publish(ctx, sent))
+	assert.NoError(t, publisher.Publish(ctx, sent))
+	assert.Error(t, publisher.Publish(ctx, failed))
+
+	// configuring the publisher keeps it decorated
+	assert.Equal(t, publisher, publisher.Configure(amqppublisher.BindingKey("send-email-routing-key")))
+
+	expected := `
+# HELP skillq_amqp_published_messages_total Number of messages published by topic & outcome.
+# TYPE skillq_amqp_published_messages_total counter
+skillq_amqp_published_messages_total{outcome="error",topic="store_user_image"} 1
+skillq_amqp_published_messages_total{outcome="success",topic="send_email_verification"} 2
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "skillq_amqp_published_messages_total"))
+}
@@ -1,6 +1,10 @@
 package amqppublisher
 
-import "github.com/BrianLusina/skillq/server/infra/messaging/amqp"
+import (
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/messaging/amqp"
+)
 
 // Option allows adding options to the AMQP publisher
 type Option func(*amqpPublisherClient)
@@ -32,9 +36,37 @@ func BindingKey(bindingKey string) Option {
 	}
 }
 
+// Route routes the messages of a topic to an exchange with a routing key, rather than to the exchange & with the binding
+// key of the publisher
+func Route(topic, exchange, routingKey string) Option {
+	return func(p *amqpPublisherClient) {
+		p.routes[topic] = route{exchange: exchange, routingKey: routingKey}
+	}
+}
+
 // MessageTypeName adds the name of the type of the message
 func MessageTypeName(messageTypeName string) Option {
 	return func(p *amqpPublisherClient) {
 		p.messageTypeName = messageTypeName
 	}
 }
+
+// ConfirmTimeout sets how long publishing a message waits for the broker to confirm it. A timeout that is not positive
+// keeps the default
+func ConfirmTimeout(timeout time.Duration) Option {
+	return func(p *amqpPublisherClient) {
+		if timeout > 0 {
+			p.confirmTimeout = timeout
+		}
+	}
+}
+
+// ChannelPoolSize sets the number of idle channels the publisher keeps open between messages. A size that is not
+// positive keeps the default
+func ChannelPoolSize(size int) Option {
+	return func(p *amqpPublisherClient) {
+		if size > 0 {
+			p.pool.size = size
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package amqppublisher
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/BrianLusina/skillq/server/infra/messaging/amqp"
+	rabbitmq "github.com/rabbitmq/amqp091-go"
+)
+
+// confirmChannel is a channel in confirm mode, with the messages the broker returns & the error it is closed with
+type confirmChannel struct {
+	channel *rabbitmq.Channel
+	returns chan rabbitmq.Return
+	closes  chan *rabbitmq.Error
+}
+
+// channelPool keeps the channels messages are published on open between messages, so that a channel is not opened for
+// every message. A channel is only used by one publish at a time, so that the messages returned on it & the errors it is
+// closed with are those of the message published on it
+type channelPool struct {
+	client *amqp.AmqpClient
+	size   int
+
+	mu   sync.Mutex
+	idle []*confirmChannel
+}
+
+// newChannelPool creates a pool that keeps up to size idle channels
+func newChannelPool(client *amqp.AmqpClient, size int) *channelPool {
+	return &channelPool{
+		client: client,
+		size:   size,
+	}
+}
+
+// get takes an idle channel from the pool, opening a new channel if there is none
+func (p *channelPool) get(ctx context.Context) (*confirmChannel, error) {
+	p.mu.Lock()
+	for len(p.idle) > 0 {
+		ch := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+
+		// channels are closed while idle when the connection is lost
+		if !ch.channel.IsClosed() {
+			p.mu.Unlock()
+			return ch, nil
+		}
+	}
+	p.mu.Unlock()
+
+	return p.open(ctx)
+}
+
+// open opens a channel & puts it in confirm mode
+func (p *channelPool) open(ctx context.Context) (*confirmChannel, error) {
+	channel, err := p.client.Channel(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open a channel: %w", err)
+	}
+
+	if err := channel.Confirm(false); err != nil {
+		_ = channel.Close()
+		return nil, fmt.Errorf("failed to put channel in confirm mode: %w", err)
+	}
+
+	// a channel publishes one message at a time, so at most one message is returned before the broker confirms it
+	return &confirmChannel{
+		channel: channel,
+		returns: channel.NotifyReturn(make(chan rabbitmq.Return, 1)),
+		closes:  channel.NotifyClose(make(chan *rabbitmq.Error, 1)),
+	}, nil
+}
+
+// put returns a channel to the pool once the broker has confirmed the message published on it. It is closed instead if
+// it is closed already or the pool is full
+func (p *channelPool) put(ch *confirmChannel) {
+	if ch.channel.IsClosed() {
+		return
+	}
+
+	p.mu.Lock()
+	if len(p.idle) < p.size {
+		p.idle = append(p.idle, ch)
+		p.mu.Unlock()
+		return
+	}
+	p.mu.Unlock()
+
+	p.discard(ch)
+}
+
+// discard closes a channel rather than returning it to the pool, such as when the broker did not confirm the message
+// published on it in time & may still confirm or return it
+func (p *channelPool) discard(ch *confirmChannel) {
+	_ = ch.channel.Close()
+}
+
+// close closes the idle channels of the pool
+func (p *channelPool) close() {
+	p.mu.Lock()
+	idle := p.idle
+	p.idle = nil
+	p.mu.Unlock()
+
+	for _, ch := range idle {
+		_ = ch.channel.Close()
+	}
+}
@@ -0,0 +1,60 @@
+package amqppublisher
+
+import (
+	"context"
+
+	"github.com/BrianLusina/skillq/server/infra/messaging"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the tracer of AMQP publishers
+const tracerName = "github.com/BrianLusina/skillq/server/infra/messaging/amqp/publisher"
+
+// tracedPublisher decorates an AMQP publisher, tracing the messages it publishes in producer spans
+type tracedPublisher struct {
+	publisher AmqpEventPublisher
+	tracer    trace.Tracer
+}
+
+// NewTracedPublisher decorates an AMQP publisher with spans created by the tracer provider. The trace context of the span
+// is carried in the headers of the published message, so it is handled in the same trace
+func NewTracedPublisher(publisher AmqpEventPublisher, tracerProvider trace.TracerProvider) AmqpEventPublisher {
+	return &tracedPublisher{
+		publisher: publisher,
+		tracer:    tracerProvider.Tracer(tracerName),
+	}
+}
+
+// Publish publishes a message with the decorated publisher in a span named after the topic of the message
+func (p *tracedPublisher) Publish(ctx context.Context, message messaging.Message) error {
+	ctx, span := p.tracer.Start(ctx, message.Topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitmq,
+			semconv.MessagingOperationPublish,
+			semconv.MessagingMessageID(message.ID),
+		),
+	)
+	defer span.End()
+
+	err := p.publisher.Publish(ctx, message)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+// Close closes the decorated publisher
+func (p *tracedPublisher) Close() error {
+	return p.publisher.Close()
+}
+
+// Configure configures the decorated publisher, keeping it decorated
+func (p *tracedPublisher) Configure(opts ...Option) AmqpEventPublisher {
+	p.publisher.Configure(opts...)
+	return p
+}
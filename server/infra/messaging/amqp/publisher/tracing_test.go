@@ -0,0 +1,57 @@
+package amqppublisher_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/BrianLusina/skillq/server/infra/messaging"
+	amqppublisher "github.com/BrianLusina/skillq/server/infra/messaging/amqp/publisher"
+	mockamqppublisher "github.com/BrianLusina/skillq/server/infra/messaging/amqp/publisher/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTracedPublisher(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	mockPublisher := mockamqppublisher.NewMockAmqpEventPublisher(mockCtrl)
+
+	recorder := tracetest.NewSpanRecorder()
+	publisher := amqppublisher.NewTracedPublisher(mockPublisher, sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	sent := messaging.New(messaging.MessageParams{Topic: "send_email_verification"})
+	failed := messaging.New(messaging.MessageParams{Topic: "store_user_image"})
+
+	mockPublisher.EXPECT().Publish(gomock.Any(), sent).
+		DoAndReturn(func(ctx context.Context, message messaging.Message) error {
+			// the message is published in the span, so its headers carry the trace context of the span
+			assert.True(t, trace.SpanContextFromContext(ctx).IsValid())
+			return nil
+		}).Times(1)
+	mockPublisher.EXPECT().Publish(gomock.Any(), failed).Return(errors.New("channel closed")).Times(1)
+	mockPublisher.EXPECT().Configure(gomock.Any()).Return(mockPublisher).Times(1)
+
+	assert.NoError(t, publisher.Publish(ctx, sent))
+	assert.Error(t, publisher.Publish(ctx, failed))
+
+	// configuring the publisher keeps it decorated
+	assert.Equal(t, publisher, publisher.Configure(amqppublisher.BindingKey("send-email-routing-key")))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+
+	assert.Equal(t, "send_email_verification publish", spans[0].Name())
+	assert.Equal(t, trace.SpanKindProducer, spans[0].SpanKind())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("messaging.system", "rabbitmq"))
+	assert.Contains(t, spans[0].Attributes(), attribute.String("messaging.message.id", sent.ID))
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+
+	assert.Equal(t, "store_user_image publish", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
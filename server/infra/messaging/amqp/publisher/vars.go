@@ -1,5 +1,7 @@
 package amqppublisher
 
+import "time"
+
 const (
 	_publishMandatory = false
 	_publishImmediate = false
@@ -8,4 +10,7 @@ const (
 	_bindingKey       = "skillq-routing-key"
 
 	_messageTypeName = "skillq"
+
+	_confirmTimeout  = 5 * time.Second
+	_channelPoolSize = 8
 )
@@ -0,0 +1,101 @@
+package amqp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	rabbitmq "github.com/rabbitmq/amqp091-go"
+)
+
+// BindingOptionParams are the parameters for binding a queue to an exchange
+type BindingOptionParams struct {
+	Queue      string
+	Exchange   string
+	RoutingKey string
+	NoWait     bool
+	Args       map[string]any
+}
+
+// Topology is the exchanges & queues declared on a broker, with the bindings that route the messages of the exchanges to
+// the queues
+type Topology struct {
+	Exchanges []ExchangeOptionParams
+	Queues    []QueueOptionParams
+	Bindings  []BindingOptionParams
+}
+
+// topologyChannel is a channel a topology is declared on
+type topologyChannel interface {
+	ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args rabbitmq.Table) error
+	QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args rabbitmq.Table) (rabbitmq.Queue, error)
+	QueueBind(name, key, exchange string, noWait bool, args rabbitmq.Table) error
+}
+
+// DeclareTopology declares a topology on the broker on a channel of its own & keeps it, so that it is declared again
+// whenever the connection is re-established. Declaring is idempotent, so the parts of the topology that are already
+// declared are left as they are, but declaring an exchange or queue that exists with other arguments fails
+func (c *AmqpClient) DeclareTopology(ctx context.Context, topology Topology) error {
+	c.mu.Lock()
+	c.topology = &topology
+	c.mu.Unlock()
+
+	ch, err := c.Channel(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open channel to declare topology: %w", err)
+	}
+
+	defer func() {
+		if err := ch.Close(); err != nil {
+			c.logger.Errorf("Failed to close channel the topology was declared on: %v", err)
+		}
+	}()
+
+	return declareTopology(ch, topology, c.logger)
+}
+
+// declareOnConnection declares a topology on a channel of its own on the connection
+func declareOnConnection(conn connection, topology Topology, log logger.Logger) error {
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open channel to declare topology: %w", err)
+	}
+
+	defer func() {
+		if err := ch.Close(); err != nil {
+			log.Errorf("Failed to close channel the topology was declared on: %v", err)
+		}
+	}()
+
+	return declareTopology(ch, topology, log)
+}
+
+// declareTopology declares the exchanges, then the queues & then the bindings of a topology, so that the exchange & queue
+// of every binding exist when it is declared
+func declareTopology(ch topologyChannel, topology Topology, log logger.Logger) error {
+	for _, exchange := range topology.Exchanges {
+		log.Infof("Declaring exchange: %s", exchange.Name)
+		err := ch.ExchangeDeclare(exchange.Name, exchange.Kind, exchange.Durable, exchange.AutoDelete, exchange.Internal, exchange.NoWait, exchange.Args)
+		if err != nil {
+			return fmt.Errorf("failed to declare exchange %s: %w", exchange.Name, err)
+		}
+	}
+
+	for _, queue := range topology.Queues {
+		log.Infof("Declaring queue: %s", queue.Name)
+		_, err := ch.QueueDeclare(queue.Name, queue.Durable, queue.AutoDelete, queue.Exclusive, queue.NoWait, queue.Args)
+		if err != nil {
+			return fmt.Errorf("failed to declare queue %s: %w", queue.Name, err)
+		}
+	}
+
+	for _, binding := range topology.Bindings {
+		log.Infof("Binding queue %s to exchange %s with routing key %s", binding.Queue, binding.Exchange, binding.RoutingKey)
+		err := ch.QueueBind(binding.Queue, binding.RoutingKey, binding.Exchange, binding.NoWait, binding.Args)
+		if err != nil {
+			return fmt.Errorf("failed to bind queue %s to exchange %s: %w", binding.Queue, binding.Exchange, err)
+		}
+	}
+
+	return nil
+}
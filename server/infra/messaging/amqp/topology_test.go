@@ -0,0 +1,77 @@
+package amqp
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	rabbitmq "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingChannel records the topology declared on it, failing to declare the queue named failQueue
+type recordingChannel struct {
+	failQueue string
+	declared  []string
+}
+
+func (r *recordingChannel) ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args rabbitmq.Table) error {
+	r.declared = append(r.declared, "exchange "+name+" "+kind)
+	return nil
+}
+
+func (r *recordingChannel) QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args rabbitmq.Table) (rabbitmq.Queue, error) {
+	if name == r.failQueue {
+		return rabbitmq.Queue{}, errors.New("PRECONDITION_FAILED")
+	}
+	r.declared = append(r.declared, "queue "+name)
+	return rabbitmq.Queue{Name: name}, nil
+}
+
+func (r *recordingChannel) QueueBind(name, key, exchange string, noWait bool, args rabbitmq.Table) error {
+	r.declared = append(r.declared, "binding "+exchange+" "+key+" "+name)
+	return nil
+}
+
+func TestDeclareTopology(t *testing.T) {
+	log, _ := logger.NewTestLogger()
+	topology := Topology{
+		Exchanges: []ExchangeOptionParams{
+			{Name: "send-email-exchange", Kind: "fanout", Durable: true},
+			{Name: "store-image-exchange", Kind: "direct", Durable: true},
+		},
+		Queues: []QueueOptionParams{
+			{Name: "send-email-queue", Durable: true},
+			{Name: "store-image-queue", Durable: true},
+		},
+		Bindings: []BindingOptionParams{
+			{Queue: "send-email-queue", Exchange: "send-email-exchange"},
+			{Queue: "store-image-queue", Exchange: "store-image-exchange", RoutingKey: "store-image"},
+		},
+	}
+
+	t.Run("declares the exchanges & queues before binding them", func(t *testing.T) {
+		ch := &recordingChannel{}
+
+		err := declareTopology(ch, topology, log)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"exchange send-email-exchange fanout",
+			"exchange store-image-exchange direct",
+			"queue send-email-queue",
+			"queue store-image-queue",
+			"binding send-email-exchange  send-email-queue",
+			"binding store-image-exchange store-image store-image-queue",
+		}, ch.declared)
+	})
+
+	t.Run("stops at the first part that fails to be declared", func(t *testing.T) {
+		ch := &recordingChannel{failQueue: "send-email-queue"}
+
+		err := declareTopology(ch, topology, log)
+
+		assert.ErrorContains(t, err, "failed to declare queue send-email-queue")
+		assert.Equal(t, []string{"exchange send-email-exchange fanout", "exchange store-image-exchange direct"}, ch.declared)
+	})
+}
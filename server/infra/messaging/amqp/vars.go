@@ -1,6 +1,14 @@
 package amqp
 
+import "time"
+
 const (
-	_retryTimes     = 5
-	_backOffSeconds = 2
+	_retryTimes = 5
+
+	_reconnectInitialDelay = 1 * time.Second
+	_reconnectMaxDelay     = 30 * time.Second
+	_channelTimeout        = 5 * time.Second
+
+	_defaultPort    = 5672
+	_defaultTLSPort = 5671
 )
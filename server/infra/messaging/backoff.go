@@ -0,0 +1,33 @@
+package messaging
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff is an exponential backoff with jitter for retrying an operation, such as reconnecting to a broker
+type Backoff struct {
+	// InitialDelay is the delay before the first retry, which doubles with every retry up to MaxDelay
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+}
+
+// Delay returns the delay before a retry, given the number of retries made before it. Half of the delay is random, so
+// that clients that lost their connection at the same time do not all retry at the same time
+func (b Backoff) Delay(retries int) time.Duration {
+	delay := b.InitialDelay
+	for i := 0; i < retries && delay < b.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > b.MaxDelay {
+		delay = b.MaxDelay
+	}
+
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
@@ -0,0 +1,19 @@
+package messaging
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	backoff := Backoff{InitialDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for retries, expected := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		delay := backoff.Delay(retries)
+
+		assert.GreaterOrEqual(t, delay, expected/2, "retries %d", retries)
+		assert.LessOrEqual(t, delay, expected, "retries %d", retries)
+	}
+}
@@ -1,12 +1,41 @@
 package messaging
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrInvalidPayload is returned by handlers for a payload that can not be decoded. Handling the message again would fail
+// the same way, so it is not handled again
+var ErrInvalidPayload = errors.New("invalid message payload")
+
+// Handler handles the payload of a message consumed from a topic
+type Handler func(ctx context.Context, payload []byte) error
 
 // EventConsumer defines a consumer that handles consumption of messages from a Broker
 type EventConsumer interface {
 	// Consumes a message from a given queue. This is mostly a blocking operation
 	Consume(ctx context.Context, queue string) error
 
-	// AddHandler adds a handler that will handle consumption of messages from a queue
-	AddHandler(ctx context.Context, task string, handler func(payload []byte) error)
+	// AddHandler adds a handler that will handle the messages of a topic consumed from a queue. Messages of a topic no
+	// handler is added for are not handled
+	AddHandler(topic string, handler Handler)
+}
+
+// EventSubscriber is an event consumer that declares the queues it subscribes to on a broker & consumes from every one of
+// them until it is stopped
+type EventSubscriber interface {
+	EventConsumer
+
+	// Declare declares the exchanges the consumer is configured with & the queues it subscribes to on the broker, binding
+	// the queues to the exchanges. It is called once at startup
+	Declare(ctx context.Context) error
+
+	// Start consumes from every queue the consumer subscribes to, each with a pool of workers of its own, until the consumer
+	// is stopped. This is a blocking operation
+	Start() error
+
+	// Stop stops consuming messages from every queue & waits until the workers have handled the messages they are on, or
+	// until the context is done
+	Stop(ctx context.Context) error
 }
@@ -0,0 +1,35 @@
+package messaging
+
+import (
+	"context"
+
+	"github.com/BrianLusina/skillq/server/infra/requestid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// HeaderRequestID is the message header carrying the ID of the request a message was published while handling, which is
+// the same for every broker
+const HeaderRequestID = "x-request-id"
+
+// HeadersFromContext builds the headers of a message published with the context whatever the broker it is published to,
+// carrying the request ID of the context if it has one & the trace context of its span
+func HeadersFromContext(ctx context.Context) map[string]string {
+	headers := map[string]string{}
+	if requestID, ok := requestid.FromContext(ctx); ok {
+		headers[HeaderRequestID] = requestID
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(headers))
+	return headers
+}
+
+// ContextWithHeaders returns a copy of the context carrying the request ID & trace context from the headers of a message,
+// so that the message is published again in the request & trace it was first published in. The request ID is left out
+// if it is not valid
+func ContextWithHeaders(ctx context.Context, headers map[string]string) context.Context {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(headers))
+	if requestID, ok := headers[HeaderRequestID]; ok && requestid.IsValid(requestID) {
+		ctx = requestid.WithRequestID(ctx, requestID)
+	}
+	return ctx
+}
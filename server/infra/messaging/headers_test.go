@@ -0,0 +1,53 @@
+package messaging
+
+import (
+	"context"
+	"testing"
+
+	"github.com/BrianLusina/skillq/server/infra/requestid"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestHeaders(t *testing.T) {
+	t.Run("carries the request ID of the context", func(t *testing.T) {
+		ctx := requestid.WithRequestID(context.Background(), "request-1")
+
+		headers := HeadersFromContext(ctx)
+		assert.Equal(t, "request-1", headers[HeaderRequestID])
+
+		requestID, ok := requestid.FromContext(ContextWithHeaders(context.Background(), headers))
+		assert.True(t, ok)
+		assert.Equal(t, "request-1", requestID)
+	})
+
+	t.Run("leaves out a request ID that is missing or invalid", func(t *testing.T) {
+		assert.NotContains(t, HeadersFromContext(context.Background()), HeaderRequestID)
+
+		_, ok := requestid.FromContext(ContextWithHeaders(context.Background(), map[string]string{HeaderRequestID: "bad\nid"}))
+		assert.False(t, ok)
+	})
+
+	t.Run("carries the trace context of the context", func(t *testing.T) {
+		defer otel.SetTextMapPropagator(otel.GetTextMapPropagator())
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		provider := sdktrace.NewTracerProvider()
+		defer func() {
+			assert.NoError(t, provider.Shutdown(context.Background()))
+		}()
+
+		ctx, span := provider.Tracer("test").Start(context.Background(), "publish")
+		defer span.End()
+
+		headers := HeadersFromContext(ctx)
+		assert.Contains(t, headers, "traceparent")
+
+		extracted := trace.SpanContextFromContext(ContextWithHeaders(context.Background(), headers))
+		assert.True(t, extracted.IsRemote())
+		assert.Equal(t, span.SpanContext().TraceID(), extracted.TraceID())
+		assert.Equal(t, span.SpanContext().SpanID(), extracted.SpanID())
+	})
+}
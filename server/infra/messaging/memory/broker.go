@@ -0,0 +1,202 @@
+package memorybroker
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// ExchangeFanout is the kind of exchange that routes messages to every queue bound to it, whatever their routing key
+	ExchangeFanout = "fanout"
+
+	// ExchangeDirect is the kind of exchange that routes messages to the queues bound to it with their routing key
+	ExchangeDirect = "direct"
+)
+
+var (
+	// ErrExchangeNotFound is returned when publishing to or binding to an exchange that is not declared
+	ErrExchangeNotFound = errors.New("exchange not found")
+
+	// ErrQueueNotFound is returned when binding or consuming a queue that is not declared
+	ErrQueueNotFound = errors.New("queue not found")
+
+	// ErrUnroutable is returned when publishing a message that is not routed to any queue
+	ErrUnroutable = errors.New("message is not routed to any queue")
+
+	// ErrExchangeKind is returned when declaring an exchange of an unknown kind, or of another kind than the exchange of
+	// the same name that is already declared
+	ErrExchangeKind = errors.New("invalid exchange kind")
+
+	// ErrAlreadySettled is returned when acknowledging or rejecting a delivery that was already acknowledged or rejected
+	ErrAlreadySettled = errors.New("delivery already acknowledged or rejected")
+)
+
+// QueueParams are the parameters of a queue declared on the broker
+type QueueParams struct {
+	// Name is the name of the queue
+	Name string
+
+	// DeadLetterQueue is the queue deliveries that are rejected without being requeued are moved to. They are dropped if
+	// it is not set
+	DeadLetterQueue string
+
+	// RedeliveryDelay is how long a requeued delivery waits before it is delivered again
+	RedeliveryDelay time.Duration
+}
+
+// exchange routes the messages published to it to the queues bound to it
+type exchange struct {
+	kind     string
+	bindings []binding
+}
+
+// binding binds a queue to an exchange with a routing key
+type binding struct {
+	queue      string
+	routingKey string
+}
+
+// Broker is an in-memory message broker that routes the messages published to its exchanges to the queues bound to them.
+// Deliveries are handed out to consumers once & are redelivered when they are requeued, until they are acknowledged or
+// dead-lettered. Declaring exchanges, queues & bindings is idempotent
+type Broker struct {
+	mu        sync.RWMutex
+	exchanges map[string]*exchange
+	queues    map[string]*queue
+}
+
+// NewBroker creates an in-memory broker that has no exchanges or queues declared yet
+func NewBroker() *Broker {
+	return &Broker{
+		exchanges: map[string]*exchange{},
+		queues:    map[string]*queue{},
+	}
+}
+
+// DeclareExchange declares an exchange of a kind, which is either fanout or direct. Declaring an exchange that is already
+// declared with the same kind does nothing
+func (b *Broker) DeclareExchange(name, kind string) error {
+	if kind != ExchangeFanout && kind != ExchangeDirect {
+		return fmt.Errorf("%w: %q of exchange %s", ErrExchangeKind, kind, name)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if existing, ok := b.exchanges[name]; ok {
+		if existing.kind != kind {
+			return fmt.Errorf("%w: exchange %s is declared as %s, not %s", ErrExchangeKind, name, existing.kind, kind)
+		}
+		return nil
+	}
+
+	b.exchanges[name] = &exchange{kind: kind}
+	return nil
+}
+
+// DeclareQueue declares a queue. Declaring a queue that is already declared keeps its deliveries & updates its parameters
+func (b *Broker) DeclareQueue(params QueueParams) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if existing, ok := b.queues[params.Name]; ok {
+		existing.setParams(params)
+		return nil
+	}
+
+	b.queues[params.Name] = newQueue(b, params)
+	return nil
+}
+
+// BindQueue binds a queue to an exchange with a routing key, which direct exchanges route messages by
+func (b *Broker) BindQueue(queue, exchangeName, routingKey string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ex, ok := b.exchanges[exchangeName]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrExchangeNotFound, exchangeName)
+	}
+
+	if _, ok := b.queues[queue]; !ok {
+		return fmt.Errorf("%w: %s", ErrQueueNotFound, queue)
+	}
+
+	bound := binding{queue: queue, routingKey: routingKey}
+	if !slices.Contains(ex.bindings, bound) {
+		ex.bindings = append(ex.bindings, bound)
+	}
+	return nil
+}
+
+// Publish publishes a message to an exchange, which routes it to the queues bound to it. ErrUnroutable is returned if the
+// message is not routed to any queue
+func (b *Broker) Publish(exchangeName, routingKey string, msg Message) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	ex, ok := b.exchanges[exchangeName]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrExchangeNotFound, exchangeName)
+	}
+
+	var routed []*queue
+	for _, bound := range ex.bindings {
+		if ex.kind == ExchangeDirect && bound.routingKey != routingKey {
+			continue
+		}
+
+		q := b.queues[bound.queue]
+		if !slices.Contains(routed, q) {
+			routed = append(routed, q)
+		}
+	}
+
+	if len(routed) == 0 {
+		return fmt.Errorf("%w: exchange %s, routing key %s", ErrUnroutable, exchangeName, routingKey)
+	}
+
+	for _, q := range routed {
+		q.push(msg.clone(), 0)
+	}
+
+	return nil
+}
+
+// Len is the number of deliveries in a queue that are ready to be delivered
+func (b *Broker) Len(queueName string) (int, error) {
+	q, err := b.queue(queueName)
+	if err != nil {
+		return 0, err
+	}
+	return q.len(), nil
+}
+
+// queue is the queue of a name if it is declared
+func (b *Broker) queue(name string) (*queue, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	q, ok := b.queues[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrQueueNotFound, name)
+	}
+	return q, nil
+}
+
+// deadLetter moves a message to a dead letter queue, dropping it if the queue is not declared
+func (b *Broker) deadLetter(queueName string, msg Message) {
+	if queueName == "" {
+		return
+	}
+
+	q, err := b.queue(queueName)
+	if err != nil {
+		return
+	}
+
+	q.push(msg, 0)
+}
@@ -0,0 +1,111 @@
+package memorybroker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroker(t *testing.T) {
+	newTestBroker := func(t *testing.T) *Broker {
+		broker := NewBroker()
+		require.NoError(t, broker.DeclareExchange("send-email-exchange", ExchangeFanout))
+		require.NoError(t, broker.DeclareExchange("tasks-exchange", ExchangeDirect))
+		require.NoError(t, broker.DeclareQueue(QueueParams{Name: "send-email-queue", DeadLetterQueue: "send-email-queue.dlq"}))
+		require.NoError(t, broker.DeclareQueue(QueueParams{Name: "send-email-queue.dlq"}))
+		require.NoError(t, broker.DeclareQueue(QueueParams{Name: "audit-queue"}))
+		require.NoError(t, broker.DeclareQueue(QueueParams{Name: "store-image-queue"}))
+		return broker
+	}
+
+	get := func(t *testing.T, broker *Broker, queue string) *Delivery {
+		q, err := broker.queue(queue)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		delivery, ok := q.get(ctx)
+		require.True(t, ok, "no delivery from queue %s", queue)
+		return delivery
+	}
+
+	assertLen := func(t *testing.T, broker *Broker, queue string, expected int) {
+		n, err := broker.Len(queue)
+		require.NoError(t, err)
+		assert.Equal(t, expected, n, "deliveries in queue %s", queue)
+	}
+
+	t.Run("routes messages to every queue bound to a fanout exchange", func(t *testing.T) {
+		broker := newTestBroker(t)
+		require.NoError(t, broker.BindQueue("send-email-queue", "send-email-exchange", "send-email"))
+		require.NoError(t, broker.BindQueue("audit-queue", "send-email-exchange", "audit"))
+
+		err := broker.Publish("send-email-exchange", "anything", Message{ID: "1", Topic: "SendEmailVerification", Body: []byte(`{}`)})
+
+		require.NoError(t, err)
+		assertLen(t, broker, "send-email-queue", 1)
+		assertLen(t, broker, "audit-queue", 1)
+	})
+
+	t.Run("routes messages to the queues bound to a direct exchange with their routing key", func(t *testing.T) {
+		broker := newTestBroker(t)
+		require.NoError(t, broker.BindQueue("send-email-queue", "tasks-exchange", "send-email"))
+		require.NoError(t, broker.BindQueue("store-image-queue", "tasks-exchange", "store-image"))
+
+		err := broker.Publish("tasks-exchange", "store-image", Message{ID: "1", Topic: "StoreUserImage"})
+
+		require.NoError(t, err)
+		assertLen(t, broker, "send-email-queue", 0)
+		assertLen(t, broker, "store-image-queue", 1)
+	})
+
+	t.Run("does not route messages that no queue is bound for", func(t *testing.T) {
+		broker := newTestBroker(t)
+		require.NoError(t, broker.BindQueue("send-email-queue", "tasks-exchange", "send-email"))
+
+		assert.ErrorIs(t, broker.Publish("tasks-exchange", "store-image", Message{ID: "1"}), ErrUnroutable)
+		assert.ErrorIs(t, broker.Publish("unknown-exchange", "", Message{ID: "1"}), ErrExchangeNotFound)
+	})
+
+	t.Run("does not declare an exchange of another kind", func(t *testing.T) {
+		broker := newTestBroker(t)
+
+		assert.NoError(t, broker.DeclareExchange("send-email-exchange", ExchangeFanout))
+		assert.ErrorIs(t, broker.DeclareExchange("send-email-exchange", ExchangeDirect), ErrExchangeKind)
+		assert.ErrorIs(t, broker.DeclareExchange("topic-exchange", "topic"), ErrExchangeKind)
+	})
+
+	t.Run("redelivers requeued deliveries", func(t *testing.T) {
+		broker := newTestBroker(t)
+		require.NoError(t, broker.BindQueue("send-email-queue", "send-email-exchange", ""))
+		require.NoError(t, broker.Publish("send-email-exchange", "", Message{ID: "1", Body: []byte(`{}`)}))
+
+		delivery := get(t, broker, "send-email-queue")
+		assert.False(t, delivery.Redelivered)
+		require.NoError(t, delivery.Nack(true))
+
+		redelivery := get(t, broker, "send-email-queue")
+		assert.Equal(t, "1", redelivery.ID)
+		assert.True(t, redelivery.Redelivered)
+		assert.Equal(t, 1, redelivery.Redeliveries)
+		require.NoError(t, redelivery.Ack())
+
+		assertLen(t, broker, "send-email-queue", 0)
+		assert.ErrorIs(t, redelivery.Ack(), ErrAlreadySettled)
+	})
+
+	t.Run("dead-letters rejected deliveries", func(t *testing.T) {
+		broker := newTestBroker(t)
+		require.NoError(t, broker.BindQueue("send-email-queue", "send-email-exchange", ""))
+		require.NoError(t, broker.Publish("send-email-exchange", "", Message{ID: "1"}))
+
+		require.NoError(t, get(t, broker, "send-email-queue").Nack(false))
+
+		assertLen(t, broker, "send-email-queue", 0)
+		assert.Equal(t, "1", get(t, broker, "send-email-queue.dlq").ID)
+	})
+}
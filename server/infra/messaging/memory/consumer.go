@@ -0,0 +1,405 @@
+package memorybroker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/messaging"
+)
+
+const (
+	// _deadLetterQueueSuffix is the suffix of the name of the queue deliveries of a queue are dead-lettered to
+	_deadLetterQueueSuffix = ".dlq"
+
+	// _workers is the number of workers that handle the deliveries of a queue unless the subscription sets it
+	_workers = 1
+
+	// _maxRedeliveries is the number of times a delivery that fails to be handled is redelivered before it is dead-lettered
+	_maxRedeliveries = 3
+)
+
+var (
+	// ErrNotSubscribed is returned when consuming from a queue the consumer does not subscribe to
+	ErrNotSubscribed = errors.New("consumer does not subscribe to queue")
+
+	// ErrNoSubscriptions is returned when starting a consumer that subscribes to no queues
+	ErrNoSubscriptions = errors.New("consumer subscribes to no queues")
+)
+
+// ExchangeParams are the parameters of an exchange the consumer declares
+type ExchangeParams struct {
+	Name string
+	Kind string
+}
+
+// BindingParams bind a queue to an exchange with a routing key
+type BindingParams struct {
+	Exchange   string
+	RoutingKey string
+}
+
+// SubscriptionParams are the parameters of a queue the consumer consumes from
+type SubscriptionParams struct {
+	// Queue is the name of the queue consumed from
+	Queue string
+
+	// Bindings bind the queue to the exchanges it receives messages from
+	Bindings []BindingParams
+
+	// Workers is the number of workers that handle the deliveries of the queue concurrently
+	Workers int
+
+	// Topics are the topics of the deliveries handled from the queue. Deliveries of other topics are dead-lettered. The
+	// queue handles the deliveries of every topic a handler is added for if no topics are set
+	Topics []string
+}
+
+// deadLetterQueue is the name of the queue deliveries of the subscription are dead-lettered to
+func (s SubscriptionParams) deadLetterQueue() string {
+	return s.Queue + _deadLetterQueueSuffix
+}
+
+// handles checks if deliveries of a topic are handled from the queue
+func (s SubscriptionParams) handles(topic string) bool {
+	return len(s.Topics) == 0 || slices.Contains(s.Topics, topic)
+}
+
+// ConsumerOption allows adding options to the in-memory consumer
+type ConsumerOption func(*Consumer)
+
+// Exchange adds an exchange for the consumer to declare, replacing an exchange of the same name
+func Exchange(params ExchangeParams) ConsumerOption {
+	return func(c *Consumer) {
+		for i, exchange := range c.exchanges {
+			if exchange.Name == params.Name {
+				c.exchanges[i] = params
+				return
+			}
+		}
+		c.exchanges = append(c.exchanges, params)
+	}
+}
+
+// Subscribe adds a queue for the consumer to declare & consume from with a pool of workers of its own, replacing a
+// subscription to a queue of the same name
+func Subscribe(params SubscriptionParams) ConsumerOption {
+	return func(c *Consumer) {
+		if params.Workers <= 0 {
+			params.Workers = _workers
+		}
+
+		for i, sub := range c.subscriptions {
+			if sub.Queue == params.Queue {
+				c.subscriptions[i] = params
+				return
+			}
+		}
+		c.subscriptions = append(c.subscriptions, params)
+	}
+}
+
+// MaxRedeliveries sets the number of times a delivery that fails to be handled is redelivered before it is dead-lettered.
+// Deliveries are dead-lettered as soon as they fail if it is zero
+func MaxRedeliveries(maxRedeliveries int) ConsumerOption {
+	return func(c *Consumer) {
+		c.maxRedeliveries = maxRedeliveries
+	}
+}
+
+// RedeliveryDelay sets how long a delivery that fails to be handled waits before it is redelivered
+func RedeliveryDelay(delay time.Duration) ConsumerOption {
+	return func(c *Consumer) {
+		c.redeliveryDelay = delay
+	}
+}
+
+// DefaultRetryPolicy sets the retry policy of the deliveries of the topics that have no retry policy of their own, which
+// overrides the max redeliveries & redelivery delay of the consumer. A delivery is redelivered after the delay of the
+// policy for the number of times it has been redelivered
+func DefaultRetryPolicy(policy messaging.RetryPolicy) ConsumerOption {
+	return func(c *Consumer) {
+		c.defaultRetryPolicy = &policy
+	}
+}
+
+// TopicRetryPolicy sets the retry policy of the deliveries of a topic
+func TopicRetryPolicy(topic string, policy messaging.RetryPolicy) ConsumerOption {
+	return func(c *Consumer) {
+		c.retryPolicies[topic] = policy
+	}
+}
+
+// Consumer consumes messages from the queues of an in-memory broker it subscribes to, handling them with the handlers
+// added for their topic. A delivery is acknowledged once it is handled, while a delivery that fails to be handled is
+// requeued until its redeliveries run out & is then dead-lettered. A delivery whose payload is invalid or of a topic the
+// queue has no handler for is dead-lettered without being redelivered
+type Consumer struct {
+	broker          *Broker
+	exchanges       []ExchangeParams
+	subscriptions   []SubscriptionParams
+	maxRedeliveries int
+	redeliveryDelay time.Duration
+
+	// defaultRetryPolicy & retryPolicies are the retry policies of the deliveries of every topic & of the topics that have
+	// one of their own. Deliveries are redelivered by the max redeliveries & redelivery delay of the consumer if their
+	// topic has no retry policy
+	defaultRetryPolicy *messaging.RetryPolicy
+	retryPolicies      map[string]messaging.RetryPolicy
+
+	handlers map[string]messaging.Handler
+	logger   logger.Logger
+
+	// mu guards the handlers, which are added before the consumer is started, & workers being started once the consumer
+	// is stopped
+	mu sync.RWMutex
+
+	// stopped is cancelled once the consumer is stopped, which stops the workers of every queue
+	stopped context.Context
+	stop    context.CancelFunc
+	workers sync.WaitGroup
+}
+
+var _ messaging.EventSubscriber = (*Consumer)(nil)
+
+// NewConsumer creates a consumer of the queues of an in-memory broker
+func NewConsumer(broker *Broker, log logger.Logger, opts ...ConsumerOption) *Consumer {
+	stopped, stop := context.WithCancel(context.Background())
+
+	c := &Consumer{
+		broker:          broker,
+		maxRedeliveries: _maxRedeliveries,
+		retryPolicies:   map[string]messaging.RetryPolicy{},
+		handlers:        map[string]messaging.Handler{},
+		logger:          log,
+		stopped:         stopped,
+		stop:            stop,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Declare declares the exchanges the consumer is configured with & the queues it subscribes to on the broker, with their
+// dead letter queues, binding the queues to the exchanges
+func (c *Consumer) Declare(ctx context.Context) error {
+	for _, exchange := range c.exchanges {
+		if err := c.broker.DeclareExchange(exchange.Name, exchange.Kind); err != nil {
+			return fmt.Errorf("failed to declare exchange %s: %w", exchange.Name, err)
+		}
+	}
+
+	for _, sub := range c.subscriptions {
+		if err := c.broker.DeclareQueue(QueueParams{Name: sub.deadLetterQueue()}); err != nil {
+			return fmt.Errorf("failed to declare queue %s: %w", sub.deadLetterQueue(), err)
+		}
+
+		err := c.broker.DeclareQueue(QueueParams{
+			Name:            sub.Queue,
+			DeadLetterQueue: sub.deadLetterQueue(),
+			RedeliveryDelay: c.redeliveryDelay,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to declare queue %s: %w", sub.Queue, err)
+		}
+
+		for _, binding := range sub.Bindings {
+			if err := c.broker.BindQueue(sub.Queue, binding.Exchange, binding.RoutingKey); err != nil {
+				return fmt.Errorf("failed to bind queue %s to exchange %s: %w", sub.Queue, binding.Exchange, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// AddHandler adds a handler that will handle the deliveries of a topic from the queues that handle the topic
+func (c *Consumer) AddHandler(topic string, handler messaging.Handler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers[topic] = handler
+}
+
+// Consume consumes deliveries from a queue the consumer subscribes to until the consumer is stopped or the context is
+// done. This is a blocking operation
+func (c *Consumer) Consume(ctx context.Context, queue string) error {
+	for _, sub := range c.subscriptions {
+		if sub.Queue == queue {
+			return c.consume(ctx, sub)
+		}
+	}
+
+	return fmt.Errorf("%w: %s", ErrNotSubscribed, queue)
+}
+
+// Start consumes from every queue the consumer subscribes to until the consumer is stopped, returning the first error
+// consuming from a queue fails with. This is a blocking operation
+func (c *Consumer) Start() error {
+	if len(c.subscriptions) == 0 {
+		return ErrNoSubscriptions
+	}
+
+	errs := make(chan error, len(c.subscriptions))
+	for _, sub := range c.subscriptions {
+		go func() {
+			errs <- c.consume(context.Background(), sub)
+		}()
+	}
+
+	for range c.subscriptions {
+		if err := <-errs; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// consume consumes deliveries from the queue of a subscription with its pool of workers until the consumer is stopped or
+// the context is done
+func (c *Consumer) consume(ctx context.Context, sub SubscriptionParams) error {
+	q, err := c.broker.queue(sub.Queue)
+	if err != nil {
+		return fmt.Errorf("failed to consume from queue %s: %w", sub.Queue, err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stopAfter := context.AfterFunc(c.stopped, cancel)
+	defer stopAfter()
+
+	c.mu.Lock()
+	if c.stopped.Err() != nil {
+		c.mu.Unlock()
+		return nil
+	}
+	c.workers.Add(sub.Workers)
+	c.mu.Unlock()
+
+	c.logger.Infof("Consuming from queue %s with %d workers", sub.Queue, sub.Workers)
+
+	var workers sync.WaitGroup
+	for i := 0; i < sub.Workers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			defer c.workers.Done()
+
+			for {
+				delivery, ok := q.get(ctx)
+				if !ok {
+					return
+				}
+
+				// a delivery that is being handled is finished when the consumer is stopped
+				c.dispatch(context.WithoutCancel(ctx), sub, delivery)
+			}
+		}()
+	}
+
+	workers.Wait()
+	c.logger.Infof("Stopped consuming from queue %s", sub.Queue)
+
+	return nil
+}
+
+// dispatch handles a delivery with the handler of its topic in the context of the delivery
+func (c *Consumer) dispatch(ctx context.Context, sub SubscriptionParams, delivery *Delivery) {
+	ctx = DeliveryContext(ctx, delivery, c.logger)
+	log := logger.FromContext(ctx)
+
+	log.Infof("Processing message %s with Topic %s", delivery.ID, delivery.Topic)
+
+	handler, ok := c.handler(sub, delivery.Topic)
+	if !ok {
+		log.Warn(fmt.Sprintf("No handler for message with Topic %s in queue %s, dead-lettering it", delivery.Topic, sub.Queue))
+		c.settle(ctx, delivery.Nack(false))
+		return
+	}
+
+	err := handler(ctx, delivery.Body)
+	switch {
+	case errors.Is(err, messaging.ErrInvalidPayload):
+		// handling a poison message again would fail the same way, so it is dead-lettered straight away
+		log.Errorf("Failed to decode message, dead-lettering it: %s", err)
+		c.settle(ctx, delivery.Nack(false))
+	case err != nil:
+		log.Errorf("Failed to process delivery with err: %s", err)
+
+		// a delivery with a retry policy is redelivered after the delay of its policy rather than that of the queue
+		if policy, ok := c.retryPolicy(delivery.Topic); ok {
+			if delivery.Redeliveries < policy.MaxRetries {
+				c.settle(ctx, delivery.requeueAfter(policy.Delay(delivery.Redeliveries)))
+				return
+			}
+		} else if delivery.Redeliveries < c.maxRedeliveries {
+			c.settle(ctx, delivery.Nack(true))
+			return
+		}
+
+		log.Errorf("Message with Topic %s failed after %d redeliveries, dead-lettering it", delivery.Topic, delivery.Redeliveries)
+		c.settle(ctx, delivery.Nack(false))
+	default:
+		c.settle(ctx, delivery.Ack())
+	}
+}
+
+// retryPolicy is the retry policy of the deliveries of a topic, which is the default retry policy unless the topic has its
+// own, if either is set
+func (c *Consumer) retryPolicy(topic string) (messaging.RetryPolicy, bool) {
+	if policy, ok := c.retryPolicies[topic]; ok {
+		return policy, true
+	}
+	if c.defaultRetryPolicy != nil {
+		return *c.defaultRetryPolicy, true
+	}
+	return messaging.RetryPolicy{}, false
+}
+
+// handler is the handler of the deliveries of a topic consumed from a queue, if the queue handles them & a handler is added
+// for them
+func (c *Consumer) handler(sub SubscriptionParams, topic string) (messaging.Handler, bool) {
+	if !sub.handles(topic) {
+		return nil, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	handler, ok := c.handlers[topic]
+	return handler, ok
+}
+
+// settle logs the error a delivery failed to be acknowledged or rejected with
+func (c *Consumer) settle(ctx context.Context, err error) {
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to settle delivery with err: %s", err)
+	}
+}
+
+// Stop stops consuming from every queue & waits for the workers to return after handling the delivery they are on.
+// Deliveries that are not handled yet stay in their queues
+func (c *Consumer) Stop(ctx context.Context) error {
+	c.mu.Lock()
+	c.stop()
+	c.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		c.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("workers of the consumer did not finish: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,255 @@
+package memorybroker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/messaging"
+	"github.com/BrianLusina/skillq/server/infra/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumer(t *testing.T) {
+	log, _ := logger.NewTestLogger()
+
+	type task struct {
+		Email string `json:"email"`
+	}
+
+	// newTestBroker creates a broker with a consumer of the send email & store image queues, & a publisher that routes
+	// tasks to them
+	newTestBroker := func(t *testing.T, opts ...ConsumerOption) (*Broker, *Consumer, *Publisher) {
+		broker := NewBroker()
+		opts = append([]ConsumerOption{
+			Exchange(ExchangeParams{Name: "tasks-exchange", Kind: ExchangeDirect}),
+			Subscribe(SubscriptionParams{
+				Queue:    "send-email-queue",
+				Bindings: []BindingParams{{Exchange: "tasks-exchange", RoutingKey: "send-email"}},
+				Workers:  2,
+				Topics:   []string{"SendEmailVerification", "SendPasswordReset"},
+			}),
+			Subscribe(SubscriptionParams{
+				Queue:    "store-image-queue",
+				Bindings: []BindingParams{{Exchange: "tasks-exchange", RoutingKey: "store-image"}},
+			}),
+		}, opts...)
+
+		consumer := NewConsumer(broker, log, opts...)
+		require.NoError(t, consumer.Declare(context.Background()))
+
+		publisher := NewPublisher(broker, log,
+			Route("SendEmailVerification", "tasks-exchange", "send-email"),
+			Route("SendPasswordReset", "tasks-exchange", "send-email"),
+			Route("StoreUserImage", "tasks-exchange", "store-image"),
+		)
+
+		return broker, consumer, publisher
+	}
+
+	// start starts the consumer, stopping it once the test finishes
+	start := func(t *testing.T, consumer *Consumer) {
+		started := make(chan error, 1)
+		go func() {
+			started <- consumer.Start()
+		}()
+
+		t.Cleanup(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			require.NoError(t, consumer.Stop(ctx))
+			require.NoError(t, <-started)
+		})
+	}
+
+	publish := func(t *testing.T, ctx context.Context, publisher *Publisher, topic string, payload any) {
+		require.NoError(t, publisher.Publish(ctx, messaging.New(messaging.MessageParams{Topic: topic, ContentType: "application/json", Payload: payload})))
+	}
+
+	waitForLen := func(t *testing.T, broker *Broker, queue string, expected int) {
+		assert.Eventually(t, func() bool {
+			n, err := broker.Len(queue)
+			return err == nil && n == expected
+		}, time.Second, time.Millisecond, "deliveries in queue %s", queue)
+	}
+
+	t.Run("handles the tasks of every queue with the handlers of their topic", func(t *testing.T) {
+		_, consumer, publisher := newTestBroker(t)
+
+		var mu sync.Mutex
+		handled := map[string][]string{}
+		record := func(topic string) messaging.Handler {
+			return func(ctx context.Context, payload []byte) error {
+				var task task
+				if err := json.Unmarshal(payload, &task); err != nil {
+					return err
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+				handled[topic] = append(handled[topic], task.Email)
+				return nil
+			}
+		}
+		consumer.AddHandler("SendEmailVerification", record("SendEmailVerification"))
+		consumer.AddHandler("StoreUserImage", record("StoreUserImage"))
+		start(t, consumer)
+
+		publish(t, context.Background(), publisher, "SendEmailVerification", task{Email: "jane@example.com"})
+		publish(t, context.Background(), publisher, "StoreUserImage", task{Email: "john@example.com"})
+
+		assert.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(handled["SendEmailVerification"]) == 1 && len(handled["StoreUserImage"]) == 1
+		}, time.Second, time.Millisecond)
+		assert.Equal(t, []string{"jane@example.com"}, handled["SendEmailVerification"])
+		assert.Equal(t, []string{"john@example.com"}, handled["StoreUserImage"])
+	})
+
+	t.Run("handles tasks with the request ID they were published with", func(t *testing.T) {
+		_, consumer, publisher := newTestBroker(t)
+
+		requestIDs := make(chan string, 1)
+		consumer.AddHandler("SendEmailVerification", func(ctx context.Context, payload []byte) error {
+			requestID, _ := requestid.FromContext(ctx)
+			requestIDs <- requestID
+			return nil
+		})
+		start(t, consumer)
+
+		ctx := requestid.WithRequestID(context.Background(), "request-1")
+		publish(t, ctx, publisher, "SendEmailVerification", task{Email: "jane@example.com"})
+
+		select {
+		case requestID := <-requestIDs:
+			assert.Equal(t, "request-1", requestID)
+		case <-time.After(time.Second):
+			t.Fatal("task was not handled")
+		}
+	})
+
+	t.Run("redelivers failed tasks until their redeliveries run out, then dead-letters them", func(t *testing.T) {
+		broker, consumer, publisher := newTestBroker(t, MaxRedeliveries(2))
+
+		var mu sync.Mutex
+		attempts := 0
+		consumer.AddHandler("SendEmailVerification", func(ctx context.Context, payload []byte) error {
+			mu.Lock()
+			defer mu.Unlock()
+			attempts++
+			return errors.New("smtp unavailable")
+		})
+		start(t, consumer)
+
+		publish(t, context.Background(), publisher, "SendEmailVerification", task{Email: "jane@example.com"})
+
+		waitForLen(t, broker, "send-email-queue.dlq", 1)
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, 3, attempts, "handled once & redelivered twice")
+	})
+
+	t.Run("redelivers failed tasks by the retry policy of their topic with a growing delay", func(t *testing.T) {
+		broker, consumer, publisher := newTestBroker(t,
+			DefaultRetryPolicy(messaging.RetryPolicy{MaxRetries: 0}),
+			TopicRetryPolicy("SendEmailVerification", messaging.RetryPolicy{
+				MaxRetries:   2,
+				InitialDelay: 20 * time.Millisecond,
+				MaxDelay:     time.Second,
+				Multiplier:   3,
+			}),
+		)
+
+		var mu sync.Mutex
+		attempts := map[string][]time.Time{}
+		handler := func(topic string) messaging.Handler {
+			return func(ctx context.Context, payload []byte) error {
+				mu.Lock()
+				defer mu.Unlock()
+				attempts[topic] = append(attempts[topic], time.Now())
+				return errors.New("smtp unavailable")
+			}
+		}
+		consumer.AddHandler("SendEmailVerification", handler("SendEmailVerification"))
+		consumer.AddHandler("SendPasswordReset", handler("SendPasswordReset"))
+		start(t, consumer)
+
+		publish(t, context.Background(), publisher, "SendEmailVerification", task{Email: "jane@example.com"})
+		publish(t, context.Background(), publisher, "SendPasswordReset", task{Email: "jane@example.com"})
+
+		waitForLen(t, broker, "send-email-queue.dlq", 2)
+		mu.Lock()
+		defer mu.Unlock()
+		require.Len(t, attempts["SendEmailVerification"], 3, "handled once & redelivered twice")
+		assert.Len(t, attempts["SendPasswordReset"], 1, "dead-lettered without being redelivered")
+
+		// the second redelivery waits the initial delay times the multiplier
+		retried := attempts["SendEmailVerification"]
+		assert.GreaterOrEqual(t, retried[1].Sub(retried[0]), 20*time.Millisecond)
+		assert.GreaterOrEqual(t, retried[2].Sub(retried[1]), 60*time.Millisecond)
+	})
+
+	t.Run("handles a redelivered task that succeeds", func(t *testing.T) {
+		broker, consumer, publisher := newTestBroker(t, RedeliveryDelay(10*time.Millisecond))
+
+		handled := make(chan int, 2)
+		consumer.AddHandler("SendEmailVerification", func(ctx context.Context, payload []byte) error {
+			handled <- 1
+			if len(handled) == 1 {
+				return errors.New("smtp unavailable")
+			}
+			return nil
+		})
+		start(t, consumer)
+
+		publish(t, context.Background(), publisher, "SendEmailVerification", task{Email: "jane@example.com"})
+
+		assert.Eventually(t, func() bool { return len(handled) == 2 }, time.Second, time.Millisecond)
+		waitForLen(t, broker, "send-email-queue", 0)
+		waitForLen(t, broker, "send-email-queue.dlq", 0)
+	})
+
+	t.Run("dead-letters tasks that are invalid or have no handler without redelivering them", func(t *testing.T) {
+		broker, consumer, publisher := newTestBroker(t)
+
+		consumer.AddHandler("SendEmailVerification", func(ctx context.Context, payload []byte) error {
+			return fmt.Errorf("%w: missing email", messaging.ErrInvalidPayload)
+		})
+		start(t, consumer)
+
+		publish(t, context.Background(), publisher, "SendEmailVerification", task{})
+		publish(t, context.Background(), publisher, "SendPasswordReset", task{Email: "jane@example.com"})
+
+		waitForLen(t, broker, "send-email-queue.dlq", 2)
+	})
+
+	t.Run("does not consume from a queue it does not subscribe to", func(t *testing.T) {
+		_, consumer, _ := newTestBroker(t)
+
+		assert.ErrorIs(t, consumer.Consume(context.Background(), "unknown-queue"), ErrNotSubscribed)
+	})
+
+	t.Run("does not start without subscriptions", func(t *testing.T) {
+		consumer := NewConsumer(NewBroker(), log)
+
+		assert.ErrorIs(t, consumer.Start(), ErrNoSubscriptions)
+		assert.NoError(t, consumer.Stop(context.Background()))
+	})
+
+	t.Run("keeps tasks that are published while it is stopped", func(t *testing.T) {
+		broker, consumer, publisher := newTestBroker(t)
+		require.NoError(t, consumer.Stop(context.Background()))
+
+		publish(t, context.Background(), publisher, "StoreUserImage", task{Email: "jane@example.com"})
+
+		assert.NoError(t, consumer.Start())
+		waitForLen(t, broker, "store-image-queue", 1)
+	})
+}
@@ -0,0 +1,4 @@
+// Package memorybroker contains an in-memory message broker with a publisher & a consumer for it, which routes messages
+// through exchanges to queues within the process. It is only suitable for tests & a single instance of the service, as
+// messages are lost when the process exits
+package memorybroker
@@ -0,0 +1,28 @@
+package memorybroker
+
+import (
+	"context"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/messaging"
+	"github.com/BrianLusina/skillq/server/infra/requestid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// HeaderRequestID is the message header carrying the ID of the request a message was published while handling
+const HeaderRequestID = messaging.HeaderRequestID
+
+// DeliveryContext returns a copy of the context to handle a delivery with. It carries the request ID & trace context from
+// the headers of the delivery, or a new request ID if the publisher did not set one, & a logger that adds the request ID &
+// message ID to every line it logs
+func DeliveryContext(ctx context.Context, delivery *Delivery, log logger.Logger) context.Context {
+	requestID, ok := delivery.Headers[HeaderRequestID]
+	if !ok || !requestid.IsValid(requestID) {
+		requestID = requestid.New()
+	}
+
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(delivery.Headers))
+	ctx = requestid.WithRequestID(ctx, requestID)
+	return logger.WithLogger(ctx, log.With("requestID", requestID, "messageID", delivery.ID))
+}
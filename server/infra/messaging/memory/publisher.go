@@ -0,0 +1,94 @@
+package memorybroker
+
+import (
+	"context"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/messaging"
+	"github.com/pkg/errors"
+)
+
+// route is the exchange & routing key messages are published to
+type route struct {
+	exchange   string
+	routingKey string
+}
+
+// Publisher publishes messages to the exchanges of an in-memory broker
+type Publisher struct {
+	broker *Broker
+	routes map[string]route
+	logger logger.Logger
+}
+
+var _ messaging.EventPublisher = (*Publisher)(nil)
+
+// PublisherOption allows adding options to the in-memory publisher
+type PublisherOption func(*Publisher)
+
+// Route routes the messages of a topic to an exchange with a routing key. Messages of a topic that is not routed are
+// published to the exchange named after the topic
+func Route(topic, exchange, routingKey string) PublisherOption {
+	return func(p *Publisher) {
+		p.routes[topic] = route{exchange: exchange, routingKey: routingKey}
+	}
+}
+
+// NewPublisher creates a publisher of messages to an in-memory broker
+func NewPublisher(broker *Broker, log logger.Logger, opts ...PublisherOption) *Publisher {
+	p := &Publisher{
+		broker: broker,
+		routes: map[string]route{},
+		logger: log,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// route is the route of the messages of a topic
+func (p *Publisher) route(topic string) route {
+	if to, ok := p.routes[topic]; ok {
+		return to
+	}
+	return route{exchange: topic}
+}
+
+// Publish publishes a message to the exchange its topic is routed to. The message is routed to the queues bound to the
+// exchange by the time Publish returns, & an error wrapping ErrUnroutable is returned if there are none
+func (p *Publisher) Publish(ctx context.Context, message messaging.Message) error {
+	log := logger.FromContextOr(ctx, p.logger)
+
+	body, err := message.PayloadToBytes()
+	if err != nil {
+		log.Errorf("Failed to parse message: %v", err)
+		return errors.Wrapf(err, "failed to parse message event")
+	}
+
+	to := p.route(message.Topic)
+
+	err = p.broker.Publish(to.exchange, to.routingKey, Message{
+		ID:          message.ID,
+		Topic:       message.Topic,
+		ContentType: message.ContentType,
+		Timestamp:   message.Timestamp,
+		Headers:     messaging.HeadersFromContext(ctx),
+		Body:        body,
+	})
+	if err != nil {
+		log.Errorf("Failed to publish message %v to exchange %s with routingKey %s: %v", message, to.exchange, to.routingKey, err)
+		return errors.Wrapf(err, "failed to publish message %s", message.ID)
+	}
+
+	log.Infof("Successfully published message %v to exchange: %s, with routingKey: %s", message, to.exchange, to.routingKey)
+
+	return nil
+}
+
+// Close does nothing, as the publisher holds no connection to the broker
+func (p *Publisher) Close() error {
+	return nil
+}
@@ -0,0 +1,207 @@
+package memorybroker
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Message is a message published to the broker
+type Message struct {
+	// ID is the ID of the message
+	ID string
+
+	// Topic is the topic of the message, which consumers pick the handler of the message by
+	Topic string
+
+	// ContentType is the content type of the body
+	ContentType string
+
+	// Timestamp is the time the message was published
+	Timestamp time.Time
+
+	// Headers carry the request ID & trace context the message was published with
+	Headers map[string]string
+
+	// Body is the payload of the message
+	Body []byte
+}
+
+// clone copies a message, so that every queue it is routed to has a copy of its own
+func (m Message) clone() Message {
+	m.Headers = maps.Clone(m.Headers)
+	m.Body = append([]byte(nil), m.Body...)
+	return m
+}
+
+// Delivery is a message delivered from a queue to a consumer, which has to acknowledge it once it is handled or reject it
+type Delivery struct {
+	Message
+
+	// Redelivered is whether the message was requeued & delivered again
+	Redelivered bool
+
+	// Redeliveries is the number of times the message was requeued & delivered again
+	Redeliveries int
+
+	queue   *queue
+	settled atomic.Bool
+}
+
+// Ack acknowledges a delivery that was handled, which removes it from its queue
+func (d *Delivery) Ack() error {
+	if !d.settled.CompareAndSwap(false, true) {
+		return ErrAlreadySettled
+	}
+	return nil
+}
+
+// Nack rejects a delivery that failed to be handled. A requeued delivery is delivered again after the redelivery delay of
+// its queue, while a delivery that is not requeued is moved to the dead letter queue of its queue
+func (d *Delivery) Nack(requeue bool) error {
+	if !d.settled.CompareAndSwap(false, true) {
+		return ErrAlreadySettled
+	}
+
+	if requeue {
+		d.queue.requeue(d.Message, d.Redeliveries+1)
+		return nil
+	}
+
+	d.queue.deadLetter(d.Message)
+	return nil
+}
+
+// requeueAfter rejects a delivery that failed to be handled, delivering it again after the delay rather than the
+// redelivery delay of its queue
+func (d *Delivery) requeueAfter(delay time.Duration) error {
+	if !d.settled.CompareAndSwap(false, true) {
+		return ErrAlreadySettled
+	}
+
+	d.queue.requeueAfter(d.Message, d.Redeliveries+1, delay)
+	return nil
+}
+
+// entry is a message in a queue with the number of times it has been redelivered
+type entry struct {
+	msg          Message
+	redeliveries int
+}
+
+// queue holds the messages routed to it until they are delivered. Deliveries are handed out in the order they are ready
+type queue struct {
+	broker *Broker
+
+	// mu guards the parameters of the queue & the entries that are ready to be delivered
+	mu     sync.Mutex
+	params QueueParams
+	ready  []entry
+
+	// notify is signalled when an entry is ready, waking a consumer that waits for one
+	notify chan struct{}
+}
+
+// newQueue creates an empty queue of a broker
+func newQueue(broker *Broker, params QueueParams) *queue {
+	return &queue{
+		broker: broker,
+		params: params,
+		notify: make(chan struct{}, 1),
+	}
+}
+
+// setParams updates the parameters of the queue when it is declared again
+func (q *queue) setParams(params QueueParams) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.params = params
+}
+
+// push adds a message that is ready to be delivered to the end of the queue
+func (q *queue) push(msg Message, redeliveries int) {
+	q.mu.Lock()
+	q.ready = append(q.ready, entry{msg: msg, redeliveries: redeliveries})
+	q.mu.Unlock()
+
+	q.signal()
+}
+
+// signal wakes a consumer that waits for an entry, unless one is already woken
+func (q *queue) signal() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+// len is the number of entries that are ready to be delivered
+func (q *queue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.ready)
+}
+
+// get waits for the next entry of the queue & delivers it, returning false if the context is done first
+func (q *queue) get(ctx context.Context) (*Delivery, bool) {
+	for {
+		q.mu.Lock()
+		if len(q.ready) > 0 {
+			next := q.ready[0]
+			q.ready = q.ready[1:]
+			more := len(q.ready) > 0
+			q.mu.Unlock()
+
+			// another consumer is woken for the entries that are left, as a signal only wakes one
+			if more {
+				q.signal()
+			}
+
+			return &Delivery{
+				Message:      next.msg,
+				Redelivered:  next.redeliveries > 0,
+				Redeliveries: next.redeliveries,
+				queue:        q,
+			}, true
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-q.notify:
+		case <-ctx.Done():
+			return nil, false
+		}
+	}
+}
+
+// requeue adds a message back to the queue once the redelivery delay of the queue has passed
+func (q *queue) requeue(msg Message, redeliveries int) {
+	q.mu.Lock()
+	delay := q.params.RedeliveryDelay
+	q.mu.Unlock()
+
+	q.requeueAfter(msg, redeliveries, delay)
+}
+
+// requeueAfter adds a message back to the queue once the delay has passed
+func (q *queue) requeueAfter(msg Message, redeliveries int, delay time.Duration) {
+	if delay <= 0 {
+		q.push(msg, redeliveries)
+		return
+	}
+
+	time.AfterFunc(delay, func() {
+		q.push(msg, redeliveries)
+	})
+}
+
+// deadLetter moves a message to the dead letter queue of the queue
+func (q *queue) deadLetter(msg Message) {
+	q.mu.Lock()
+	deadLetterQueue := q.params.DeadLetterQueue
+	q.mu.Unlock()
+
+	q.broker.deadLetter(deadLetterQueue, msg)
+}
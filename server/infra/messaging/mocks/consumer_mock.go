@@ -13,6 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	messaging "github.com/BrianLusina/skillq/server/infra/messaging"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -40,15 +41,15 @@ func (m *MockConsumer) EXPECT() *MockConsumerMockRecorder {
 }
 
 // AddHandler mocks base method.
-func (m *MockConsumer) AddHandler(ctx context.Context, task string, handler func([]byte) error) {
+func (m *MockConsumer) AddHandler(topic string, handler messaging.Handler) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "AddHandler", ctx, task, handler)
+	m.ctrl.Call(m, "AddHandler", topic, handler)
 }
 
 // AddHandler indicates an expected call of AddHandler.
-func (mr *MockConsumerMockRecorder) AddHandler(ctx, task, handler any) *gomock.Call {
+func (mr *MockConsumerMockRecorder) AddHandler(topic, handler any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddHandler", reflect.TypeOf((*MockConsumer)(nil).AddHandler), ctx, task, handler)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddHandler", reflect.TypeOf((*MockConsumer)(nil).AddHandler), topic, handler)
 }
 
 // Consume mocks base method.
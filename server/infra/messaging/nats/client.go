@@ -0,0 +1,108 @@
+package natsbroker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	// _reconnectWait is the delay between attempts to reconnect once the connection is lost unless the configuration sets it
+	_reconnectWait = 2 * time.Second
+
+	// _connectionName is the name the connection is opened with unless the configuration sets it
+	_connectionName = "skillq"
+)
+
+// ErrConnectionClosed is returned when checking the health of a client whose connection is not open
+var ErrConnectionClosed = errors.New("nats connection is not open")
+
+// Config is the configuration to establish a connection to a NATS server
+type Config struct {
+	// URL is the URL of the server, or a comma separated list of the URLs of the servers of a cluster
+	URL string
+
+	// Name is the name of the connection, which the server reports it by
+	Name string
+
+	// ReconnectWait is the delay between attempts to reconnect once the connection is lost. Reconnecting is attempted
+	// until the client is closed
+	ReconnectWait time.Duration
+}
+
+// Client manages the connection to a NATS server & the JetStream context the publisher & consumer use. The connection is
+// re-established whenever it is lost, until the client is closed
+type Client struct {
+	conn      *nats.Conn
+	jetStream jetstream.JetStream
+	logger    logger.Logger
+}
+
+// NewClient creates a client that is connected to a NATS server with JetStream enabled
+func NewClient(config Config, log logger.Logger) (*Client, error) {
+	if config.Name == "" {
+		config.Name = _connectionName
+	}
+	if config.ReconnectWait <= 0 {
+		config.ReconnectWait = _reconnectWait
+	}
+
+	conn, err := nats.Connect(config.URL,
+		nats.Name(config.Name),
+		nats.RetryOnFailedConnect(false),
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(config.ReconnectWait),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				log.Errorf("Lost connection to NATS: %s", err)
+			}
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			log.Infof("Reconnected to NATS at %s", conn.ConnectedUrlRedacted())
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	jetStream, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+
+	log.Infof("Connected to NATS at %s", conn.ConnectedUrlRedacted())
+
+	return &Client{conn: conn, jetStream: jetStream, logger: log}, nil
+}
+
+// JetStream returns the JetStream context of the connection
+func (c *Client) JetStream() jetstream.JetStream {
+	return c.jetStream
+}
+
+// HealthCheck checks that the connection is open & that JetStream is available to the account it is opened with
+func (c *Client) HealthCheck(ctx context.Context) error {
+	if !c.conn.IsConnected() {
+		return ErrConnectionClosed
+	}
+
+	if _, err := c.jetStream.AccountInfo(ctx); err != nil {
+		return fmt.Errorf("failed to get JetStream account info: %w", err)
+	}
+
+	return nil
+}
+
+// Close drains the connection, so that messages that are being published are flushed before it is closed
+func (c *Client) Close() error {
+	if c.conn.IsClosed() {
+		return nil
+	}
+	return c.conn.Drain()
+}
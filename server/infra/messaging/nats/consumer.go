@@ -0,0 +1,532 @@
+package natsbroker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/messaging"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	// _deadLetterStreamSuffix is the suffix of the name of the stream messages of a subscription are dead-lettered to
+	_deadLetterStreamSuffix = "-dlq"
+
+	// _deadLetterSubjectPrefix is the prefix of the subject messages of a subscription are dead-lettered to
+	_deadLetterSubjectPrefix = "dlq."
+
+	// _workers is the number of workers that handle the messages of a subscription unless the subscription sets it
+	_workers = 1
+
+	// _maxRedeliveries is the number of times a message that fails to be handled is redelivered before it is dead-lettered
+	_maxRedeliveries = 3
+
+	// _ackWait is how long the server waits for a message to be acknowledged before it redelivers it, unless it is set
+	_ackWait = 30 * time.Second
+)
+
+var (
+	// ErrNotSubscribed is returned when consuming from a durable consumer the consumer does not subscribe to
+	ErrNotSubscribed = errors.New("consumer does not subscribe to durable consumer")
+
+	// ErrNoSubscriptions is returned when starting a consumer that subscribes to no durable consumers
+	ErrNoSubscriptions = errors.New("consumer subscribes to no durable consumers")
+)
+
+// StreamParams are the parameters of a stream the consumer declares. Streams retain messages until they are acknowledged
+// or terminated, like a work queue
+type StreamParams struct {
+	// Name is the name of the stream
+	Name string
+
+	// Subjects are the subjects the messages of the stream are published to
+	Subjects []string
+
+	// MaxAge is how long messages are kept in the stream, which is forever if it is not set
+	MaxAge time.Duration
+}
+
+// SubscriptionParams are the parameters of a durable pull consumer of a stream the consumer consumes from
+type SubscriptionParams struct {
+	// Durable is the name of the durable consumer, which keeps track of the messages handled from the stream across
+	// restarts
+	Durable string
+
+	// Stream is the name of the stream consumed from
+	Stream string
+
+	// Subjects filter the messages consumed from the stream to those published to the subjects. Every message of the
+	// stream is consumed if no subjects are set
+	Subjects []string
+
+	// Workers is the number of workers that handle the messages of the durable consumer concurrently
+	Workers int
+
+	// Topics are the topics of the messages handled from the stream. Messages of other topics are dead-lettered. The
+	// durable consumer handles the messages of every topic a handler is added for if no topics are set
+	Topics []string
+}
+
+// deadLetterStream is the name of the stream messages of the subscription are dead-lettered to
+func (s SubscriptionParams) deadLetterStream() string {
+	return s.Durable + _deadLetterStreamSuffix
+}
+
+// deadLetterSubject is the subject messages of the subscription are dead-lettered to
+func (s SubscriptionParams) deadLetterSubject() string {
+	return _deadLetterSubjectPrefix + s.Durable
+}
+
+// handles checks if messages of a topic are handled by the durable consumer
+func (s SubscriptionParams) handles(topic string) bool {
+	return len(s.Topics) == 0 || slices.Contains(s.Topics, topic)
+}
+
+// ConsumerOption allows adding options to the NATS consumer
+type ConsumerOption func(*Consumer)
+
+// Stream adds a stream for the consumer to declare, replacing a stream of the same name
+func Stream(params StreamParams) ConsumerOption {
+	return func(c *Consumer) {
+		for i, stream := range c.streams {
+			if stream.Name == params.Name {
+				c.streams[i] = params
+				return
+			}
+		}
+		c.streams = append(c.streams, params)
+	}
+}
+
+// Subscribe adds a durable consumer for the consumer to declare & consume from with a pool of workers of its own,
+// replacing a subscription to a durable consumer of the same name
+func Subscribe(params SubscriptionParams) ConsumerOption {
+	return func(c *Consumer) {
+		if params.Workers <= 0 {
+			params.Workers = _workers
+		}
+
+		for i, sub := range c.subscriptions {
+			if sub.Durable == params.Durable {
+				c.subscriptions[i] = params
+				return
+			}
+		}
+		c.subscriptions = append(c.subscriptions, params)
+	}
+}
+
+// MaxRedeliveries sets the number of times a message that fails to be handled is redelivered before it is dead-lettered.
+// Messages are dead-lettered as soon as they fail if it is zero
+func MaxRedeliveries(maxRedeliveries int) ConsumerOption {
+	return func(c *Consumer) {
+		c.maxRedeliveries = maxRedeliveries
+	}
+}
+
+// RedeliveryDelay sets how long a message that fails to be handled waits before it is redelivered
+func RedeliveryDelay(delay time.Duration) ConsumerOption {
+	return func(c *Consumer) {
+		c.redeliveryDelay = delay
+	}
+}
+
+// DefaultRetryPolicy sets the retry policy of the messages of the topics that have no retry policy of their own, which
+// overrides the max redeliveries & redelivery delay of the consumer. A message is redelivered after the delay of the policy
+// for the number of times it has been redelivered
+func DefaultRetryPolicy(policy messaging.RetryPolicy) ConsumerOption {
+	return func(c *Consumer) {
+		c.defaultRetryPolicy = &policy
+	}
+}
+
+// TopicRetryPolicy sets the retry policy of the messages of a topic
+func TopicRetryPolicy(topic string, policy messaging.RetryPolicy) ConsumerOption {
+	return func(c *Consumer) {
+		c.retryPolicies[topic] = policy
+	}
+}
+
+// AckWait sets how long the server waits for a message to be acknowledged before it redelivers it, which bounds how long
+// a handler may take
+func AckWait(ackWait time.Duration) ConsumerOption {
+	return func(c *Consumer) {
+		c.ackWait = ackWait
+	}
+}
+
+// Consumer consumes messages from the durable pull consumers of JetStream streams it subscribes to, handling them with
+// the handlers added for their topic. A message is acknowledged once it is handled, while a message that fails to be
+// handled is negatively acknowledged, so the server redelivers it, until its redeliveries run out & is then
+// dead-lettered. A message whose payload is invalid or of a topic the durable consumer has no handler for is
+// dead-lettered without being redelivered. Dead-lettered messages are published to a stream of their own & terminated
+type Consumer struct {
+	jetStream       jetstream.JetStream
+	streams         []StreamParams
+	subscriptions   []SubscriptionParams
+	maxRedeliveries int
+	redeliveryDelay time.Duration
+	ackWait         time.Duration
+	handlers        map[string]messaging.Handler
+	logger          logger.Logger
+
+	// defaultRetryPolicy & retryPolicies are the retry policies of the messages of every topic & of the topics that have
+	// one of their own. Messages are redelivered by the max redeliveries & redelivery delay of the consumer if their topic
+	// has no retry policy
+	defaultRetryPolicy *messaging.RetryPolicy
+	retryPolicies      map[string]messaging.RetryPolicy
+
+	// mu guards the handlers, which are added before the consumer is started, & workers being started once the consumer
+	// is stopped
+	mu sync.RWMutex
+
+	// stopped is cancelled once the consumer is stopped, which stops the workers of every subscription
+	stopped context.Context
+	stop    context.CancelFunc
+	workers sync.WaitGroup
+}
+
+var _ messaging.EventSubscriber = (*Consumer)(nil)
+
+// NewConsumer creates a consumer of the JetStream streams of the server the client is connected to
+func NewConsumer(client *Client, log logger.Logger, opts ...ConsumerOption) *Consumer {
+	return newConsumer(client.JetStream(), log, opts...)
+}
+
+// newConsumer creates a consumer of the streams of a JetStream context
+func newConsumer(jetStream jetstream.JetStream, log logger.Logger, opts ...ConsumerOption) *Consumer {
+	stopped, stop := context.WithCancel(context.Background())
+
+	c := &Consumer{
+		jetStream:       jetStream,
+		maxRedeliveries: _maxRedeliveries,
+		ackWait:         _ackWait,
+		handlers:        map[string]messaging.Handler{},
+		retryPolicies:   map[string]messaging.RetryPolicy{},
+		logger:          log,
+		stopped:         stopped,
+		stop:            stop,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Declare creates or updates the streams the consumer is configured with & the durable consumers it subscribes to on the
+// server, with the streams their messages are dead-lettered to
+func (c *Consumer) Declare(ctx context.Context) error {
+	for _, stream := range c.streams {
+		_, err := c.jetStream.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+			Name:      stream.Name,
+			Subjects:  stream.Subjects,
+			Retention: jetstream.WorkQueuePolicy,
+			Storage:   jetstream.FileStorage,
+			MaxAge:    stream.MaxAge,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to declare stream %s: %w", stream.Name, err)
+		}
+	}
+
+	for _, sub := range c.subscriptions {
+		_, err := c.jetStream.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+			Name:     sub.deadLetterStream(),
+			Subjects: []string{sub.deadLetterSubject()},
+			Storage:  jetstream.FileStorage,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to declare stream %s: %w", sub.deadLetterStream(), err)
+		}
+
+		_, err = c.jetStream.CreateOrUpdateConsumer(ctx, sub.Stream, jetstream.ConsumerConfig{
+			Durable:        sub.Durable,
+			FilterSubjects: sub.Subjects,
+			AckPolicy:      jetstream.AckExplicitPolicy,
+			AckWait:        c.ackWait,
+			// messages are dead-lettered by the consumer on their last delivery, this only stops the server redelivering
+			// a message whose handler never returns
+			MaxDeliver: c.maxDeliver(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to declare durable consumer %s of stream %s: %w", sub.Durable, sub.Stream, err)
+		}
+	}
+
+	return nil
+}
+
+// AddHandler adds a handler that will handle the messages of a topic from the durable consumers that handle the topic
+func (c *Consumer) AddHandler(topic string, handler messaging.Handler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers[topic] = handler
+}
+
+// Consume consumes messages from a durable consumer the consumer subscribes to until the consumer is stopped or the
+// context is done. This is a blocking operation
+func (c *Consumer) Consume(ctx context.Context, durable string) error {
+	for _, sub := range c.subscriptions {
+		if sub.Durable == durable {
+			return c.consume(ctx, sub)
+		}
+	}
+
+	return fmt.Errorf("%w: %s", ErrNotSubscribed, durable)
+}
+
+// Start consumes from every durable consumer the consumer subscribes to until the consumer is stopped. It returns once
+// every durable consumer has stopped being consumed from. When consuming from a durable consumer fails, the consumer is
+// stopped & it returns the error once the other durable consumers have stopped being consumed from too. This is a
+// blocking operation
+func (c *Consumer) Start() error {
+	if len(c.subscriptions) == 0 {
+		return ErrNoSubscriptions
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errs := make(chan error, len(c.subscriptions))
+	for _, sub := range c.subscriptions {
+		go func() {
+			errs <- c.consume(ctx, sub)
+		}()
+	}
+
+	var firstErr error
+	for range c.subscriptions {
+		err := <-errs
+		if err == nil || firstErr != nil {
+			continue
+		}
+
+		firstErr = err
+		c.logger.Errorf("Stopping consumer as consuming from a durable consumer failed: %v", err)
+
+		// the durable consumers that are still consumed from are stopped, so that none is left consuming once Start
+		// returns
+		cancel()
+		if err := c.Stop(context.Background()); err != nil {
+			c.logger.Errorf("Failed to stop consumer: %v", err)
+		}
+	}
+
+	return firstErr
+}
+
+// consume consumes messages from the durable consumer of a subscription with its pool of workers until the consumer is
+// stopped or the context is done. The workers stop as soon as one of them fails
+func (c *Consumer) consume(ctx context.Context, sub SubscriptionParams) error {
+	consumer, err := c.jetStream.Consumer(ctx, sub.Stream, sub.Durable)
+	if err != nil {
+		return fmt.Errorf("failed to consume from durable consumer %s: %w", sub.Durable, err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stopAfter := context.AfterFunc(c.stopped, cancel)
+	defer stopAfter()
+
+	c.mu.Lock()
+	if c.stopped.Err() != nil {
+		c.mu.Unlock()
+		return nil
+	}
+	c.workers.Add(sub.Workers)
+	c.mu.Unlock()
+
+	c.logger.Infof("Consuming from durable consumer %s of stream %s with %d workers", sub.Durable, sub.Stream, sub.Workers)
+
+	errs := make(chan error, sub.Workers)
+	for i := 0; i < sub.Workers; i++ {
+		go func() {
+			defer c.workers.Done()
+			errs <- c.work(ctx, sub, consumer)
+		}()
+	}
+
+	var consumeErr error
+	for i := 0; i < sub.Workers; i++ {
+		if err := <-errs; err != nil && consumeErr == nil {
+			consumeErr = err
+			cancel()
+		}
+	}
+
+	c.logger.Infof("Stopped consuming from durable consumer %s", sub.Durable)
+
+	return consumeErr
+}
+
+// work pulls messages from a durable consumer one at a time & handles them until the context is done
+func (c *Consumer) work(ctx context.Context, sub SubscriptionParams, consumer jetstream.Consumer) error {
+	// a worker only pulls the message it handles next, so the others are left to the other workers & are not held while
+	// the worker is busy. Missing heartbeats are not reported, as the client reconnects by itself
+	messages, err := consumer.Messages(jetstream.PullMaxMessages(1), jetstream.WithMessagesErrOnMissingHeartbeat(false))
+	if err != nil {
+		return fmt.Errorf("failed to pull from durable consumer %s: %w", sub.Durable, err)
+	}
+	defer messages.Stop()
+
+	stopAfter := context.AfterFunc(ctx, messages.Stop)
+	defer stopAfter()
+
+	for {
+		msg, err := messages.Next()
+		if errors.Is(err, jetstream.ErrMsgIteratorClosed) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to pull from durable consumer %s: %w", sub.Durable, err)
+		}
+
+		// a message that is being handled is finished when the consumer is stopped
+		c.dispatch(context.WithoutCancel(ctx), sub, msg)
+	}
+}
+
+// dispatch handles a message with the handler of its topic in the context of the message
+func (c *Consumer) dispatch(ctx context.Context, sub SubscriptionParams, msg jetstream.Msg) {
+	ctx = MessageContext(ctx, msg, c.logger)
+	log := logger.FromContext(ctx)
+
+	topic := msg.Headers().Get(HeaderTopic)
+	log.Infof("Processing message %s with Topic %s", msg.Headers().Get(jetstream.MsgIDHeader), topic)
+
+	metadata, err := msg.Metadata()
+	if err != nil {
+		log.Errorf("Failed to read metadata of message, terminating it: %s", err)
+		c.settle(ctx, msg.Term())
+		return
+	}
+
+	handler, ok := c.handler(sub, topic)
+	if !ok {
+		log.Warn(fmt.Sprintf("No handler for message with Topic %s from durable consumer %s, dead-lettering it", topic, sub.Durable))
+		c.deadLetter(ctx, sub, msg)
+		return
+	}
+
+	err = handler(ctx, msg.Data())
+	switch {
+	case errors.Is(err, messaging.ErrInvalidPayload):
+		// handling a poison message again would fail the same way, so it is dead-lettered straight away
+		log.Errorf("Failed to decode message, dead-lettering it: %s", err)
+		c.deadLetter(ctx, sub, msg)
+	case err != nil:
+		log.Errorf("Failed to process message with err: %s", err)
+
+		redeliveries := int(metadata.NumDelivered) - 1
+		maxRedeliveries, delay := c.redelivery(topic, redeliveries)
+		if redeliveries < maxRedeliveries {
+			c.settle(ctx, nak(msg, delay))
+			return
+		}
+
+		log.Errorf("Message with Topic %s failed after %d redeliveries, dead-lettering it", topic, redeliveries)
+		c.deadLetter(ctx, sub, msg)
+	default:
+		c.settle(ctx, msg.Ack())
+	}
+}
+
+// handler is the handler of the messages of a topic consumed from a durable consumer, if the durable consumer handles
+// them & a handler is added for them
+func (c *Consumer) handler(sub SubscriptionParams, topic string) (messaging.Handler, bool) {
+	if !sub.handles(topic) {
+		return nil, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	handler, ok := c.handlers[topic]
+	return handler, ok
+}
+
+// redelivery is the number of times a message of a topic is redelivered before it is dead-lettered & the delay before it
+// is redelivered again after the given number of redeliveries, by the retry policy of the topic if it has one
+func (c *Consumer) redelivery(topic string, redeliveries int) (int, time.Duration) {
+	if policy, ok := c.retryPolicies[topic]; ok {
+		return policy.MaxRetries, policy.Delay(redeliveries)
+	}
+	if c.defaultRetryPolicy != nil {
+		return c.defaultRetryPolicy.MaxRetries, c.defaultRetryPolicy.Delay(redeliveries)
+	}
+	return c.maxRedeliveries, c.redeliveryDelay
+}
+
+// maxDeliver is the number of times the server delivers a message, which covers the redeliveries of every retry policy
+func (c *Consumer) maxDeliver() int {
+	maxRedeliveries := c.maxRedeliveries
+	if c.defaultRetryPolicy != nil {
+		maxRedeliveries = c.defaultRetryPolicy.MaxRetries
+	}
+	for _, policy := range c.retryPolicies {
+		maxRedeliveries = max(maxRedeliveries, policy.MaxRetries)
+	}
+	return maxRedeliveries + 1
+}
+
+// nak negatively acknowledges a message, so that the server redelivers it once the delay passes
+func nak(msg jetstream.Msg, delay time.Duration) error {
+	if delay <= 0 {
+		return msg.Nak()
+	}
+	return msg.NakWithDelay(delay)
+}
+
+// deadLetter publishes a message to the dead letter stream of the subscription with the headers it was published with &
+// terminates it. The message is redelivered instead if it fails to be published, so that it is not lost
+func (c *Consumer) deadLetter(ctx context.Context, sub SubscriptionParams, msg jetstream.Msg) {
+	headers := nats.Header{}
+	for key, values := range msg.Headers() {
+		headers[key] = slices.Clone(values)
+	}
+
+	_, err := c.jetStream.PublishMsg(ctx, &nats.Msg{Subject: sub.deadLetterSubject(), Header: headers, Data: msg.Data()})
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to dead-letter message to subject %s, redelivering it: %s", sub.deadLetterSubject(), err)
+		_, delay := c.redelivery(msg.Headers().Get(HeaderTopic), 0)
+		c.settle(ctx, nak(msg, delay))
+		return
+	}
+
+	c.settle(ctx, msg.Term())
+}
+
+// settle logs the error a message failed to be acknowledged or rejected with
+func (c *Consumer) settle(ctx context.Context, err error) {
+	if err != nil {
+		logger.FromContext(ctx).Errorf("Failed to settle message with err: %s", err)
+	}
+}
+
+// Stop stops consuming from every durable consumer & waits for the workers to return after handling the message they are
+// on. Messages that are not handled yet stay in their streams
+func (c *Consumer) Stop(ctx context.Context) error {
+	c.mu.Lock()
+	c.stop()
+	c.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		c.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("workers of the consumer did not finish: %w", ctx.Err())
+	}
+}
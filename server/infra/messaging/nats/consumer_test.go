@@ -0,0 +1,219 @@
+package natsbroker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/messaging"
+	natstest "github.com/BrianLusina/skillq/server/infra/messaging/nats/test"
+	"github.com/BrianLusina/skillq/server/infra/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumerIntegration(t *testing.T) {
+	ctx := context.Background()
+
+	srv := natstest.RunJetStreamServer(t)
+
+	testLog, _ := logger.NewTestLogger()
+
+	client, err := NewClient(Config{URL: srv.ClientURL()}, testLog)
+	require.NoError(t, err)
+	defer client.Close()
+
+	type task struct {
+		Email string `json:"email"`
+	}
+
+	// newTestConsumer creates a consumer of durable consumers of a stream of its own for the send email & store image
+	// tasks, & a publisher that routes tasks to them
+	newTestConsumer := func(t *testing.T, stream string, opts ...ConsumerOption) (*Consumer, *Publisher) {
+		opts = append([]ConsumerOption{
+			Stream(StreamParams{Name: stream, Subjects: []string{stream + ".>"}}),
+			Subscribe(SubscriptionParams{
+				Durable:  stream + "-send-email",
+				Stream:   stream,
+				Subjects: []string{stream + ".send-email"},
+				Workers:  2,
+				Topics:   []string{"SendEmailVerification", "SendPasswordReset"},
+			}),
+			Subscribe(SubscriptionParams{
+				Durable:  stream + "-store-image",
+				Stream:   stream,
+				Subjects: []string{stream + ".store-image"},
+			}),
+		}, opts...)
+
+		consumer := NewConsumer(client, testLog, opts...)
+		require.NoError(t, consumer.Declare(ctx))
+
+		publisher := NewPublisher(client, testLog,
+			Route("SendEmailVerification", stream+".send-email"),
+			Route("SendPasswordReset", stream+".send-email"),
+			Route("StoreUserImage", stream+".store-image"),
+		)
+
+		started := make(chan error, 1)
+		go func() {
+			started <- consumer.Start()
+		}()
+
+		t.Cleanup(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			require.NoError(t, consumer.Stop(ctx))
+			require.NoError(t, <-started)
+		})
+
+		return consumer, publisher
+	}
+
+	publish := func(t *testing.T, ctx context.Context, publisher *Publisher, topic string, payload any) {
+		require.NoError(t, publisher.Publish(ctx, messaging.New(messaging.MessageParams{Topic: topic, ContentType: "application/json", Payload: payload})))
+	}
+
+	waitForMsgs := func(t *testing.T, stream string, expected uint64) {
+		assert.Eventually(t, func() bool {
+			s, err := client.JetStream().Stream(ctx, stream)
+			if err != nil {
+				return false
+			}
+			info, err := s.Info(ctx)
+			return err == nil && info.State.Msgs == expected
+		}, 10*time.Second, 10*time.Millisecond, "messages in stream %s", stream)
+	}
+
+	t.Run("stops consuming from every durable consumer when consuming from one fails", func(t *testing.T) {
+		handled := make(chan struct{}, 1)
+		declared := NewConsumer(client, testLog,
+			Stream(StreamParams{Name: "failing", Subjects: []string{"failing.>"}}),
+			Subscribe(SubscriptionParams{Durable: "failing-send-email", Stream: "failing", Subjects: []string{"failing.send-email"}}),
+		)
+		require.NoError(t, declared.Declare(ctx))
+
+		// the durable consumer of the second subscription is never declared, so consuming from it fails
+		consumer := NewConsumer(client, testLog,
+			Subscribe(SubscriptionParams{Durable: "failing-send-email", Stream: "failing", Subjects: []string{"failing.send-email"}}),
+			Subscribe(SubscriptionParams{Durable: "failing-undeclared", Stream: "failing", Subjects: []string{"failing.undeclared"}}),
+		)
+		consumer.AddHandler("SendEmailVerification", func(ctx context.Context, payload []byte) error {
+			handled <- struct{}{}
+			return nil
+		})
+
+		started := make(chan error, 1)
+		go func() {
+			started <- consumer.Start()
+		}()
+
+		select {
+		case err := <-started:
+			assert.ErrorContains(t, err, "failing-undeclared")
+		case <-time.After(5 * time.Second):
+			t.Fatal("start did not return")
+		}
+
+		// the durable consumer that was consumed from is no longer consumed from once start returns
+		publisher := NewPublisher(client, testLog, Route("SendEmailVerification", "failing.send-email"))
+		publish(t, ctx, publisher, "SendEmailVerification", task{Email: "jane@example.com"})
+
+		select {
+		case <-handled:
+			t.Fatal("task was handled after start returned")
+		case <-time.After(200 * time.Millisecond):
+		}
+	})
+
+	t.Run("checks the health of the connection", func(t *testing.T) {
+		assert.NoError(t, client.HealthCheck(ctx))
+	})
+
+	t.Run("handles the tasks of every durable consumer with the handlers of their topic & request ID", func(t *testing.T) {
+		var mu sync.Mutex
+		handled := map[string][]string{}
+		requestIDs := make(chan string, 2)
+
+		consumer, publisher := newTestConsumer(t, "handled")
+		record := func(topic string) messaging.Handler {
+			return func(ctx context.Context, payload []byte) error {
+				var task task
+				if err := json.Unmarshal(payload, &task); err != nil {
+					return err
+				}
+
+				requestID, _ := requestid.FromContext(ctx)
+				requestIDs <- requestID
+
+				mu.Lock()
+				defer mu.Unlock()
+				handled[topic] = append(handled[topic], task.Email)
+				return nil
+			}
+		}
+		consumer.AddHandler("SendEmailVerification", record("SendEmailVerification"))
+		consumer.AddHandler("StoreUserImage", record("StoreUserImage"))
+
+		publish(t, requestid.WithRequestID(ctx, "request-1"), publisher, "SendEmailVerification", task{Email: "jane@example.com"})
+		publish(t, requestid.WithRequestID(ctx, "request-1"), publisher, "StoreUserImage", task{Email: "john@example.com"})
+
+		assert.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(handled["SendEmailVerification"]) == 1 && len(handled["StoreUserImage"]) == 1
+		}, 10*time.Second, 10*time.Millisecond)
+		assert.Equal(t, []string{"jane@example.com"}, handled["SendEmailVerification"])
+		assert.Equal(t, []string{"john@example.com"}, handled["StoreUserImage"])
+		assert.Equal(t, "request-1", <-requestIDs)
+		assert.Equal(t, "request-1", <-requestIDs)
+
+		// handled tasks are removed from the work queue
+		waitForMsgs(t, "handled", 0)
+	})
+
+	t.Run("redelivers failed tasks until their redeliveries run out, then dead-letters them", func(t *testing.T) {
+		var mu sync.Mutex
+		attempts := 0
+
+		consumer, publisher := newTestConsumer(t, "failed", MaxRedeliveries(2), RedeliveryDelay(10*time.Millisecond))
+		consumer.AddHandler("SendEmailVerification", func(ctx context.Context, payload []byte) error {
+			mu.Lock()
+			defer mu.Unlock()
+			attempts++
+			return errors.New("smtp unavailable")
+		})
+
+		publish(t, ctx, publisher, "SendEmailVerification", task{Email: "jane@example.com"})
+
+		waitForMsgs(t, "failed-send-email-dlq", 1)
+		waitForMsgs(t, "failed", 0)
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, 3, attempts, "handled once & redelivered twice")
+	})
+
+	t.Run("dead-letters tasks that have no handler without redelivering them", func(t *testing.T) {
+		_, publisher := newTestConsumer(t, "unhandled")
+
+		publish(t, ctx, publisher, "SendPasswordReset", task{Email: "jane@example.com"})
+
+		waitForMsgs(t, "unhandled-send-email-dlq", 1)
+	})
+
+	t.Run("publishes a task that is published again only once", func(t *testing.T) {
+		// the stream is not consumed from, so that the tasks stay in it
+		require.NoError(t, NewConsumer(client, testLog, Stream(StreamParams{Name: "duplicated", Subjects: []string{"duplicated.>"}})).Declare(ctx))
+		publisher := NewPublisher(client, testLog, Route("StoreUserImage", "duplicated.store-image"))
+
+		message := messaging.New(messaging.MessageParams{Topic: "StoreUserImage", ContentType: "application/json", Payload: task{Email: "jane@example.com"}})
+		require.NoError(t, publisher.Publish(ctx, message))
+		require.NoError(t, publisher.Publish(ctx, message))
+
+		waitForMsgs(t, "duplicated", 1)
+	})
+}
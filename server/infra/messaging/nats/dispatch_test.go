@@ -0,0 +1,203 @@
+package natsbroker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/messaging"
+	"github.com/BrianLusina/skillq/server/infra/requestid"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMsg is a JetStream message that records how it is settled
+type fakeMsg struct {
+	jetstream.Msg
+
+	headers      nats.Header
+	data         []byte
+	numDelivered uint64
+
+	settled string
+	delay   time.Duration
+}
+
+func (m *fakeMsg) Metadata() (*jetstream.MsgMetadata, error) {
+	return &jetstream.MsgMetadata{NumDelivered: m.numDelivered}, nil
+}
+
+func (m *fakeMsg) Headers() nats.Header { return m.headers }
+func (m *fakeMsg) Data() []byte         { return m.data }
+func (m *fakeMsg) Ack() error           { m.settled = "ack"; return nil }
+func (m *fakeMsg) Nak() error           { m.settled = "nak"; return nil }
+func (m *fakeMsg) Term() error          { m.settled = "term"; return nil }
+
+func (m *fakeMsg) NakWithDelay(delay time.Duration) error {
+	m.settled, m.delay = "nak", delay
+	return nil
+}
+
+// fakeJetStream records the messages that are published to it
+type fakeJetStream struct {
+	jetstream.JetStream
+
+	mu        sync.Mutex
+	published []*nats.Msg
+	err       error
+}
+
+func (f *fakeJetStream) PublishMsg(_ context.Context, msg *nats.Msg, _ ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.published = append(f.published, msg)
+	return &jetstream.PubAck{Stream: "send-email-dlq"}, nil
+}
+
+func TestConsumerDispatch(t *testing.T) {
+	log, _ := logger.NewTestLogger()
+
+	sub := SubscriptionParams{Durable: "send-email", Stream: "tasks", Topics: []string{"SendEmailVerification", "SendPasswordReset"}}
+
+	newMsg := func(topic string, numDelivered uint64) *fakeMsg {
+		headers := nats.Header{}
+		headers.Set(HeaderTopic, topic)
+		headers.Set(jetstream.MsgIDHeader, "message-1")
+		return &fakeMsg{headers: headers, data: []byte(`{"email":"jane@example.com"}`), numDelivered: numDelivered}
+	}
+
+	t.Run("acknowledges messages that are handled", func(t *testing.T) {
+		consumer := newConsumer(&fakeJetStream{}, log)
+
+		var payload []byte
+		consumer.AddHandler("SendEmailVerification", func(ctx context.Context, p []byte) error {
+			payload = p
+			return nil
+		})
+
+		msg := newMsg("SendEmailVerification", 1)
+		consumer.dispatch(context.Background(), sub, msg)
+
+		assert.Equal(t, "ack", msg.settled)
+		assert.JSONEq(t, `{"email":"jane@example.com"}`, string(payload))
+	})
+
+	t.Run("handles messages with the request ID they were published with", func(t *testing.T) {
+		consumer := newConsumer(&fakeJetStream{}, log)
+
+		var requestID string
+		consumer.AddHandler("SendEmailVerification", func(ctx context.Context, _ []byte) error {
+			requestID, _ = requestid.FromContext(ctx)
+			return nil
+		})
+
+		msg := newMsg("SendEmailVerification", 1)
+		msg.headers.Set(HeaderRequestID, "request-1")
+		consumer.dispatch(context.Background(), sub, msg)
+
+		assert.Equal(t, "request-1", requestID)
+	})
+
+	t.Run("redelivers failed messages after the redelivery delay until their redeliveries run out", func(t *testing.T) {
+		jetStream := &fakeJetStream{}
+		consumer := newConsumer(jetStream, log, MaxRedeliveries(2), RedeliveryDelay(time.Second))
+		consumer.AddHandler("SendEmailVerification", func(ctx context.Context, _ []byte) error {
+			return errors.New("smtp unavailable")
+		})
+
+		for numDelivered := uint64(1); numDelivered <= 2; numDelivered++ {
+			msg := newMsg("SendEmailVerification", numDelivered)
+			consumer.dispatch(context.Background(), sub, msg)
+
+			assert.Equal(t, "nak", msg.settled, "delivery %d", numDelivered)
+			assert.Equal(t, time.Second, msg.delay, "delivery %d", numDelivered)
+		}
+		assert.Empty(t, jetStream.published)
+
+		msg := newMsg("SendEmailVerification", 3)
+		consumer.dispatch(context.Background(), sub, msg)
+
+		assert.Equal(t, "term", msg.settled)
+		require.Len(t, jetStream.published, 1)
+		assert.Equal(t, "dlq.send-email", jetStream.published[0].Subject)
+		assert.Equal(t, "SendEmailVerification", jetStream.published[0].Header.Get(HeaderTopic))
+		assert.Equal(t, msg.data, jetStream.published[0].Data)
+	})
+
+	t.Run("redelivers failed messages by the retry policy of their topic with a growing delay", func(t *testing.T) {
+		jetStream := &fakeJetStream{}
+		consumer := newConsumer(jetStream, log,
+			DefaultRetryPolicy(messaging.RetryPolicy{MaxRetries: 0}),
+			TopicRetryPolicy("SendEmailVerification", messaging.RetryPolicy{
+				MaxRetries:   2,
+				InitialDelay: time.Second,
+				MaxDelay:     time.Minute,
+				Multiplier:   3,
+			}),
+		)
+		failing := func(ctx context.Context, _ []byte) error {
+			return errors.New("smtp unavailable")
+		}
+		consumer.AddHandler("SendEmailVerification", failing)
+		consumer.AddHandler("SendPasswordReset", failing)
+
+		for numDelivered, delay := range map[uint64]time.Duration{1: time.Second, 2: 3 * time.Second} {
+			msg := newMsg("SendEmailVerification", numDelivered)
+			consumer.dispatch(context.Background(), sub, msg)
+
+			assert.Equal(t, "nak", msg.settled, "delivery %d", numDelivered)
+			assert.Equal(t, delay, msg.delay, "delivery %d", numDelivered)
+		}
+		assert.Empty(t, jetStream.published)
+
+		msg := newMsg("SendEmailVerification", 3)
+		consumer.dispatch(context.Background(), sub, msg)
+		assert.Equal(t, "term", msg.settled)
+
+		// the default retry policy dead-letters the messages of other topics as soon as they fail
+		msg = newMsg("SendPasswordReset", 1)
+		consumer.dispatch(context.Background(), sub, msg)
+		assert.Equal(t, "term", msg.settled)
+
+		assert.Len(t, jetStream.published, 2)
+		assert.Equal(t, 3, consumer.maxDeliver())
+	})
+
+	t.Run("dead-letters messages that are invalid or have no handler without redelivering them", func(t *testing.T) {
+		jetStream := &fakeJetStream{}
+		consumer := newConsumer(jetStream, log)
+		consumer.AddHandler("SendEmailVerification", func(ctx context.Context, _ []byte) error {
+			return fmt.Errorf("%w: missing email", messaging.ErrInvalidPayload)
+		})
+		consumer.AddHandler("StoreUserImage", func(ctx context.Context, _ []byte) error {
+			return nil
+		})
+
+		for _, topic := range []string{"SendEmailVerification", "SendPasswordReset", "StoreUserImage"} {
+			msg := newMsg(topic, 1)
+			consumer.dispatch(context.Background(), sub, msg)
+
+			assert.Equal(t, "term", msg.settled, "message with topic %s", topic)
+		}
+		assert.Len(t, jetStream.published, 3)
+	})
+
+	t.Run("redelivers messages that fail to be dead-lettered", func(t *testing.T) {
+		consumer := newConsumer(&fakeJetStream{err: errors.New("no stream")}, log)
+
+		msg := newMsg("SendPasswordReset", 1)
+		consumer.dispatch(context.Background(), sub, msg)
+
+		assert.Equal(t, "nak", msg.settled)
+	})
+}
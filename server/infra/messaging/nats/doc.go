@@ -0,0 +1,4 @@
+// Package natsbroker contains a publisher & a consumer for NATS JetStream, an alternative to RabbitMQ as the broker tasks
+// are published to & consumed from. Topics are published to subjects captured by streams, & durable pull consumers of
+// the streams handle them, acknowledging, redelivering & dead-lettering messages the same way the AMQP consumer does
+package natsbroker
@@ -0,0 +1,69 @@
+package natsbroker
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/messaging"
+	"github.com/BrianLusina/skillq/server/infra/requestid"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	// HeaderRequestID is the message header carrying the ID of the request a message was published while handling
+	HeaderRequestID = messaging.HeaderRequestID
+
+	// HeaderTopic is the message header carrying the topic of a message, which selects the handler it is handled with
+	HeaderTopic = "x-topic"
+
+	// HeaderContentType is the message header carrying the content type of the payload of a message
+	HeaderContentType = "content-type"
+
+	// HeaderTimestamp is the message header carrying when a message was created, in RFC 3339 format
+	HeaderTimestamp = "x-timestamp"
+)
+
+// headerCarrier carries the trace context in the headers of a message. The headers are canonicalized the same way as
+// HTTP headers, as the trace context is injected & extracted through the same carrier
+func headerCarrier(headers nats.Header) propagation.HeaderCarrier {
+	return propagation.HeaderCarrier(http.Header(headers))
+}
+
+// headersFromContext builds the headers of a message of a topic published with the context, carrying the request ID of
+// the context if it has one & the trace context of its span
+func headersFromContext(ctx context.Context, topic, contentType string, timestamp time.Time) nats.Header {
+	headers := nats.Header{}
+	headers.Set(HeaderTopic, topic)
+	if contentType != "" {
+		headers.Set(HeaderContentType, contentType)
+	}
+	if !timestamp.IsZero() {
+		headers.Set(HeaderTimestamp, timestamp.UTC().Format(time.RFC3339Nano))
+	}
+	if requestID, ok := requestid.FromContext(ctx); ok {
+		headers.Set(HeaderRequestID, requestID)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier(headers))
+	return headers
+}
+
+// MessageContext returns a copy of the context to handle a message with. It carries the request ID & trace context from
+// the headers of the message, or a new request ID if the publisher did not set one, & a logger that adds the request ID &
+// message ID to every line it logs
+func MessageContext(ctx context.Context, msg jetstream.Msg, log logger.Logger) context.Context {
+	headers := msg.Headers()
+
+	requestID := headers.Get(HeaderRequestID)
+	if !requestid.IsValid(requestID) {
+		requestID = requestid.New()
+	}
+
+	ctx = otel.GetTextMapPropagator().Extract(ctx, headerCarrier(headers))
+	ctx = requestid.WithRequestID(ctx, requestID)
+	return logger.WithLogger(ctx, log.With("requestID", requestID, "messageID", headers.Get(jetstream.MsgIDHeader)))
+}
@@ -0,0 +1,88 @@
+package natsbroker
+
+import (
+	"context"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/messaging"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/pkg/errors"
+)
+
+// Publisher publishes messages to the subjects of JetStream streams
+type Publisher struct {
+	jetStream jetstream.JetStream
+	subjects  map[string]string
+	logger    logger.Logger
+}
+
+var _ messaging.EventPublisher = (*Publisher)(nil)
+
+// PublisherOption allows adding options to the NATS publisher
+type PublisherOption func(*Publisher)
+
+// Route routes the messages of a topic to a subject. Messages of a topic that is not routed are published to the subject
+// named after the topic
+func Route(topic, subject string) PublisherOption {
+	return func(p *Publisher) {
+		p.subjects[topic] = subject
+	}
+}
+
+// NewPublisher creates a publisher of messages to the JetStream streams of the server the client is connected to
+func NewPublisher(client *Client, log logger.Logger, opts ...PublisherOption) *Publisher {
+	p := &Publisher{
+		jetStream: client.JetStream(),
+		subjects:  map[string]string{},
+		logger:    log,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// subject is the subject the messages of a topic are published to
+func (p *Publisher) subject(topic string) string {
+	if subject, ok := p.subjects[topic]; ok {
+		return subject
+	}
+	return topic
+}
+
+// Publish publishes a message to the subject its topic is routed to & waits for the stream capturing the subject to
+// acknowledge it. The ID of the message is its JetStream message ID, so a message that is published again within the
+// duplicate window of the stream is only stored once
+func (p *Publisher) Publish(ctx context.Context, message messaging.Message) error {
+	log := logger.FromContextOr(ctx, p.logger)
+
+	body, err := message.PayloadToBytes()
+	if err != nil {
+		log.Errorf("Failed to parse message: %v", err)
+		return errors.Wrapf(err, "failed to parse message event")
+	}
+
+	subject := p.subject(message.Topic)
+
+	ack, err := p.jetStream.PublishMsg(ctx, &nats.Msg{
+		Subject: subject,
+		Header:  headersFromContext(ctx, message.Topic, message.ContentType, message.Timestamp),
+		Data:    body,
+	}, jetstream.WithMsgID(message.ID))
+	if err != nil {
+		log.Errorf("Failed to publish message %v to subject %s: %v", message, subject, err)
+		return errors.Wrapf(err, "failed to publish message %s", message.ID)
+	}
+
+	log.Infof("Successfully published message %v to subject: %s, in stream: %s", message, subject, ack.Stream)
+
+	return nil
+}
+
+// Close does nothing, as the connection is owned by the client, which is closed separately
+func (p *Publisher) Close() error {
+	return nil
+}
@@ -0,0 +1,41 @@
+package natstest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+// _readyTimeout is how long the server is given to accept connections once it is started
+const _readyTimeout = 5 * time.Second
+
+// RunJetStreamServer starts a NATS server with JetStream enabled within the test process, listening on a random port &
+// storing its streams in a temporary directory. The server is shut down once the test finishes
+func RunJetStreamServer(t testing.TB) *server.Server {
+	t.Helper()
+
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      server.RANDOM_PORT,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create nats server: %s", err)
+	}
+
+	go srv.Start()
+	t.Cleanup(func() {
+		srv.Shutdown()
+		srv.WaitForShutdown()
+	})
+
+	if !srv.ReadyForConnections(_readyTimeout) {
+		t.Fatalf("nats server is not ready for connections")
+	}
+
+	return srv
+}
@@ -0,0 +1,33 @@
+package messaging
+
+import (
+	"math"
+	"time"
+)
+
+// RetryPolicy is how deliveries of a type that fail to be handled are retried. A delivery is retried after a delay that
+// grows exponentially with the number of times it has been retried, & is dead-lettered once it has been retried the
+// maximum number of times
+type RetryPolicy struct {
+	// MaxRetries is the number of times a delivery is retried before it is dead-lettered. Deliveries are dead-lettered
+	// as soon as they fail if it is zero
+	MaxRetries int
+
+	// InitialDelay is the delay before a delivery is retried the first time
+	InitialDelay time.Duration
+
+	// MaxDelay caps the delay before a delivery is retried
+	MaxDelay time.Duration
+
+	// Multiplier is the factor the delay grows by with every retry
+	Multiplier float64
+}
+
+// Delay is the delay before a delivery that has been retried the given number of times is retried again
+func (p RetryPolicy) Delay(retries int) time.Duration {
+	delay := time.Duration(float64(p.InitialDelay) * math.Pow(p.Multiplier, float64(retries)))
+	if p.MaxDelay > 0 && (delay > p.MaxDelay || delay <= 0) {
+		return p.MaxDelay
+	}
+	return delay
+}
@@ -0,0 +1,3 @@
+// Package metrics contains the registry the Prometheus metrics of the application are registered with, the handler that
+// exposes them for scraping & the metrics of HTTP requests
+package metrics
@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace prefixes the names of the metrics of the application
+const Namespace = "skillq"
+
+const (
+	// OutcomeSuccess labels an operation that succeeded
+	OutcomeSuccess = "success"
+
+	// OutcomeError labels an operation that failed
+	OutcomeError = "error"
+)
+
+// NewRegistry creates a registry with the metrics of the Go runtime & of the process already registered
+func NewRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return registry
+}
+
+// Handler returns an HTTP handler that exposes the metrics of the registry in the Prometheus exposition format
+func Handler(registry *prometheus.Registry) http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// Outcome labels an operation by the error it returned
+func Outcome(err error) string {
+	if err != nil {
+		return OutcomeError
+	}
+	return OutcomeSuccess
+}
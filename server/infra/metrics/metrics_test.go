@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutcome(t *testing.T) {
+	assert.Equal(t, OutcomeSuccess, Outcome(nil))
+	assert.Equal(t, OutcomeError, Outcome(errors.New("failed")))
+}
+
+func TestHTTPMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m := NewHTTPMetrics(registry)
+
+	m.Observe("GET", "/api/v1/users/:id", 200, 10*time.Millisecond)
+	m.Observe("GET", "/api/v1/users/:id", 200, 20*time.Millisecond)
+	m.Observe("GET", "/api/v1/users/:id", 404, 5*time.Millisecond)
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.requests.WithLabelValues("GET", "/api/v1/users/:id", "200")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.requests.WithLabelValues("GET", "/api/v1/users/:id", "404")))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.duration))
+}
+
+func TestHandler(t *testing.T) {
+	registry := NewRegistry()
+	NewHTTPMetrics(registry).Observe("GET", "/healthz", 200, time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	Handler(registry).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body, err := io.ReadAll(rec.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, rec.Code)
+	assert.True(t, strings.Contains(string(body), `skillq_http_requests_total{method="GET",route="/healthz",status="200"} 1`))
+	assert.True(t, strings.Contains(string(body), "go_goroutines"))
+}
@@ -20,15 +20,29 @@ type MongoDBClient[T any] interface {
 	// FindAll retrieves all the items with a given filter
 	FindAll(ctx context.Context, filterOptions FilterOptions) ([]T, error)
 
+	// Count counts all the items matching the field filter of the given filter options
+	Count(ctx context.Context, filterOptions FilterOptions) (int64, error)
+
 	// Update updates the model
 	Update(ctx context.Context, model T, updateOptions UpdateOptions) error
 
+	// UpdateMany updates all the documents matching the filter params of the update options & returns the number of documents
+	// that were modified
+	UpdateMany(ctx context.Context, updateOptions UpdateOptions) (int64, error)
+
+	// FindOneAndUpdate atomically updates the document matching the filter params & conditions of the update options & returns
+	// the document as it is after the update. A not found error is returned if no document matches & none is upserted
+	FindOneAndUpdate(ctx context.Context, updateOptions UpdateOptions) (T, error)
+
 	// Delete deletes a record given it's ID name and the id value
 	Delete(ctx context.Context, keyName string, id string) error
 
 	// Disconnect disconnects from the current connection
 	Disconnect(context.Context) error
 
-	// CreateIndex creates unique index for the given keys
+	// HealthCheck pings the primary of the database to check that it can be reached
+	HealthCheck(ctx context.Context) error
+
+	// CreateIndex creates an index for the given keys
 	CreateIndex(ctx context.Context, indexParam IndexParam) (string, error)
 }
@@ -0,0 +1,94 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// Connection is a connection to a mongo database that the clients of its collections can share. The operations of the
+// clients that share a connection can be run in the same transaction
+type Connection struct {
+	mongoClient *mongo.Client
+	database    *mongo.Database
+	logger      logger.Logger
+
+	disconnectOnce sync.Once
+	disconnectErr  error
+}
+
+// Connect connects to the mongo database of the configuration
+func Connect(config MongoDBConfig, log logger.Logger) (*Connection, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	uri := fmt.Sprintf("mongodb://%s:%s@%s:%s", config.Client.User, config.Client.Password, config.Client.Host, config.Client.Port)
+	clientOptions := options.Client().ApplyURI(uri)
+
+	clientOptions.Hosts = []string{config.Client.Host}
+	clientOptions.SetRetryWrites(config.Client.RetryWrites)
+
+	dbClient, err := mongo.Connect(ctx, clientOptions)
+	if err != nil {
+		log.Errorf("failed to connect to mongo db: %v", err)
+		return nil, errdefs.NewUnavailableError("failed to connect to mongo DB", err)
+	}
+
+	// TODO: set database options if provided
+	dbOptions := options.Database()
+
+	db := dbClient.Database(config.DBConfig.DatabaseName, dbOptions)
+	if err := dbClient.Ping(ctx, readpref.Primary()); err != nil {
+		log.Errorf("DB Connection failed with err: %v", err)
+		return nil, errdefs.NewUnavailableError("failed to ping mongo DB", err)
+	}
+
+	log.Infof("connected to mongo db %s", config.DBConfig.DatabaseName)
+
+	return &Connection{
+		mongoClient: dbClient,
+		database:    db,
+		logger:      log,
+	}, nil
+}
+
+// WithTransaction runs fn in a transaction, which is committed if fn returns no error & aborted otherwise. The operations
+// of the clients of the connection that are given the context passed to fn are part of the transaction. The transaction
+// is retried as a whole if it fails with an error the database labels as transient, so fn may be called more than once.
+// Transactions require the database to be a replica set
+func (c *Connection) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := c.mongoClient.StartSession()
+	if err != nil {
+		return mapError(err, "failed to start session")
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (any, error) {
+		return nil, fn(sessionCtx)
+	})
+
+	return err
+}
+
+// HealthCheck pings the primary of the database to check that it can be reached
+func (c *Connection) HealthCheck(ctx context.Context) error {
+	if err := c.mongoClient.Ping(ctx, readpref.Primary()); err != nil {
+		return errdefs.NewUnavailableError("failed to ping mongo DB", err)
+	}
+	return nil
+}
+
+// Disconnect disconnects from the database. Disconnecting a connection that is already disconnected is a no-op
+func (c *Connection) Disconnect(ctx context.Context) error {
+	c.disconnectOnce.Do(func() {
+		c.disconnectErr = c.mongoClient.Disconnect(ctx)
+	})
+	return c.disconnectErr
+}
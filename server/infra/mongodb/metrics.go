@@ -0,0 +1,151 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/infra/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// outcomeNotFound labels an operation that found no document, which is not counted as a failure
+const outcomeNotFound = "not_found"
+
+// Metrics are the metrics of the operations of mongo DB clients. A single instance is shared by the clients of every
+// collection, which are told apart by the collection label
+type Metrics struct {
+	operations *prometheus.CounterVec
+	duration   *prometheus.HistogramVec
+}
+
+// NewMetrics creates the metrics of mongo DB operations & registers them with the registerer
+func NewMetrics(registerer prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "mongodb",
+			Name:      "operations_total",
+			Help:      "Number of mongo DB operations by collection, operation & outcome.",
+		}, []string{"collection", "operation", "outcome"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "mongodb",
+			Name:      "operation_duration_seconds",
+			Help:      "Duration of mongo DB operations by collection & operation.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"collection", "operation"}),
+	}
+
+	registerer.MustRegister(m.operations, m.duration)
+
+	return m
+}
+
+// observe records an operation on the collection that started at the given time & returned the error
+func (m *Metrics) observe(collection, operation string, start time.Time, err error) {
+	outcome := metrics.Outcome(err)
+	if errdefs.IsNotFound(err) {
+		outcome = outcomeNotFound
+	}
+
+	m.operations.WithLabelValues(collection, operation, outcome).Inc()
+	m.duration.WithLabelValues(collection, operation).Observe(time.Since(start).Seconds())
+}
+
+// instrumentedClient decorates a mongo DB client, recording metrics of the operations on its collection
+type instrumentedClient[T any] struct {
+	client     MongoDBClient[T]
+	collection string
+	metrics    *Metrics
+}
+
+var _ MongoDBClient[any] = (*instrumentedClient[any])(nil)
+
+// NewInstrumentedClient decorates a mongo DB client of the given collection with metrics
+func NewInstrumentedClient[T any](client MongoDBClient[T], collection string, m *Metrics) MongoDBClient[T] {
+	return &instrumentedClient[T]{
+		client:     client,
+		collection: collection,
+		metrics:    m,
+	}
+}
+
+func (c *instrumentedClient[T]) Insert(ctx context.Context, model T) (primitive.ObjectID, error) {
+	start := time.Now()
+	id, err := c.client.Insert(ctx, model)
+	c.metrics.observe(c.collection, "insert", start, err)
+	return id, err
+}
+
+func (c *instrumentedClient[T]) BulkInsert(ctx context.Context, models []any) ([]primitive.ObjectID, error) {
+	start := time.Now()
+	ids, err := c.client.BulkInsert(ctx, models)
+	c.metrics.observe(c.collection, "bulk_insert", start, err)
+	return ids, err
+}
+
+func (c *instrumentedClient[T]) FindById(ctx context.Context, keyName string, id string) (T, error) {
+	start := time.Now()
+	model, err := c.client.FindById(ctx, keyName, id)
+	c.metrics.observe(c.collection, "find_by_id", start, err)
+	return model, err
+}
+
+func (c *instrumentedClient[T]) FindAll(ctx context.Context, filterOptions FilterOptions) ([]T, error) {
+	start := time.Now()
+	models, err := c.client.FindAll(ctx, filterOptions)
+	c.metrics.observe(c.collection, "find_all", start, err)
+	return models, err
+}
+
+func (c *instrumentedClient[T]) Count(ctx context.Context, filterOptions FilterOptions) (int64, error) {
+	start := time.Now()
+	count, err := c.client.Count(ctx, filterOptions)
+	c.metrics.observe(c.collection, "count", start, err)
+	return count, err
+}
+
+func (c *instrumentedClient[T]) Update(ctx context.Context, model T, updateOptions UpdateOptions) error {
+	start := time.Now()
+	err := c.client.Update(ctx, model, updateOptions)
+	c.metrics.observe(c.collection, "update", start, err)
+	return err
+}
+
+func (c *instrumentedClient[T]) UpdateMany(ctx context.Context, updateOptions UpdateOptions) (int64, error) {
+	start := time.Now()
+	modified, err := c.client.UpdateMany(ctx, updateOptions)
+	c.metrics.observe(c.collection, "update_many", start, err)
+	return modified, err
+}
+
+func (c *instrumentedClient[T]) FindOneAndUpdate(ctx context.Context, updateOptions UpdateOptions) (T, error) {
+	start := time.Now()
+	model, err := c.client.FindOneAndUpdate(ctx, updateOptions)
+	c.metrics.observe(c.collection, "find_one_and_update", start, err)
+	return model, err
+}
+
+func (c *instrumentedClient[T]) Delete(ctx context.Context, keyName string, id string) error {
+	start := time.Now()
+	err := c.client.Delete(ctx, keyName, id)
+	c.metrics.observe(c.collection, "delete", start, err)
+	return err
+}
+
+func (c *instrumentedClient[T]) CreateIndex(ctx context.Context, indexParam IndexParam) (string, error) {
+	start := time.Now()
+	name, err := c.client.CreateIndex(ctx, indexParam)
+	c.metrics.observe(c.collection, "create_index", start, err)
+	return name, err
+}
+
+func (c *instrumentedClient[T]) Disconnect(ctx context.Context) error {
+	return c.client.Disconnect(ctx)
+}
+
+func (c *instrumentedClient[T]) HealthCheck(ctx context.Context) error {
+	return c.client.HealthCheck(ctx)
+}
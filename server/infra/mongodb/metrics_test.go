@@ -0,0 +1,56 @@
+package mongodb_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/infra/mongodb"
+	mockmongodb "github.com/BrianLusina/skillq/server/infra/mongodb/mocks"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type testModel struct {
+	ID string
+}
+
+func TestInstrumentedClient(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	mockClient := mockmongodb.NewMockMongoDBClient[testModel](mockCtrl)
+
+	registry := prometheus.NewRegistry()
+	client := mongodb.NewInstrumentedClient[testModel](mockClient, "users", mongodb.NewMetrics(registry))
+
+	mockClient.EXPECT().FindById(ctx, "id", "1").Return(testModel{ID: "1"}, nil).Times(1)
+	mockClient.EXPECT().FindById(ctx, "id", "2").Return(testModel{}, errdefs.NewNotFoundError("not found", nil)).Times(1)
+	mockClient.EXPECT().Delete(ctx, "id", "1").Return(errors.New("connection reset")).Times(1)
+	mockClient.EXPECT().Disconnect(ctx).Return(nil).Times(1)
+
+	model, err := client.FindById(ctx, "id", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", model.ID)
+
+	_, err = client.FindById(ctx, "id", "2")
+	assert.True(t, errdefs.IsNotFound(err))
+
+	assert.Error(t, client.Delete(ctx, "id", "1"))
+
+	// disconnecting is not an operation on the collection, so it is not recorded
+	assert.NoError(t, client.Disconnect(ctx))
+
+	expected := `
+# HELP skillq_mongodb_operations_total Number of mongo DB operations by collection, operation & outcome.
+# TYPE skillq_mongodb_operations_total counter
+skillq_mongodb_operations_total{collection="users",operation="delete",outcome="error"} 1
+skillq_mongodb_operations_total{collection="users",operation="find_by_id",outcome="not_found"} 1
+skillq_mongodb_operations_total{collection="users",operation="find_by_id",outcome="success"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "skillq_mongodb_operations_total"))
+	assert.Equal(t, 2, testutil.CollectAndCount(registry, "skillq_mongodb_operation_duration_seconds"))
+}
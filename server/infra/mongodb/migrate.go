@@ -0,0 +1,55 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// migrationsCollection is the collection that records the migrations that have been applied to a database
+const migrationsCollection = "migrations"
+
+// Migration is a change to the documents of a database that is applied once. Its ID identifies it in the migrations
+// collection, so it must not change once the migration has been released
+type Migration struct {
+	ID string
+	Up func(ctx context.Context, db *mongo.Database) error
+}
+
+// migrationRecord is a migration that has been applied to a database
+type migrationRecord struct {
+	ID        string    `bson:"_id"`
+	AppliedAt time.Time `bson:"appliedAt"`
+}
+
+// Migrate applies the migrations that have not yet been applied to the database of the connection in the order they are
+// given & records each one once it succeeds. Migrations should be idempotent, as a migration that fails part way through
+// is applied again the next time
+func (c *Connection) Migrate(ctx context.Context, migrations ...Migration) error {
+	collection := c.database.Collection(migrationsCollection)
+
+	for _, migration := range migrations {
+		err := collection.FindOne(ctx, bson.M{"_id": migration.ID}).Err()
+		if err == nil {
+			continue
+		}
+		if err != mongo.ErrNoDocuments {
+			return mapError(err, fmt.Sprintf("failed to check migration %s", migration.ID))
+		}
+
+		c.logger.Infof("applying migration %s", migration.ID)
+		if err := migration.Up(ctx, c.database); err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", migration.ID, err)
+		}
+
+		record := migrationRecord{ID: migration.ID, AppliedAt: time.Now()}
+		if _, err := collection.InsertOne(ctx, record); err != nil {
+			return mapError(err, fmt.Sprintf("failed to record migration %s", migration.ID))
+		}
+	}
+
+	return nil
+}
@@ -56,6 +56,21 @@ func (mr *MockMongoDBClientMockRecorder[T]) BulkInsert(ctx, models any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkInsert", reflect.TypeOf((*MockMongoDBClient[T])(nil).BulkInsert), ctx, models)
 }
 
+// Count mocks base method.
+func (m *MockMongoDBClient[T]) Count(ctx context.Context, filterOptions mongodb.FilterOptions) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, filterOptions)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockMongoDBClientMockRecorder[T]) Count(ctx, filterOptions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockMongoDBClient[T])(nil).Count), ctx, filterOptions)
+}
+
 // CreateIndex mocks base method.
 func (m *MockMongoDBClient[T]) CreateIndex(ctx context.Context, indexParam mongodb.IndexParam) (string, error) {
 	m.ctrl.T.Helper()
@@ -129,6 +144,20 @@ func (mr *MockMongoDBClientMockRecorder[T]) FindById(ctx, keyName, id any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockMongoDBClient[T])(nil).FindById), ctx, keyName, id)
 }
 
+// HealthCheck mocks base method.
+func (m *MockMongoDBClient[T]) HealthCheck(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HealthCheck", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HealthCheck indicates an expected call of HealthCheck.
+func (mr *MockMongoDBClientMockRecorder[T]) HealthCheck(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HealthCheck", reflect.TypeOf((*MockMongoDBClient[T])(nil).HealthCheck), ctx)
+}
+
 // Insert mocks base method.
 func (m *MockMongoDBClient[T]) Insert(ctx context.Context, model T) (primitive.ObjectID, error) {
 	m.ctrl.T.Helper()
@@ -157,3 +186,33 @@ func (mr *MockMongoDBClientMockRecorder[T]) Update(ctx, model, updateOptions any
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockMongoDBClient[T])(nil).Update), ctx, model, updateOptions)
 }
+
+// FindOneAndUpdate mocks base method.
+func (m *MockMongoDBClient[T]) FindOneAndUpdate(ctx context.Context, updateOptions mongodb.UpdateOptions) (T, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOneAndUpdate", ctx, updateOptions)
+	ret0, _ := ret[0].(T)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOneAndUpdate indicates an expected call of FindOneAndUpdate.
+func (mr *MockMongoDBClientMockRecorder[T]) FindOneAndUpdate(ctx, updateOptions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOneAndUpdate", reflect.TypeOf((*MockMongoDBClient[T])(nil).FindOneAndUpdate), ctx, updateOptions)
+}
+
+// UpdateMany mocks base method.
+func (m *MockMongoDBClient[T]) UpdateMany(ctx context.Context, updateOptions mongodb.UpdateOptions) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMany", ctx, updateOptions)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMany indicates an expected call of UpdateMany.
+func (mr *MockMongoDBClientMockRecorder[T]) UpdateMany(ctx, updateOptions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMany", reflect.TypeOf((*MockMongoDBClient[T])(nil).UpdateMany), ctx, updateOptions)
+}
@@ -3,62 +3,44 @@ package mongodb
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
-	"time"
 
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
 	"github.com/BrianLusina/skillq/server/infra/logger"
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
-	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
-// mongoDBClient is a structure for handling mongo db connections. This contains the underlying mongo client, database and collection
-// each instance might have a different client database and collection each with different settings depending on the configuration provided
+// mongoDBClient is a structure for handling mongo db connections. This contains the connection, database and collection
+// each instance might have a different connection database and collection each with different settings depending on the configuration provided
 type mongoDBClient[T any] struct {
-	mongoClient *mongo.Client
-	database    *mongo.Database
-	collection  *mongo.Collection
-	logger      logger.Logger
+	conn       *Connection
+	collection *mongo.Collection
+	logger     logger.Logger
 }
 
-// New creates a new mongo DB client
+// New creates a new mongo DB client with a connection of its own
 func New[T any](config MongoDBConfig, log logger.Logger) (MongoDBClient[T], error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	uri := fmt.Sprintf("mongodb://%s:%s@%s:%s", config.Client.User, config.Client.Password, config.Client.Host, config.Client.Port)
-	clientOptions := options.Client().ApplyURI(uri)
-
-	clientOptions.Hosts = []string{config.Client.Host}
-	clientOptions.SetRetryWrites(config.Client.RetryWrites)
-
-	dbClient, err := mongo.Connect(ctx, clientOptions)
+	conn, err := Connect(config, log)
 	if err != nil {
-		log.Fatalf(fmt.Sprintf("failed to connect to mongo db: %v", err))
-		return nil, errors.Wrapf(err, "failed to connect to mongo DB")
-	}
-
-	// TODO: set database options if provided
-	dbOptions := options.Database()
-
-	db := dbClient.Database(config.DBConfig.DatabaseName, dbOptions)
-	if err := dbClient.Ping(ctx, readpref.Primary()); err != nil {
-		log.Fatalf("DB Connection failed with err: %v", err)
+		return nil, err
 	}
 
-	collection := db.Collection(config.DBConfig.CollectionName)
-
-	log.Infof("connected to mongo db %s", config.DBConfig.DatabaseName)
+	return NewCollectionClient[T](conn, config.DBConfig.CollectionName), nil
+}
 
+// NewCollectionClient creates a mongo DB client of a collection that shares the connection, so that its operations can be
+// part of the transactions run on the connection
+func NewCollectionClient[T any](conn *Connection, collectionName string) MongoDBClient[T] {
 	return &mongoDBClient[T]{
-		mongoClient: dbClient,
-		database:    db,
-		collection:  collection,
-		logger:      log,
-	}, nil
+		conn:       conn,
+		collection: conn.database.Collection(collectionName),
+		logger:     conn.logger,
+	}
 }
 
 // Insert inserts a given model to the database's collection & returns the ID /error if any
@@ -66,7 +48,7 @@ func (client *mongoDBClient[T]) Insert(ctx context.Context, model T) (primitive.
 	result, err := client.collection.InsertOne(ctx, model)
 	if err != nil {
 		client.logger.Errorf("failed to insert item: %v with err: %v", model, err)
-		return primitive.ObjectID{}, err
+		return primitive.ObjectID{}, mapError(err, "failed to insert document")
 	}
 
 	oid, ok := result.InsertedID.(primitive.ObjectID)
@@ -95,11 +77,16 @@ func (client *mongoDBClient[T]) CreateIndex(ctx context.Context, indexParam Inde
 		indexName = indexParam.Name
 	}
 
+	indexOptions := options.Index().
+		SetUnique(indexParam.Unique).
+		SetName(indexName)
+	if indexParam.ExpireAfter != nil {
+		indexOptions.SetExpireAfterSeconds(int32(indexParam.ExpireAfter.Seconds()))
+	}
+
 	indexModel := mongo.IndexModel{
-		Keys: keys,
-		Options: options.Index().
-			SetUnique(true).
-			SetName(indexName),
+		Keys:    keys,
+		Options: indexOptions,
 	}
 
 	indexName, err := client.collection.Indexes().CreateOne(ctx, indexModel)
@@ -115,7 +102,7 @@ func (client *mongoDBClient[T]) CreateIndex(ctx context.Context, indexParam Inde
 func (client *mongoDBClient[T]) BulkInsert(ctx context.Context, models []any) ([]primitive.ObjectID, error) {
 	result, err := client.collection.InsertMany(ctx, models)
 	if err != nil {
-		return nil, err
+		return nil, mapError(err, "failed to insert documents")
 	}
 	insertedIds := []primitive.ObjectID{}
 
@@ -141,7 +128,7 @@ func (client *mongoDBClient[T]) Delete(ctx context.Context, keyName string, id s
 	var d bson.D
 	err := result.Decode(&d)
 	if err != nil {
-		return err
+		return mapError(err, fmt.Sprintf("failed to delete document with ID %s", id))
 	}
 
 	return nil
@@ -156,38 +143,50 @@ func (client *mongoDBClient[T]) FindById(ctx context.Context, keyName string, id
 	var result T
 	err := client.collection.FindOne(ctx, filter).Decode(&result)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return *new(T), errors.Wrapf(err, "no document with ID %s exists", id)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return *new(T), errdefs.NewNotFoundError(fmt.Sprintf("no document with ID %s exists", id), err)
 		}
-		return *new(T), err
+		return *new(T), mapError(err, fmt.Sprintf("failed to find document with ID %s", id))
 	}
 
 	return result, nil
 }
 
 func (client *mongoDBClient[T]) FindAll(ctx context.Context, filterOptions FilterOptions) ([]T, error) {
-	filterValues := bson.M{}
-	for key, value := range filterOptions.FieldFilter {
-		nestedBsonMap := bson.D{}
+	filterValues := buildFieldFilter(filterOptions.FieldFilter)
+
+	sortOrder := mapSortOrder(filterOptions.SortOrder)
+	sortValues := bson.D{{Key: filterOptions.OrderBy, Value: sortOrder}}
 
-		for nestedKey, nestedValue := range value {
-			nestedElement := bson.E{Key: nestedKey, Value: nestedValue}
-			nestedBsonMap = append(nestedBsonMap, nestedElement)
+	opts := options.Find().
+		SetLimit(int64(filterOptions.Limit))
+
+	cursorOptions := filterOptions.Cursor
+	if cursorOptions != nil {
+		// records before the cursor are retrieved by reversing the sort order, so the records nearest to the cursor come first
+		if cursorOptions.Backwards {
+			sortOrder = -sortOrder
+		}
+
+		if cursorOptions.Value != nil {
+			operator := "$gt"
+			if sortOrder < 0 {
+				operator = "$lt"
+			}
+			filterValues[cursorOptions.Key] = bson.D{{Key: operator, Value: cursorOptions.Value}}
 		}
 
-		filterValues[key] = nestedBsonMap
+		sortValues = bson.D{{Key: cursorOptions.Key, Value: sortOrder}}
+	} else {
+		opts = opts.SetSkip(int64(filterOptions.Offset))
 	}
 
-	sortValues := bson.D{{Key: filterOptions.OrderBy, Value: mapSortOrder(filterOptions.SortOrder)}}
+	opts = opts.SetSort(sortValues)
 
-	opts := options.Find().
-		SetLimit(int64(filterOptions.Limit)).
-		SetSkip(int64(filterOptions.Offset)).
-		SetSort(sortValues)
 	cursor, err := client.collection.Find(ctx, filterValues, opts)
 	if err != nil {
 		client.logger.Errorf("Failed to retrieve cursor with error %s", err)
-		return nil, err
+		return nil, mapError(err, "failed to retrieve documents")
 	}
 
 	defer func() {
@@ -208,54 +207,102 @@ func (client *mongoDBClient[T]) FindAll(ctx context.Context, filterOptions Filte
 		results = append(results, model)
 	}
 
+	// restore the requested sort order of records that were retrieved backwards
+	if cursorOptions != nil && cursorOptions.Backwards {
+		slices.Reverse(results)
+	}
+
 	return results, nil
 }
 
+// Count counts all the items matching the field filter of the given filter options
+func (client *mongoDBClient[T]) Count(ctx context.Context, filterOptions FilterOptions) (int64, error) {
+	count, err := client.collection.CountDocuments(ctx, buildFieldFilter(filterOptions.FieldFilter))
+	if err != nil {
+		client.logger.Errorf("Failed to count documents with error %s", err)
+		return 0, mapError(err, "failed to count documents")
+	}
+
+	return count, nil
+}
+
 // Update updates the model
 func (client *mongoDBClient[T]) Update(ctx context.Context, model T, updateOptions UpdateOptions) error {
 	opts := options.Update().SetUpsert(updateOptions.Upsert)
 
-	update := bson.D{}
+	update := buildUpdate(updateOptions)
+	filter := buildUpdateFilter(updateOptions)
 
-	for key, value := range updateOptions.FieldOptions {
-		switch v := value.(type) {
-		case []any:
-			update = append(update, bson.E{Key: "$addToSet", Value: bson.D{{Key: key, Value: v}}})
-		default:
-			update = append(update, bson.E{Key: "$set", Value: bson.D{{Key: key, Value: value}}})
-		}
+	result, err := client.collection.UpdateOne(ctx, filter, update, opts)
+	if err != nil {
+		client.logger.Errorf("Failed to update item %v with error %s", model, err)
+		return mapError(err, fmt.Sprintf("failed to update item %v", model))
 	}
 
-	for key, value := range updateOptions.SetOptions {
-		nestedDocument := bson.D{}
+	if result.MatchedCount == 0 && result.UpsertedCount == 0 {
+		return errdefs.NewNotFoundError(fmt.Sprintf("no document with %s %v exists", updateOptions.FilterParams.Key, updateOptions.FilterParams.Value), nil)
+	}
 
-		for k, v := range value {
-			nestedDocument = append(nestedDocument, bson.E{Key: k, Value: v})
-		}
+	if result.MatchedCount != 0 {
+		client.logger.Infof("Matched and replaced an existing document %v", model)
+	}
 
-		update = append(update, bson.E{Key: "$addToSet", Value: bson.D{{Key: key, Value: nestedDocument}}})
+	if result.UpsertedCount != 0 {
+		client.logger.Infof("Inserted a new document with ID %v", result.UpsertedID)
 	}
 
-	filter := bson.D{{Key: updateOptions.FilterParams.Key, Value: updateOptions.FilterParams.Value}}
+	return nil
+}
 
-	result, err := client.collection.UpdateOne(ctx, filter, update, opts)
+// UpdateMany updates all the documents matching the filter params of the update options
+func (client *mongoDBClient[T]) UpdateMany(ctx context.Context, updateOptions UpdateOptions) (int64, error) {
+	update := buildUpdate(updateOptions)
+	filter := buildUpdateFilter(updateOptions)
+
+	result, err := client.collection.UpdateMany(ctx, filter, update)
 	if err != nil {
-		client.logger.Errorf("Failed to update item %v with error %s", err)
-		return errors.Wrapf(err, "failed to update item %v", model)
+		client.logger.Errorf("Failed to update items matching %v with error %s", filter, err)
+		return 0, mapError(err, fmt.Sprintf("failed to update items with %s %v", updateOptions.FilterParams.Key, updateOptions.FilterParams.Value))
 	}
 
-	if result.MatchedCount != 0 {
-		client.logger.Info("Matched and replaced an existing document %v", model)
+	client.logger.Infof("Matched %d & modified %d documents", result.MatchedCount, result.ModifiedCount)
+
+	return result.ModifiedCount, nil
+}
+
+// FindOneAndUpdate atomically updates the document matching the filter params & conditions of the update options, returning
+// the document after the update. The first document in the sort order of the update options is updated if several match
+func (client *mongoDBClient[T]) FindOneAndUpdate(ctx context.Context, updateOptions UpdateOptions) (T, error) {
+	var model T
+
+	opts := options.FindOneAndUpdate().
+		SetUpsert(updateOptions.Upsert).
+		SetReturnDocument(options.After)
+
+	if len(updateOptions.Sort) > 0 {
+		sort := bson.D{}
+		for _, keyParam := range updateOptions.Sort {
+			sort = append(sort, bson.E{Key: keyParam.Key, Value: keyParam.Value})
+		}
+		opts.SetSort(sort)
 	}
 
-	if result.UpsertedCount != 0 {
-		client.logger.Infof("Inserted a new document with ID %v", result.UpsertedID)
+	update := buildUpdate(updateOptions)
+	filter := buildUpdateFilter(updateOptions)
+
+	if err := client.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&model); err != nil {
+		return model, mapError(err, fmt.Sprintf("failed to update item with %s %v", updateOptions.FilterParams.Key, updateOptions.FilterParams.Value))
 	}
 
-	return nil
+	return model, nil
 }
 
-// Disconnect disconnects from a mongo db client connection
+// Disconnect disconnects from a mongo db client connection. The connection is only closed once when it is shared
 func (client *mongoDBClient[T]) Disconnect(ctx context.Context) error {
-	return client.mongoClient.Disconnect(ctx)
+	return client.conn.Disconnect(ctx)
+}
+
+// HealthCheck pings the primary of the database to check that it can be reached
+func (client *mongoDBClient[T]) HealthCheck(ctx context.Context) error {
+	return client.conn.HealthCheck(ctx)
 }
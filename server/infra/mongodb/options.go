@@ -1,5 +1,7 @@
 package mongodb
 
+import "time"
+
 type SortOrder string
 
 const (
@@ -25,6 +27,23 @@ type FilterOptions struct {
 
 	// FieldFilter is the map to apply to a filter to retrieve fields that match the given criteria
 	FieldFilter map[string]map[string]string
+
+	// Cursor enables keyset pagination. If set, records are sorted by the key of the cursor instead of OrderBy & Offset is ignored
+	Cursor *CursorOptions
+}
+
+// CursorOptions are the options for keyset pagination, where a page of records is retrieved relative to the value of a
+// unique & sortable key instead of skipping records
+type CursorOptions struct {
+	// Key is the name of the field to page on. The field should be unique & sortable
+	Key string
+
+	// Value is the value of the key to retrieve records after, in the sort order. The record with the value is not included.
+	// If nil, records are retrieved from the start
+	Value any
+
+	// Backwards retrieves the records before the value instead of after it. The records are still returned in the sort order
+	Backwards bool
 }
 
 // UpdateOptions is a structure that contains update options
@@ -35,11 +54,25 @@ type UpdateOptions struct {
 	// FieldOptions are field options to use for updating a document
 	FieldOptions map[string]any
 
+	// IncOptions are the fields to increment by the given amounts when updating a document
+	IncOptions map[string]int
+
+	// SetOnInsertOptions are the fields to set only when an upsert inserts a new document
+	SetOnInsertOptions map[string]any
+
 	// SetOptions are set options to use for updating a nested document in a document
 	SetOptions map[string]map[string]any
 
 	// FilterParams is the filter parameters to use for querying a document to update
 	FilterParams FilterParams
+
+	// Conditions are additional filter parameters that a document has to match to be updated. A nil value matches a field
+	// that is null or missing
+	Conditions []FilterParams
+
+	// Sort is the order the document to update is picked in when several documents match, with 1 for ascending & -1 for
+	// descending order of a key. It is only used when updating a single document with FindOneAndUpdate
+	Sort []KeyParam
 }
 
 // FilterParams are the filter parameters used for querying specific fields in a document
@@ -61,11 +94,19 @@ type KeyParam struct {
 	Value any
 }
 
-// IndexParam is used to create unique index for a given key
+// IndexParam is used to create an index for the given keys
 type IndexParam struct {
 	// Keys to apply an index to
 	Keys []KeyParam
 
 	// Name is the name of the index
 	Name string
+
+	// Unique sets whether the index rejects documents with duplicate values for the keys
+	Unique bool
+
+	// ExpireAfter makes the index a TTL index that removes documents once the given duration has passed since the date in
+	// the single key of the index. Documents are removed by a background task that runs every minute, so they can outlive
+	// the duration for a while
+	ExpireAfter *time.Duration
 }
@@ -0,0 +1,131 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the tracer of mongo DB clients
+const tracerName = "github.com/BrianLusina/skillq/server/infra/mongodb"
+
+// tracedClient decorates a mongo DB client, tracing the operations on its collection in client spans
+type tracedClient[T any] struct {
+	client     MongoDBClient[T]
+	collection string
+	tracer     trace.Tracer
+}
+
+var _ MongoDBClient[any] = (*tracedClient[any])(nil)
+
+// NewTracedClient decorates a mongo DB client of the given collection with spans created by the tracer provider
+func NewTracedClient[T any](client MongoDBClient[T], collection string, tracerProvider trace.TracerProvider) MongoDBClient[T] {
+	return &tracedClient[T]{
+		client:     client,
+		collection: collection,
+		tracer:     tracerProvider.Tracer(tracerName),
+	}
+}
+
+// start starts the span of an operation on the collection
+func (c *tracedClient[T]) start(ctx context.Context, operation string) (context.Context, trace.Span) {
+	return c.tracer.Start(ctx, operation+" "+c.collection,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemMongoDB,
+			semconv.DBMongoDBCollection(c.collection),
+			semconv.DBOperation(operation),
+		),
+	)
+}
+
+// end ends the span of an operation that returned the error. An operation that found no document has not failed
+func end(span trace.Span, err error) {
+	if err != nil && !errdefs.IsNotFound(err) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (c *tracedClient[T]) Insert(ctx context.Context, model T) (primitive.ObjectID, error) {
+	ctx, span := c.start(ctx, "insert")
+	id, err := c.client.Insert(ctx, model)
+	end(span, err)
+	return id, err
+}
+
+func (c *tracedClient[T]) BulkInsert(ctx context.Context, models []any) ([]primitive.ObjectID, error) {
+	ctx, span := c.start(ctx, "bulk_insert")
+	ids, err := c.client.BulkInsert(ctx, models)
+	end(span, err)
+	return ids, err
+}
+
+func (c *tracedClient[T]) FindById(ctx context.Context, keyName string, id string) (T, error) {
+	ctx, span := c.start(ctx, "find_by_id")
+	model, err := c.client.FindById(ctx, keyName, id)
+	end(span, err)
+	return model, err
+}
+
+func (c *tracedClient[T]) FindAll(ctx context.Context, filterOptions FilterOptions) ([]T, error) {
+	ctx, span := c.start(ctx, "find_all")
+	models, err := c.client.FindAll(ctx, filterOptions)
+	end(span, err)
+	return models, err
+}
+
+func (c *tracedClient[T]) Count(ctx context.Context, filterOptions FilterOptions) (int64, error) {
+	ctx, span := c.start(ctx, "count")
+	count, err := c.client.Count(ctx, filterOptions)
+	end(span, err)
+	return count, err
+}
+
+func (c *tracedClient[T]) Update(ctx context.Context, model T, updateOptions UpdateOptions) error {
+	ctx, span := c.start(ctx, "update")
+	err := c.client.Update(ctx, model, updateOptions)
+	end(span, err)
+	return err
+}
+
+func (c *tracedClient[T]) UpdateMany(ctx context.Context, updateOptions UpdateOptions) (int64, error) {
+	ctx, span := c.start(ctx, "update_many")
+	modified, err := c.client.UpdateMany(ctx, updateOptions)
+	end(span, err)
+	return modified, err
+}
+
+func (c *tracedClient[T]) FindOneAndUpdate(ctx context.Context, updateOptions UpdateOptions) (T, error) {
+	ctx, span := c.start(ctx, "find_one_and_update")
+	model, err := c.client.FindOneAndUpdate(ctx, updateOptions)
+	end(span, err)
+	return model, err
+}
+
+func (c *tracedClient[T]) Delete(ctx context.Context, keyName string, id string) error {
+	ctx, span := c.start(ctx, "delete")
+	err := c.client.Delete(ctx, keyName, id)
+	end(span, err)
+	return err
+}
+
+func (c *tracedClient[T]) CreateIndex(ctx context.Context, indexParam IndexParam) (string, error) {
+	ctx, span := c.start(ctx, "create_index")
+	name, err := c.client.CreateIndex(ctx, indexParam)
+	end(span, err)
+	return name, err
+}
+
+func (c *tracedClient[T]) Disconnect(ctx context.Context) error {
+	return c.client.Disconnect(ctx)
+}
+
+func (c *tracedClient[T]) HealthCheck(ctx context.Context) error {
+	return c.client.HealthCheck(ctx)
+}
@@ -0,0 +1,76 @@
+package mongodb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/BrianLusina/skillq/server/infra/mongodb"
+	mockmongodb "github.com/BrianLusina/skillq/server/infra/mongodb/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTracedClient(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	mockClient := mockmongodb.NewMockMongoDBClient[testModel](mockCtrl)
+
+	newClient := func() (mongodb.MongoDBClient[testModel], *tracetest.SpanRecorder) {
+		recorder := tracetest.NewSpanRecorder()
+		return mongodb.NewTracedClient[testModel](mockClient, "users", sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))), recorder
+	}
+
+	t.Run("traces an operation in a client span", func(t *testing.T) {
+		client, recorder := newClient()
+		mockClient.EXPECT().Insert(gomock.Any(), testModel{ID: "1"}).
+			DoAndReturn(func(ctx context.Context, model testModel) (primitive.ObjectID, error) {
+				// the operation is called in the span, so the driver's own spans are its children
+				assert.True(t, trace.SpanContextFromContext(ctx).IsValid())
+				return primitive.NewObjectID(), nil
+			}).Times(1)
+
+		_, err := client.Insert(ctx, testModel{ID: "1"})
+		assert.NoError(t, err)
+
+		spans := recorder.Ended()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, "insert users", spans[0].Name())
+		assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+		assert.Contains(t, spans[0].Attributes(), attribute.String("db.system", "mongodb"))
+		assert.Contains(t, spans[0].Attributes(), attribute.String("db.mongodb.collection", "users"))
+		assert.Contains(t, spans[0].Attributes(), attribute.String("db.operation", "insert"))
+		assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	})
+
+	t.Run("records a failed operation", func(t *testing.T) {
+		client, recorder := newClient()
+		mockClient.EXPECT().Delete(gomock.Any(), "id", "1").Return(errors.New("connection reset")).Times(1)
+
+		assert.Error(t, client.Delete(ctx, "id", "1"))
+
+		spans := recorder.Ended()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Len(t, spans[0].Events(), 1)
+	})
+
+	t.Run("does not record an operation that found no document as failed", func(t *testing.T) {
+		client, recorder := newClient()
+		mockClient.EXPECT().FindById(gomock.Any(), "id", "2").Return(testModel{}, errdefs.NewNotFoundError("not found", nil)).Times(1)
+
+		_, err := client.FindById(ctx, "id", "2")
+		assert.True(t, errdefs.IsNotFound(err))
+
+		spans := recorder.Ended()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	})
+}
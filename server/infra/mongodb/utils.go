@@ -1,5 +1,12 @@
 package mongodb
 
+import (
+	"github.com/BrianLusina/skillq/server/domain/errdefs"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
 // mapSortOrder maps the sort order to an integer value
 func mapSortOrder(sortOrder SortOrder) int {
 	switch sortOrder {
@@ -11,3 +18,97 @@ func mapSortOrder(sortOrder SortOrder) int {
 		return 1
 	}
 }
+
+// buildFieldFilter builds the filter document of a query from the field filter of the filter options
+func buildFieldFilter(fieldFilter map[string]map[string]string) bson.M {
+	filterValues := bson.M{}
+	for key, value := range fieldFilter {
+		nestedBsonMap := bson.D{}
+
+		for nestedKey, nestedValue := range value {
+			nestedElement := bson.E{Key: nestedKey, Value: nestedValue}
+			nestedBsonMap = append(nestedBsonMap, nestedElement)
+		}
+
+		filterValues[key] = nestedBsonMap
+	}
+
+	return filterValues
+}
+
+// buildUpdate builds the update document from the update options
+func buildUpdate(updateOptions UpdateOptions) bson.D {
+	// each update operator can only appear once in an update document, so fields are grouped by the operator applied to them
+	setFields := bson.D{}
+	addToSetFields := bson.D{}
+
+	for key, value := range updateOptions.FieldOptions {
+		switch v := value.(type) {
+		case []any:
+			addToSetFields = append(addToSetFields, bson.E{Key: key, Value: v})
+		default:
+			setFields = append(setFields, bson.E{Key: key, Value: value})
+		}
+	}
+
+	for key, value := range updateOptions.SetOptions {
+		nestedDocument := bson.D{}
+
+		for k, v := range value {
+			nestedDocument = append(nestedDocument, bson.E{Key: k, Value: v})
+		}
+
+		addToSetFields = append(addToSetFields, bson.E{Key: key, Value: nestedDocument})
+	}
+
+	incFields := bson.D{}
+	for key, value := range updateOptions.IncOptions {
+		incFields = append(incFields, bson.E{Key: key, Value: value})
+	}
+
+	setOnInsertFields := bson.D{}
+	for key, value := range updateOptions.SetOnInsertOptions {
+		setOnInsertFields = append(setOnInsertFields, bson.E{Key: key, Value: value})
+	}
+
+	update := bson.D{}
+	if len(setFields) > 0 {
+		update = append(update, bson.E{Key: "$set", Value: setFields})
+	}
+	if len(incFields) > 0 {
+		update = append(update, bson.E{Key: "$inc", Value: incFields})
+	}
+	if len(addToSetFields) > 0 {
+		update = append(update, bson.E{Key: "$addToSet", Value: addToSetFields})
+	}
+	if len(setOnInsertFields) > 0 {
+		update = append(update, bson.E{Key: "$setOnInsert", Value: setOnInsertFields})
+	}
+
+	return update
+}
+
+// buildUpdateFilter builds the filter document of an update from the filter params & conditions of the update options
+func buildUpdateFilter(updateOptions UpdateOptions) bson.D {
+	filter := bson.D{{Key: updateOptions.FilterParams.Key, Value: updateOptions.FilterParams.Value}}
+	for _, condition := range updateOptions.Conditions {
+		filter = append(filter, bson.E{Key: condition.Key, Value: condition.Value})
+	}
+
+	return filter
+}
+
+// mapError maps an error returned by the mongo driver to a typed domain error with the given message. Errors that have no domain
+// equivalent are wrapped with the message
+func mapError(err error, msg string) error {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return errdefs.NewNotFoundError(msg, err)
+	case mongo.IsDuplicateKeyError(err):
+		return errdefs.NewConflictError(msg, err)
+	case mongo.IsNetworkError(err), mongo.IsTimeout(err), errors.Is(err, mongo.ErrClientDisconnected):
+		return errdefs.NewUnavailableError(msg, err)
+	default:
+		return errors.Wrap(err, msg)
+	}
+}
@@ -0,0 +1,2 @@
+// Package ratelimit contains the stores used to throttle actions by counting them in fixed windows of time
+package ratelimit
@@ -0,0 +1,2 @@
+// Package memory contains an in-memory rate limit store, which is only suitable for a single instance of the service
+package memory
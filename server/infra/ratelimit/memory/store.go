@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/ratelimit"
+)
+
+// sweepInterval is how often the windows of all keys are checked for expired ones, which removes the windows of keys
+// that are no longer used
+const sweepInterval = time.Minute
+
+// window is the count of actions of a key in the current window
+type window struct {
+	count     int64
+	expiresAt time.Time
+}
+
+// MemoryStore is a rate limit store that keeps the windows of keys in memory
+type MemoryStore struct {
+	mu        sync.Mutex
+	windows   map[string]*window
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+// NewStore creates a new in-memory rate limit store
+func NewStore() ratelimit.Store {
+	return &MemoryStore{
+		windows: make(map[string]*window),
+		now:     time.Now,
+	}
+}
+
+// Allow counts an action against the key in the current window, starting a new window if the previous one has expired
+func (s *MemoryStore) Allow(_ context.Context, key string, limit int, duration time.Duration) (ratelimit.Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	// the window of the key is replaced once it has expired, whether or not it has been swept yet
+	w, ok := s.windows[key]
+	if !ok || !now.Before(w.expiresAt) {
+		w = &window{expiresAt: now.Add(duration)}
+		s.windows[key] = w
+	}
+	w.count++
+
+	return ratelimit.NewResult(w.count, limit, w.expiresAt.Sub(now)), nil
+}
+
+// sweep removes the windows that have expired so that keys that are no longer used do not grow the store. The windows are
+// only swept once every sweep interval, so that counting an action does not have to check the windows of every key
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+	s.nextSweep = now.Add(sweepInterval)
+
+	for key, w := range s.windows {
+		if !now.Before(w.expiresAt) {
+			delete(s.windows, key)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	store := NewStore().(*MemoryStore)
+	store.now = func() time.Time { return now }
+
+	t.Run("allows actions up to the limit of the window", func(t *testing.T) {
+		for i := 2; i >= 0; i-- {
+			result, err := store.Allow(ctx, "user:1", 3, time.Hour)
+			assert.NoError(t, err)
+			assert.True(t, result.Allowed)
+			assert.Equal(t, i, result.Remaining)
+			assert.Equal(t, time.Hour, result.RetryAfter)
+		}
+
+		now = now.Add(10 * time.Minute)
+		result, err := store.Allow(ctx, "user:1", 3, time.Hour)
+		assert.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, 0, result.Remaining)
+		assert.Equal(t, 50*time.Minute, result.RetryAfter)
+	})
+
+	t.Run("counts keys separately", func(t *testing.T) {
+		result, err := store.Allow(ctx, "user:2", 3, time.Hour)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 2, result.Remaining)
+	})
+
+	t.Run("starts a new window once the window expires", func(t *testing.T) {
+		now = now.Add(time.Hour)
+
+		result, err := store.Allow(ctx, "user:1", 3, time.Hour)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 2, result.Remaining)
+		assert.Len(t, store.windows, 1)
+	})
+
+	t.Run("replaces an expired window before it is swept", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			_, err := store.Allow(ctx, "user:3", 3, time.Second)
+			assert.NoError(t, err)
+		}
+
+		now = now.Add(2 * time.Second)
+		result, err := store.Allow(ctx, "user:3", 3, time.Second)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 2, result.Remaining)
+	})
+
+	t.Run("sweeps the windows of keys that are no longer used once every sweep interval", func(t *testing.T) {
+		_, err := store.Allow(ctx, "user:4", 3, time.Second)
+		assert.NoError(t, err)
+
+		now = now.Add(2 * time.Second)
+		_, err = store.Allow(ctx, "user:1", 3, time.Hour)
+		assert.NoError(t, err)
+		assert.Contains(t, store.windows, "user:4")
+
+		now = now.Add(sweepInterval)
+		_, err = store.Allow(ctx, "user:1", 3, time.Hour)
+		assert.NoError(t, err)
+		assert.NotContains(t, store.windows, "user:4")
+	})
+}
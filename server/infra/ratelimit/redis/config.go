@@ -0,0 +1,16 @@
+package redis
+
+// Config is the configuration for setting up a redis rate limit store
+type Config struct {
+	// Address is the host:port address of the redis server
+	Address string
+
+	// Password is the password to use for the connection, can be a blank string
+	Password string
+
+	// DB is the redis database to select after connecting
+	DB int
+
+	// KeyPrefix is prepended to the keys of the store so that they do not clash with other keys in the database
+	KeyPrefix string
+}
@@ -0,0 +1,2 @@
+// Package redis contains a rate limit store backed by redis, which shares the windows of keys between instances of the service
+package redis
@@ -0,0 +1,73 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/BrianLusina/skillq/server/infra/ratelimit"
+	"github.com/pkg/errors"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// allowScript increments the count of the key & starts the window on the first action, returning the count & the time left in
+// the window in milliseconds. Running it as a script keeps the increment & expiry atomic, so a key can never be left without an
+// expiry
+var allowScript = goredis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+local ttl = redis.call("PTTL", KEYS[1])
+if ttl < 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+	ttl = tonumber(ARGV[1])
+end
+return {count, ttl}
+`)
+
+// RedisStore is a rate limit store that keeps the windows of keys in redis
+type RedisStore struct {
+	client    goredis.UniversalClient
+	keyPrefix string
+	log       logger.Logger
+}
+
+// NewStore creates a new redis rate limit store, checking that the redis server can be reached
+func NewStore(config Config, log logger.Logger) (ratelimit.Store, error) {
+	client := goredis.NewClient(&goredis.Options{
+		Addr:     config.Address,
+		Password: config.Password,
+		DB:       config.DB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to redis at %s", config.Address)
+	}
+
+	log.Infof("Redis rate limit store is connected to %s", config.Address)
+
+	return &RedisStore{
+		client:    client,
+		keyPrefix: config.KeyPrefix,
+		log:       log,
+	}, nil
+}
+
+// Allow counts an action against the key in the current window, starting a new window if the previous one has expired
+func (s *RedisStore) Allow(ctx context.Context, key string, limit int, window time.Duration) (ratelimit.Result, error) {
+	values, err := allowScript.Run(ctx, s.client, []string{s.keyPrefix + key}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		s.log.Errorf("failed to count action of key %s: %v", key, err)
+		return ratelimit.Result{}, errors.Wrapf(err, "failed to count action of key %s", key)
+	}
+
+	count, ttl := values[0], time.Duration(values[1])*time.Millisecond
+
+	return ratelimit.NewResult(count, limit, ttl), nil
+}
+
+// Close closes the connection to redis
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
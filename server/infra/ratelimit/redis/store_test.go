@@ -0,0 +1,65 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/BrianLusina/skillq/server/infra/logger"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisStore(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+
+	log := logger.New()
+	store, err := NewStore(Config{Address: server.Addr(), KeyPrefix: "ratelimit:"}, log)
+	assert.NoError(t, err)
+
+	t.Run("allows actions up to the limit of the window", func(t *testing.T) {
+		for i := 2; i >= 0; i-- {
+			result, err := store.Allow(ctx, "user:1", 3, time.Hour)
+			assert.NoError(t, err)
+			assert.True(t, result.Allowed)
+			assert.Equal(t, i, result.Remaining)
+		}
+
+		result, err := store.Allow(ctx, "user:1", 3, time.Hour)
+		assert.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, 0, result.Remaining)
+		assert.Equal(t, time.Hour, result.RetryAfter)
+		assert.Equal(t, time.Hour, server.TTL("ratelimit:user:1"))
+	})
+
+	t.Run("counts keys separately", func(t *testing.T) {
+		result, err := store.Allow(ctx, "user:2", 3, time.Hour)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 2, result.Remaining)
+	})
+
+	t.Run("starts a new window once the window expires", func(t *testing.T) {
+		server.FastForward(time.Hour)
+
+		result, err := store.Allow(ctx, "user:1", 3, time.Hour)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 2, result.Remaining)
+	})
+
+	t.Run("fails to create a store when redis is unreachable", func(t *testing.T) {
+		_, err := NewStore(Config{Address: "127.0.0.1:1"}, log)
+		assert.Error(t, err)
+	})
+
+	t.Run("returns an error when redis fails", func(t *testing.T) {
+		server.SetError("server error")
+		defer server.SetError("")
+
+		_, err := store.Allow(ctx, "user:3", 3, time.Hour)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,38 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Result is the outcome of counting an action against a limit
+type Result struct {
+	// Allowed is true if the action is within the limit of the current window
+	Allowed bool
+
+	// Remaining is the number of actions left in the current window
+	Remaining int
+
+	// RetryAfter is the time left until the current window resets
+	RetryAfter time.Duration
+}
+
+// Store defines the capabilities of a rate limit store, which counts actions identified by a key in fixed windows of time
+type Store interface {
+	// Allow counts an action against the key & reports whether it is within the limit of actions allowed in the window
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (Result, error)
+}
+
+// NewResult creates the result of an action that was counted as the given count in a window with the given time left
+func NewResult(count int64, limit int, ttl time.Duration) Result {
+	remaining := int64(limit) - count
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return Result{
+		Allowed:    count <= int64(limit),
+		Remaining:  int(remaining),
+		RetryAfter: ttl,
+	}
+}
@@ -0,0 +1,3 @@
+// Package requestid contains helpers to identify a request & carry its ID in a context, so that the logs of a request &
+// of the work it triggers can be joined
+package requestid
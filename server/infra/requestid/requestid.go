@@ -0,0 +1,51 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Header is the HTTP header a request ID is read from & written to
+const Header = "X-Request-ID"
+
+// maxLength is the longest request ID that is accepted from a client, longer IDs are replaced
+const maxLength = 128
+
+type keyRequestID int
+
+// requestIDKey is the key used to store the request ID in a context
+const requestIDKey keyRequestID = 0
+
+// New generates a new request ID
+func New() string {
+	return uuid.New().String()
+}
+
+// IsValid checks whether a request ID received from a client can be used. Valid IDs are not empty, at most 128
+// characters long & only contain printable ASCII characters, so they are safe to log & to echo back in a header
+func IsValid(requestID string) bool {
+	if requestID == "" || len(requestID) > maxLength {
+		return false
+	}
+
+	for i := 0; i < len(requestID); i++ {
+		if requestID[i] < ' ' || requestID[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// WithRequestID returns a copy of the context that carries the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// FromContext retrieves the request ID from the context. The second return value is false if the context has no request
+// ID
+func FromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDKey).(string)
+	return requestID, ok && requestID != ""
+}
@@ -0,0 +1,55 @@
+package requestid
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	first, second := New(), New()
+
+	assert.True(t, IsValid(first))
+	assert.NotEqual(t, first, second)
+}
+
+func TestIsValid(t *testing.T) {
+	tests := []struct {
+		name      string
+		requestID string
+		expected  bool
+	}{
+		{name: "uuid", requestID: "0b6b3a3e-5b8c-4c8e-9a54-5a0f0a9f1d2e", expected: true},
+		{name: "printable ascii", requestID: "req_123:abc", expected: true},
+		{name: "empty", requestID: "", expected: false},
+		{name: "too long", requestID: strings.Repeat("a", maxLength+1), expected: false},
+		{name: "control characters", requestID: "abc\r\nInjected: header", expected: false},
+		{name: "non ascii", requestID: "abcé", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsValid(tt.requestID))
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	t.Run("context with request ID", func(t *testing.T) {
+		ctx := WithRequestID(context.Background(), "123")
+
+		requestID, ok := FromContext(ctx)
+
+		assert.True(t, ok)
+		assert.Equal(t, "123", requestID)
+	})
+
+	t.Run("context without request ID", func(t *testing.T) {
+		requestID, ok := FromContext(context.Background())
+
+		assert.False(t, ok)
+		assert.Empty(t, requestID)
+	})
+}
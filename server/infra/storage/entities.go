@@ -1,6 +1,9 @@
 package storage
 
-import "time"
+import (
+	"io"
+	"time"
+)
 
 // StorageItem is the item that will be stored in blob storage
 type StorageItem struct {
@@ -23,6 +26,30 @@ type StorageItem struct {
 	PolicyType PolicyType
 }
 
+// StorageStreamItem is an item whose content is streamed to blob storage from a reader instead of being held in memory
+type StorageStreamItem struct {
+	// Name is the name of the document, including its file extension
+	Name string
+
+	// Content is the reader the content of the item is streamed from
+	Content io.Reader
+
+	// Size is the number of bytes that will be read from Content or -1 if this is not known up front
+	Size int64
+
+	// ContentType is the type of content of this item
+	ContentType string
+
+	// Bucket is where to store the document
+	Bucket string
+
+	// Metadata is optional additional key value pair data
+	Metadata map[string]string
+
+	// PolicyType for a storage item
+	PolicyType PolicyType
+}
+
 // Document structure represents a document representation
 type Document struct {
 	// MimeType is the type of the document, application/zip, text/plain, application/pdf, image/png
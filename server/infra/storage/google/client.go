@@ -11,6 +11,7 @@ import (
 	"github.com/BrianLusina/skillq/server/infra/storage"
 	"github.com/pkg/errors"
 	"golang.org/x/oauth2/google"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 
 	googleStorage "cloud.google.com/go/storage"
@@ -96,6 +97,41 @@ func (sc *GoogleStorageClient) Upload(ctx context.Context, item storage.StorageI
 	return fmt.Sprintf("https://storage.cloud.google.com/%s/%s", bucket, item.Name), nil
 }
 
+// UploadStream streams the content of the storage item to the bucket returning the URL to the stored item
+func (sc *GoogleStorageClient) UploadStream(ctx context.Context, item storage.StorageStreamItem) (string, error) {
+	bucket := item.Bucket
+
+	if ok, err := sc.BucketExists(ctx, bucket); !ok && err != nil {
+		sc.log.Infof("Bucket %s does not exist. Error info: %v. Creating bucket...", bucket, err)
+
+		err := sc.CreateBucket(ctx, bucket)
+		if err != nil {
+			sc.log.Errorf("Failed to create bucket %s with error: %v", bucket, err)
+			return "", errors.Wrapf(err, "failed to create bucket %s when uploading item %s", bucket, item.Name)
+		}
+	}
+
+	// cancelling the context of the writer before it is closed aborts the upload
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	objectWriter := sc.client.Bucket(bucket).Object(item.Name).NewWriter(ctx)
+	objectWriter.ContentType = item.ContentType
+	objectWriter.Metadata = item.Metadata
+
+	if _, err := io.Copy(objectWriter, item.Content); err != nil {
+		sc.log.Errorf("Failed to upload document data of item %s with err: %v", item.Name, err)
+		return "", errors.Wrapf(err, "failed to upload document %s", item.Name)
+	}
+
+	if err := objectWriter.Close(); err != nil {
+		sc.log.Errorf("Failed to close object writer with err: %v", err)
+		return "", errors.Wrapf(err, "failed to close object writer: %v", err)
+	}
+
+	return fmt.Sprintf("https://storage.cloud.google.com/%s/%s", bucket, item.Name), nil
+}
+
 // CreateBucket creates a bucket
 func (sc *GoogleStorageClient) CreateBucket(ctx context.Context, bucketName string) error {
 	if ok, err := sc.BucketExists(ctx, bucketName); err == nil && ok {
@@ -130,3 +166,13 @@ func (sc *GoogleStorageClient) BucketExists(ctx context.Context, bucketName stri
 
 	return true, nil
 }
+
+// HealthCheck lists a bucket of the project to check that google cloud storage can be reached with the credentials of the
+// client
+func (sc *GoogleStorageClient) HealthCheck(ctx context.Context) error {
+	_, err := sc.client.Buckets(ctx, sc.projectID).Next()
+	if err != nil && !errors.Is(err, iterator.Done) {
+		return errors.Wrapf(err, "failed to list google cloud storage buckets")
+	}
+	return nil
+}
@@ -13,6 +13,10 @@ import (
 	"github.com/pkg/errors"
 )
 
+// unknownSizePartSize is the size of the parts content of an unknown size is uploaded in, which is the smallest part size
+// minio allows
+const unknownSizePartSize = 5 * 1024 * 1024
+
 // MinioStorageClient is a wrapper around minio that enables interactions with a Minio cluster
 type MinioStorageClient struct {
 	client    *minio.Client
@@ -50,7 +54,30 @@ func NewClient(config Config, log logger.Logger) (storage.StorageClient, error)
 	}, nil
 }
 
+// Upload decodes the base64 content of the storage item & uploads it returning the URL to the stored item
 func (sc *MinioStorageClient) Upload(ctx context.Context, item storage.StorageItem) (string, error) {
+	document, err := storage.GetDocumentData(item.Content)
+	if err != nil {
+		sc.log.Errorf("Failed to retrieve document data from item %v", item)
+		return "", errors.Wrapf(err, "failed to retrieve document data")
+	}
+
+	reader := bytes.NewReader(document.Data)
+
+	return sc.UploadStream(ctx, storage.StorageStreamItem{
+		Name:        fmt.Sprintf("%s.%s", item.Name, document.FileExtension),
+		Content:     reader,
+		Size:        reader.Size(),
+		ContentType: item.ContentType,
+		Bucket:      item.Bucket,
+		Metadata:    item.Metadata,
+		PolicyType:  item.PolicyType,
+	})
+}
+
+// UploadStream streams the content of the storage item to minio returning the URL to the stored item. If the size of the
+// item is unknown, minio uploads the content in parts
+func (sc *MinioStorageClient) UploadStream(ctx context.Context, item storage.StorageStreamItem) (string, error) {
 	bucket := item.Bucket
 	if err := sc.CreateBucket(ctx, bucket); err != nil {
 		// check to see if we already own this bucket, which happens if this is run twice
@@ -62,22 +89,20 @@ func (sc *MinioStorageClient) Upload(ctx context.Context, item storage.StorageIt
 		}
 	}
 
-	sc.log.Infof("Successfully created bucket %s", bucket)
-
-	document, err := storage.GetDocumentData(item.Content)
-	if err != nil {
-		sc.log.Errorf("Failed to retrieve document data from item %v", item)
-		return "", errors.Wrapf(err, "failed to retrieve document data")
+	options := minio.PutObjectOptions{
+		ContentType:  item.ContentType,
+		UserMetadata: item.Metadata,
 	}
 
-	// upload document
-	reader := bytes.NewReader(document.Data)
+	size := item.Size
+	if size < 0 {
+		// content of an unknown size is uploaded in parts, each of which is buffered in memory. Without a part size, minio
+		// sizes the parts for the largest object it supports
+		size = -1
+		options.PartSize = unknownSizePartSize
+	}
 
-	info, err := sc.client.PutObject(ctx, bucket, item.Name, reader, reader.Size(), minio.PutObjectOptions{
-		ContentType:  item.ContentType,
-		UserMetadata: item.Metadata,
-		Progress:     reader,
-	})
+	info, err := sc.client.PutObject(ctx, bucket, item.Name, item.Content, size, options)
 	if err != nil {
 		sc.log.Errorf("Failed to upload document: %v", err)
 		return "", errors.Wrapf(err, "failed to upload document")
@@ -102,9 +127,9 @@ func (sc *MinioStorageClient) Upload(ctx context.Context, item storage.StorageIt
 		sc.log.Infof("Successfully created bucket policy of %s for bucket %s", item.PolicyType, bucket)
 	}
 
-	sc.log.Infof("Successfully uploaded document to bucket %s at location %s", info.Bucket, info.Location)
+	sc.log.Infof("Successfully uploaded document of %d bytes to bucket %s at location %s", info.Size, info.Bucket, info.Location)
 
-	loc := fmt.Sprintf("%s/%s/%s.%s", sc.publicUrl, bucket, item.Name, document.FileExtension)
+	loc := fmt.Sprintf("%s/%s/%s", sc.publicUrl, bucket, item.Name)
 
 	return loc, nil
 }
@@ -135,3 +160,11 @@ func (sc *MinioStorageClient) BucketExists(ctx context.Context, bucketName strin
 	}
 	return exists, nil
 }
+
+// HealthCheck lists the buckets to check that minio can be reached with the credentials of the client
+func (sc *MinioStorageClient) HealthCheck(ctx context.Context) error {
+	if _, err := sc.client.ListBuckets(ctx); err != nil {
+		return errors.Wrapf(err, "failed to list minio buckets")
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	"testing"
 
 	"github.com/BrianLusina/skillq/server/infra/logger"
@@ -79,4 +80,18 @@ func TestMinioStorageClient(t *testing.T) {
 		_, err := minioClient.Upload(ctx, storageItem)
 		assert.NoError(t, err)
 	})
+
+	t.Run("should stream a file of unknown size for storage", func(t *testing.T) {
+		storageItem := storage.StorageStreamItem{
+			Name:        "streamed-document.txt",
+			Content:     strings.NewReader("heya"),
+			Size:        -1,
+			ContentType: "text/plain",
+			Bucket:      "images",
+		}
+
+		location, err := minioClient.UploadStream(ctx, storageItem)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasSuffix(location, "/images/streamed-document.txt"))
+	})
 }
@@ -69,6 +69,20 @@ func (mr *MockStorageClientMockRecorder) CreateBucket(arg0, arg1 any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBucket", reflect.TypeOf((*MockStorageClient)(nil).CreateBucket), arg0, arg1)
 }
 
+// HealthCheck mocks base method.
+func (m *MockStorageClient) HealthCheck(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HealthCheck", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HealthCheck indicates an expected call of HealthCheck.
+func (mr *MockStorageClientMockRecorder) HealthCheck(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HealthCheck", reflect.TypeOf((*MockStorageClient)(nil).HealthCheck), ctx)
+}
+
 // Upload mocks base method.
 func (m *MockStorageClient) Upload(arg0 context.Context, arg1 storage.StorageItem) (string, error) {
 	m.ctrl.T.Helper()
@@ -83,3 +97,18 @@ func (mr *MockStorageClientMockRecorder) Upload(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockStorageClient)(nil).Upload), arg0, arg1)
 }
+
+// UploadStream mocks base method.
+func (m *MockStorageClient) UploadStream(arg0 context.Context, arg1 storage.StorageStreamItem) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadStream", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadStream indicates an expected call of UploadStream.
+func (mr *MockStorageClientMockRecorder) UploadStream(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadStream", reflect.TypeOf((*MockStorageClient)(nil).UploadStream), arg0, arg1)
+}
@@ -43,7 +43,30 @@ func NewClient(config Config, log logger.Logger) (storage.StorageClient, error)
 	return &S3StorageClient{s3Client: client, log: log, region: config.Region}, nil
 }
 
+// Upload decodes the base64 content of the storage item & uploads it returning the URL to the stored item
 func (sc *S3StorageClient) Upload(ctx context.Context, item storage.StorageItem) (string, error) {
+	document, err := storage.GetDocumentData(item.Content)
+	if err != nil {
+		sc.log.Errorf("Failed to retrieve document data from item %v", item)
+		return "", errors.Wrapf(err, "failed to retrieve document data")
+	}
+
+	bufferedReader := bytes.NewReader(document.Data)
+
+	return sc.UploadStream(ctx, storage.StorageStreamItem{
+		Name:        fmt.Sprintf("%s.%s", item.Name, document.FileExtension),
+		Content:     bufferedReader,
+		Size:        bufferedReader.Size(),
+		ContentType: document.MimeType,
+		Bucket:      item.Bucket,
+		Metadata:    item.Metadata,
+		PolicyType:  item.PolicyType,
+	})
+}
+
+// UploadStream streams the content of the storage item to S3 returning the URL to the stored item. S3 requires the content
+// length of an object up front, so content of an unknown size is read into memory before it is uploaded
+func (sc *S3StorageClient) UploadStream(ctx context.Context, item storage.StorageStreamItem) (string, error) {
 	bucket := item.Bucket
 	if ok, err := sc.BucketExists(ctx, bucket); err != nil || !ok {
 		err := sc.CreateBucket(ctx, bucket)
@@ -52,34 +75,35 @@ func (sc *S3StorageClient) Upload(ctx context.Context, item storage.StorageItem)
 		}
 	}
 
-	sc.log.Infof("Uploading storage item %v", item)
+	sc.log.Infof("Uploading storage item %s to bucket %s", item.Name, bucket)
 
-	document, err := storage.GetDocumentData(item.Content)
-	if err != nil {
-		sc.log.Errorf("Failed to retrieve document data from item %v", item)
-		return "", errors.Wrapf(err, "failed to retrieve document data")
+	content, size := item.Content, item.Size
+	if size < 0 {
+		data, err := io.ReadAll(item.Content)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to read storage item %s", item.Name)
+		}
+		content, size = bytes.NewReader(data), int64(len(data))
 	}
 
-	// upload document
-	bufferedReader := bytes.NewReader(document.Data)
-	key := fmt.Sprintf("%s.%s", item.Name, document.FileExtension)
-
-	_, err = sc.s3Client.PutObject(ctx, &awsS3.PutObjectInput{
+	_, err := sc.s3Client.PutObject(ctx, &awsS3.PutObjectInput{
 		Bucket:               aws.String(bucket),
-		Key:                  aws.String(key),
-		Body:                 bufferedReader,
-		ContentType:          aws.String(document.MimeType),
+		Key:                  aws.String(item.Name),
+		Body:                 content,
+		ContentLength:        aws.Int64(size),
+		ContentType:          aws.String(item.ContentType),
 		ContentDisposition:   aws.String("attachment"),
+		Metadata:             item.Metadata,
 		ServerSideEncryption: types.ServerSideEncryptionAes256,
 		StorageClass:         types.StorageClassIntelligentTiering,
 		ACL:                  types.ObjectCannedACLPublicRead,
 	})
 	if err != nil {
-		sc.log.Errorf("Failed to upload item %v, Err: %v", item, err)
-		return "", errors.Wrapf(err, "failed to upload storage item %v", item)
+		sc.log.Errorf("Failed to upload item %s, Err: %v", item.Name, err)
+		return "", errors.Wrapf(err, "failed to upload storage item %s", item.Name)
 	}
 
-	location := fmt.Sprintf("https://%s.s3-%s.amazonaws.com/%s", bucket, sc.region, key)
+	location := fmt.Sprintf("https://%s.s3-%s.amazonaws.com/%s", bucket, sc.region, item.Name)
 
 	return location, nil
 }
@@ -152,3 +176,11 @@ func (sc *S3StorageClient) BucketExists(ctx context.Context, bucketName string)
 
 	return exists, err
 }
+
+// HealthCheck lists the buckets to check that S3 can be reached with the credentials of the client
+func (sc *S3StorageClient) HealthCheck(ctx context.Context) error {
+	if _, err := sc.s3Client.ListBuckets(ctx, &awsS3.ListBucketsInput{}); err != nil {
+		return errors.Wrapf(err, "failed to list S3 buckets")
+	}
+	return nil
+}
@@ -7,9 +7,15 @@ type StorageClient interface {
 	// Upload uploads a new storage item and returns the URL to the stored item
 	Upload(context.Context, StorageItem) (string, error)
 
+	// UploadStream streams the content of a storage item to storage and returns the URL to the stored item
+	UploadStream(context.Context, StorageStreamItem) (string, error)
+
 	// CreateBucket creates a bucket
 	CreateBucket(context.Context, string) error
 
 	// BucketExists checks if a bucket exists
 	BucketExists(ctx context.Context, bucketName string) (bool, error)
+
+	// HealthCheck checks that the storage can be reached with the credentials of the client
+	HealthCheck(ctx context.Context) error
 }
@@ -0,0 +1,3 @@
+// Package tracing sets up OpenTelemetry tracing, exporting the spans of the application over OTLP or to stdout &
+// propagating trace context in the W3C trace context format
+package tracing
@@ -0,0 +1,107 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+const (
+	// ExporterOTLP exports spans to an OTLP receiver over HTTP, such as an OpenTelemetry collector or Jaeger
+	ExporterOTLP = "otlp"
+
+	// ExporterStdout writes spans to stdout, which is meant for local use
+	ExporterStdout = "stdout"
+
+	// ExporterNone does not export spans. Trace context is still propagated, so traces are not broken by this service
+	ExporterNone = "none"
+)
+
+// Config is the configuration of tracing
+type Config struct {
+	// ServiceName & ServiceVersion identify the service the spans are exported from
+	ServiceName    string
+	ServiceVersion string
+
+	// Exporter is where spans are exported to, one of otlp, stdout or none
+	Exporter string
+
+	// Endpoint is the host & port of the OTLP receiver, such as localhost:4318
+	Endpoint string
+
+	// Insecure exports spans to the OTLP receiver over plain HTTP instead of HTTPS
+	Insecure bool
+
+	// SampleRatio is the ratio of traces started by this service that are sampled, from 0 to 1. Traces started by other
+	// services are sampled if they were sampled there
+	SampleRatio float64
+}
+
+// Setup creates a tracer provider that exports spans with the configured exporter & registers it as the global tracer
+// provider, along with the W3C trace context & baggage propagators. The tracer provider has to be shut down to flush the
+// spans that have not been exported yet
+func Setup(ctx context.Context, config Config) (*sdktrace.TracerProvider, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(config.ServiceName),
+		semconv.ServiceVersion(config.ServiceVersion),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing resource: %w", err)
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	}
+
+	exporter, err := newExporter(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	provider := sdktrace.NewTracerProvider(opts...)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider, nil
+}
+
+// newExporter creates the configured span exporter, which is nil if spans are not exported
+func newExporter(ctx context.Context, config Config) (sdktrace.SpanExporter, error) {
+	switch config.Exporter {
+	case ExporterOTLP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(config.Endpoint)}
+		if config.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+
+		exporter, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		return exporter, nil
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdout exporter: %w", err)
+		}
+		return exporter, nil
+	case ExporterNone, "":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", config.Exporter)
+	}
+}
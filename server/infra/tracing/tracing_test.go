@@ -0,0 +1,41 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestSetup(t *testing.T) {
+	t.Run("registers the tracer provider & W3C propagators", func(t *testing.T) {
+		provider, err := Setup(context.Background(), Config{ServiceName: "skillq-service", Exporter: ExporterNone, SampleRatio: 1})
+		assert.NoError(t, err)
+		defer func() {
+			assert.NoError(t, provider.Shutdown(context.Background()))
+		}()
+
+		assert.Equal(t, provider, otel.GetTracerProvider())
+
+		ctx, span := otel.Tracer("test").Start(context.Background(), "operation")
+		defer span.End()
+		assert.True(t, span.SpanContext().IsSampled())
+
+		carrier := propagation.MapCarrier{}
+		otel.GetTextMapPropagator().Inject(ctx, carrier)
+		assert.Contains(t, carrier["traceparent"], span.SpanContext().TraceID().String())
+	})
+
+	t.Run("creates the stdout exporter", func(t *testing.T) {
+		provider, err := Setup(context.Background(), Config{ServiceName: "skillq-service", Exporter: ExporterStdout})
+		assert.NoError(t, err)
+		assert.NoError(t, provider.Shutdown(context.Background()))
+	})
+
+	t.Run("fails for an unknown exporter", func(t *testing.T) {
+		_, err := Setup(context.Background(), Config{ServiceName: "skillq-service", Exporter: "zipkin"})
+		assert.Error(t, err)
+	})
+}
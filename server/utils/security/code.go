@@ -1,13 +1,38 @@
 package security
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
-	"math/rand"
+	"math/big"
 )
 
-// GenerateCode generates a random user code
-func GenerateCode() string {
-	randNum := rand.Intn(10000)
-	code := fmt.Sprintf("%04d", randNum)
-	return code
+// GenerateCode generates a random numeric code of the given length, such as a code sent to users to verify their email
+func GenerateCode(length int) (string, error) {
+	if length <= 0 {
+		return "", fmt.Errorf("code length must be positive, got %d", length)
+	}
+
+	ten := big.NewInt(10)
+	code := make([]byte, length)
+	for i := range code {
+		digit, err := rand.Int(rand.Reader, ten)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate code: %w", err)
+		}
+		code[i] = byte('0' + digit.Int64())
+	}
+
+	return string(code), nil
+}
+
+// HashCode hashes a code issued to the given subject, such as a user ID, with HMAC-SHA256 keyed with the given secret returning
+// the hex encoded hash. Codes are short enough to be guessed from a plain hash, so the hash can only be computed with the
+// secret, & the subject is hashed along with the code giving the same code a different hash for every subject
+func HashCode(secret, subject, code string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(subject + ":" + code))
+	return hex.EncodeToString(mac.Sum(nil))
 }
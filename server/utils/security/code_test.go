@@ -0,0 +1,35 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateCode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("generates numeric codes of the given length", func(t *testing.T) {
+		for _, length := range []int{4, 6, 8} {
+			code, err := GenerateCode(length)
+			assert.NoError(t, err)
+			assert.Len(t, code, length)
+			assert.Regexp(t, "^[0-9]+$", code)
+		}
+	})
+
+	t.Run("rejects invalid lengths", func(t *testing.T) {
+		_, err := GenerateCode(0)
+		assert.Error(t, err)
+	})
+}
+
+func TestHashCode(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, HashCode("secret", "user-1", "123456"), HashCode("secret", "user-1", "123456"))
+	assert.NotEqual(t, HashCode("secret", "user-1", "123456"), HashCode("secret", "user-1", "654321"))
+	assert.NotEqual(t, HashCode("secret", "user-1", "123456"), HashCode("secret", "user-2", "123456"))
+	assert.NotEqual(t, HashCode("secret", "user-1", "123456"), HashCode("other-secret", "user-1", "123456"))
+	assert.NotContains(t, HashCode("secret", "user-1", "123456"), "123456")
+}
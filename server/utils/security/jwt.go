@@ -0,0 +1,99 @@
+package security
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTConfig is the configuration used to sign & verify JSON Web Tokens
+type JWTConfig struct {
+	// Secret is the key used to sign tokens with HMAC-SHA256
+	Secret string
+
+	// Issuer is the issuer of the tokens. Tokens from other issuers are rejected
+	Issuer string
+
+	// TTL is how long a token is valid for after it is issued
+	TTL time.Duration
+}
+
+// AccessTokenClaims are the claims of an access token. The subject of the token is the ID of the user it was issued to
+type AccessTokenClaims struct {
+	jwt.RegisteredClaims
+}
+
+// JWTManager signs & verifies JSON Web Tokens
+type JWTManager struct {
+	secret []byte
+	issuer string
+	ttl    time.Duration
+}
+
+// NewJWTManager creates a new JWTManager from the given configuration
+func NewJWTManager(config JWTConfig) (*JWTManager, error) {
+	if config.Secret == "" {
+		return nil, errors.New("jwt secret must be provided")
+	}
+
+	if config.TTL <= 0 {
+		return nil, fmt.Errorf("jwt ttl must be positive, got %s", config.TTL)
+	}
+
+	return &JWTManager{
+		secret: []byte(config.Secret),
+		issuer: config.Issuer,
+		ttl:    config.TTL,
+	}, nil
+}
+
+// Generate creates a signed token for the given subject returning the token & the time it expires at
+func (m *JWTManager) Generate(subject string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(m.ttl)
+
+	claims := AccessTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    m.issuer,
+			Subject:   subject,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return token, expiresAt, nil
+}
+
+// Verify parses the token, verifying its signature, issuer & expiry and returns its claims
+func (m *JWTManager) Verify(token string) (*AccessTokenClaims, error) {
+	claims := &AccessTokenClaims{}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if m.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(m.issuer))
+	}
+
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) {
+		return m.secret, nil
+	}, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	if claims.Subject == "" {
+		return nil, errors.New("invalid token: missing subject")
+	}
+
+	return claims, nil
+}
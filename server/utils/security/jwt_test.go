@@ -0,0 +1,108 @@
+package security
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWTManager(t *testing.T) {
+	t.Parallel()
+
+	config := JWTConfig{
+		Secret: "test-secret",
+		Issuer: "skillq",
+		TTL:    time.Minute,
+	}
+
+	t.Run("rejects invalid configuration", func(t *testing.T) {
+		_, err := NewJWTManager(JWTConfig{TTL: time.Minute})
+		assert.Error(t, err)
+
+		_, err = NewJWTManager(JWTConfig{Secret: "secret"})
+		assert.Error(t, err)
+	})
+
+	t.Run("verifies generated tokens", func(t *testing.T) {
+		manager, err := NewJWTManager(config)
+		assert.NoError(t, err)
+
+		token, expiresAt, err := manager.Generate("user-id")
+		assert.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), expiresAt, time.Second)
+
+		claims, err := manager.Verify(token)
+		assert.NoError(t, err)
+		assert.Equal(t, "user-id", claims.Subject)
+		assert.Equal(t, "skillq", claims.Issuer)
+	})
+
+	t.Run("rejects tokens signed with another secret", func(t *testing.T) {
+		manager, err := NewJWTManager(config)
+		assert.NoError(t, err)
+
+		otherConfig := config
+		otherConfig.Secret = "other-secret"
+		otherManager, err := NewJWTManager(otherConfig)
+		assert.NoError(t, err)
+
+		token, _, err := otherManager.Generate("user-id")
+		assert.NoError(t, err)
+
+		_, err = manager.Verify(token)
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects tokens from another issuer", func(t *testing.T) {
+		manager, err := NewJWTManager(config)
+		assert.NoError(t, err)
+
+		otherConfig := config
+		otherConfig.Issuer = "other"
+		otherManager, err := NewJWTManager(otherConfig)
+		assert.NoError(t, err)
+
+		token, _, err := otherManager.Generate("user-id")
+		assert.NoError(t, err)
+
+		_, err = manager.Verify(token)
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects expired tokens", func(t *testing.T) {
+		manager, err := NewJWTManager(config)
+		assert.NoError(t, err)
+		manager.ttl = -time.Minute
+
+		token, _, err := manager.Generate("user-id")
+		assert.NoError(t, err)
+
+		_, err = manager.Verify(token)
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects malformed tokens", func(t *testing.T) {
+		manager, err := NewJWTManager(config)
+		assert.NoError(t, err)
+
+		_, err = manager.Verify("not-a-token")
+		assert.Error(t, err)
+	})
+}
+
+func TestHashToken(t *testing.T) {
+	t.Parallel()
+
+	token, err := GenerateToken(32)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+
+	otherToken, err := GenerateToken(32)
+	assert.NoError(t, err)
+	assert.NotEqual(t, token, otherToken)
+
+	assert.Equal(t, HashToken(token), HashToken(token))
+	assert.NotEqual(t, HashToken(token), HashToken(otherToken))
+	assert.NotContains(t, HashToken(token), token)
+}
@@ -0,0 +1,27 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// GenerateToken generates a random URL safe token from the given number of random bytes. This is suitable for opaque
+// tokens such as refresh tokens, which should only be stored hashed with HashToken
+func GenerateToken(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken hashes a token with SHA-256 returning the hex encoded hash. Unlike passwords, random tokens have enough entropy
+// to not need a slow hash, which allows them to be looked up by their hash
+func HashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
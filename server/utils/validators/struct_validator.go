@@ -0,0 +1,43 @@
+package validators
+
+import (
+	"encoding/base64"
+	"regexp"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// base64DataURLTag is the validation tag for a field that should be a base64 encoded data URL
+const base64DataURLTag = "base64dataurl"
+
+var (
+	// base64DataURLRegex matches data URLs of the form data:<mediatype>[;<param>=<value>];base64,<data>
+	base64DataURLRegex = regexp.MustCompile(`^data:[a-z]+/[a-zA-Z0-9.+-]+(;[a-zA-Z0-9-]+=[^;,]+)*;base64,(.+)$`)
+)
+
+// NewStructValidator creates a validator for struct-tag validation of structs. Besides the validations provided by
+// go-playground/validator, it registers a base64dataurl tag to validate base64 encoded data URLs
+func NewStructValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// the tag is registered on a fresh validator which can only fail if the tag or function is empty
+	_ = v.RegisterValidation(base64DataURLTag, validateBase64DataURL)
+
+	return v
+}
+
+// validateBase64DataURL checks that the field is a data URL whose data is base64 encoded
+func validateBase64DataURL(fl validator.FieldLevel) bool {
+	return IsBase64DataURL(fl.Field().String())
+}
+
+// IsBase64DataURL checks whether the given value is a data URL whose data is base64 encoded
+func IsBase64DataURL(value string) bool {
+	matches := base64DataURLRegex.FindStringSubmatch(value)
+	if matches == nil {
+		return false
+	}
+
+	_, err := base64.StdEncoding.DecodeString(matches[2])
+	return err == nil
+}
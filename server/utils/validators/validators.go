@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"strings"
 
 	ozzoValidation "github.com/go-ozzo/ozzo-validation"
 	"github.com/go-ozzo/ozzo-validation/is"
@@ -66,39 +67,120 @@ func ValidateCurrencyCode(code string) error {
 	return nil
 }
 
+// FieldError describes a validation failure on a single field of a struct
+type FieldError struct {
+	// Field is the path to the field using the JSON names of the fields, e.g. image.content
+	Field string `json:"field"`
+
+	// Message is a human readable description of the failure
+	Message string `json:"message"`
+}
+
 // GetValidationErrMsg checks to see if the provided err is a validation error and
 // returns the first validation error message.
 func GetValidationErrMsg(s any, err error) (errMsg string) {
+	fieldErrors := GetValidationErrMsgs(s, err)
+	if len(fieldErrors) == 0 {
+		return ""
+	}
+
+	return fieldErrors[0].Message
+}
+
+// GetValidationErrMsgs checks to see if the provided err is a validation error and
+// returns a validation error message for every field that failed validation.
+func GetValidationErrMsgs(s any, err error) []FieldError {
 	fieldErrors := validator.ValidationErrors{}
 
-	if ok := errors.As(err, &fieldErrors); ok {
-		fieldErr := fieldErrors[0]
-		fieldName := getStructTag(s, fieldErr.Field(), "json")
+	if ok := errors.As(err, &fieldErrors); !ok {
+		return nil
+	}
+
+	errs := make([]FieldError, 0, len(fieldErrors))
+	for _, fieldErr := range fieldErrors {
+		fieldName := getJSONFieldPath(s, fieldErr.StructNamespace())
+
+		errs = append(errs, FieldError{
+			Field:   fieldName,
+			Message: buildValidationErrMsg(fieldName, fieldErr),
+		})
+	}
+
+	return errs
+}
 
-		switch fieldErr.Tag() {
-		case "required":
-			errMsg = fmt.Sprintf("%s is a required field", fieldName)
-		default:
-			errMsg = fmt.Sprintf("Invalid input on %s", fieldName)
+// buildValidationErrMsg formats the message for a single failed validation of a field
+func buildValidationErrMsg(fieldName string, fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is a required field", fieldName)
+	case "email":
+		return fmt.Sprintf("%s must be a valid email address", fieldName)
+	case "uuid":
+		return fmt.Sprintf("%s must be a valid UUID", fieldName)
+	case base64DataURLTag:
+		return fmt.Sprintf("%s must be a base64 encoded data URL", fieldName)
+	case "min":
+		if isCollection(fieldErr.Kind()) {
+			return fmt.Sprintf("%s must contain at least %s items", fieldName, fieldErr.Param())
 		}
+		return fmt.Sprintf("%s must be at least %s characters long", fieldName, fieldErr.Param())
+	case "max":
+		if isCollection(fieldErr.Kind()) {
+			return fmt.Sprintf("%s must contain at most %s items", fieldName, fieldErr.Param())
+		}
+		return fmt.Sprintf("%s must be at most %s characters long", fieldName, fieldErr.Param())
+	default:
+		return fmt.Sprintf("Invalid input on %s", fieldName)
 	}
+}
 
-	return errMsg
+func isCollection(kind reflect.Kind) bool {
+	return kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map
 }
 
-func getStructTag(s any, fieldName string, tagKey string) string {
+// getJSONFieldPath maps the struct namespace of a field, e.g. userRequestDto.Image.Content to the path of the field
+// using the JSON tags of the fields of s, e.g. image.content. Indexes of slice elements are kept, e.g. skills[0]
+func getJSONFieldPath(s any, structNamespace string) string {
 	t := reflect.TypeOf(s)
-	field, found := t.FieldByName(fieldName)
 
-	if t.Kind() != reflect.Struct {
-		return fieldName
-	}
+	// the first part of the namespace is the name of the top level struct
+	parts := strings.Split(structNamespace, ".")[1:]
+	names := make([]string, 0, len(parts))
+
+	for _, part := range parts {
+		fieldName, index := part, ""
+		if i := strings.Index(part, "["); i != -1 {
+			fieldName, index = part[:i], part[i:]
+		}
+
+		for t != nil && (t.Kind() == reflect.Pointer || isCollection(t.Kind())) {
+			t = t.Elem()
+		}
+
+		name := fieldName
+		if t != nil && t.Kind() == reflect.Struct {
+			if field, found := t.FieldByName(fieldName); found {
+				name = getJSONName(field)
+				t = field.Type
+			} else {
+				t = nil
+			}
+		}
 
-	if !found {
-		return fieldName
+		names = append(names, name+index)
 	}
 
-	return field.Tag.Get(tagKey)
+	return strings.Join(names, ".")
+}
+
+// getJSONName returns the name of a struct field as defined in its json tag, defaulting to the field's name
+func getJSONName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
 }
 
 // IsValidationError checks to see if error is of type validator.ValidationErrors.
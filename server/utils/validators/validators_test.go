@@ -141,3 +141,86 @@ func BenchmarkValidateCountryCode(b *testing.B) {
 		}
 	}
 }
+
+type imageTestDto struct {
+	Content string `json:"content" validate:"required,base64dataurl"`
+}
+
+type userTestDto struct {
+	Name   string        `json:"name" validate:"required,min=2"`
+	Email  string        `json:"email" validate:"required,email"`
+	Skills []string      `json:"skills" validate:"min=1,dive,required"`
+	Image  *imageTestDto `json:"image" validate:"required"`
+}
+
+func TestGetValidationErrMsgs(t *testing.T) {
+	t.Parallel()
+
+	v := NewStructValidator()
+
+	t.Run("reports every failing field by its JSON name", func(t *testing.T) {
+		dto := userTestDto{
+			Name:   "",
+			Email:  "not-an-email",
+			Skills: []string{"go", ""},
+			Image:  &imageTestDto{Content: "aGV5YQ=="},
+		}
+
+		err := v.Struct(dto)
+		assert.Error(t, err)
+		assert.True(t, IsValidationError(err))
+
+		fieldErrs := GetValidationErrMsgs(dto, err)
+		assert.Equal(t, []FieldError{
+			{Field: "name", Message: "name is a required field"},
+			{Field: "email", Message: "email must be a valid email address"},
+			{Field: "skills[1]", Message: "skills[1] is a required field"},
+			{Field: "image.content", Message: "image.content must be a base64 encoded data URL"},
+		}, fieldErrs)
+		assert.Equal(t, "name is a required field", GetValidationErrMsg(dto, err))
+	})
+
+	t.Run("reports length validations", func(t *testing.T) {
+		dto := userTestDto{
+			Name:   "a",
+			Email:  "jane@example.com",
+			Skills: []string{},
+			Image:  &imageTestDto{Content: "data:image/png;base64,aGV5YQ=="},
+		}
+
+		fieldErrs := GetValidationErrMsgs(dto, v.Struct(dto))
+		assert.Equal(t, []FieldError{
+			{Field: "name", Message: "name must be at least 2 characters long"},
+			{Field: "skills", Message: "skills must contain at least 1 items"},
+		}, fieldErrs)
+	})
+
+	t.Run("returns nothing for errors that are not validation errors", func(t *testing.T) {
+		assert.Empty(t, GetValidationErrMsgs(userTestDto{}, errors.New("some error")))
+		assert.Equal(t, "", GetValidationErrMsg(userTestDto{}, errors.New("some error")))
+	})
+}
+
+func TestIsBase64DataURL(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{name: "valid image data url", input: "data:image/png;base64,aGV5YQ==", expected: true},
+		{name: "valid data url with parameters", input: "data:text/plain;charset=utf-8;base64,aGV5YQ==", expected: true},
+		{name: "missing base64 marker", input: "data:image/png,aGV5YQ==", expected: false},
+		{name: "missing data scheme", input: "image/png;base64,aGV5YQ==", expected: false},
+		{name: "invalid base64 data", input: "data:image/png;base64,not base64!", expected: false},
+		{name: "plain base64", input: "aGV5YQ==", expected: false},
+		{name: "empty", input: "", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, IsBase64DataURL(tc.input))
+		})
+	}
+}